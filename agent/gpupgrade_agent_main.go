@@ -11,6 +11,8 @@ import (
 
 	"github.com/greenplum-db/gp-common-go-libs/gplog"
 	"github.com/greenplum-db/gpupgrade/agent/services"
+	"github.com/greenplum-db/gpupgrade/certs"
+	"github.com/greenplum-db/gpupgrade/config"
 	"github.com/greenplum-db/gpupgrade/helpers"
 	"github.com/greenplum-db/gpupgrade/utils"
 	"github.com/spf13/cobra"
@@ -24,7 +26,7 @@ func main() {
 	//if err != nil {
 	//	os.Exit(utils.GetExitCodeForError(err))
 	//}
-	var logdir, statedir string
+	var logdir, statedir, hubAddress string
 	var daemonize bool
 	var daemon bool
 	var RootCmd = &cobra.Command{
@@ -32,6 +34,14 @@ func main() {
 		Short: "Start the Command Listener (blocks)",
 		Long:  `Start the Command Listener (blocks)`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			gpupgradeConf, err := config.Load(statedir)
+			if err != nil {
+				return err
+			}
+			if !cmd.Flags().Changed("log-directory") {
+				logdir = gpupgradeConf.LogDir
+			}
+
 			gplog.InitializeLogging("gpupgrade_agent", logdir)
 
 			if daemon && terminal.IsTerminal(int(os.Stdout.Fd())) {
@@ -52,7 +62,7 @@ func main() {
 
 				command := exec.Command(os.Args[0], daemonArgs...)
 				// TODO: what's a good timeout?
-				err := utils.Daemonize(command, os.Stdout, os.Stderr, 2*time.Second)
+				err = utils.Daemonize(command, os.Stdout, os.Stderr, 2*time.Second)
 
 				if err != nil {
 					exitError, ok := err.(*exec.ExitError)
@@ -75,9 +85,23 @@ func main() {
 				return err
 			}
 
+			creds, err := certs.ServerCredentials(statedir, certs.Agent, certs.Hub)
+			if err != nil {
+				return err
+			}
+
 			conf := services.AgentConfig{
-				Port:     6416,
-				StateDir: statedir,
+				Port:        gpupgradeConf.AgentPort,
+				StateDir:    statedir,
+				BindAddress: gpupgradeConf.AgentBindAddress,
+				Credentials: creds,
+				HubAddress:  hubAddress,
+			}
+			if hubAddress != "" {
+				conf.HubCredentials, err = certs.ClientCredentials(statedir, certs.Agent)
+				if err != nil {
+					return err
+				}
 			}
 
 			commandExecer := func(command string, vars ...string) helpers.Command {
@@ -99,6 +123,7 @@ func main() {
 
 	RootCmd.Flags().StringVar(&logdir, "log-directory", "", "command_listener log directory")
 	RootCmd.Flags().StringVar(&statedir, "state-directory", utils.GetStateDir(), "Agent state directory")
+	RootCmd.Flags().StringVar(&hubAddress, "hub-address", "", "host:port of the hub to register with")
 
 	RootCmd.Flags().BoolVar(&daemonize, "daemonize", false, "start hub in the background")
 	RootCmd.Flags().BoolVar(&daemon, "daemon", false, "disconnect standard streams (internal option; use --daemonize instead)")
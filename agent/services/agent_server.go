@@ -4,40 +4,69 @@ import (
 	"fmt"
 	"net"
 	"os"
+	"path/filepath"
 	"strconv"
 	"sync"
+	"time"
 
 	"github.com/greenplum-db/gpupgrade/helpers"
 	pb "github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/utils"
 
 	"github.com/greenplum-db/gp-common-go-libs/gplog"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/reflection"
 )
 
+// ShutdownGracePeriod is how long an agent that has been asked to shut down
+// waits for the RPCs in flight to finish before stopping anyway.
+var ShutdownGracePeriod = 30 * time.Second
+
 type AgentServer struct {
-	GetDiskUsage  func() (map[string]float64, error)
+	GetFreeSpace  func(path string) (device uint64, available uint64, err error)
 	commandExecer helpers.CommandExecer
 	conf          AgentConfig
-
-	mu      sync.Mutex
-	server  *grpc.Server
-	lis     net.Listener
-	stopped chan struct{}
-	daemon  bool
+	processes     *utils.Processes
+
+	mu           sync.Mutex
+	server       *grpc.Server
+	lis          net.Listener
+	stopped      chan struct{}
+	done         chan struct{}
+	drained      chan struct{}
+	daemon       bool
+	shuttingDown bool
 }
 
 type AgentConfig struct {
 	Port     int
 	StateDir string
+
+	// BindAddress is the address the agent listens on; empty means every
+	// interface.
+	BindAddress string
+
+	// Credentials is the agent's certificate, which it serves with. Only hubs
+	// presenting a certificate from the same CA are accepted. The agent
+	// executable always sets it; connections are only left insecure in tests.
+	Credentials credentials.TransportCredentials
+
+	// HubAddress is where the agent registers with the hub and sends it
+	// heartbeats, presenting HubCredentials; empty means it doesn't.
+	HubAddress     string
+	HubCredentials credentials.TransportCredentials
 }
 
 func NewAgentServer(execer helpers.CommandExecer, conf AgentConfig) *AgentServer {
 	return &AgentServer{
-		GetDiskUsage:  diskUsage,
+		GetFreeSpace:  freeSpace,
 		commandExecer: execer,
 		conf:          conf,
+		processes:     utils.NewProcesses(processRecordPath(conf.StateDir)),
 		stopped:       make(chan struct{}, 1),
+		done:          make(chan struct{}),
+		drained:       make(chan struct{}),
 	}
 }
 
@@ -49,12 +78,17 @@ func (a *AgentServer) MakeDaemon() {
 
 func (a *AgentServer) Start() {
 	createIfNotExists(a.conf.StateDir)
-	lis, err := net.Listen("tcp", ":"+strconv.Itoa(a.conf.Port))
+	lis, err := net.Listen("tcp", net.JoinHostPort(a.conf.BindAddress, strconv.Itoa(a.conf.Port)))
 	if err != nil {
 		gplog.Fatal(err, "failed to listen")
 	}
 
-	server := grpc.NewServer()
+	var opts []grpc.ServerOption
+	if a.conf.Credentials != nil {
+		opts = append(opts, grpc.Creds(a.conf.Credentials))
+	}
+
+	server := grpc.NewServer(opts...)
 	a.mu.Lock()
 	a.server = server
 	a.lis = lis
@@ -71,6 +105,10 @@ func (a *AgentServer) Start() {
 		os.Stdout.Close()
 	}
 
+	if a.conf.HubAddress != "" {
+		go a.keepRegistered(a.done)
+	}
+
 	err = server.Serve(lis)
 	if err != nil {
 		gplog.Fatal(err, "failed to serve", err)
@@ -84,9 +122,21 @@ func (a *AgentServer) Stop() {
 	defer a.mu.Unlock()
 
 	if a.server != nil {
+		close(a.done)
 		a.server.Stop()
 		<-a.stopped
+		a.server = nil
+	}
+}
+
+// processRecordPath returns where the agent keeps the records of the
+// processes it starts, in stateDir; it is not the hub's file, since the two
+// may share a state directory. Without a state directory they are not kept.
+func processRecordPath(stateDir string) string {
+	if stateDir == "" {
+		return ""
 	}
+	return filepath.Join(stateDir, "agent_processes.json")
 }
 
 func createIfNotExists(dir string) {
@@ -0,0 +1,22 @@
+package services
+
+import (
+	"context"
+
+	pb "github.com/greenplum-db/gpupgrade/idl"
+
+	"github.com/greenplum-db/gp-common-go-libs/gplog"
+)
+
+// Cancel stops the pg_upgrade processes converting the primaries on this
+// host, and the servers they left running.
+func (s *AgentServer) Cancel(ctx context.Context, in *pb.CancelAgentRequest) (*pb.CancelAgentReply, error) {
+	gplog.Info("got a request to cancel from the hub")
+
+	cancelled := s.processes.Terminate()
+	for _, name := range cancelled {
+		gplog.Info("cancelled %s", name)
+	}
+
+	return &pb.CancelAgentReply{Cancelled: cancelled}, nil
+}
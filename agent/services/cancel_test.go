@@ -0,0 +1,77 @@
+package services_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/greenplum-db/gpupgrade/agent/services"
+	pb "github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/testutils"
+
+	"github.com/greenplum-db/gp-common-go-libs/testhelper"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Cancel", func() {
+	var (
+		agent         *services.AgentServer
+		dir           string
+		commandExecer *testutils.FakeCommandExecer
+	)
+
+	BeforeEach(func() {
+		testhelper.SetupTestLogger()
+
+		var err error
+		dir, err = ioutil.TempDir("", "")
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(os.MkdirAll(filepath.Join(dir, "pg_upgrade"), 0700)).To(Succeed())
+		oidFile := filepath.Join(dir, "pg_upgrade", "pg_upgrade_dump_1_oids.sql")
+		Expect(ioutil.WriteFile(oidFile, nil, 0600)).To(Succeed())
+
+		commandExecer = &testutils.FakeCommandExecer{}
+		commandExecer.SetOutput(&testutils.FakeCommand{})
+		agent = services.NewAgentServer(commandExecer.Exec, services.AgentConfig{StateDir: dir})
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	It("stops the upgrade of each primary, and the servers pg_upgrade left running", func() {
+		newDataDir := filepath.Join(dir, "new")
+		Expect(os.MkdirAll(newDataDir, 0700)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(newDataDir, "postmaster.pid"), []byte("1234\n"), 0600)).To(Succeed())
+
+		_, err := agent.UpgradeConvertPrimarySegments(nil, &pb.UpgradeConvertPrimarySegmentsRequest{
+			OldBinDir: "/old/bin",
+			NewBinDir: "/new/bin",
+			DataDirPairs: []*pb.DataDirPair{
+				{OldDataDir: filepath.Join(dir, "old"), NewDataDir: newDataDir, Content: 0},
+				{OldDataDir: filepath.Join(dir, "old1"), NewDataDir: filepath.Join(dir, "new1"), Content: 1},
+			},
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		reply, err := agent.Cancel(nil, &pb.CancelAgentRequest{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(reply.Cancelled).To(Equal([]string{
+			"convert-primary (segment 0)",
+			"convert-primary (segment 1)",
+		}))
+
+		Expect(commandExecer.Calls()).To(ContainElement(
+			"bash -c source /new/bin/../greenplum_path.sh; /new/bin/pg_ctl stop -D " + newDataDir + " -m fast -w",
+		))
+	})
+
+	It("cancels nothing when nothing is running", func() {
+		reply, err := agent.Cancel(nil, &pb.CancelAgentRequest{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(reply.Cancelled).To(BeEmpty())
+	})
+})
@@ -5,38 +5,139 @@ import (
 	"errors"
 	"fmt"
 	"path/filepath"
+	"strings"
 
 	"github.com/greenplum-db/gpupgrade/hub/upgradestatus"
 	pb "github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/utils"
+
+	"github.com/greenplum-db/gp-common-go-libs/gplog"
 )
 
 func (s *AgentServer) CheckConversionStatus(ctx context.Context, in *pb.CheckConversionStatusRequest) (*pb.CheckConversionStatusReply, error) {
 	if len(in.GetSegments()) == 0 {
 		return nil, errors.New("no segment information was passed to the agent")
 	}
-	format := "%s - DBID %d - CONTENT ID %d - %s - %s"
 
-	var replies []string
-	var master string
+	var replies []*pb.SegmentConversionStatus
+	var master *pb.SegmentConversionStatus
 	for _, segment := range in.GetSegments() {
+		pgUpgradePath := filepath.Join(s.conf.StateDir, "pg_upgrade", fmt.Sprintf("seg-%d", segment.GetContent()))
 		conversionStatus := upgradestatus.NewPGUpgradeStatusChecker(
-			filepath.Join(s.conf.StateDir, "pg_upgrade", fmt.Sprintf("seg-%d", segment.GetContent())),
+			pgUpgradePath,
 			segment.GetDataDir(),
 			s.commandExecer,
 		)
+		record := s.processes.Record(convertPrimaryName(segment.GetContent()))
+		conversionStatus.UseRecord(record)
 
-		status := conversionStatus.GetStatus()
+		status := &pb.SegmentConversionStatus{
+			Dbid:     segment.GetDbid(),
+			Content:  segment.GetContent(),
+			Role:     pb.SegmentRole_PRIMARY,
+			Hostname: in.GetHostname(),
+			Status:   conversionStatus.GetStatus().Status,
+		}
+		if record != nil {
+			status.StartTime, status.EndTime = conversionStatus.GetTiming(status.Status)
+		} else {
+			status.StartTime, status.EndTime = progressTimes(pgUpgradePath, status.Status)
+		}
+		if status.Status == pb.StepStatus_FAILED {
+			if record != nil && record.Cancelled {
+				status.Error = utils.ErrCancelled.Error()
+			} else {
+				status.Error = lastProgressMessage(pgUpgradePath)
+			}
+		}
+		if status.Status != pb.StepStatus_PENDING {
+			status.Progress = conversionStatus.GetProgress(status.Status)
+		}
 
 		if segment.GetDbid() == 1 && segment.GetContent() == -1 {
-			master = fmt.Sprintf(format, status.Status.String(), segment.GetDbid(), segment.GetContent(), "MASTER", in.GetHostname())
+			status.Role = pb.SegmentRole_MASTER
+			master = status
 		} else {
-			replies = append(replies, fmt.Sprintf(format, status.Status.String(), segment.GetDbid(), segment.GetContent(), "PRIMARY", in.GetHostname()))
+			replies = append(replies, status)
 		}
 	}
 
-	replies = append([]string{master}, replies...)
+	if master != nil {
+		replies = append([]*pb.SegmentConversionStatus{master}, replies...)
+	}
 
 	return &pb.CheckConversionStatusReply{
 		Statuses: replies,
 	}, nil
 }
+
+// progressFiles returns the *.inprogress and *.done files that pg_upgrade
+// has written to pgUpgradePath.
+func progressFiles(pgUpgradePath string) []string {
+	var files []string
+	for _, pattern := range []string{"*.inprogress", "*.done"} {
+		matches, err := utils.System.FilePathGlob(filepath.Join(pgUpgradePath, pattern))
+		if err != nil {
+			gplog.Error("could not list pg_upgrade progress files in %s: %s", pgUpgradePath, err)
+			continue
+		}
+		files = append(files, matches...)
+	}
+
+	return files
+}
+
+// progressTimes derives when pg_upgrade started and, once it has stopped
+// running, when it finished from the modification times of its progress
+// files. Times that are not known are returned as zero.
+func progressTimes(pgUpgradePath string, status pb.StepStatus) (start int64, end int64) {
+	finished := status == pb.StepStatus_COMPLETE || status == pb.StepStatus_FAILED
+
+	for _, file := range progressFiles(pgUpgradePath) {
+		fi, err := utils.System.Stat(file)
+		if err != nil {
+			continue
+		}
+
+		modTime := fi.ModTime().Unix()
+		if start == 0 || modTime < start {
+			start = modTime
+		}
+		if finished && modTime > end {
+			end = modTime
+		}
+	}
+
+	return start, end
+}
+
+// lastProgressMessage returns the last line pg_upgrade wrote to its most
+// recently modified progress file.
+func lastProgressMessage(pgUpgradePath string) string {
+	var latest string
+	var latestModTime int64
+	for _, file := range progressFiles(pgUpgradePath) {
+		fi, err := utils.System.Stat(file)
+		if err != nil {
+			continue
+		}
+
+		if modTime := fi.ModTime().UnixNano(); latest == "" || modTime > latestModTime {
+			latest = file
+			latestModTime = modTime
+		}
+	}
+
+	if latest == "" {
+		return ""
+	}
+
+	contents, err := utils.System.ReadFile(latest)
+	if err != nil {
+		gplog.Error("could not read pg_upgrade progress file %s: %s", latest, err)
+		return ""
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(contents)), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
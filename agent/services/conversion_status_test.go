@@ -1,9 +1,11 @@
 package services_test
 
 import (
+	"encoding/json"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"time"
 
 	pb "github.com/greenplum-db/gpupgrade/idl"
 	"github.com/greenplum-db/gpupgrade/testutils"
@@ -50,7 +52,7 @@ var _ = Describe("CommandListener", func() {
 		os.RemoveAll(dir)
 	})
 
-	It("returns a status string for each DBID passed from the hub", func() {
+	It("returns a status for each DBID passed from the hub", func() {
 		status, err := agent.CheckConversionStatus(nil, &pb.CheckConversionStatusRequest{
 			Segments: []*pb.SegmentInfo{{
 				Content: 1,
@@ -65,9 +67,9 @@ var _ = Describe("CommandListener", func() {
 		})
 		Expect(err).ToNot(HaveOccurred())
 
-		Expect(status.GetStatuses()).To(Equal([]string{
-			"PENDING - DBID 1 - CONTENT ID -1 - MASTER - localhost",
-			"PENDING - DBID 3 - CONTENT ID 1 - PRIMARY - localhost",
+		Expect(status.GetStatuses()).To(Equal([]*pb.SegmentConversionStatus{
+			{Dbid: 1, Content: -1, Role: pb.SegmentRole_MASTER, Hostname: "localhost", Status: pb.StepStatus_PENDING},
+			{Dbid: 3, Content: 1, Role: pb.SegmentRole_PRIMARY, Hostname: "localhost", Status: pb.StepStatus_PENDING},
 		}))
 	})
 
@@ -94,10 +96,14 @@ var _ = Describe("CommandListener", func() {
 		})
 		Expect(err).ToNot(HaveOccurred())
 
-		Expect(status.GetStatuses()).To(Equal([]string{
-			"PENDING - DBID 1 - CONTENT ID -1 - MASTER - localhost",
-			"RUNNING - DBID 3 - CONTENT ID 1 - PRIMARY - localhost",
-		}))
+		statuses := status.GetStatuses()
+		Expect(statuses).To(HaveLen(2))
+		Expect(statuses[0].GetStatus()).To(Equal(pb.StepStatus_PENDING))
+		Expect(statuses[1].GetStatus()).To(Equal(pb.StepStatus_RUNNING))
+		Expect(statuses[1].GetStartTime()).ToNot(BeZero())
+		Expect(statuses[1].GetEndTime()).To(BeZero())
+		Expect(statuses[1].GetProgress()).ToNot(BeNil())
+		Expect(statuses[0].GetProgress()).To(BeNil())
 	})
 
 	It("returns COMPLETE for segments that have completed the upgrade", func() {
@@ -122,10 +128,61 @@ var _ = Describe("CommandListener", func() {
 		})
 		Expect(err).ToNot(HaveOccurred())
 
-		Expect(status.GetStatuses()).To(Equal([]string{
-			"COMPLETE - DBID 1 - CONTENT ID -1 - MASTER - localhost",
-			"PENDING - DBID 3 - CONTENT ID 1 - PRIMARY - localhost",
-		}))
+		statuses := status.GetStatuses()
+		Expect(statuses).To(HaveLen(2))
+		Expect(statuses[0].GetRole()).To(Equal(pb.SegmentRole_MASTER))
+		Expect(statuses[0].GetStatus()).To(Equal(pb.StepStatus_COMPLETE))
+		Expect(statuses[0].GetStartTime()).ToNot(BeZero())
+		Expect(statuses[0].GetEndTime()).ToNot(BeZero())
+		Expect(statuses[1].GetStatus()).To(Equal(pb.StepStatus_PENDING))
+	})
+
+	It("returns the last message pg_upgrade wrote for segments that failed", func() {
+		err := os.MkdirAll(filepath.Join(dir, "pg_upgrade", "seg-1"), 0700)
+		Expect(err).ToNot(HaveOccurred())
+		err = ioutil.WriteFile(filepath.Join(dir, "pg_upgrade", "seg-1", ".done"),
+			[]byte("Checking for reg* system OID user data types\nfatal: could not create relation\n"), 0600)
+		Expect(err).ToNot(HaveOccurred())
+
+		status, err := agent.CheckConversionStatus(nil, &pb.CheckConversionStatusRequest{
+			Segments: []*pb.SegmentInfo{{
+				Content: 1,
+				Dbid:    3,
+				DataDir: "/old/data/dir",
+			}},
+			Hostname: "localhost",
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		statuses := status.GetStatuses()
+		Expect(statuses).To(HaveLen(1))
+		Expect(statuses[0].GetStatus()).To(Equal(pb.StepStatus_FAILED))
+		Expect(statuses[0].GetError()).To(Equal("fatal: could not create relation"))
+		Expect(statuses[0].GetEndTime()).ToNot(BeZero())
+	})
+
+	It("reports segments whose upgrade was cancelled as such", func() {
+		records, err := json.Marshal([]utils.ProcessRecord{{
+			Name:      "convert-primary (segment 1)",
+			StartTime: time.Now().Add(-time.Minute),
+			EndTime:   time.Now(),
+			ExitCode:  -1,
+			Cancelled: true,
+		}})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(ioutil.WriteFile(filepath.Join(dir, "agent_processes.json"), records, 0600)).To(Succeed())
+		Expect(os.MkdirAll(filepath.Join(dir, "pg_upgrade", "seg-1"), 0700)).To(Succeed())
+		agent = services.NewAgentServer(commandExecer.Exec, services.AgentConfig{StateDir: dir})
+
+		status, err := agent.CheckConversionStatus(nil, &pb.CheckConversionStatusRequest{
+			Segments: []*pb.SegmentInfo{{Content: 1, Dbid: 3, DataDir: filepath.Join(dir, "new")}},
+			Hostname: "localhost",
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		statuses := status.GetStatuses()
+		Expect(statuses[0].GetStatus()).To(Equal(pb.StepStatus_FAILED))
+		Expect(statuses[0].GetError()).To(Equal("cancelled"))
 	})
 
 	It("returns an error if no segments are passed", func() {
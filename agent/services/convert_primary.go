@@ -4,8 +4,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os"
+	"os/exec"
 	"path/filepath"
 
+	"github.com/greenplum-db/gpupgrade/helpers"
+	"github.com/greenplum-db/gpupgrade/hub/upgradestatus"
 	pb "github.com/greenplum-db/gpupgrade/idl"
 	"github.com/greenplum-db/gpupgrade/utils"
 
@@ -46,10 +50,21 @@ func (s *AgentServer) UpgradeConvertPrimarySegments(ctx context.Context, in *pb.
 
 		convertPrimaryArgs := fmt.Sprintf("cd %s && nohup %s --old-bindir=%s --old-datadir=%s --new-bindir=%s --new-datadir=%s --old-port=%d --new-port=%d --progress",
 			pathToSegment, in.NewBinDir+"/pg_upgrade", in.OldBinDir, segment.OldDataDir, in.NewBinDir, segment.NewDataDir, segment.OldPort, segment.NewPort)
+		if in.LinkMode {
+			convertPrimaryArgs += " --link"
+		}
 
 		convertPrimaryCmd := s.commandExecer("bash", "-c", convertPrimaryArgs)
 
-		err = convertPrimaryCmd.Start()
+		logPath := filepath.Join(pathToSegment, "pg_upgrade_segment.log")
+		err = redirectOutput(convertPrimaryCmd, logPath)
+		if err != nil {
+			gplog.Error("Could not create the pg_upgrade log for segment %d. Err: %v", segment.Content, err)
+			return &pb.UpgradeConvertPrimarySegmentsReply{}, err
+		}
+
+		err = s.processes.Start(convertPrimaryName(segment.Content), logPath, convertPrimaryCmd, s.stopServers(in.OldBinDir, in.NewBinDir, segment))
+		closeOutput(convertPrimaryCmd)
 		if err != nil {
 			gplog.Error("An error occurred: %v", err)
 			return &pb.UpgradeConvertPrimarySegmentsReply{}, err
@@ -58,3 +73,49 @@ func (s *AgentServer) UpgradeConvertPrimarySegments(ctx context.Context, in *pb.
 
 	return &pb.UpgradeConvertPrimarySegmentsReply{}, nil
 }
+
+// convertPrimaryName is the name that the pg_upgrade of the segment with the
+// given content is supervised under.
+func convertPrimaryName(content int32) string {
+	return fmt.Sprintf("%s (segment %d)", upgradestatus.CONVERT_PRIMARY, content)
+}
+
+// redirectOutput has cmd write its output to the file at logPath, replacing
+// what an earlier run wrote there.
+func redirectOutput(cmd helpers.Command, logPath string) error {
+	execCmd, ok := cmd.(*exec.Cmd)
+	if !ok {
+		return nil
+	}
+
+	f, err := utils.System.OpenFile(logPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	execCmd.Stdout = f
+	execCmd.Stderr = f
+	return nil
+}
+
+// closeOutput closes the log file that redirectOutput opened for cmd; once
+// cmd has started, its process has its own copy.
+func closeOutput(cmd helpers.Command) {
+	if execCmd, ok := cmd.(*exec.Cmd); ok {
+		if f, ok := execCmd.Stdout.(*os.File); ok {
+			f.Close()
+		}
+	}
+}
+
+// stopServers returns a function that stops the servers that pg_upgrade may
+// have left running for segment, for cleaning up once it has been cancelled.
+func (s *AgentServer) stopServers(oldBinDir, newBinDir string, segment *pb.DataDirPair) func() error {
+	return func() error {
+		oldErr := utils.StopPostgres(s.commandExecer, oldBinDir, segment.OldDataDir)
+		newErr := utils.StopPostgres(s.commandExecer, newBinDir, segment.NewDataDir)
+		if oldErr != nil {
+			return oldErr
+		}
+		return newErr
+	}
+}
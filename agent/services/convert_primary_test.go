@@ -82,6 +82,20 @@ var _ = Describe("CommandListener", func() {
 		Expect(commandExecer.Calls()).To(ContainElement(fmt.Sprintf("bash -c cd %s/pg_upgrade/seg-1 && nohup /new/bin/pg_upgrade --old-bindir=/old/bin --old-datadir=old/datadir2 --new-bindir=/new/bin --new-datadir=new/datadir2 --old-port=2 --new-port=22 --progress", dir)))
 	})
 
+	It("has pg_upgrade hard link the data files in link mode", func() {
+		commandExecer.SetOutput(&testutils.FakeCommand{})
+
+		_, err := agent.UpgradeConvertPrimarySegments(nil, &pb.UpgradeConvertPrimarySegmentsRequest{
+			OldBinDir:    "/old/bin",
+			NewBinDir:    "/new/bin",
+			DataDirPairs: []*pb.DataDirPair{{OldDataDir: "old/datadir1", NewDataDir: "new/datadir1", Content: 0, OldPort: 1, NewPort: 11}},
+			LinkMode:     true,
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(commandExecer.Calls()).To(ContainElement(HaveSuffix("--old-port=1 --new-port=11 --progress --link")))
+	})
+
 	It("returns an an error if the oid files glob fails", func() {
 		utils.System.FilePathGlob = func(pattern string) ([]string, error) {
 			return []string{}, errors.New("failed to find files")
@@ -2,52 +2,143 @@ package services
 
 import (
 	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
 
 	pb "github.com/greenplum-db/gpupgrade/idl"
 
-	"github.com/cloudfoundry/gosigar"
 	"github.com/greenplum-db/gp-common-go-libs/gplog"
 )
 
+// firstNormalObjectID is the lowest OID, and so relfilenode, that a user's
+// relation can have. pg_upgrade's link mode hard links the data files at or
+// above it, and writes everything else anew.
+const firstNormalObjectID = 16384
+
+// CheckDiskSpaceOnAgents measures, for each segment in the request, how much
+// pg_upgrade will write to its new data dir, and whether the filesystem that
+// holds it has that much free, plus the margin the request asks for. Segments
+// whose new data dirs share a filesystem only have room if it can take all of
+// them.
 func (s *AgentServer) CheckDiskSpaceOnAgents(ctx context.Context, in *pb.CheckDiskSpaceRequestToAgent) (*pb.CheckDiskSpaceReplyFromAgent, error) {
 	gplog.Info("got a check disk command from the hub")
-	diskUsage, err := s.GetDiskUsage()
-	if err != nil {
-		gplog.Error(err.Error())
-		return nil, err
+
+	var spaces []*pb.DataDirSpace
+	devices := make(map[*pb.DataDirSpace]uint64)
+	requiredOn := make(map[uint64]uint64)
+	for _, pair := range in.DataDirPairs {
+		space := &pb.DataDirSpace{Content: pair.Content, NewDataDir: pair.NewDataDir}
+		spaces = append(spaces, space)
+
+		device, available, err := s.GetFreeSpace(pair.NewDataDir)
+		if err != nil {
+			gplog.Error("could not get the free space for %s: %s", pair.NewDataDir, err)
+			space.Error = err.Error()
+			continue
+		}
+		space.Available = available
+
+		if in.LinkMode {
+			oldDevice, _, err := s.GetFreeSpace(pair.OldDataDir)
+			if err != nil {
+				gplog.Error("could not get the free space for %s: %s", pair.OldDataDir, err)
+				space.Error = err.Error()
+				continue
+			}
+			if oldDevice != device {
+				space.Error = "link mode needs " + pair.OldDataDir + " and " + pair.NewDataDir + " on the same filesystem"
+				continue
+			}
+		}
+
+		size, linked, err := dataDirSize(pair.OldDataDir)
+		if err != nil {
+			gplog.Error("could not measure %s: %s", pair.OldDataDir, err)
+			space.Error = err.Error()
+			continue
+		}
+		space.OldDataDirSize = size
+		written := size
+		if in.LinkMode {
+			written = size - linked
+		}
+		space.Required = written + written*uint64(in.MarginPercent)/100
+
+		devices[space] = device
+		requiredOn[device] += space.Required
 	}
-	var listDiskUsages []*pb.FileSysUsage
-	for k, v := range diskUsage {
-		listDiskUsages = append(listDiskUsages, &pb.FileSysUsage{Filesystem: k, Usage: v})
+
+	for space, device := range devices {
+		space.RequiredOnFilesystem = requiredOn[device]
+		space.Sufficient = space.RequiredOnFilesystem <= space.Available
 	}
-	return &pb.CheckDiskSpaceReplyFromAgent{ListOfFileSysUsage: listDiskUsages}, nil
+
+	return &pb.CheckDiskSpaceReplyFromAgent{DataDirSpaces: spaces}, nil
 }
 
-// diskUsage() wraps a pair of calls to the gosigar library.
-// This is local repetition of the sys_utils function pointer pattern. If there was more than one of these,
-// we would've refactored.
-// "Adapted" from the gosigar usage example at https://github.com/cloudfoundry/gosigar/blob/master/examples/df.go
-func diskUsage() (map[string]float64, error) {
-	diskUsagePerFS := make(map[string]float64)
-	fslist := sigar.FileSystemList{}
-	err := fslist.Get()
+// freeSpace returns the device of the filesystem that holds path, or would
+// hold it once it has been created, and how many bytes on it are free to
+// unprivileged users.
+func freeSpace(path string) (uint64, uint64, error) {
+	dir := filepath.Clean(path)
+	for {
+		_, err := os.Stat(dir)
+		if err == nil || !os.IsNotExist(err) || dir == filepath.Dir(dir) {
+			break
+		}
+		dir = filepath.Dir(dir)
+	}
+
+	var stat syscall.Stat_t
+	err := syscall.Stat(dir, &stat)
 	if err != nil {
-		gplog.Error(err.Error())
-		return nil, err
+		return 0, 0, err
 	}
 
-	for _, fs := range fslist.List {
-		dirName := fs.DirName
+	var fs syscall.Statfs_t
+	err = syscall.Statfs(dir, &fs)
+	if err != nil {
+		return 0, 0, err
+	}
 
-		usage := sigar.FileSystemUsage{}
+	return uint64(stat.Dev), fs.Bavail * uint64(fs.Bsize), nil
+}
 
-		err = usage.Get(dirName)
+// dataDirSize returns the size of the regular files in dataDir, and how much
+// of that is in the data files of user relations, which link mode doesn't
+// copy.
+func dataDirSize(dataDir string) (size uint64, linked uint64, err error) {
+	base := filepath.Join(dataDir, "base")
+	err = filepath.Walk(dataDir, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
-			gplog.Error(err.Error())
-			return nil, err
+			return err
 		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		size += uint64(info.Size())
+		if filepath.Dir(filepath.Dir(path)) == base && isUserRelationFile(info.Name()) {
+			linked += uint64(info.Size())
+		}
+		return nil
+	})
 
-		diskUsagePerFS[dirName] = usage.UsePercent()
+	return size, linked, err
+}
+
+// isUserRelationFile is whether name, a file in a database's directory under
+// base, holds a user relation's data: it is the relfilenode, followed by any
+// fork (_fsm, _vm) and segment number (.1).
+func isUserRelationFile(name string) bool {
+	relfilenode := strings.FieldsFunc(name, func(r rune) bool { return r == '_' || r == '.' })
+	if len(relfilenode) == 0 {
+		return false
 	}
-	return diskUsagePerFS, nil
+
+	oid, err := strconv.ParseUint(relfilenode[0], 10, 32)
+	return err == nil && oid >= firstNormalObjectID
 }
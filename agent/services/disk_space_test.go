@@ -1,6 +1,11 @@
 package services_test
 
 import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+
 	"github.com/greenplum-db/gpupgrade/agent/services"
 	pb "github.com/greenplum-db/gpupgrade/idl"
 	"github.com/greenplum-db/gpupgrade/utils"
@@ -13,46 +18,132 @@ import (
 	. "github.com/onsi/gomega"
 )
 
-var _ = Describe("CommandListener", func() {
+var _ = Describe("CheckDiskSpaceOnAgents", func() {
 	var (
+		dir         string
 		testLogFile *gbytes.Buffer
+		pairs       []*pb.DataDirPair
+		devices     map[string]uint64
+		listener    *services.AgentServer
 	)
 
+	writeDataFile := func(dataDir, name string, size int) {
+		path := filepath.Join(dataDir, name)
+		Expect(os.MkdirAll(filepath.Dir(path), 0700)).To(Succeed())
+		Expect(ioutil.WriteFile(path, make([]byte, size), 0600)).To(Succeed())
+	}
+
 	BeforeEach(func() {
 		_, _, testLogFile = testhelper.SetupTestLogger()
+
+		var err error
+		dir, err = ioutil.TempDir("", "")
+		Expect(err).ToNot(HaveOccurred())
+
+		// Each old data dir has 100 bytes of catalog and 1024 bytes of
+		// user relation files.
+		pairs = nil
+		for _, content := range []int32{0, 1} {
+			oldDataDir := filepath.Join(dir, "old", strconv.Itoa(int(content)))
+			writeDataFile(oldDataDir, "base/1/1259", 60)
+			writeDataFile(oldDataDir, "global/1262", 40)
+			writeDataFile(oldDataDir, "base/16385/16390", 1000)
+			writeDataFile(oldDataDir, "base/16385/16390_fsm", 24)
+
+			pairs = append(pairs, &pb.DataDirPair{
+				Content:    content,
+				OldDataDir: oldDataDir,
+				NewDataDir: filepath.Join(dir, "new", strconv.Itoa(int(content))),
+			})
+		}
+
+		devices = make(map[string]uint64)
+		listener = &services.AgentServer{GetFreeSpace: func(path string) (uint64, uint64, error) {
+			return devices[path], 2000, nil
+		}}
 	})
 
 	AfterEach(func() {
-		//any mocking of utils.System function pointers should be reset by calling InitializeSystemFunctions
 		utils.System = utils.InitializeSystemFunctions()
+		os.RemoveAll(dir)
+	})
+
+	It("needs room for every old data dir on a filesystem in copy mode", func() {
+		resp, err := listener.CheckDiskSpaceOnAgents(nil, &pb.CheckDiskSpaceRequestToAgent{DataDirPairs: pairs})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(resp.DataDirSpaces).To(HaveLen(2))
+		for i, space := range resp.DataDirSpaces {
+			Expect(space).To(Equal(&pb.DataDirSpace{
+				Content:              int32(i),
+				NewDataDir:           pairs[i].NewDataDir,
+				OldDataDirSize:       1124,
+				Required:             1124,
+				RequiredOnFilesystem: 2248,
+				Available:            2000,
+				Sufficient:           false,
+			}))
+		}
 	})
 
-	It("returns information that a helper function got about filesystems", func() {
-		getDiskUsage := func() (map[string]float64, error) {
-			fakeDiskUsage := make(map[string]float64)
-			fakeDiskUsage["/data"] = 25.4
-			return fakeDiskUsage, nil
+	It("judges each filesystem by the segments on it", func() {
+		devices[pairs[1].NewDataDir] = 1
+
+		resp, err := listener.CheckDiskSpaceOnAgents(nil, &pb.CheckDiskSpaceRequestToAgent{DataDirPairs: pairs})
+		Expect(err).ToNot(HaveOccurred())
+
+		for _, space := range resp.DataDirSpaces {
+			Expect(space.RequiredOnFilesystem).To(Equal(uint64(1124)))
+			Expect(space.Sufficient).To(BeTrue())
 		}
-		listener := &services.AgentServer{GetDiskUsage: getDiskUsage}
+	})
+
+	It("wants the margin on top of what pg_upgrade writes", func() {
+		resp, err := listener.CheckDiskSpaceOnAgents(nil, &pb.CheckDiskSpaceRequestToAgent{DataDirPairs: pairs[:1], MarginPercent: 70})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(resp.DataDirSpaces[0].OldDataDirSize).To(Equal(uint64(1124)))
+		Expect(resp.DataDirSpaces[0].Required).To(Equal(uint64(1910)))
+		Expect(resp.DataDirSpaces[0].Sufficient).To(BeTrue())
+
+		resp, err = listener.CheckDiskSpaceOnAgents(nil, &pb.CheckDiskSpaceRequestToAgent{DataDirPairs: pairs[:1], MarginPercent: 80})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(resp.DataDirSpaces[0].Required).To(Equal(uint64(2023)))
+		Expect(resp.DataDirSpaces[0].Sufficient).To(BeFalse())
+	})
 
-		resp, err := listener.CheckDiskSpaceOnAgents(nil, &pb.CheckDiskSpaceRequestToAgent{})
-		Expect(err).To(BeNil())
-		for _, val := range resp.ListOfFileSysUsage {
-			if val.Filesystem == "/data" {
-				Expect(val.Usage).To(BeNumerically("~", 25.4, 0.001))
-				break
+	It("only needs room for what isn't hard linked in link mode", func() {
+		resp, err := listener.CheckDiskSpaceOnAgents(nil, &pb.CheckDiskSpaceRequestToAgent{DataDirPairs: pairs, LinkMode: true})
+		Expect(err).ToNot(HaveOccurred())
 
-			}
+		for _, space := range resp.DataDirSpaces {
+			Expect(space.OldDataDirSize).To(Equal(uint64(1124)))
+			Expect(space.Required).To(Equal(uint64(100)))
+			Expect(space.RequiredOnFilesystem).To(Equal(uint64(200)))
+			Expect(space.Sufficient).To(BeTrue())
 		}
 	})
 
-	It("returns an error if the helper function fails", func() {
-		getDiskUsage := func() (map[string]float64, error) {
-			return nil, errors.New("fake error")
+	It("reports old and new data dirs on different filesystems in link mode", func() {
+		devices[pairs[0].OldDataDir] = 1
+
+		resp, err := listener.CheckDiskSpaceOnAgents(nil, &pb.CheckDiskSpaceRequestToAgent{DataDirPairs: pairs, LinkMode: true})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(resp.DataDirSpaces[0].Error).To(ContainSubstring("same filesystem"))
+		Expect(resp.DataDirSpaces[0].Sufficient).To(BeFalse())
+		Expect(resp.DataDirSpaces[1].RequiredOnFilesystem).To(Equal(uint64(100)))
+		Expect(resp.DataDirSpaces[1].Sufficient).To(BeTrue())
+	})
+
+	It("reports the segments whose space it couldn't get", func() {
+		listener.GetFreeSpace = func(path string) (uint64, uint64, error) {
+			return 0, 0, errors.New("fake error")
 		}
-		listener := &services.AgentServer{GetDiskUsage: getDiskUsage}
-		_, err := listener.CheckDiskSpaceOnAgents(nil, &pb.CheckDiskSpaceRequestToAgent{})
-		Expect(err).To(HaveOccurred())
+
+		resp, err := listener.CheckDiskSpaceOnAgents(nil, &pb.CheckDiskSpaceRequestToAgent{DataDirPairs: pairs})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(resp.DataDirSpaces[0].Error).To(Equal("fake error"))
+		Expect(resp.DataDirSpaces[0].Sufficient).To(BeFalse())
 		Expect(string(testLogFile.Contents())).To(ContainSubstring("fake error"))
 	})
 })
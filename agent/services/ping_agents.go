@@ -8,7 +8,11 @@ import (
 	"github.com/greenplum-db/gp-common-go-libs/gplog"
 )
 
+// Version is the version of gpupgrade that the agent was built from. It is
+// set at build time; see the Makefile.
+var Version = ""
+
 func (s *AgentServer) PingAgents(ctx context.Context, in *pb.PingAgentsRequest) (*pb.PingAgentsReply, error) {
 	gplog.Info("Successfully pinged agent")
-	return &pb.PingAgentsReply{}, nil
+	return &pb.PingAgentsReply{Version: Version}, nil
 }
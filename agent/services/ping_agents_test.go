@@ -30,4 +30,15 @@ var _ = Describe("CommandListener", func() {
 		_, err := agent.PingAgents(nil, &pb.PingAgentsRequest{})
 		Expect(err).To(BeNil())
 	})
+
+	It("replies with the version it was built from", func() {
+		services.Version = "1.2.3"
+		defer func() { services.Version = "" }()
+
+		agent := services.NewAgentServer(nil, services.AgentConfig{})
+
+		reply, err := agent.PingAgents(nil, &pb.PingAgentsRequest{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(reply.Version).To(Equal("1.2.3"))
+	})
 })
@@ -0,0 +1,141 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	pb "github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/utils"
+
+	"github.com/greenplum-db/gp-common-go-libs/gplog"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// PushFiles writes the files that the hub streams to the agent into its state
+// directory. Each file is written next to where it belongs and only moved into
+// place once its checksum matches, so a file that is cut short or mangled on
+// the way never replaces a good one.
+func (s *AgentServer) PushFiles(stream pb.Agent_PushFilesServer) error {
+	var file *incomingFile
+	defer func() {
+		if file != nil {
+			file.discard()
+		}
+	}()
+
+	var written []string
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		if file != nil && chunk.Path != file.relPath {
+			return status.Errorf(codes.InvalidArgument, "%s was started before %s was finished", chunk.Path, file.relPath)
+		}
+		if file == nil {
+			file, err = s.createIncomingFile(chunk.Path)
+			if err != nil {
+				return err
+			}
+		}
+
+		err = file.write(chunk.Data)
+		if err != nil {
+			return err
+		}
+
+		if chunk.Checksum != "" {
+			err = file.finish(chunk.Checksum)
+			if err != nil {
+				return err
+			}
+			gplog.Info("received %s", file.path)
+			written = append(written, file.relPath)
+			file = nil
+		}
+	}
+
+	if file != nil {
+		return status.Errorf(codes.InvalidArgument, "the stream ended before %s was finished", file.relPath)
+	}
+
+	return stream.SendAndClose(&pb.PushFilesReply{Paths: written})
+}
+
+// incomingFile is a file being received by PushFiles, written to a temporary
+// file in the same directory until it is finished.
+type incomingFile struct {
+	relPath string
+	path    string
+	tmp     *os.File
+	hash    hash.Hash
+}
+
+func (s *AgentServer) createIncomingFile(relPath string) (*incomingFile, error) {
+	clean := filepath.Clean(relPath)
+	if relPath == "" || filepath.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, "../") {
+		return nil, status.Errorf(codes.InvalidArgument, "%q is not a path in the state directory", relPath)
+	}
+
+	path := filepath.Join(s.conf.StateDir, clean)
+	dir := filepath.Dir(path)
+	err := utils.System.MkdirAll(dir, 0700)
+	if err != nil {
+		return nil, err
+	}
+
+	tmp, err := ioutil.TempFile(dir, "."+filepath.Base(path)+".")
+	if err != nil {
+		return nil, err
+	}
+
+	return &incomingFile{
+		relPath: relPath,
+		path:    path,
+		tmp:     tmp,
+		hash:    sha256.New(),
+	}, nil
+}
+
+func (f *incomingFile) write(data []byte) error {
+	_, err := f.tmp.Write(data)
+	if err != nil {
+		return err
+	}
+
+	f.hash.Write(data)
+	return nil
+}
+
+// finish moves the file into place if what was received matches checksum. If
+// it fails the file is left to be discarded.
+func (f *incomingFile) finish(checksum string) error {
+	err := f.tmp.Close()
+	if err != nil {
+		return err
+	}
+
+	actual := hex.EncodeToString(f.hash.Sum(nil))
+	if actual != checksum {
+		return status.Errorf(codes.DataLoss, "%s was corrupted on the way: its checksum is %s, not %s", f.relPath, actual, checksum)
+	}
+
+	return utils.System.Rename(f.tmp.Name(), f.path)
+}
+
+// discard removes a file that was never finished.
+func (f *incomingFile) discard() {
+	f.tmp.Close()
+	utils.System.Remove(f.tmp.Name())
+}
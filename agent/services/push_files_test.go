@@ -0,0 +1,136 @@
+package services_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/greenplum-db/gpupgrade/agent/services"
+	pb "github.com/greenplum-db/gpupgrade/idl"
+
+	"github.com/greenplum-db/gp-common-go-libs/testhelper"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type spyPushFilesServer struct {
+	grpc.ServerStream
+
+	chunks []*pb.FileChunk
+	reply  *pb.PushFilesReply
+}
+
+func (s *spyPushFilesServer) Recv() (*pb.FileChunk, error) {
+	if len(s.chunks) == 0 {
+		return nil, io.EOF
+	}
+
+	chunk := s.chunks[0]
+	s.chunks = s.chunks[1:]
+	return chunk, nil
+}
+
+func (s *spyPushFilesServer) SendAndClose(reply *pb.PushFilesReply) error {
+	s.reply = reply
+	return nil
+}
+
+func (s *spyPushFilesServer) Context() context.Context {
+	return context.Background()
+}
+
+var _ = Describe("PushFiles", func() {
+	var (
+		dir   string
+		agent *services.AgentServer
+	)
+
+	BeforeEach(func() {
+		testhelper.SetupTestLogger()
+
+		var err error
+		dir, err = ioutil.TempDir("", "")
+		Expect(err).ToNot(HaveOccurred())
+
+		agent = services.NewAgentServer(nil, services.AgentConfig{StateDir: dir})
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	checksum := func(data []byte) string {
+		sum := sha256.Sum256(data)
+		return hex.EncodeToString(sum[:])
+	}
+
+	push := func(chunks ...*pb.FileChunk) (*pb.PushFilesReply, error) {
+		stream := &spyPushFilesServer{chunks: chunks}
+		err := agent.PushFiles(stream)
+		return stream.reply, err
+	}
+
+	It("writes the files it is sent into its state directory", func() {
+		first := bytes.Repeat([]byte("a"), 100)
+		second := []byte("oids")
+
+		reply, err := push(
+			&pb.FileChunk{Path: "pg_upgrade/1_oids.sql", Data: first[:60]},
+			&pb.FileChunk{Path: "pg_upgrade/1_oids.sql", Data: first[60:]},
+			&pb.FileChunk{Path: "pg_upgrade/1_oids.sql", Checksum: checksum(first)},
+			&pb.FileChunk{Path: "pg_upgrade/2_oids.sql", Data: second, Checksum: checksum(second)},
+		)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(reply.Paths).To(Equal([]string{"pg_upgrade/1_oids.sql", "pg_upgrade/2_oids.sql"}))
+
+		contents, err := ioutil.ReadFile(filepath.Join(dir, "pg_upgrade", "1_oids.sql"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(contents).To(Equal(first))
+
+		contents, err = ioutil.ReadFile(filepath.Join(dir, "pg_upgrade", "2_oids.sql"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(contents).To(Equal(second))
+	})
+
+	It("doesn't replace a file with one that doesn't match its checksum", func() {
+		Expect(os.MkdirAll(filepath.Join(dir, "pg_upgrade"), 0700)).To(Succeed())
+		path := filepath.Join(dir, "pg_upgrade", "1_oids.sql")
+		Expect(ioutil.WriteFile(path, []byte("good"), 0600)).To(Succeed())
+
+		_, err := push(&pb.FileChunk{Path: "pg_upgrade/1_oids.sql", Data: []byte("bad"), Checksum: checksum([]byte("good"))})
+		Expect(status.Code(err)).To(Equal(codes.DataLoss))
+
+		contents, err := ioutil.ReadFile(path)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(contents).To(Equal([]byte("good")))
+
+		files, err := ioutil.ReadDir(filepath.Join(dir, "pg_upgrade"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(files).To(HaveLen(1))
+	})
+
+	It("discards a file that is cut short", func() {
+		_, err := push(&pb.FileChunk{Path: "pg_upgrade/1_oids.sql", Data: []byte("half")})
+		Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+
+		files, err := ioutil.ReadDir(filepath.Join(dir, "pg_upgrade"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(files).To(BeEmpty())
+	})
+
+	It("refuses to write outside its state directory", func() {
+		for _, path := range []string{"../escaped", "/tmp/escaped", ""} {
+			_, err := push(&pb.FileChunk{Path: path, Data: []byte("x"), Checksum: checksum([]byte("x"))})
+			Expect(status.Code(err)).To(Equal(codes.InvalidArgument), path)
+		}
+	})
+})
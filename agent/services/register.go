@@ -0,0 +1,90 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	pb "github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/utils"
+
+	"github.com/greenplum-db/gp-common-go-libs/gplog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// RegistrationRetryInterval is how long an agent waits before trying again to
+// register with a hub that it couldn't reach. It is also how long each call to
+// the hub may take.
+var RegistrationRetryInterval = 5 * time.Second
+
+// keepRegistered registers the agent with the hub at HubAddress, and then
+// sends heartbeats as often as the hub asks until stop is closed. The agent
+// registers again whenever the hub has forgotten it, as it does when it is
+// restarted.
+func (a *AgentServer) keepRegistered(stop <-chan struct{}) {
+	host, err := utils.GetHost()
+	if err != nil {
+		gplog.Error("could not determine hostname, so not registering with the hub: %s", err)
+		return
+	}
+
+	opt := grpc.WithInsecure()
+	if a.conf.HubCredentials != nil {
+		opt = grpc.WithTransportCredentials(a.conf.HubCredentials)
+	}
+	conn, err := grpc.Dial(a.conf.HubAddress, opt)
+	if err != nil {
+		gplog.Error("could not connect to the hub at %s: %s", a.conf.HubAddress, err)
+		return
+	}
+	defer conn.Close()
+	client := pb.NewAgentToHubClient(conn)
+
+	registered := false
+	failing := false
+	var wait time.Duration
+	for {
+		select {
+		case <-stop:
+			return
+		case <-time.After(wait):
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), RegistrationRetryInterval)
+		if registered {
+			_, err = client.Heartbeat(ctx, &pb.HeartbeatRequest{Hostname: host, Version: Version, StateDir: a.conf.StateDir})
+			if s, ok := status.FromError(err); ok && s.Code() == codes.NotFound {
+				gplog.Info("the hub at %s has forgotten this agent; registering again", a.conf.HubAddress)
+				registered = false
+				wait = 0
+				err = nil
+			}
+		} else {
+			var reply *pb.RegisterAgentReply
+			reply, err = client.RegisterAgent(ctx, &pb.RegisterAgentRequest{Hostname: host, Version: Version, StateDir: a.conf.StateDir})
+			if err == nil {
+				gplog.Info("registered with the hub at %s", a.conf.HubAddress)
+				registered = true
+				wait = time.Duration(reply.HeartbeatIntervalSeconds) * time.Second
+				if wait <= 0 {
+					wait = RegistrationRetryInterval
+				}
+			}
+		}
+		cancel()
+
+		if err != nil {
+			// Only complain once for as long as the hub is unreachable.
+			if !failing {
+				gplog.Warn("could not reach the hub at %s: %s", a.conf.HubAddress, err)
+			}
+			failing = true
+			if !registered {
+				wait = RegistrationRetryInterval
+			}
+		} else {
+			failing = false
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package services_test
+
+import (
+	"context"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/greenplum-db/gpupgrade/agent/services"
+	pb "github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/testutils"
+	"github.com/greenplum-db/gpupgrade/utils"
+
+	"github.com/greenplum-db/gp-common-go-libs/testhelper"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// fakeHub counts the registrations and heartbeats it receives, and forgets
+// every agent after its first heartbeat, as a restarted hub would.
+type fakeHub struct {
+	mu            sync.Mutex
+	registrations []*pb.RegisterAgentRequest
+	heartbeats    int
+}
+
+func (f *fakeHub) RegisterAgent(ctx context.Context, in *pb.RegisterAgentRequest) (*pb.RegisterAgentReply, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.registrations = append(f.registrations, in)
+	return &pb.RegisterAgentReply{}, nil
+}
+
+func (f *fakeHub) Heartbeat(ctx context.Context, in *pb.HeartbeatRequest) (*pb.HeartbeatReply, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.heartbeats++
+	if f.heartbeats == 1 {
+		return &pb.HeartbeatReply{}, status.Error(codes.NotFound, "not registered")
+	}
+	return &pb.HeartbeatReply{}, nil
+}
+
+func (f *fakeHub) counts() (int, int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return len(f.registrations), f.heartbeats
+}
+
+var _ = Describe("registering with the hub", func() {
+	var (
+		hub           *fakeHub
+		server        *grpc.Server
+		address       string
+		retryInterval time.Duration
+	)
+
+	BeforeEach(func() {
+		testhelper.SetupTestLogger()
+
+		retryInterval = services.RegistrationRetryInterval
+		services.RegistrationRetryInterval = 50 * time.Millisecond
+
+		lis, err := net.Listen("tcp", "localhost:0")
+		Expect(err).ToNot(HaveOccurred())
+		address = lis.Addr().String()
+
+		hub = &fakeHub{}
+		server = grpc.NewServer()
+		pb.RegisterAgentToHubServer(server, hub)
+		go server.Serve(lis)
+	})
+
+	AfterEach(func() {
+		server.Stop()
+		services.RegistrationRetryInterval = retryInterval
+		utils.System = utils.InitializeSystemFunctions()
+	})
+
+	It("registers, and registers again when the hub forgets it", func() {
+		port, err := testutils.GetOpenPort()
+		Expect(err).ToNot(HaveOccurred())
+
+		agent := services.NewAgentServer(nil, services.AgentConfig{
+			Port:       port,
+			StateDir:   "/state/dir",
+			HubAddress: address,
+		})
+		go agent.Start()
+		defer agent.Stop()
+
+		Eventually(func() int {
+			registrations, _ := hub.counts()
+			return registrations
+		}).Should(Equal(2))
+		Eventually(func() int {
+			_, heartbeats := hub.counts()
+			return heartbeats
+		}).Should(BeNumerically(">", 1))
+
+		host, err := utils.GetHost()
+		Expect(err).ToNot(HaveOccurred())
+
+		hub.mu.Lock()
+		defer hub.mu.Unlock()
+		Expect(hub.registrations[0].Hostname).To(Equal(host))
+		Expect(hub.registrations[0].StateDir).To(Equal("/state/dir"))
+	})
+
+	It("doesn't register without a hub address", func() {
+		port, err := testutils.GetOpenPort()
+		Expect(err).ToNot(HaveOccurred())
+
+		agent := services.NewAgentServer(nil, services.AgentConfig{Port: port, StateDir: "/state/dir"})
+		go agent.Start()
+		defer agent.Stop()
+
+		Consistently(func() int {
+			registrations, _ := hub.counts()
+			return registrations
+		}, 200*time.Millisecond).Should(BeZero())
+	})
+})
@@ -0,0 +1,35 @@
+package services
+
+import (
+	"context"
+	"path/filepath"
+
+	pb "github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/utils"
+
+	"github.com/greenplum-db/gp-common-go-libs/gplog"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Revert forgets the pg_upgrade processes that converted the primaries on this
+// host, and removes the pg_upgrade working directories they ran in along with
+// the OID files shared with them, so that the primaries read as never having
+// been converted. It refuses while any of the processes is still running.
+func (s *AgentServer) Revert(ctx context.Context, in *pb.RevertAgentRequest) (*pb.RevertAgentReply, error) {
+	gplog.Info("got a request to revert from the hub")
+
+	err := s.processes.Forget()
+	if err != nil {
+		gplog.Error(err.Error())
+		return &pb.RevertAgentReply{}, status.Errorf(codes.FailedPrecondition, "cannot revert: %s", err)
+	}
+
+	err = utils.System.RemoveAll(filepath.Join(s.conf.StateDir, "pg_upgrade"))
+	if err != nil {
+		gplog.Error(err.Error())
+		return &pb.RevertAgentReply{}, err
+	}
+
+	return &pb.RevertAgentReply{}, nil
+}
@@ -0,0 +1,84 @@
+package services_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/greenplum-db/gpupgrade/agent/services"
+	pb "github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/testutils"
+
+	"github.com/greenplum-db/gp-common-go-libs/testhelper"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Revert", func() {
+	var (
+		agent         *services.AgentServer
+		dir           string
+		commandExecer *testutils.FakeCommandExecer
+		request       *pb.UpgradeConvertPrimarySegmentsRequest
+		conversion    *pb.CheckConversionStatusRequest
+	)
+
+	BeforeEach(func() {
+		testhelper.SetupTestLogger()
+
+		var err error
+		dir, err = ioutil.TempDir("", "")
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(os.MkdirAll(filepath.Join(dir, "pg_upgrade"), 0700)).To(Succeed())
+		oidFile := filepath.Join(dir, "pg_upgrade", "pg_upgrade_dump_1_oids.sql")
+		Expect(ioutil.WriteFile(oidFile, nil, 0600)).To(Succeed())
+
+		commandExecer = &testutils.FakeCommandExecer{}
+		commandExecer.SetOutput(&testutils.FakeCommand{})
+		agent = services.NewAgentServer(commandExecer.Exec, services.AgentConfig{StateDir: dir})
+
+		request = &pb.UpgradeConvertPrimarySegmentsRequest{
+			OldBinDir: "/old/bin",
+			NewBinDir: "/new/bin",
+			DataDirPairs: []*pb.DataDirPair{
+				{OldDataDir: filepath.Join(dir, "old"), NewDataDir: filepath.Join(dir, "new"), Content: 0},
+			},
+		}
+		conversion = &pb.CheckConversionStatusRequest{
+			Segments: []*pb.SegmentInfo{{Content: 0, Dbid: 2, DataDir: filepath.Join(dir, "new")}},
+			Hostname: "localhost",
+		}
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	It("forgets the conversion of the primaries and removes their working directories", func() {
+		_, err := agent.UpgradeConvertPrimarySegments(nil, request)
+		Expect(err).ToNot(HaveOccurred())
+		agent.Cancel(nil, &pb.CancelAgentRequest{})
+
+		_, err = agent.Revert(nil, &pb.RevertAgentRequest{})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(filepath.Join(dir, "pg_upgrade")).ToNot(BeADirectory())
+		reply, err := agent.CheckConversionStatus(nil, conversion)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(reply.Statuses[0].Status).To(Equal(pb.StepStatus_PENDING))
+	})
+
+	It("refuses to revert while a primary is being converted", func() {
+		_, err := agent.UpgradeConvertPrimarySegments(nil, request)
+		Expect(err).ToNot(HaveOccurred())
+		defer agent.Cancel(nil, &pb.CancelAgentRequest{})
+
+		_, err = agent.Revert(nil, &pb.RevertAgentRequest{})
+		Expect(status.Code(err)).To(Equal(codes.FailedPrecondition))
+		Expect(filepath.Join(dir, "pg_upgrade")).To(BeADirectory())
+	})
+})
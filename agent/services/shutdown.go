@@ -0,0 +1,54 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	pb "github.com/greenplum-db/gpupgrade/idl"
+
+	"github.com/greenplum-db/gp-common-go-libs/gplog"
+	"google.golang.org/grpc"
+)
+
+// Shutdown stops the agent once it has replied. The agent stops accepting
+// RPCs straight away, and gives the ones in flight ShutdownGracePeriod to
+// finish. Segments that pg_upgrade is converting are left to carry on.
+func (a *AgentServer) Shutdown(ctx context.Context, in *pb.ShutdownAgentRequest) (*pb.ShutdownAgentReply, error) {
+	gplog.Info("got a request to shut down the agent from the hub")
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if !a.shuttingDown && a.server != nil {
+		a.shuttingDown = true
+		go a.drain(a.server)
+	}
+
+	return &pb.ShutdownAgentReply{}, nil
+}
+
+// drain waits for the RPCs in flight to finish, for up to
+// ShutdownGracePeriod, and then stops the agent.
+func (a *AgentServer) drain(server *grpc.Server) {
+	drained := make(chan struct{})
+	go func() {
+		server.GracefulStop()
+		close(drained)
+	}()
+
+	select {
+	case <-drained:
+	case <-time.After(ShutdownGracePeriod):
+		gplog.Warn("RPCs were still running %s after the agent was asked to shut down; stopping anyway", ShutdownGracePeriod)
+	}
+
+	a.Stop()
+	gplog.Info("agent shut down")
+	close(a.drained)
+}
+
+// Drained returns a channel that is closed once the agent has stopped after
+// being asked to shut down.
+func (a *AgentServer) Drained() <-chan struct{} {
+	return a.drained
+}
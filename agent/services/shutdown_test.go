@@ -0,0 +1,55 @@
+package services_test
+
+import (
+	"net"
+	"strconv"
+
+	"github.com/greenplum-db/gpupgrade/agent/services"
+	pb "github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/testutils"
+	"github.com/greenplum-db/gpupgrade/utils"
+
+	"github.com/greenplum-db/gp-common-go-libs/testhelper"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Shutdown", func() {
+	BeforeEach(func() {
+		testhelper.SetupTestLogger()
+	})
+
+	AfterEach(func() {
+		utils.System = utils.InitializeSystemFunctions()
+	})
+
+	It("stops the agent once it has replied", func() {
+		port, err := testutils.GetOpenPort()
+		Expect(err).ToNot(HaveOccurred())
+
+		agent := services.NewAgentServer(nil, services.AgentConfig{Port: port, StateDir: "/tmp"})
+		stopped := make(chan struct{})
+		go func() {
+			agent.Start()
+			close(stopped)
+		}()
+		defer agent.Stop()
+
+		address := net.JoinHostPort("localhost", strconv.Itoa(port))
+		Eventually(func() error {
+			conn, err := net.Dial("tcp", address)
+			if err == nil {
+				conn.Close()
+			}
+			return err
+		}).Should(Succeed())
+
+		_, err = agent.Shutdown(nil, &pb.ShutdownAgentRequest{})
+		Expect(err).ToNot(HaveOccurred())
+
+		Eventually(stopped).Should(BeClosed())
+		Eventually(agent.Drained()).Should(BeClosed())
+		_, err = net.Dial("tcp", address)
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,49 @@
+package services
+
+import (
+	"fmt"
+	"path/filepath"
+
+	pb "github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/utils"
+
+	"github.com/greenplum-db/gp-common-go-libs/gplog"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// TailLogs streams the agent's own log, or the logs that pg_upgrade wrote
+// while converting a segment, and keeps streaming what is added to them if the
+// hub asks it to follow them.
+func (s *AgentServer) TailLogs(in *pb.TailLogsRequest, stream pb.Agent_TailLogsServer) error {
+	var patterns []string
+	var what string
+	switch in.Source {
+	case pb.LogSource_AGENT:
+		patterns = []string{gplog.GetLogFilePath()}
+		what = "agent log"
+	case pb.LogSource_PG_UPGRADE:
+		dir := filepath.Join(s.conf.StateDir, "pg_upgrade", fmt.Sprintf("seg-%d", in.Content))
+		patterns = utils.PGUpgradeLogPatterns(dir)
+		what = "pg_upgrade logs in " + dir
+	default:
+		return status.Errorf(codes.InvalidArgument, "agents don't keep %s logs", in.Source)
+	}
+
+	host, err := utils.GetHost()
+	if err != nil {
+		return err
+	}
+
+	gplog.Info("sending the %s", what)
+	err = utils.StreamLogs(stream.Context(), patterns, in.Follow, func(path string, data []byte) error {
+		return stream.Send(&pb.LogChunk{Hostname: host, Path: path, Data: data})
+	})
+	if err == utils.ErrNoLogs {
+		return status.Errorf(codes.NotFound, "there is no %s on %s", what, host)
+	}
+	if err != nil {
+		gplog.Error("could not send the %s: %s", what, err)
+	}
+	return err
+}
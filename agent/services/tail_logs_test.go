@@ -0,0 +1,94 @@
+package services_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/greenplum-db/gpupgrade/agent/services"
+	pb "github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/utils"
+
+	"github.com/greenplum-db/gp-common-go-libs/testhelper"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type spyTailLogsServer struct {
+	grpc.ServerStream
+
+	sent []*pb.LogChunk
+}
+
+func (s *spyTailLogsServer) Send(chunk *pb.LogChunk) error {
+	s.sent = append(s.sent, chunk)
+	return nil
+}
+
+func (s *spyTailLogsServer) Context() context.Context {
+	return context.Background()
+}
+
+var _ = Describe("TailLogs", func() {
+	var (
+		agent  *services.AgentServer
+		dir    string
+		stream *spyTailLogsServer
+	)
+
+	BeforeEach(func() {
+		testhelper.SetupTestLogger()
+
+		var err error
+		dir, err = ioutil.TempDir("", "")
+		Expect(err).ToNot(HaveOccurred())
+
+		agent = services.NewAgentServer(nil, services.AgentConfig{StateDir: dir})
+		stream = &spyTailLogsServer{}
+	})
+
+	AfterEach(func() {
+		utils.System = utils.InitializeSystemFunctions()
+		os.RemoveAll(dir)
+	})
+
+	It("sends the pg_upgrade logs of the segment", func() {
+		segDir := filepath.Join(dir, "pg_upgrade", "seg-1")
+		Expect(os.MkdirAll(segDir, 0700)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(segDir, "pg_upgrade_internal.log"), []byte("checking\n"), 0600)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(dir, "pg_upgrade", "pg_upgrade_master.log"), []byte("master\n"), 0600)).To(Succeed())
+
+		err := agent.TailLogs(&pb.TailLogsRequest{Source: pb.LogSource_PG_UPGRADE, Content: 1}, stream)
+		Expect(err).ToNot(HaveOccurred())
+
+		host, err := utils.GetHost()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(stream.sent).To(Equal([]*pb.LogChunk{{
+			Hostname: host,
+			Path:     filepath.Join(segDir, "pg_upgrade_internal.log"),
+			Data:     []byte("checking\n"),
+		}}))
+	})
+
+	It("reports that a segment has no logs", func() {
+		err := agent.TailLogs(&pb.TailLogsRequest{Source: pb.LogSource_PG_UPGRADE, Content: 2}, stream)
+
+		s, ok := status.FromError(err)
+		Expect(ok).To(BeTrue())
+		Expect(s.Code()).To(Equal(codes.NotFound))
+		Expect(s.Message()).To(ContainSubstring(filepath.Join(dir, "pg_upgrade", "seg-2")))
+	})
+
+	It("doesn't send the hub's logs", func() {
+		err := agent.TailLogs(&pb.TailLogsRequest{Source: pb.LogSource_HUB}, stream)
+
+		s, ok := status.FromError(err)
+		Expect(ok).To(BeTrue())
+		Expect(s.Code()).To(Equal(codes.InvalidArgument))
+	})
+})
@@ -2,19 +2,41 @@ package services
 
 import (
 	"context"
+	"fmt"
+	"strings"
+	"time"
 
 	pb "github.com/greenplum-db/gpupgrade/idl"
-
-	"github.com/greenplum-db/gp-common-go-libs/gplog"
+	"github.com/greenplum-db/gpupgrade/utils"
 )
 
+// CheckUpgradeStatus lists the pg_upgrade processes that the agent has
+// started, one per line, as its records of them stand.
 func (s *AgentServer) CheckUpgradeStatus(ctx context.Context, in *pb.CheckUpgradeStatusRequest) (*pb.CheckUpgradeStatusReply, error) {
-	cmd := "ps auxx | grep pg_upgrade"
+	var lines []string
+	for _, record := range s.processes.Records() {
+		lines = append(lines, describeProcess(record))
+	}
+
+	return &pb.CheckUpgradeStatusReply{ProcessList: strings.Join(lines, "\n")}, nil
+}
+
+func describeProcess(record utils.ProcessRecord) string {
+	var state string
+	switch {
+	case record.Running():
+		state = "running"
+	case record.EndTime.IsZero():
+		state = "exited"
+	case record.Cancelled:
+		state = "cancelled"
+	default:
+		state = fmt.Sprintf("exited with code %d at %s", record.ExitCode, record.EndTime.Format(time.RFC3339))
+	}
 
-	output, err := s.commandExecer("bash", "-c", cmd).Output()
-	if err != nil {
-		gplog.Error(err.Error())
-		return nil, err
+	description := fmt.Sprintf("%s: pid %d, started at %s, %s", record.Name, record.PID, record.StartTime.Format(time.RFC3339), state)
+	if record.LogPath != "" {
+		description += fmt.Sprintf(", logging to %s", record.LogPath)
 	}
-	return &pb.CheckUpgradeStatusReply{ProcessList: string(output)}, nil
+	return description
 }
@@ -2,64 +2,69 @@ package services_test
 
 import (
 	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 
 	"github.com/greenplum-db/gpupgrade/agent/services"
+	pb "github.com/greenplum-db/gpupgrade/idl"
 	"github.com/greenplum-db/gpupgrade/testutils"
 	"github.com/greenplum-db/gpupgrade/utils"
 
 	"github.com/greenplum-db/gp-common-go-libs/testhelper"
-	"github.com/onsi/gomega/gbytes"
-	"github.com/pkg/errors"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 )
 
-var _ = Describe("CommandListener", func() {
+var _ = Describe("CheckUpgradeStatus", func() {
 	var (
-		testLogFile   *gbytes.Buffer
-		agent         *services.AgentServer
+		dir           string
 		commandExecer *testutils.FakeCommandExecer
-		errChan       chan error
-		outChan       chan []byte
 	)
 
 	BeforeEach(func() {
-		_, _, testLogFile = testhelper.SetupTestLogger()
+		testhelper.SetupTestLogger()
 
-		errChan = make(chan error, 2)
-		outChan = make(chan []byte, 2)
+		var err error
+		dir, err = ioutil.TempDir("", "")
+		Expect(err).ToNot(HaveOccurred())
 
 		commandExecer = &testutils.FakeCommandExecer{}
-		commandExecer.SetOutput(&testutils.FakeCommand{
-			Err: errChan,
-			Out: outChan,
-		})
-
-		agent = services.NewAgentServer(commandExecer.Exec, services.AgentConfig{})
+		commandExecer.SetOutput(&testutils.FakeCommand{})
 	})
 
 	AfterEach(func() {
 		//any mocking of utils.System function pointers should be reset by calling InitializeSystemFunctions
 		utils.System = utils.InitializeSystemFunctions()
+		os.RemoveAll(dir)
 	})
 
-	It("returns the shell command output", func() {
-		outChan <- []byte("shell command output")
-
-		resp, err := agent.CheckUpgradeStatus(context.TODO(), nil)
+	It("lists the pg_upgrade processes the agent started, from their records", func() {
+		records := `[
+  {"name": "convert-primary (segment 0)", "pid": 999999, "logPath": "/state/seg-0.log",
+   "startTime": "2018-01-01T10:00:00Z", "endTime": "2018-01-01T10:05:00Z", "exitCode": 1},
+  {"name": "convert-primary (segment 1)", "pid": 999998,
+   "startTime": "2018-01-01T10:00:00Z", "endTime": "2018-01-01T10:01:00Z", "exitCode": -1, "cancelled": true}
+]`
+		Expect(ioutil.WriteFile(filepath.Join(dir, "agent_processes.json"), []byte(records), 0600)).To(Succeed())
+		agent := services.NewAgentServer(commandExecer.Exec, services.AgentConfig{StateDir: dir})
+
+		resp, err := agent.CheckUpgradeStatus(context.TODO(), &pb.CheckUpgradeStatusRequest{})
 		Expect(err).ToNot(HaveOccurred())
 
-		Expect(resp.ProcessList).To(Equal("shell command output"))
+		Expect(resp.ProcessList).To(Equal(
+			"convert-primary (segment 0): pid 999999, started at 2018-01-01T10:00:00Z, exited with code 1 at 2018-01-01T10:05:00Z, logging to /state/seg-0.log\n" +
+				"convert-primary (segment 1): pid 999998, started at 2018-01-01T10:00:00Z, cancelled",
+		))
+		Expect(commandExecer.GetNumInvocations()).To(Equal(0))
 	})
 
-	It("returns only err if anything is reported as an error", func() {
-		errChan <- errors.New("couldn't find bash")
+	It("lists nothing before any pg_upgrade has been started", func() {
+		agent := services.NewAgentServer(commandExecer.Exec, services.AgentConfig{StateDir: dir})
 
-		resp, err := agent.CheckUpgradeStatus(context.TODO(), nil)
-		Expect(err).To(HaveOccurred())
-
-		Expect(resp).To(BeNil())
-		Expect(testLogFile.Contents()).To(ContainSubstring("couldn't find bash"))
+		resp, err := agent.CheckUpgradeStatus(context.TODO(), &pb.CheckUpgradeStatusRequest{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(resp.ProcessList).To(BeEmpty())
 	})
 })
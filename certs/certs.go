@@ -0,0 +1,328 @@
+// Package certs manages the private certificate authority that secures the
+// gRPC connections between the CLI, the hub and the agents.
+//
+// The authority lives in the certs directory of the hub's state dir, next to
+// the certificates for the hub and the CLI. Agent certificates are issued per
+// host into a subdirectory that holds everything an agent needs, so that it
+// can be copied into the certs directory of the agent's state dir as is.
+package certs
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"path/filepath"
+	"time"
+
+	"github.com/greenplum-db/gpupgrade/utils"
+
+	"google.golang.org/grpc/credentials"
+)
+
+// The identities that certificates are issued to. Each is stored as
+// <name>.crt and <name>.key in the certs directory.
+const (
+	CA    = "ca"
+	Hub   = "hub"
+	CLI   = "cli"
+	Agent = "agent"
+)
+
+// ValidFor is how long issued certificates remain valid.
+var ValidFor = 5 * 365 * 24 * time.Hour
+
+// Dir returns the directory that holds the certificates under stateDir.
+func Dir(stateDir string) string {
+	return filepath.Join(stateDir, "certs")
+}
+
+// AgentDir returns the directory under stateDir that holds the CA and agent
+// certificates issued for host.
+func AgentDir(stateDir string, host string) string {
+	return filepath.Join(Dir(stateDir), "agents", host)
+}
+
+func certPath(dir string, name string) string {
+	return filepath.Join(dir, name+".crt")
+}
+
+func keyPath(dir string, name string) string {
+	return filepath.Join(dir, name+".key")
+}
+
+// CreateAuthority creates the certificate authority in stateDir, and issues
+// the certificates that the hub and the CLI present to each other. Each file
+// that already exists is left alone, unless the authority itself has to be
+// created again, in which case every certificate it signed is reissued.
+func CreateAuthority(stateDir string) error {
+	dir := Dir(stateDir)
+	err := utils.System.MkdirAll(dir, 0700)
+	if err != nil {
+		return err
+	}
+
+	created := false
+	if !pairExists(dir, CA) {
+		err = createCA(stateDir)
+		if err != nil {
+			return err
+		}
+		created = true
+	}
+
+	if created || !pairExists(dir, Hub) {
+		// The hub serves the CLI on localhost, and is also a client of the
+		// agents.
+		hostname, err := utils.System.Hostname()
+		if err != nil {
+			return err
+		}
+		err = issue(stateDir, dir, Hub, []string{"localhost", "127.0.0.1", "::1", hostname},
+			x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth)
+		if err != nil {
+			return err
+		}
+	}
+
+	if created || !pairExists(dir, CLI) {
+		return issue(stateDir, dir, CLI, nil, x509.ExtKeyUsageClientAuth)
+	}
+	return nil
+}
+
+// createCA writes a new CA certificate and key, and removes the agent
+// certificates signed by any previous one.
+func createCA(stateDir string) error {
+	template, err := newTemplate("gpupgrade CA")
+	if err != nil {
+		return err
+	}
+	template.IsCA = true
+	template.BasicConstraintsValid = true
+	template.KeyUsage = x509.KeyUsageCertSign | x509.KeyUsageCRLSign
+
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return err
+	}
+	err = writePair(Dir(stateDir), CA, template, template, caKey, caKey)
+	if err != nil {
+		return err
+	}
+
+	return utils.System.RemoveAll(filepath.Join(Dir(stateDir), "agents"))
+}
+
+// IssueAgentCertificate issues the certificate that the agent on host serves
+// with, and registers with the hub with, unless it has been issued already,
+// and returns the directory it was written to. The directory also holds a
+// copy of the CA certificate.
+func IssueAgentCertificate(stateDir string, host string) (string, error) {
+	dir := AgentDir(stateDir, host)
+	if exists(certPath(dir, CA)) && pairExists(dir, Agent) {
+		return dir, nil
+	}
+
+	err := utils.System.MkdirAll(dir, 0700)
+	if err != nil {
+		return "", err
+	}
+
+	caCert, err := utils.System.ReadFile(certPath(Dir(stateDir), CA))
+	if err != nil {
+		return "", err
+	}
+	err = utils.System.WriteFile(certPath(dir, CA), caCert, 0600)
+	if err != nil {
+		return "", err
+	}
+
+	return dir, issue(stateDir, dir, Agent, []string{host}, x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth)
+}
+
+// ServerCredentials loads the certificate of name from the certs directory of
+// stateDir, for a server that only accepts clients presenting a certificate
+// signed by the CA that was issued to peer. The hub, for example, only serves
+// the CLI, and the agents only serve the hub.
+func ServerCredentials(stateDir string, name string, peer string) (credentials.TransportCredentials, error) {
+	cert, pool, err := load(Dir(stateDir), name)
+	if err != nil {
+		return nil, err
+	}
+
+	return credentials.NewTLS(&tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ClientCAs:    pool,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		MinVersion:   tls.VersionTLS12,
+		VerifyPeerCertificate: func(_ [][]byte, chains [][]*x509.Certificate) error {
+			return verifyPeer(chains, peer)
+		},
+	}), nil
+}
+
+// verifyPeer checks that the client certificate, which TLS has already
+// verified against the CA, was issued to peer for client authentication.
+func verifyPeer(chains [][]*x509.Certificate, peer string) error {
+	if len(chains) == 0 || len(chains[0]) == 0 {
+		return fmt.Errorf("expected a certificate issued to the %s", peer)
+	}
+
+	cert := chains[0][0]
+	if cert.Subject.CommonName != commonName(peer) {
+		return fmt.Errorf("expected a certificate issued to the %s, got one issued to %q", peer, cert.Subject.CommonName)
+	}
+	for _, usage := range cert.ExtKeyUsage {
+		if usage == x509.ExtKeyUsageClientAuth {
+			return nil
+		}
+	}
+	return fmt.Errorf("the %s certificate is not valid for client authentication", peer)
+}
+
+// ClientCredentials loads the certificate of name from the certs directory of
+// stateDir, for a client that only trusts servers presenting a certificate
+// signed by the CA for the host it dialed.
+func ClientCredentials(stateDir string, name string) (credentials.TransportCredentials, error) {
+	cert, pool, err := load(Dir(stateDir), name)
+	if err != nil {
+		return nil, err
+	}
+
+	return credentials.NewTLS(&tls.Config{
+		Certificates: []tls.Certificate{cert},
+		RootCAs:      pool,
+		MinVersion:   tls.VersionTLS12,
+	}), nil
+}
+
+func load(dir string, name string) (tls.Certificate, *x509.CertPool, error) {
+	certPEM, err := utils.System.ReadFile(certPath(dir, name))
+	if err != nil {
+		return tls.Certificate{}, nil, fmt.Errorf("could not read the %s certificate (did you run gpupgrade prepare init?): %s", name, err)
+	}
+	keyPEM, err := utils.System.ReadFile(keyPath(dir, name))
+	if err != nil {
+		return tls.Certificate{}, nil, fmt.Errorf("could not read the %s key: %s", name, err)
+	}
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return tls.Certificate{}, nil, fmt.Errorf("%s certificate in %s is invalid: %s", name, dir, err)
+	}
+
+	caPEM, err := utils.System.ReadFile(certPath(dir, CA))
+	if err != nil {
+		return tls.Certificate{}, nil, fmt.Errorf("could not read the CA certificate: %s", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caPEM) {
+		return tls.Certificate{}, nil, fmt.Errorf("CA certificate in %s is invalid", dir)
+	}
+
+	return cert, pool, nil
+}
+
+// issue signs a certificate for name with the CA in stateDir and writes it to
+// dir. hosts are the DNS names and IP addresses the certificate is valid for.
+func issue(stateDir string, dir string, name string, hosts []string, usages ...x509.ExtKeyUsage) error {
+	caDir := Dir(stateDir)
+	caCertPEM, err := utils.System.ReadFile(certPath(caDir, CA))
+	if err != nil {
+		return err
+	}
+	caKeyPEM, err := utils.System.ReadFile(keyPath(caDir, CA))
+	if err != nil {
+		return err
+	}
+	ca, err := tls.X509KeyPair(caCertPEM, caKeyPEM)
+	if err != nil {
+		return fmt.Errorf("could not load the CA from %s: %s", caDir, err)
+	}
+	caCert, err := x509.ParseCertificate(ca.Certificate[0])
+	if err != nil {
+		return err
+	}
+	caKey, ok := ca.PrivateKey.(*ecdsa.PrivateKey)
+	if !ok {
+		return errors.New("the CA key is not an ECDSA key")
+	}
+
+	template, err := newTemplate(commonName(name))
+	if err != nil {
+		return err
+	}
+	template.KeyUsage = x509.KeyUsageDigitalSignature
+	template.ExtKeyUsage = usages
+	for _, host := range hosts {
+		if ip := net.ParseIP(host); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else {
+			template.DNSNames = append(template.DNSNames, host)
+		}
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return err
+	}
+
+	return writePair(dir, name, template, caCert, key, caKey)
+}
+
+// commonName is the subject that the certificate for name is issued to.
+func commonName(name string) string {
+	return "gpupgrade " + name
+}
+
+func newTemplate(subject string) (*x509.Certificate, error) {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, err
+	}
+
+	now := utils.System.Now()
+	return &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: subject},
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.Add(ValidFor),
+	}, nil
+}
+
+func writePair(dir string, name string, template, parent *x509.Certificate, key, parentKey *ecdsa.PrivateKey) error {
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	if err != nil {
+		return err
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return err
+	}
+
+	// Write the key first: a certificate is only taken to exist once its key
+	// is in place.
+	err = utils.System.WriteFile(keyPath(dir, name), pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600)
+	if err != nil {
+		return err
+	}
+	return utils.System.WriteFile(certPath(dir, name), pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600)
+}
+
+// pairExists reports whether both the certificate and the key of name are in
+// dir.
+func pairExists(dir string, name string) bool {
+	return exists(certPath(dir, name)) && exists(keyPath(dir, name))
+}
+
+func exists(path string) bool {
+	_, err := utils.System.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,25 @@
+package certs_test
+
+import (
+	"testing"
+
+	"github.com/greenplum-db/gpupgrade/utils"
+
+	"github.com/greenplum-db/gp-common-go-libs/testhelper"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestCerts(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Certs Suite")
+}
+
+var _ = BeforeSuite(func() {
+	testhelper.SetupTestLogger()
+})
+
+var _ = AfterEach(func() {
+	utils.System = utils.InitializeSystemFunctions()
+})
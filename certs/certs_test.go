@@ -0,0 +1,212 @@
+package certs_test
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/greenplum-db/gpupgrade/certs"
+	pb "github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/testutils"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("certs", func() {
+	var dir string
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "")
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(certs.CreateAuthority(dir)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	readCert := func(path string) *x509.Certificate {
+		contents, err := ioutil.ReadFile(path)
+		Expect(err).ToNot(HaveOccurred())
+
+		block, _ := pem.Decode(contents)
+		Expect(block).ToNot(BeNil())
+
+		cert, err := x509.ParseCertificate(block.Bytes)
+		Expect(err).ToNot(HaveOccurred())
+		return cert
+	}
+
+	Describe("CreateAuthority", func() {
+		It("creates the CA and the hub and CLI certificates, readable only by the owner", func() {
+			for _, name := range []string{"ca", "hub", "cli"} {
+				for _, ext := range []string{".crt", ".key"} {
+					fi, err := os.Stat(filepath.Join(certs.Dir(dir), name+ext))
+					Expect(err).ToNot(HaveOccurred())
+					Expect(fi.Mode().Perm()).To(Equal(os.FileMode(0600)))
+				}
+			}
+
+			ca := readCert(filepath.Join(certs.Dir(dir), "ca.crt"))
+			Expect(ca.IsCA).To(BeTrue())
+
+			hub := readCert(filepath.Join(certs.Dir(dir), "hub.crt"))
+			Expect(hub.CheckSignatureFrom(ca)).To(Succeed())
+			Expect(hub.DNSNames).To(ContainElement("localhost"))
+		})
+
+		It("leaves an existing authority alone", func() {
+			before, err := ioutil.ReadFile(filepath.Join(certs.Dir(dir), "ca.crt"))
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(certs.CreateAuthority(dir)).To(Succeed())
+
+			after, err := ioutil.ReadFile(filepath.Join(certs.Dir(dir), "ca.crt"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(after).To(Equal(before))
+		})
+
+		It("issues a missing certificate with the existing authority", func() {
+			ca, err := ioutil.ReadFile(filepath.Join(certs.Dir(dir), "ca.crt"))
+			Expect(err).ToNot(HaveOccurred())
+			hub, err := ioutil.ReadFile(filepath.Join(certs.Dir(dir), "hub.crt"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(os.Remove(filepath.Join(certs.Dir(dir), "cli.key"))).To(Succeed())
+
+			Expect(certs.CreateAuthority(dir)).To(Succeed())
+
+			Expect(ioutil.ReadFile(filepath.Join(certs.Dir(dir), "ca.crt"))).To(Equal(ca))
+			Expect(ioutil.ReadFile(filepath.Join(certs.Dir(dir), "hub.crt"))).To(Equal(hub))
+			Expect(filepath.Join(certs.Dir(dir), "cli.key")).To(BeAnExistingFile())
+			cli := readCert(filepath.Join(certs.Dir(dir), "cli.crt"))
+			Expect(cli.CheckSignatureFrom(readCert(filepath.Join(certs.Dir(dir), "ca.crt")))).To(Succeed())
+		})
+
+		It("reissues every certificate when the authority is recreated", func() {
+			_, err := certs.IssueAgentCertificate(dir, "sdw1")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(os.Remove(filepath.Join(certs.Dir(dir), "ca.key"))).To(Succeed())
+
+			Expect(certs.CreateAuthority(dir)).To(Succeed())
+
+			ca := readCert(filepath.Join(certs.Dir(dir), "ca.crt"))
+			for _, name := range []string{"hub", "cli"} {
+				cert := readCert(filepath.Join(certs.Dir(dir), name+".crt"))
+				Expect(cert.CheckSignatureFrom(ca)).To(Succeed())
+			}
+			Expect(certs.AgentDir(dir, "sdw1")).ToNot(BeADirectory())
+		})
+	})
+
+	Describe("IssueAgentCertificate", func() {
+		It("issues a certificate for the host alongside the CA certificate", func() {
+			agentDir, err := certs.IssueAgentCertificate(dir, "sdw1")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(agentDir).To(Equal(certs.AgentDir(dir, "sdw1")))
+
+			for _, name := range []string{"ca.crt", "agent.crt", "agent.key"} {
+				Expect(filepath.Join(agentDir, name)).To(BeAnExistingFile())
+			}
+
+			ca := readCert(filepath.Join(agentDir, "ca.crt"))
+			agent := readCert(filepath.Join(agentDir, "agent.crt"))
+			Expect(agent.CheckSignatureFrom(ca)).To(Succeed())
+			Expect(agent.VerifyHostname("sdw1")).To(Succeed())
+			Expect(agent.ExtKeyUsage).To(Equal([]x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth}))
+		})
+
+		It("fails without a CA", func() {
+			_, err := certs.IssueAgentCertificate(filepath.Join(dir, "missing"), "sdw1")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("credentials", func() {
+		var (
+			agent *testutils.MockAgentServer
+			addr  string
+		)
+
+		BeforeEach(func() {
+			creds, err := testutils.AgentCredentials(dir, "localhost")
+			Expect(err).ToNot(HaveOccurred())
+
+			var port int
+			agent, port = testutils.NewMockAgentServer(grpc.Creds(creds))
+			addr = "localhost:" + strconv.Itoa(port)
+		})
+
+		AfterEach(func() {
+			agent.Stop()
+		})
+
+		ping := func(opt grpc.DialOption) error {
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+
+			conn, err := grpc.DialContext(ctx, addr, opt, grpc.WithBlock())
+			if err != nil {
+				return err
+			}
+			defer conn.Close()
+
+			_, err = pb.NewAgentClient(conn).PingAgents(ctx, &pb.PingAgentsRequest{})
+			return err
+		}
+
+		It("lets the hub connect to an agent", func() {
+			creds, err := certs.ClientCredentials(dir, certs.Hub)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(ping(grpc.WithTransportCredentials(creds))).To(Succeed())
+		})
+
+		It("rejects clients other than the hub", func() {
+			creds, err := certs.ClientCredentials(dir, certs.CLI)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ping(grpc.WithTransportCredentials(creds))).ToNot(Succeed())
+
+			creds, err = testutils.AgentClientCredentials(dir, "sdw1")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ping(grpc.WithTransportCredentials(creds))).ToNot(Succeed())
+		})
+
+		It("rejects clients without a certificate", func() {
+			pool := x509.NewCertPool()
+			pool.AddCert(readCert(filepath.Join(certs.Dir(dir), "ca.crt")))
+			creds := credentials.NewTLS(&tls.Config{RootCAs: pool})
+
+			Expect(ping(grpc.WithTransportCredentials(creds))).ToNot(Succeed())
+			Expect(ping(grpc.WithInsecure())).ToNot(Succeed())
+		})
+
+		It("rejects agents with a certificate from another CA", func() {
+			otherDir, err := ioutil.TempDir("", "")
+			Expect(err).ToNot(HaveOccurred())
+			defer os.RemoveAll(otherDir)
+			Expect(certs.CreateAuthority(otherDir)).To(Succeed())
+
+			creds, err := certs.ClientCredentials(otherDir, certs.Hub)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(ping(grpc.WithTransportCredentials(creds))).ToNot(Succeed())
+		})
+
+		It("reports a missing certificate", func() {
+			_, err := certs.ServerCredentials(filepath.Join(dir, "missing"), certs.Hub, certs.CLI)
+			Expect(err).To(MatchError(ContainSubstring("could not read the hub certificate")))
+		})
+	})
+})
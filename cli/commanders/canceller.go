@@ -0,0 +1,35 @@
+package commanders
+
+import (
+	"context"
+
+	pb "github.com/greenplum-db/gpupgrade/idl"
+
+	"github.com/greenplum-db/gp-common-go-libs/gplog"
+)
+
+type Canceller struct {
+	client pb.CliToHubClient
+}
+
+func NewCanceller(client pb.CliToHubClient) Canceller {
+	return Canceller{client: client}
+}
+
+// Cancel asks the hub to stop the upgrade steps that are running, on the
+// master and on the segments, and reports what was stopped.
+func (c Canceller) Cancel() error {
+	reply, err := c.client.Cancel(context.Background(), &pb.CancelRequest{})
+	if err != nil {
+		return fromHubError(err)
+	}
+
+	if len(reply.Cancelled) == 0 {
+		gplog.Info("Nothing was running, so there was nothing to cancel.")
+	}
+	for _, cancelled := range reply.Cancelled {
+		gplog.Info("Cancelled %s", cancelled)
+	}
+
+	return emitReply(reply)
+}
@@ -0,0 +1,56 @@
+package commanders_test
+
+import (
+	"github.com/greenplum-db/gpupgrade/cli/commanders"
+	pb "github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/testutils"
+
+	"github.com/greenplum-db/gp-common-go-libs/testhelper"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+)
+
+var _ = Describe("Canceller", func() {
+	var (
+		hubClient  *testutils.MockHubClient
+		canceller  commanders.Canceller
+		testStdout *gbytes.Buffer
+	)
+
+	BeforeEach(func() {
+		testStdout, _, _ = testhelper.SetupTestLogger()
+
+		hubClient = testutils.NewMockHubClient()
+		hubClient.CancelReply = &pb.CancelReply{}
+		canceller = commanders.NewCanceller(hubClient)
+	})
+
+	It("reports what the hub cancelled", func() {
+		hubClient.CancelReply.Cancelled = []string{"convert-master", "convert-primary (segment 0) on sdw1"}
+
+		err := canceller.Cancel()
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(hubClient.CancelRequest).To(Equal(&pb.CancelRequest{}))
+		Eventually(testStdout).Should(gbytes.Say("Cancelled convert-master"))
+		Eventually(testStdout).Should(gbytes.Say(`Cancelled convert-primary \(segment 0\) on sdw1`))
+	})
+
+	It("says so when nothing was running", func() {
+		err := canceller.Cancel()
+		Expect(err).ToNot(HaveOccurred())
+
+		Eventually(testStdout).Should(gbytes.Say("nothing to cancel"))
+	})
+
+	It("returns an error when the agents can't be reached", func() {
+		hubClient.Err = status.Error(codes.Unavailable, "could not cancel the upgrade of the primaries: could not connect to the agents on sdw1")
+
+		err := canceller.Cancel()
+		Expect(err).To(MatchError(ContainSubstring("could not connect to the agents on sdw1")))
+	})
+})
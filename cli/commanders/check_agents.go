@@ -0,0 +1,71 @@
+package commanders
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	pb "github.com/greenplum-db/gpupgrade/idl"
+
+	"github.com/greenplum-db/gp-common-go-libs/gplog"
+)
+
+type AgentsChecker struct {
+	client pb.CliToHubClient
+}
+
+func NewAgentsChecker(client pb.CliToHubClient) AgentsChecker {
+	return AgentsChecker{client: client}
+}
+
+// Execute asks the hub to ping the agents, and prints a table of the results.
+// It fails if any of the agents couldn't be reached.
+func (req AgentsChecker) Execute() error {
+	reply, err := req.client.CheckAgents(context.Background(), &pb.CheckAgentsRequest{})
+	if err != nil {
+		return fromHubError(err)
+	}
+
+	for _, line := range formatAgentStatuses(reply.Agents) {
+		gplog.Info("%s", line)
+	}
+
+	err = emitReply(reply)
+	if err != nil {
+		return err
+	}
+
+	var unreachable []string
+	for _, agent := range reply.Agents {
+		if !agent.Reachable {
+			unreachable = append(unreachable, agent.Hostname)
+		}
+	}
+	if len(unreachable) != 0 {
+		return fmt.Errorf("could not reach the agents on %s", strings.Join(unreachable, ", "))
+	}
+	return nil
+}
+
+// formatAgentStatuses lays out agents as the lines of a table, with a header.
+func formatAgentStatuses(agents []*pb.AgentStatus) []string {
+	var table bytes.Buffer
+	w := tabwriter.NewWriter(&table, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "HOST\tSTATUS\tLATENCY\tVERSION\tERROR")
+	for _, agent := range agents {
+		if agent.Reachable {
+			version := agent.Version
+			if version == "" {
+				version = "unknown"
+			}
+			fmt.Fprintf(w, "%s\treachable\t%.1fms\t%s\t\n", agent.Hostname, agent.LatencyMilliseconds, version)
+		} else {
+			fmt.Fprintf(w, "%s\tunreachable\t-\t-\t%s\n", agent.Hostname, agent.Error)
+		}
+	}
+	w.Flush()
+
+	return strings.Split(strings.TrimRight(table.String(), "\n"), "\n")
+}
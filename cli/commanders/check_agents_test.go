@@ -0,0 +1,81 @@
+package commanders_test
+
+import (
+	"errors"
+
+	"github.com/greenplum-db/gpupgrade/cli/commanders"
+	pb "github.com/greenplum-db/gpupgrade/idl"
+	mockpb "github.com/greenplum-db/gpupgrade/mock_idl"
+	"github.com/greenplum-db/gpupgrade/utils"
+
+	"github.com/golang/mock/gomock"
+	"github.com/greenplum-db/gp-common-go-libs/testhelper"
+	"github.com/onsi/gomega/gbytes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("check agents", func() {
+	var (
+		client *mockpb.MockCliToHubClient
+		ctrl   *gomock.Controller
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		client = mockpb.NewMockCliToHubClient(ctrl)
+	})
+
+	AfterEach(func() {
+		utils.System = utils.InitializeSystemFunctions()
+		ctrl.Finish()
+	})
+
+	It("prints a table of the agents", func() {
+		testStdout, _, _ := testhelper.SetupTestLogger()
+
+		client.EXPECT().CheckAgents(
+			gomock.Any(),
+			&pb.CheckAgentsRequest{},
+		).Return(&pb.CheckAgentsReply{Agents: []*pb.AgentStatus{
+			{Hostname: "hostone", Reachable: true, LatencyMilliseconds: 1.25, Version: "1.2.3"},
+			{Hostname: "hosttwo", Reachable: true, LatencyMilliseconds: 12},
+		}}, nil)
+
+		err := commanders.NewAgentsChecker(client).Execute()
+		Expect(err).ToNot(HaveOccurred())
+
+		Eventually(testStdout).Should(gbytes.Say(`HOST     STATUS     LATENCY  VERSION  ERROR`))
+		Eventually(testStdout).Should(gbytes.Say(`hostone  reachable  1.2ms    1.2.3`))
+		Eventually(testStdout).Should(gbytes.Say(`hosttwo  reachable  12.0ms   unknown`))
+	})
+
+	It("fails if any agent could not be reached", func() {
+		testStdout, _, _ := testhelper.SetupTestLogger()
+
+		client.EXPECT().CheckAgents(
+			gomock.Any(),
+			&pb.CheckAgentsRequest{},
+		).Return(&pb.CheckAgentsReply{Agents: []*pb.AgentStatus{
+			{Hostname: "hostone", Reachable: true, LatencyMilliseconds: 1, Version: "1.2.3"},
+			{Hostname: "hosttwo", Error: "could not connect to the agent"},
+		}}, nil)
+
+		err := commanders.NewAgentsChecker(client).Execute()
+		Expect(err).To(MatchError("could not reach the agents on hosttwo"))
+		Eventually(testStdout).Should(gbytes.Say(`hosttwo  unreachable  -\s+-\s+could not connect to the agent`))
+	})
+
+	It("returns the error when the hub can't be reached", func() {
+		testhelper.SetupTestLogger()
+
+		client.EXPECT().CheckAgents(
+			gomock.Any(),
+			&pb.CheckAgentsRequest{},
+		).Return(nil, errors.New("couldn't connect to hub"))
+
+		err := commanders.NewAgentsChecker(client).Execute()
+		Expect(err).To(MatchError("couldn't connect to hub"))
+	})
+})
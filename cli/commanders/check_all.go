@@ -0,0 +1,95 @@
+package commanders
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	pb "github.com/greenplum-db/gpupgrade/idl"
+
+	"github.com/greenplum-db/gp-common-go-libs/gplog"
+)
+
+type AllChecker struct {
+	client pb.CliToHubClient
+}
+
+func NewAllChecker(client pb.CliToHubClient) AllChecker {
+	return AllChecker{client: client}
+}
+
+// Execute has the hub run every pre-upgrade check, and prints a report of
+// what each one found. It fails if a check that would stop the upgrade didn't
+// pass.
+func (req AllChecker) Execute(masterHost string, dbPort int) error {
+	reply, err := req.client.CheckAll(context.Background(),
+		&pb.CheckAllRequest{MasterHost: masterHost, DbPort: int32(dbPort)})
+	if err != nil {
+		return fromHubError(err)
+	}
+
+	for _, line := range formatCheckResults(reply.Results) {
+		gplog.Info("%s", line)
+	}
+
+	err = emitReply(reply)
+	if err != nil {
+		return err
+	}
+
+	if !reply.Passed {
+		var failed []string
+		for _, result := range reply.Results {
+			if result.Severity == pb.CheckSeverity_ERROR && !result.Passed {
+				failed = append(failed, result.Name)
+			}
+		}
+		return fmt.Errorf("the upgrade cannot proceed until these checks pass: %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// formatCheckResults lays out results as a report: a line per check, with
+// what it found and what it noted indented below it, and a summary.
+func formatCheckResults(results []*pb.CheckResult) []string {
+	var lines []string
+	passed := 0
+	for _, result := range results {
+		if result.Passed {
+			passed++
+		}
+
+		line := fmt.Sprintf("[%s] %s", checkLabel(result), result.Name)
+		if len(result.Hosts) != 0 {
+			line += " on " + strings.Join(result.Hosts, ", ")
+		}
+		lines = append(lines, line)
+
+		if result.Error != "" {
+			lines = append(lines, "    could not run: "+result.Error)
+		}
+		for _, finding := range result.Findings {
+			lines = append(lines, fmt.Sprintf("    %s: %s", finding.Host, finding.Message))
+		}
+		for _, note := range result.Notes {
+			lines = append(lines, fmt.Sprintf("    %s: %s", note.Host, note.Message))
+		}
+	}
+
+	return append(lines, fmt.Sprintf("%d of %d checks passed", passed, len(results)))
+}
+
+func checkLabel(result *pb.CheckResult) string {
+	if result.Passed {
+		return "PASS"
+	}
+
+	switch result.Severity {
+	case pb.CheckSeverity_ERROR:
+		return "FAIL"
+	case pb.CheckSeverity_WARNING:
+		return "WARN"
+	default:
+		return "INFO"
+	}
+}
@@ -0,0 +1,84 @@
+package commanders_test
+
+import (
+	"errors"
+	"regexp"
+
+	"github.com/greenplum-db/gpupgrade/cli/commanders"
+	pb "github.com/greenplum-db/gpupgrade/idl"
+	mockpb "github.com/greenplum-db/gpupgrade/mock_idl"
+
+	"github.com/golang/mock/gomock"
+	"github.com/greenplum-db/gp-common-go-libs/testhelper"
+	"github.com/onsi/gomega/gbytes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("check all", func() {
+	var (
+		client *mockpb.MockCliToHubClient
+		ctrl   *gomock.Controller
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		client = mockpb.NewMockCliToHubClient(ctrl)
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	It("prints a report of what each check found", func() {
+		testStdout, _, _ := testhelper.SetupTestLogger()
+
+		client.EXPECT().CheckAll(
+			gomock.Any(),
+			&pb.CheckAllRequest{MasterHost: "mdw", DbPort: 5432},
+		).Return(&pb.CheckAllReply{Passed: true, Results: []*pb.CheckResult{
+			{Name: "version", Severity: pb.CheckSeverity_ERROR, Hosts: []string{"mdw"}, Passed: true},
+			{Name: "disk-space", Severity: pb.CheckSeverity_WARNING, Hosts: []string{"sdw1", "sdw2"},
+				Findings: []*pb.CheckFinding{{Host: "sdw2", Message: "/data is 91.0% full"}}},
+			{Name: "object-count", Severity: pb.CheckSeverity_INFO, Hosts: []string{"mdw"}, Passed: true,
+				Notes: []*pb.CheckFinding{{Host: "mdw", Message: "database postgres has 0 append-optimized and 3 heap tables"}}},
+		}}, nil)
+
+		err := commanders.NewAllChecker(client).Execute("mdw", 5432)
+		Expect(err).ToNot(HaveOccurred())
+
+		Eventually(testStdout).Should(gbytes.Say(`\[PASS\] version on mdw`))
+		Eventually(testStdout).Should(gbytes.Say(`\[WARN\] disk-space on sdw1, sdw2`))
+		Eventually(testStdout).Should(gbytes.Say(regexp.QuoteMeta("    sdw2: /data is 91.0% full")))
+		Eventually(testStdout).Should(gbytes.Say(`\[PASS\] object-count on mdw`))
+		Eventually(testStdout).Should(gbytes.Say(`    mdw: database postgres has 0 append-optimized and 3 heap tables`))
+		Eventually(testStdout).Should(gbytes.Say(`2 of 3 checks passed`))
+	})
+
+	It("fails naming the checks that stop the upgrade", func() {
+		testStdout, _, _ := testhelper.SetupTestLogger()
+
+		client.EXPECT().CheckAll(
+			gomock.Any(),
+			gomock.Any(),
+		).Return(&pb.CheckAllReply{Passed: false, Results: []*pb.CheckResult{
+			{Name: "version", Severity: pb.CheckSeverity_ERROR, Error: "could not connect"},
+			{Name: "object-count", Severity: pb.CheckSeverity_INFO},
+		}}, nil)
+
+		err := commanders.NewAllChecker(client).Execute("mdw", 5432)
+		Expect(err).To(MatchError("the upgrade cannot proceed until these checks pass: version"))
+
+		Eventually(testStdout).Should(gbytes.Say(`\[FAIL\] version`))
+		Eventually(testStdout).Should(gbytes.Say(`    could not run: could not connect`))
+		Eventually(testStdout).Should(gbytes.Say(`\[INFO\] object-count`))
+	})
+
+	It("returns an error when the hub can't be reached", func() {
+		client.EXPECT().CheckAll(gomock.Any(), gomock.Any()).Return(nil, errors.New("hub is down"))
+
+		err := commanders.NewAllChecker(client).Execute("mdw", 5432)
+		Expect(err).To(MatchError("hub is down"))
+	})
+})
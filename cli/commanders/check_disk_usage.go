@@ -2,7 +2,9 @@ package commanders
 
 import (
 	"context"
+	"fmt"
 
+	"github.com/greenplum-db/gpupgrade/config"
 	pb "github.com/greenplum-db/gpupgrade/idl"
 
 	"github.com/greenplum-db/gp-common-go-libs/gplog"
@@ -24,10 +26,44 @@ func (req DiskSpaceChecker) Execute() error {
 		return err
 	}
 
+	mode := config.ModeCopy
+	if reply.LinkMode {
+		mode = config.ModeLink
+	}
+
 	//TODO: do we want to report results to the user earlier? Should we make a gRPC call per db?
-	for _, segmentFileSysUsage := range reply.SegmentFileSysUsage {
-		gplog.Info(segmentFileSysUsage)
+	for _, host := range reply.Hosts {
+		if host.Error != "" {
+			gplog.Info("diskspace check - %s - could not get disk space: %s", host.Hostname, host.Error)
+			continue
+		}
+		for _, space := range host.DataDirSpaces {
+			gplog.Info("diskspace check - %s - %s", host.Hostname, describeDataDirSpace(space, mode))
+		}
 	}
 	gplog.Info("Check disk space request is processed.")
-	return nil
+	return emitReply(reply)
+}
+
+// describeDataDirSpace is a line on whether a segment's new data dir has
+// room for what pg_upgrade will write there in mode.
+func describeDataDirSpace(space *pb.DataDirSpace, mode string) string {
+	segment := fmt.Sprintf("segment %d", space.Content)
+	if space.Content == -1 {
+		segment = "master"
+	}
+
+	status := "OK"
+	if !space.Sufficient {
+		status = "INSUFFICIENT"
+	}
+	if space.Error != "" {
+		return fmt.Sprintf("%s %s: %s", status, segment, space.Error)
+	}
+
+	line := fmt.Sprintf("%s %s %s: needs %d bytes for %s mode", status, segment, space.NewDataDir, space.Required, mode)
+	if space.RequiredOnFilesystem != space.Required {
+		line += fmt.Sprintf(" (%d bytes with the segments that share its filesystem)", space.RequiredOnFilesystem)
+	}
+	return line + fmt.Sprintf(", %d bytes free", space.Available)
 }
@@ -48,25 +48,31 @@ var _ = Describe("object count tests", func() {
 		It("prints out the results of disk usage check from gRPC reply", func() {
 			testStdout, _, _ := testhelper.SetupTestLogger()
 
-			var expectedFilesystemsUsage []string
-			expectedFilesystemsUsage = append(expectedFilesystemsUsage, "diskspace check - hostC  - Couldn't connect")
-			expectedFilesystemsUsage = append(expectedFilesystemsUsage, "diskspace check - hostA  - OK")
-			//to log a percent sign, use %% to avoid logger substitution
-			expectedFilesystemsUsage = append(expectedFilesystemsUsage, "diskspace check - hostD  - WARNING /data 90%% use")
-
 			client.EXPECT().CheckDiskSpace(
 				gomock.Any(),
 				&pb.CheckDiskSpaceRequest{},
-			).Return(&pb.CheckDiskSpaceReply{SegmentFileSysUsage: expectedFilesystemsUsage}, nil)
+			).Return(&pb.CheckDiskSpaceReply{
+				LinkMode: true,
+				Hosts: []*pb.HostDiskSpace{
+					{Hostname: "hostA", DataDirSpaces: []*pb.DataDirSpace{
+						{Content: -1, NewDataDir: "/new/datadir", Required: 100, RequiredOnFilesystem: 100, Available: 2000, Sufficient: true},
+						{Content: 0, NewDataDir: "/new/datadir0", Required: 1500, RequiredOnFilesystem: 3000, Available: 2000},
+						{Content: 1, Error: "link mode needs /old/datadir1 and /new/datadir1 on the same filesystem"},
+					}},
+					{Hostname: "hostC", Error: "connection refused"},
+				},
+			}, nil)
 
 			request := commanders.NewDiskSpaceChecker(client)
 			err := request.Execute()
 
 			Expect(err).To(BeNil())
-			Expect(string(testStdout.Contents())).To(ContainSubstring("diskspace check - hostC  - Couldn't connect"))
-			Expect(string(testStdout.Contents())).To(ContainSubstring("diskspace check - hostA  - OK"))
-			Expect(string(testStdout.Contents())).To(ContainSubstring("diskspace check - hostD  - WARNING /data 90% use"))
-
+			stdout := string(testStdout.Contents())
+			Expect(stdout).To(ContainSubstring("diskspace check - hostA - OK master /new/datadir: needs 100 bytes for link mode, 2000 bytes free"))
+			Expect(stdout).To(ContainSubstring("diskspace check - hostA - INSUFFICIENT segment 0 /new/datadir0: needs 1500 bytes for link mode " +
+				"(3000 bytes with the segments that share its filesystem), 2000 bytes free"))
+			Expect(stdout).To(ContainSubstring("diskspace check - hostA - INSUFFICIENT segment 1: link mode needs /old/datadir1 and /new/datadir1 on the same filesystem"))
+			Expect(stdout).To(ContainSubstring("diskspace check - hostC - could not get disk space: connection refused"))
 		})
 	})
 })
@@ -30,5 +30,5 @@ func (req ObjectCountChecker) Execute(dbPort int) error {
 		gplog.Info("Number of heap objects - %d", count.HeapCount)
 	}
 	gplog.Info("Check object count request is processed.")
-	return nil
+	return emitReply(reply)
 }
@@ -17,9 +17,13 @@ func NewSeginstallChecker(client pb.CliToHubClient) SeginstallChecker {
 }
 
 func (req SeginstallChecker) Execute() error {
-	_, err := req.client.CheckSeginstall(
+	reply, err := req.client.CheckSeginstall(
 		context.Background(),
 		&pb.CheckSeginstallRequest{},
 	)
-	return err
+	if err != nil {
+		return fromHubError(err)
+	}
+
+	return emitReply(reply)
 }
@@ -29,8 +29,9 @@ func (req VersionChecker) Execute(masterHost string, dbPort int) error {
 		gplog.Info("gpupgrade: Version Compatibility Check [OK]\n")
 	} else {
 		gplog.Info("gpupgrade: Version Compatibility Check [Failed]\n")
+		gplog.Info(resp.Reason)
 	}
 	gplog.Info("Check version request is processed.")
 
-	return nil
+	return emitReply(resp)
 }
@@ -50,12 +50,13 @@ var _ bool = Describe("object count tests", func() {
 			client.EXPECT().CheckVersion(
 				gomock.Any(),
 				&pb.CheckVersionRequest{DbPort: 9999, Host: "localhost"},
-			).Return(&pb.CheckVersionReply{IsVersionCompatible: false}, nil)
+			).Return(&pb.CheckVersionReply{IsVersionCompatible: false, Reason: "Greenplum 6.1.0 cannot be downgraded to 5.10.2"}, nil)
 			request := commanders.NewVersionChecker(client)
 			err := request.Execute("localhost", 9999)
 			Expect(err).To(BeNil())
 			// this eventually should actually be an expect -- convert it
 			Eventually(string(testStdout.Contents())).Should(ContainSubstring("gpupgrade: Version Compatibility Check [Failed]\n"))
+			Eventually(string(testStdout.Contents())).Should(ContainSubstring("Greenplum 6.1.0 cannot be downgraded to 5.10.2"))
 			Eventually(string(testStdout.Contents())).Should(ContainSubstring("Check version request is processed."))
 		})
 		It("prints out that it was unable to connect to hub", func() {
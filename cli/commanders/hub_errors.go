@@ -0,0 +1,29 @@
+package commanders
+
+import (
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// UnmetPrerequisitesError is returned when the hub refuses to run a step
+// because steps it depends on have not completed yet. Message names the
+// missing steps.
+type UnmetPrerequisitesError struct {
+	Message string
+}
+
+func (e UnmetPrerequisitesError) Error() string {
+	return e.Message + ". Use command \"gpupgrade status upgrade\" to see the status of each step."
+}
+
+// fromHubError translates a FailedPrecondition status returned by a hub RPC
+// into an UnmetPrerequisitesError, so that it can be shown to the user without
+// the gRPC decoration. Any other error is returned unchanged.
+func fromHubError(err error) error {
+	s, ok := status.FromError(err)
+	if !ok || s.Code() != codes.FailedPrecondition {
+		return err
+	}
+
+	return UnmetPrerequisitesError{Message: s.Message()}
+}
@@ -0,0 +1,58 @@
+package commanders
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	pb "github.com/greenplum-db/gpupgrade/idl"
+)
+
+type LogsReader struct {
+	client pb.CliToHubClient
+	out    io.Writer
+}
+
+func NewLogsReader(client pb.CliToHubClient, out io.Writer) LogsReader {
+	return LogsReader{client: client, out: out}
+}
+
+// Execute writes the logs that the hub streams for request to out as they
+// are, with a header naming the host and file whenever the stream moves on to
+// another file, like tail does. It only returns once the hub ends the stream,
+// which it doesn't while following the logs. The logs are text, so it refuses
+// a machine-readable --format rather than mixing them into the output.
+func (r LogsReader) Execute(request *pb.LogsRequest) error {
+	if IsMachineReadable() {
+		return fmt.Errorf("gpupgrade logs shows the logs as they are written, and does not support --format %s", outputFormat)
+	}
+
+	stream, err := r.client.Logs(context.Background(), request)
+	if err != nil {
+		return fromHubError(err)
+	}
+
+	var host, path string
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fromHubError(err)
+		}
+
+		if chunk.Hostname != host || chunk.Path != path {
+			if path != "" {
+				fmt.Fprintln(r.out)
+			}
+			host, path = chunk.Hostname, chunk.Path
+			fmt.Fprintf(r.out, "==> %s:%s <==\n", host, path)
+		}
+
+		_, err = r.out.Write(chunk.Data)
+		if err != nil {
+			return err
+		}
+	}
+}
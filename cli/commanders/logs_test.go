@@ -0,0 +1,71 @@
+package commanders_test
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/greenplum-db/gpupgrade/cli/commanders"
+	pb "github.com/greenplum-db/gpupgrade/idl"
+	mockpb "github.com/greenplum-db/gpupgrade/mock_idl"
+
+	"github.com/golang/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("logs", func() {
+	var (
+		client *mockpb.MockCliToHubClient
+		stream *mockpb.MockCliToHub_LogsClient
+		ctrl   *gomock.Controller
+		out    *bytes.Buffer
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		client = mockpb.NewMockCliToHubClient(ctrl)
+		stream = mockpb.NewMockCliToHub_LogsClient(ctrl)
+		out = &bytes.Buffer{}
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	It("writes each file under a header", func() {
+		request := &pb.LogsRequest{Source: pb.LogSource_PG_UPGRADE, Content: 1, Follow: true}
+		client.EXPECT().Logs(gomock.Any(), request).Return(stream, nil)
+		gomock.InOrder(
+			stream.EXPECT().Recv().Return(&pb.LogChunk{Hostname: "sdw1", Path: "/seg-1/a.log", Data: []byte("one ")}, nil),
+			stream.EXPECT().Recv().Return(&pb.LogChunk{Hostname: "sdw1", Path: "/seg-1/a.log", Data: []byte("two\n")}, nil),
+			stream.EXPECT().Recv().Return(&pb.LogChunk{Hostname: "sdw1", Path: "/seg-1/nohup.out", Data: []byte("three\n")}, nil),
+			stream.EXPECT().Recv().Return(nil, io.EOF),
+		)
+
+		err := commanders.NewLogsReader(client, out).Execute(request)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(out.String()).To(Equal("==> sdw1:/seg-1/a.log <==\none two\n\n==> sdw1:/seg-1/nohup.out <==\nthree\n"))
+	})
+
+	It("returns the error that ends the stream", func() {
+		request := &pb.LogsRequest{Source: pb.LogSource_AGENT, Hostname: "sdw1"}
+		client.EXPECT().Logs(gomock.Any(), request).Return(stream, nil)
+		stream.EXPECT().Recv().Return(nil, status.Error(codes.NotFound, "there is no agent log on sdw1"))
+
+		err := commanders.NewLogsReader(client, out).Execute(request)
+		Expect(err).To(MatchError(ContainSubstring("there is no agent log on sdw1")))
+	})
+
+	It("refuses a machine-readable format", func() {
+		Expect(commanders.SetOutputFormat(commanders.FormatJSON)).To(Succeed())
+		defer commanders.SetOutputFormat(commanders.FormatText)
+
+		err := commanders.NewLogsReader(client, out).Execute(&pb.LogsRequest{})
+		Expect(err).To(MatchError(ContainSubstring("does not support --format json")))
+		Expect(out.Len()).To(Equal(0))
+	})
+})
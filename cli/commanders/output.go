@@ -0,0 +1,90 @@
+package commanders
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+	FormatYAML = "yaml"
+)
+
+// Output is where replies from the hub are written when a machine-readable
+// format has been selected. Tests may replace it.
+var Output io.Writer = os.Stdout
+
+var outputFormat = FormatText
+
+// SetOutputFormat selects how replies from the hub are reported. With
+// FormatText they are only logged; with FormatJSON or FormatYAML each reply is
+// also written to Output as a JSON object on a line of its own, or as a YAML
+// document.
+func SetOutputFormat(format string) error {
+	switch format {
+	case FormatText, FormatJSON, FormatYAML:
+		outputFormat = format
+		return nil
+	default:
+		return fmt.Errorf("unknown output format %q: must be one of %s, %s or %s",
+			format, FormatText, FormatJSON, FormatYAML)
+	}
+}
+
+// IsMachineReadable returns true when replies are written to Output, in which
+// case nothing else may be written to stdout.
+func IsMachineReadable() bool {
+	return outputFormat != FormatText
+}
+
+// emitReply writes reply to Output in the selected format. It does nothing if
+// the output is meant for humans.
+//
+// Field names are the ones declared in the .proto files, and fields with their
+// default value are included, so that documents for the same reply always have
+// the same shape.
+func emitReply(reply proto.Message) error {
+	if !IsMachineReadable() {
+		return nil
+	}
+
+	marshaler := jsonpb.Marshaler{OrigName: true, EmitDefaults: true}
+	var doc bytes.Buffer
+	err := marshaler.Marshal(&doc, reply)
+	if err != nil {
+		return fmt.Errorf("could not encode %s reply: %s", proto.MessageName(reply), err)
+	}
+
+	return writeDocument(doc.Bytes())
+}
+
+// writeDocument writes a single JSON document to Output, converting it to YAML
+// first if that is the selected format.
+func writeDocument(doc []byte) error {
+	if outputFormat == FormatYAML {
+		// JSON is valid YAML, and a MapSlice keeps the fields in order.
+		var fields yaml.MapSlice
+		err := yaml.Unmarshal(doc, &fields)
+		if err != nil {
+			return err
+		}
+
+		doc, err = yaml.Marshal(fields)
+		if err != nil {
+			return err
+		}
+		doc = append([]byte("---\n"), doc...)
+	} else {
+		doc = append(doc, '\n')
+	}
+
+	_, err := Output.Write(doc)
+	return err
+}
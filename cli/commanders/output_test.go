@@ -0,0 +1,102 @@
+package commanders_test
+
+import (
+	"io"
+	"os"
+
+	"github.com/greenplum-db/gpupgrade/cli/commanders"
+	pb "github.com/greenplum-db/gpupgrade/idl"
+	mockpb "github.com/greenplum-db/gpupgrade/mock_idl"
+
+	"github.com/golang/mock/gomock"
+	"github.com/greenplum-db/gp-common-go-libs/testhelper"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+)
+
+var _ = Describe("machine-readable output", func() {
+	var (
+		client *mockpb.MockCliToHubClient
+		ctrl   *gomock.Controller
+		output *gbytes.Buffer
+	)
+
+	BeforeEach(func() {
+		testhelper.SetupTestLogger()
+		ctrl = gomock.NewController(GinkgoT())
+		client = mockpb.NewMockCliToHubClient(ctrl)
+
+		output = gbytes.NewBuffer()
+		commanders.Output = output
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+		commanders.Output = io.Writer(os.Stdout)
+		Expect(commanders.SetOutputFormat(commanders.FormatText)).To(Succeed())
+	})
+
+	objectCountReply := &pb.CheckObjectCountReply{
+		ListOfCounts: []*pb.CountPerDb{{DbName: "postgres", AoCount: 2, HeapCount: 3}},
+	}
+
+	It("rejects unknown formats", func() {
+		Expect(commanders.SetOutputFormat("xml")).ToNot(Succeed())
+		Expect(commanders.IsMachineReadable()).To(BeFalse())
+	})
+
+	It("writes nothing to the output in text format", func() {
+		client.EXPECT().CheckObjectCount(gomock.Any(), gomock.Any()).Return(objectCountReply, nil)
+
+		err := commanders.NewObjectCountChecker(client).Execute(9999)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(output.Contents()).To(BeEmpty())
+	})
+
+	It("writes the reply as a line of JSON, using the proto field names", func() {
+		Expect(commanders.SetOutputFormat(commanders.FormatJSON)).To(Succeed())
+		client.EXPECT().CheckObjectCount(gomock.Any(), gomock.Any()).Return(objectCountReply, nil)
+
+		err := commanders.NewObjectCountChecker(client).Execute(9999)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(output.Contents())).To(Equal(
+			`{"ListOfCounts":[{"DbName":"postgres","AoCount":2,"HeapCount":3}]}` + "\n"))
+	})
+
+	It("writes the reply as a YAML document", func() {
+		Expect(commanders.SetOutputFormat(commanders.FormatYAML)).To(Succeed())
+		client.EXPECT().CheckObjectCount(gomock.Any(), gomock.Any()).Return(objectCountReply, nil)
+
+		err := commanders.NewObjectCountChecker(client).Execute(9999)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(output.Contents())).To(Equal(`---
+ListOfCounts:
+- DbName: postgres
+  AoCount: 2
+  HeapCount: 3
+`))
+	})
+
+	It("includes fields that have their default value", func() {
+		Expect(commanders.SetOutputFormat(commanders.FormatJSON)).To(Succeed())
+		client.EXPECT().StatusUpgrade(gomock.Any(), gomock.Any()).Return(&pb.StatusUpgradeReply{
+			ListOfUpgradeStepStatuses: []*pb.UpgradeStepStatus{
+				{Step: pb.UpgradeSteps_CHECK_CONFIG},
+			},
+		}, nil)
+
+		err := commanders.NewReporter(client).OverallUpgradeStatus()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(output.Contents())).To(ContainSubstring(`"step":"CHECK_CONFIG","status":"UNKNOWN_STATUS"`))
+	})
+
+	It("reports the version as a document", func() {
+		Expect(commanders.SetOutputFormat(commanders.FormatJSON)).To(Succeed())
+
+		err := commanders.PrintVersion()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(output.Contents())).To(Equal(`{"Version":"` + commanders.VersionString() + `"}` + "\n"))
+	})
+})
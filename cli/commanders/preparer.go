@@ -10,6 +10,8 @@ import (
 	"strings"
 	"time"
 
+	"github.com/greenplum-db/gpupgrade/certs"
+	"github.com/greenplum-db/gpupgrade/config"
 	"github.com/greenplum-db/gpupgrade/hub/services"
 	pb "github.com/greenplum-db/gpupgrade/idl"
 
@@ -28,13 +30,13 @@ func NewPreparer(client pb.CliToHubClient) Preparer {
 var NumberOfConnectionAttempt = 100
 
 func (p Preparer) ShutdownClusters(oldBinDir string, newBinDir string) error {
-	_, err := p.client.PrepareShutdownClusters(context.Background(),
+	reply, err := p.client.PrepareShutdownClusters(context.Background(),
 		&pb.PrepareShutdownClustersRequest{OldBinDir: oldBinDir, NewBinDir: newBinDir})
 	if err != nil {
-		gplog.Error(err.Error())
+		return fromHubError(err)
 	}
 	gplog.Info("request to shutdown clusters sent to hub")
-	return nil
+	return emitReply(reply)
 }
 
 func (p Preparer) StartHub() error {
@@ -64,13 +66,13 @@ func (p Preparer) StartHub() error {
 }
 
 func (p Preparer) InitCluster(dbPort int, newBinDir string) error {
-	_, err := p.client.PrepareInitCluster(context.Background(), &pb.PrepareInitClusterRequest{DbPort: int32(dbPort), NewBinDir: newBinDir})
+	reply, err := p.client.PrepareInitCluster(context.Background(), &pb.PrepareInitClusterRequest{DbPort: int32(dbPort), NewBinDir: newBinDir})
 	if err != nil {
-		return err
+		return fromHubError(err)
 	}
 
 	gplog.Info("Gleaning the new cluster config")
-	return nil
+	return emitReply(reply)
 }
 
 func (p Preparer) VerifyConnectivity(client pb.CliToHubClient) error {
@@ -83,15 +85,56 @@ func (p Preparer) VerifyConnectivity(client pb.CliToHubClient) error {
 }
 
 func (p Preparer) StartAgents() error {
-	_, err := p.client.PrepareStartAgents(context.Background(), &pb.PrepareStartAgentsRequest{})
+	reply, err := p.client.PrepareStartAgents(context.Background(), &pb.PrepareStartAgentsRequest{})
 	if err != nil {
-		return err
+		return fromHubError(err)
 	}
 
 	gplog.Info("Started Agents in progress, check gpupgrade_agent logs for details")
+	return emitReply(reply)
+}
+
+// StopAgents asks the hub to shut down the agents on the segment hosts, and
+// reports which hosts confirmed it. It fails if any of them didn't.
+func (p Preparer) StopAgents() error {
+	reply, err := p.client.PrepareStopAgents(context.Background(), &pb.PrepareStopAgentsRequest{})
+	if err != nil {
+		return fromHubError(err)
+	}
+
+	var failedHosts []string
+	for _, agent := range reply.Agents {
+		if agent.Stopped {
+			gplog.Info("Agent on %s confirmed shutdown", agent.Hostname)
+		} else {
+			gplog.Error("Agent on %s did not shut down: %s", agent.Hostname, agent.Error)
+			failedHosts = append(failedHosts, agent.Hostname)
+		}
+	}
+
+	err = emitReply(reply)
+	if err != nil {
+		return err
+	}
+
+	if len(failedHosts) != 0 {
+		return fmt.Errorf("agents on %s did not confirm shutdown", strings.Join(failedHosts, ", "))
+	}
 	return nil
 }
 
+// StopHub asks the hub to shut down. The hub finishes the requests it is
+// working on first, but refuses while an upgrade run or a revert is going.
+func (p Preparer) StopHub() error {
+	reply, err := p.client.Shutdown(context.Background(), &pb.ShutdownRequest{})
+	if err != nil {
+		return fromHubError(err)
+	}
+
+	gplog.Info("Hub on %s confirmed shutdown", reply.Hostname)
+	return emitReply(reply)
+}
+
 func HowManyHubsRunning() (int, error) {
 	howToLookForHub := `ps -ef | grep -Gc "[g]pupgrade_hub$"` // use square brackets to avoid finding yourself in matches
 	output, err := exec.Command("bash", "-c", howToLookForHub).Output()
@@ -124,5 +167,16 @@ func DoInit(stateDir string, oldBinDir string) error {
 	cp.OldCluster = &cluster.Cluster{}
 	cp.OldCluster.ContentIDs = []int{}
 	cp.OldBinDir = oldBinDir
-	return cp.WriteOldConfig(stateDir)
+	err = cp.WriteOldConfig(stateDir)
+	if err != nil {
+		return err
+	}
+
+	err = config.Write(stateDir, config.Default())
+	if err != nil {
+		return err
+	}
+
+	// The CLI, hub and agents only talk to each other over mutual TLS.
+	return certs.CreateAuthority(stateDir)
 }
@@ -6,7 +6,9 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/greenplum-db/gpupgrade/certs"
 	"github.com/greenplum-db/gpupgrade/cli/commanders"
+	"github.com/greenplum-db/gpupgrade/config"
 	"github.com/greenplum-db/gpupgrade/hub/services"
 	pb "github.com/greenplum-db/gpupgrade/idl"
 	mockpb "github.com/greenplum-db/gpupgrade/mock_idl"
@@ -18,6 +20,8 @@ import (
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	"github.com/onsi/gomega/gbytes"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 var _ = Describe("preparer", func() {
@@ -109,6 +113,17 @@ var _ = Describe("preparer", func() {
 			Expect(err).To(BeNil())
 			Eventually(testStdout).Should(gbytes.Say("request to shutdown clusters sent to hub"))
 		})
+
+		It("returns an error when the hub refuses the request", func() {
+			client.EXPECT().PrepareShutdownClusters(
+				gomock.Any(),
+				&pb.PrepareShutdownClustersRequest{OldBinDir: "/old", NewBinDir: "/new"},
+			).Return(&pb.PrepareShutdownClustersReply{}, status.Error(codes.FailedPrecondition,
+				"shutdown-clusters cannot run until the following steps have completed: init-cluster"))
+			preparer := commanders.NewPreparer(client)
+			err := preparer.ShutdownClusters("/old", "/new")
+			Expect(err).To(MatchError(ContainSubstring("following steps have completed: init-cluster")))
+		})
 	})
 	Describe("PrepareStartAgents", func() {
 		It("returns successfully", func() {
@@ -124,6 +139,53 @@ var _ = Describe("preparer", func() {
 			Eventually(testStdout).Should(gbytes.Say("Started Agents in progress, check gpupgrade_agent logs for details"))
 		})
 	})
+	Describe("StopAgents", func() {
+		It("reports the hosts that confirmed shutdown", func() {
+			testStdout, _, _ := testhelper.SetupTestLogger()
+
+			client.EXPECT().PrepareStopAgents(
+				gomock.Any(),
+				&pb.PrepareStopAgentsRequest{},
+			).Return(&pb.PrepareStopAgentsReply{Agents: []*pb.AgentShutdownStatus{
+				{Hostname: "hostone", Stopped: true},
+				{Hostname: "hosttwo", Stopped: true},
+			}}, nil)
+			preparer := commanders.NewPreparer(client)
+			err := preparer.StopAgents()
+			Expect(err).ToNot(HaveOccurred())
+			Eventually(testStdout).Should(gbytes.Say("Agent on hostone confirmed shutdown"))
+			Eventually(testStdout).Should(gbytes.Say("Agent on hosttwo confirmed shutdown"))
+		})
+
+		It("fails if any agent did not shut down", func() {
+			testhelper.SetupTestLogger()
+
+			client.EXPECT().PrepareStopAgents(
+				gomock.Any(),
+				&pb.PrepareStopAgentsRequest{},
+			).Return(&pb.PrepareStopAgentsReply{Agents: []*pb.AgentShutdownStatus{
+				{Hostname: "hostone", Stopped: true},
+				{Hostname: "hosttwo", Error: "could not connect to the agent"},
+			}}, nil)
+			preparer := commanders.NewPreparer(client)
+			err := preparer.StopAgents()
+			Expect(err).To(MatchError("agents on hosttwo did not confirm shutdown"))
+		})
+	})
+	Describe("StopHub", func() {
+		It("reports the host the hub was running on", func() {
+			testStdout, _, _ := testhelper.SetupTestLogger()
+
+			client.EXPECT().Shutdown(
+				gomock.Any(),
+				&pb.ShutdownRequest{},
+			).Return(&pb.ShutdownReply{Hostname: "mdw"}, nil)
+			preparer := commanders.NewPreparer(client)
+			err := preparer.StopHub()
+			Expect(err).ToNot(HaveOccurred())
+			Eventually(testStdout).Should(gbytes.Say("Hub on mdw confirmed shutdown"))
+		})
+	})
 	Describe("Prepare init", func() {
 		It("creates dir when none exists", func() {
 			dir, err := ioutil.TempDir("", "")
@@ -138,6 +200,33 @@ var _ = Describe("preparer", func() {
 			cp.ReadOldConfig(stateDir)
 			Expect(cp.OldBinDir).To(Equal("/does/not/exist"))
 		})
+		It("creates the certificates for the hub and the CLI", func() {
+			dir, err := ioutil.TempDir("", "")
+			Expect(err).ToNot(HaveOccurred())
+			defer os.RemoveAll(dir)
+
+			stateDir := filepath.Join(dir, "foo")
+			err = commanders.DoInit(stateDir, "/does/not/exist")
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = certs.ServerCredentials(stateDir, certs.Hub, certs.CLI)
+			Expect(err).ToNot(HaveOccurred())
+			_, err = certs.ClientCredentials(stateDir, certs.CLI)
+			Expect(err).ToNot(HaveOccurred())
+		})
+		It("writes the default configuration file", func() {
+			dir, err := ioutil.TempDir("", "")
+			Expect(err).ToNot(HaveOccurred())
+			defer os.RemoveAll(dir)
+
+			stateDir := filepath.Join(dir, "foo")
+			err = commanders.DoInit(stateDir, "/does/not/exist")
+			Expect(err).ToNot(HaveOccurred())
+
+			conf, err := config.Load(stateDir)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(conf).To(Equal(config.Default()))
+		})
 		It("errs out when dir exists", func() {
 			dir, err := ioutil.TempDir("", "")
 			Expect(err).ToNot(HaveOccurred())
@@ -3,8 +3,11 @@ package commanders
 import (
 	"context"
 	"fmt"
+	"io"
+	"time"
 
 	pb "github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/utils"
 
 	"github.com/greenplum-db/gp-common-go-libs/gplog"
 	"github.com/pkg/errors"
@@ -55,9 +58,53 @@ func (r *Reporter) OverallUpgradeStatus() error {
 	}
 
 	for _, step := range status.GetListOfUpgradeStepStatuses() {
-		reportString := fmt.Sprintf("%v %s", step.GetStatus(),
-			UpgradeStepsMessage[step.GetStep()])
-		gplog.Info(reportString)
+		reportString := fmt.Sprintf("%v %s%s%s", step.GetStatus(),
+			UpgradeStepsMessage[step.GetStep()], formatProgress(step.GetProgress()), formatTiming(step))
+		gplog.Info("%s", reportString)
+	}
+
+	return emitReply(status)
+}
+
+// WatchUpgradeStatus prints each step's status as the hub reports it, and
+// keeps printing updates until every step is COMPLETE or one of them has
+// FAILED. A failed step is returned as an error.
+func (r *Reporter) WatchUpgradeStatus() error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stream, err := r.client.WatchUpgradeStatus(ctx, &pb.WatchUpgradeStatusRequest{})
+	if err != nil {
+		return errors.New("Failed to watch status from hub: " + err.Error())
+	}
+
+	received := false
+	for {
+		step, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return errors.New("Lost status stream from hub: " + err.Error())
+		}
+		received = true
+
+		reportString := fmt.Sprintf("%v %s%s%s", step.GetStatus(),
+			UpgradeStepsMessage[step.GetStep()], formatProgress(step.GetProgress()), formatTiming(step))
+		gplog.Info("%s", reportString)
+
+		err = emitReply(step)
+		if err != nil {
+			return err
+		}
+
+		if step.GetStatus() == pb.StepStatus_FAILED {
+			return fmt.Errorf("upgrade step failed %s", UpgradeStepsMessage[step.GetStep()])
+		}
+	}
+
+	if !received {
+		return errors.New("Received no list of upgrade statuses from hub")
 	}
 
 	return nil
@@ -74,8 +121,90 @@ func (r *Reporter) OverallConversionStatus() error {
 	}
 
 	for _, status := range conversionStatus.GetConversionStatuses() {
-		gplog.Info(status)
+		gplog.Info("%s", formatSegmentConversionStatus(status))
 	}
 
-	return nil
+	return emitReply(conversionStatus)
+}
+
+// AgentsStatus reports how the hub last heard from the agent on each segment
+// host.
+func (r *Reporter) AgentsStatus() error {
+	reply, err := r.client.StatusAgents(context.Background(), &pb.StatusAgentsRequest{})
+	if err != nil {
+		return errors.New("hub returned an error when checking the status of the agents: " + err.Error())
+	}
+
+	for _, status := range reply.GetAgents() {
+		gplog.Info("%s", formatAgentHealthStatus(status))
+	}
+
+	return emitReply(reply)
+}
+
+// formatAgentHealthStatus renders the health of a single agent as a line of
+// the agents report.
+func formatAgentHealthStatus(status *pb.AgentHealthStatus) string {
+	line := fmt.Sprintf("%s - %s", status.GetHealth(), status.GetHostname())
+	if status.GetHealth() == pb.AgentHealth_MISSING {
+		return line
+	}
+
+	since := utils.System.Now().Sub(time.Unix(status.GetLastHeartbeat(), 0)).Round(time.Second)
+	return fmt.Sprintf("%s - version %s - state dir %s - last heartbeat %s ago",
+		line, status.GetVersion(), status.GetStateDir(), since)
+}
+
+// formatSegmentConversionStatus renders the conversion status of a single
+// segment as a line of the overall conversion report.
+func formatSegmentConversionStatus(status *pb.SegmentConversionStatus) string {
+	line := fmt.Sprintf("%s - DBID %d - CONTENT ID %d - %s - %s%s",
+		status.GetStatus(), status.GetDbid(), status.GetContent(), status.GetRole(), status.GetHostname(),
+		formatProgress(status.GetProgress()))
+	if status.GetError() != "" {
+		line += " - " + status.GetError()
+	}
+
+	return line
+}
+
+// formatProgress renders the progress of pg_upgrade as a suffix for a status
+// line, such as " (phase 3/14, 21%, 2m10s elapsed: Copying user relation files)".
+// It returns an empty string if there is no progress to report.
+func formatProgress(progress *pb.PgUpgradeProgress) string {
+	if progress == nil {
+		return ""
+	}
+
+	elapsed := time.Duration(progress.GetElapsedSeconds()) * time.Second
+	suffix := fmt.Sprintf(" (phase %d/%d, %d%%, %s elapsed",
+		progress.GetPhasesDone(), progress.GetTotalPhases(), progress.GetPercentComplete(), elapsed)
+	if progress.GetPhase() != "" {
+		suffix += ": " + progress.GetPhase()
+	}
+
+	return suffix + ")"
+}
+
+// formatTiming renders how long a step has been running, or took, as a suffix
+// for a status line, such as " - running for 2m10s, about 5m0s left". It
+// returns an empty string for steps that haven't started.
+func formatTiming(step *pb.UpgradeStepStatus) string {
+	if step.GetStartTime() == 0 {
+		return ""
+	}
+
+	duration := time.Duration(step.GetDurationSeconds()) * time.Second
+	switch step.GetStatus() {
+	case pb.StepStatus_RUNNING:
+		suffix := fmt.Sprintf(" - running for %s", duration)
+		if step.GetEstimatedSecondsRemaining() > 0 {
+			suffix += fmt.Sprintf(", about %s left", time.Duration(step.GetEstimatedSecondsRemaining())*time.Second)
+		}
+		return suffix
+	case pb.StepStatus_COMPLETE, pb.StepStatus_FAILED:
+		return fmt.Sprintf(" - took %s", duration)
+	default:
+		return ""
+	}
 }
@@ -2,6 +2,8 @@ package commanders_test
 
 import (
 	"errors"
+	"regexp"
+	"time"
 
 	"github.com/greenplum-db/gpupgrade/cli/commanders"
 	pb "github.com/greenplum-db/gpupgrade/idl"
@@ -37,17 +39,55 @@ var _ = Describe("Reporter", func() {
 	})
 
 	Describe("StatusConversion", func() {
-		It("prints cluster status returned from hub", func() {
-			status := []string{"cluster status"}
+		It("prints the status of each segment returned from hub", func() {
 			spyClient.statusConversionReply = &pb.StatusConversionReply{
-				ConversionStatuses: status,
+				ConversionStatuses: []*pb.SegmentConversionStatus{{
+					Dbid:     1,
+					Content:  -1,
+					Role:     pb.SegmentRole_MASTER,
+					Hostname: "mdw",
+					Status:   pb.StepStatus_COMPLETE,
+				}, {
+					Dbid:     2,
+					Content:  0,
+					Role:     pb.SegmentRole_PRIMARY,
+					Hostname: "sdw1",
+					Status:   pb.StepStatus_FAILED,
+					Error:    "could not create relation",
+				}},
 			}
 
 			err := reporter.OverallConversionStatus()
 			Expect(err).ToNot(HaveOccurred())
 
 			Expect(spyClient.statusConversionCount).To(Equal(1))
-			Expect(testLogFile.Contents()).To(ContainSubstring("cluster status"))
+			Expect(testLogFile).To(gbytes.Say("COMPLETE - DBID 1 - CONTENT ID -1 - MASTER - mdw"))
+			Expect(testLogFile).To(gbytes.Say("FAILED - DBID 2 - CONTENT ID 0 - PRIMARY - sdw1 - could not create relation"))
+		})
+
+		It("prints the progress of pg_upgrade on each segment", func() {
+			spyClient.statusConversionReply = &pb.StatusConversionReply{
+				ConversionStatuses: []*pb.SegmentConversionStatus{{
+					Dbid:     2,
+					Content:  0,
+					Role:     pb.SegmentRole_PRIMARY,
+					Hostname: "sdw1",
+					Status:   pb.StepStatus_RUNNING,
+					Progress: &pb.PgUpgradeProgress{
+						Phase:           "Copying user relation files",
+						PhasesDone:      3,
+						TotalPhases:     14,
+						PercentComplete: 21,
+						ElapsedSeconds:  130,
+					},
+				}},
+			}
+
+			err := reporter.OverallConversionStatus()
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(testLogFile).To(gbytes.Say(regexp.QuoteMeta(
+				"RUNNING - DBID 2 - CONTENT ID 0 - PRIMARY - sdw1 (phase 3/14, 21%, 2m10s elapsed: Copying user relation files)")))
 		})
 
 		It("returns an error upon a failure", func() {
@@ -66,6 +106,102 @@ var _ = Describe("Reporter", func() {
 		})
 	})
 
+	Describe("AgentsStatus", func() {
+		It("prints the health of each agent", func() {
+			now := time.Unix(1500000000, 0)
+			utils.System.Now = func() time.Time { return now }
+
+			spyClient.statusAgentsReply = &pb.StatusAgentsReply{
+				Agents: []*pb.AgentHealthStatus{{
+					Hostname:      "sdw1",
+					Health:        pb.AgentHealth_HEALTHY,
+					Version:       "1.2.3",
+					StateDir:      "/home/gpadmin/.gpupgrade",
+					LastHeartbeat: now.Add(-5 * time.Second).Unix(),
+				}, {
+					Hostname:      "sdw2",
+					Health:        pb.AgentHealth_STALE,
+					Version:       "1.2.3",
+					StateDir:      "/home/gpadmin/.gpupgrade",
+					LastHeartbeat: now.Add(-2 * time.Minute).Unix(),
+				}, {
+					Hostname: "sdw3",
+				}},
+			}
+
+			err := reporter.AgentsStatus()
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(testLogFile).To(gbytes.Say("HEALTHY - sdw1 - version 1.2.3 - state dir /home/gpadmin/.gpupgrade - last heartbeat 5s ago"))
+			Expect(testLogFile).To(gbytes.Say("STALE - sdw2 - version 1.2.3 - state dir /home/gpadmin/.gpupgrade - last heartbeat 2m0s ago"))
+			Expect(testLogFile).To(gbytes.Say("MISSING - sdw3\n"))
+		})
+
+		It("returns an error upon a failure", func() {
+			spyClient.err = errors.New("error error")
+			err := reporter.AgentsStatus()
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("WatchUpgradeStatus", func() {
+		It("prints each status update sent by the hub", func() {
+			spyClient.watchUpgradeStatusStream = &spyWatchUpgradeStatusClient{
+				statuses: []*pb.UpgradeStepStatus{
+					{Step: pb.UpgradeSteps_CHECK_CONFIG, Status: pb.StepStatus_RUNNING},
+					{Step: pb.UpgradeSteps_MASTERUPGRADE, Status: pb.StepStatus_PENDING},
+					{Step: pb.UpgradeSteps_CHECK_CONFIG, Status: pb.StepStatus_COMPLETE},
+				},
+			}
+
+			err := reporter.WatchUpgradeStatus()
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(spyClient.watchUpgradeStatusCount).To(Equal(1))
+			Expect(testLogFile).To(gbytes.Say("RUNNING - Configuration Check"))
+			Expect(testLogFile).To(gbytes.Say("PENDING - Run pg_upgrade on master"))
+			Expect(testLogFile).To(gbytes.Say("COMPLETE - Configuration Check"))
+		})
+
+		It("stops and returns an error when a step fails", func() {
+			spyClient.watchUpgradeStatusStream = &spyWatchUpgradeStatusClient{
+				statuses: []*pb.UpgradeStepStatus{
+					{Step: pb.UpgradeSteps_SHARE_OIDS, Status: pb.StepStatus_FAILED},
+					{Step: pb.UpgradeSteps_CHECK_CONFIG, Status: pb.StepStatus_COMPLETE},
+				},
+			}
+
+			err := reporter.WatchUpgradeStatus()
+			Expect(err).To(HaveOccurred())
+
+			Expect(testLogFile.Contents()).To(ContainSubstring("FAILED - Copy OID files from master to segments"))
+			Expect(testLogFile.Contents()).ToNot(ContainSubstring("Configuration Check"))
+		})
+
+		It("returns an error when the watch cannot be started", func() {
+			spyClient.err = errors.New("some error")
+
+			err := reporter.WatchUpgradeStatus()
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("returns an error when the stream breaks", func() {
+			spyClient.watchUpgradeStatusStream = &spyWatchUpgradeStatusClient{
+				err: errors.New("connection reset"),
+			}
+
+			err := reporter.WatchUpgradeStatus()
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("returns an error when the hub sends no statuses", func() {
+			spyClient.watchUpgradeStatusStream = &spyWatchUpgradeStatusClient{}
+
+			err := reporter.WatchUpgradeStatus()
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
 	Describe("StatusUpgrade", func() {
 		It("returns an error upon a failure", func() {
 			spyClient.err = errors.New("some error")
@@ -86,6 +222,21 @@ var _ = Describe("Reporter", func() {
 			Expect(testLogFile.Contents()).To(ContainSubstring("PENDING - Run pg_upgrade on master"))
 		})
 
+		It("reports how long each step has taken, and how much longer a running step should take", func() {
+			spyClient.statusUpgradeReply = &pb.StatusUpgradeReply{
+				ListOfUpgradeStepStatuses: []*pb.UpgradeStepStatus{
+					{Step: pb.UpgradeSteps_STOPPED_CLUSTER, Status: pb.StepStatus_COMPLETE, StartTime: 1000, EndTime: 1090, DurationSeconds: 90},
+					{Step: pb.UpgradeSteps_CONVERT_PRIMARIES, Status: pb.StepStatus_RUNNING, StartTime: 1100, DurationSeconds: 130, EstimatedSecondsRemaining: 300},
+					{Step: pb.UpgradeSteps_VALIDATE_START_CLUSTER, Status: pb.StepStatus_PENDING},
+				},
+			}
+			err := reporter.OverallUpgradeStatus()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(testLogFile).To(gbytes.Say("COMPLETE - Shutdown clusters - took 1m30s"))
+			Expect(testLogFile).To(gbytes.Say("RUNNING - Primary segment upgrade - running for 2m10s, about 5m0s left"))
+			Expect(testLogFile).To(gbytes.Say("PENDING - Validate the upgraded cluster can start up\n"))
+		})
+
 		It("returns an error when the hub returns no error, but the reply has an empty list", func() {
 			By("having an empty status list")
 			spyClient.statusUpgradeReply = &pb.StatusUpgradeReply{}
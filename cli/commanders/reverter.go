@@ -0,0 +1,31 @@
+package commanders
+
+import (
+	"context"
+
+	pb "github.com/greenplum-db/gpupgrade/idl"
+
+	"github.com/greenplum-db/gp-common-go-libs/gplog"
+)
+
+type Reverter struct {
+	client pb.CliToHubClient
+}
+
+func NewReverter(client pb.CliToHubClient) Reverter {
+	return Reverter{client: client}
+}
+
+// Revert asks the hub to roll back to the old cluster, and waits for it to
+// finish.
+func (r Reverter) Revert() error {
+	gplog.Info("Reverting to the old cluster. This may take a while.")
+
+	reply, err := r.client.Revert(context.Background(), &pb.RevertRequest{})
+	if err != nil {
+		return fromHubError(err)
+	}
+
+	gplog.Info("Revert complete. The old cluster has been restarted.")
+	return emitReply(reply)
+}
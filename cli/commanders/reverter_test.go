@@ -0,0 +1,55 @@
+package commanders_test
+
+import (
+	"errors"
+
+	"github.com/greenplum-db/gpupgrade/cli/commanders"
+	pb "github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/testutils"
+
+	"github.com/greenplum-db/gp-common-go-libs/testhelper"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+)
+
+var _ = Describe("Reverter", func() {
+	var (
+		hubClient  *testutils.MockHubClient
+		reverter   commanders.Reverter
+		testStdout *gbytes.Buffer
+	)
+
+	BeforeEach(func() {
+		testStdout, _, _ = testhelper.SetupTestLogger()
+
+		hubClient = testutils.NewMockHubClient()
+		reverter = commanders.NewReverter(hubClient)
+	})
+
+	It("asks the hub to revert and reports when it is done", func() {
+		err := reverter.Revert()
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(hubClient.RevertRequest).To(Equal(&pb.RevertRequest{}))
+		Eventually(testStdout).Should(gbytes.Say("Revert complete"))
+	})
+
+	It("returns an error when the revert fails", func() {
+		hubClient.Err = errors.New("revert-start-old-cluster failed: gpstart failed")
+
+		err := reverter.Revert()
+		Expect(err).To(MatchError("revert-start-old-cluster failed: gpstart failed"))
+	})
+
+	It("explains why the hub refused to revert", func() {
+		hubClient.Err = status.Error(codes.FailedPrecondition, "there is nothing to revert: shutdown-clusters has not been run")
+
+		err := reverter.Revert()
+		Expect(err).To(BeAssignableToTypeOf(commanders.UnmetPrerequisitesError{}))
+		Expect(err.Error()).To(HavePrefix("there is nothing to revert: shutdown-clusters has not been run."))
+	})
+})
@@ -1,6 +1,8 @@
 package commanders_test
 
 import (
+	"io"
+
 	pb "github.com/greenplum-db/gpupgrade/idl"
 
 	"golang.org/x/net/context"
@@ -15,9 +17,14 @@ type spyCliToHubClient struct {
 	statusUpgradeCount int
 	statusUpgradeReply *pb.StatusUpgradeReply
 
+	watchUpgradeStatusCount  int
+	watchUpgradeStatusStream *spyWatchUpgradeStatusClient
+
 	statusConversionCount int
 	statusConversionReply *pb.StatusConversionReply
 
+	statusAgentsReply *pb.StatusAgentsReply
+
 	err error
 }
 
@@ -56,3 +63,43 @@ func (s *spyCliToHubClient) StatusConversion(
 	s.statusConversionCount++
 	return s.statusConversionReply, s.err
 }
+
+func (s *spyCliToHubClient) StatusAgents(
+	ctx context.Context,
+	request *pb.StatusAgentsRequest,
+	opts ...grpc.CallOption,
+) (*pb.StatusAgentsReply, error) {
+
+	return s.statusAgentsReply, s.err
+}
+
+func (s *spyCliToHubClient) WatchUpgradeStatus(
+	ctx context.Context,
+	request *pb.WatchUpgradeStatusRequest,
+	opts ...grpc.CallOption,
+) (pb.CliToHub_WatchUpgradeStatusClient, error) {
+
+	s.watchUpgradeStatusCount++
+	return s.watchUpgradeStatusStream, s.err
+}
+
+type spyWatchUpgradeStatusClient struct {
+	grpc.ClientStream
+
+	statuses []*pb.UpgradeStepStatus
+	err      error
+}
+
+// Recv hands out the canned statuses in order, then the canned error (or EOF).
+func (s *spyWatchUpgradeStatusClient) Recv() (*pb.UpgradeStepStatus, error) {
+	if len(s.statuses) == 0 {
+		if s.err != nil {
+			return nil, s.err
+		}
+		return nil, io.EOF
+	}
+
+	status := s.statuses[0]
+	s.statuses = s.statuses[1:]
+	return status, nil
+}
@@ -23,61 +23,86 @@ func (u *Upgrader) ConvertMaster(oldDataDir string, oldBinDir string, newDataDir
 		NewDataDir: newDataDir,
 		NewBinDir:  newBinDir,
 	}
-	_, err := u.client.UpgradeConvertMaster(context.Background(), &upgradeConvertMasterRequest)
+	reply, err := u.client.UpgradeConvertMaster(context.Background(), &upgradeConvertMasterRequest)
 	if err != nil {
+		err = fromHubError(err)
+		if _, ok := err.(UnmetPrerequisitesError); ok {
+			return err
+		}
+
 		// TODO: Change the logging message?
 		gplog.Error("ERROR - Unable to connect to hub")
 		return err
 	}
 
 	gplog.Info("Kicked off pg_upgrade request.")
-	return nil
+	return emitReply(reply)
 }
 
 func (u *Upgrader) ConvertPrimaries(oldBinDir, newBinDir string) error {
-	_, err := u.client.UpgradeConvertPrimaries(context.Background(), &pb.UpgradeConvertPrimariesRequest{
+	reply, err := u.client.UpgradeConvertPrimaries(context.Background(), &pb.UpgradeConvertPrimariesRequest{
 		OldBinDir: oldBinDir,
 		NewBinDir: newBinDir,
 	})
 	if err != nil {
+		err = fromHubError(err)
 		// TODO: Change the logging message?
 		gplog.Error("Error when calling hub upgrade convert primaries: %v", err.Error())
 		return err
 	}
 
 	gplog.Info("Kicked off pg_upgrade request for primaries")
-	return nil
+	return emitReply(reply)
 }
 
 func (u *Upgrader) ShareOids() error {
-	_, err := u.client.UpgradeShareOids(context.Background(), &pb.UpgradeShareOidsRequest{})
+	reply, err := u.client.UpgradeShareOids(context.Background(), &pb.UpgradeShareOidsRequest{})
 	if err != nil {
+		err = fromHubError(err)
 		gplog.Error(err.Error())
 		return err
 	}
 
 	gplog.Info("Kicked off request to share oids")
-	return nil
+	return emitReply(reply)
 }
 
 func (u *Upgrader) ValidateStartCluster() error {
-	_, err := u.client.UpgradeValidateStartCluster(context.Background(), &pb.UpgradeValidateStartClusterRequest{})
+	reply, err := u.client.UpgradeValidateStartCluster(context.Background(), &pb.UpgradeValidateStartClusterRequest{})
 	if err != nil {
+		err = fromHubError(err)
 		gplog.Error(err.Error())
 		return err
 	}
 
 	gplog.Info("Kicked off request for validation of cluster startup")
-	return nil
+	return emitReply(reply)
 }
 
 func (u *Upgrader) ReconfigurePorts() error {
-	_, err := u.client.UpgradeReconfigurePorts(context.Background(), &pb.UpgradeReconfigurePortsRequest{})
+	reply, err := u.client.UpgradeReconfigurePorts(context.Background(), &pb.UpgradeReconfigurePortsRequest{})
 	if err != nil {
+		err = fromHubError(err)
 		gplog.Error(err.Error())
 		return err
 	}
 
 	gplog.Info("Request to reconfigure master port on upgraded cluster complete")
-	return nil
+	return emitReply(reply)
+}
+
+func (u *Upgrader) Run(oldDbPort int, oldBinDir string, newDbPort int, newBinDir string) error {
+	reply, err := u.client.UpgradeRun(context.Background(), &pb.UpgradeRunRequest{
+		OldDbPort: int32(oldDbPort),
+		OldBinDir: oldBinDir,
+		NewDbPort: int32(newDbPort),
+		NewBinDir: newBinDir,
+	})
+	if err != nil {
+		gplog.Error("Error when calling hub upgrade run: %v", err.Error())
+		return err
+	}
+
+	gplog.Info("Kicked off upgrade run. Use command \"gpupgrade status upgrade\" to follow its progress.")
+	return emitReply(reply)
 }
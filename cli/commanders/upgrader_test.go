@@ -15,6 +15,8 @@ import (
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	"github.com/onsi/gomega/gbytes"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 var _ = Describe("reporter", func() {
@@ -64,6 +66,17 @@ var _ = Describe("reporter", func() {
 			Eventually(testStderr).Should(gbytes.Say("ERROR - Unable to connect to hub"))
 
 		})
+
+		It("reports the missing steps when the hub refuses to run pg_upgrade", func() {
+			client.EXPECT().UpgradeConvertMaster(
+				gomock.Any(),
+				&pb.UpgradeConvertMasterRequest{},
+			).Return(&pb.UpgradeConvertMasterReply{}, status.Error(codes.FailedPrecondition,
+				"pg_upgrade cannot run until the following steps have completed: shutdown-clusters"))
+			err := commanders.NewUpgrader(client).ConvertMaster("", "", "", "")
+			Expect(err).To(BeAssignableToTypeOf(commanders.UnmetPrerequisitesError{}))
+			Expect(testStderr).ToNot(gbytes.Say("Unable to connect to hub"))
+		})
 	})
 
 	Describe("ConvertPrimaries", func() {
@@ -99,6 +112,15 @@ var _ = Describe("reporter", func() {
 			err := upgrader.ShareOids()
 			Expect(err).To(HaveOccurred())
 		})
+
+		It("returns the missing steps without gRPC decoration when the hub refuses the request", func() {
+			hubClient.Err = status.Error(codes.FailedPrecondition,
+				"share-oids cannot run until the following steps have completed: pg_upgrade")
+
+			err := upgrader.ShareOids()
+			Expect(err).To(MatchError("share-oids cannot run until the following steps have completed: pg_upgrade. " +
+				"Use command \"gpupgrade status upgrade\" to see the status of each step."))
+		})
 	})
 
 	Describe("ReconfigurePorts", func() {
@@ -116,4 +138,26 @@ var _ = Describe("reporter", func() {
 			Expect(err).To(HaveOccurred())
 		})
 	})
+
+	Describe("Run", func() {
+		It("passes the ports and bindirs for both clusters to the hub", func() {
+			err := upgrader.Run(15432, "/old/bin", 25432, "/new/bin")
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(hubClient.UpgradeRunRequest).To(Equal(&pb.UpgradeRunRequest{
+				OldDbPort: 15432,
+				OldBinDir: "/old/bin",
+				NewDbPort: 25432,
+				NewBinDir: "/new/bin",
+			}))
+			Eventually(testStdout).Should(gbytes.Say("Kicked off upgrade run"))
+		})
+
+		It("returns an error when the hub can't start the run", func() {
+			hubClient.Err = errors.New("an upgrade run is already in progress")
+
+			err := upgrader.Run(15432, "/old/bin", 25432, "/new/bin")
+			Expect(err).To(HaveOccurred())
+		})
+	})
 })
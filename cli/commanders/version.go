@@ -1,5 +1,11 @@
 package commanders
 
+import (
+	"fmt"
+
+	pb "github.com/greenplum-db/gpupgrade/idl"
+)
+
 // This global var UpgradeVersion should have a value set at build time.
 // see Makefile for -ldflags "-X etc"
 var UpgradeVersion = ""
@@ -14,3 +20,14 @@ func VersionString() string {
 	}
 	return "gpupgrade version " + UpgradeVersion
 }
+
+// PrintVersion reports the version of gpupgrade, either as text or, if a
+// machine-readable format was selected, as a VersionReply.
+func PrintVersion() error {
+	if IsMachineReadable() {
+		return emitReply(&pb.VersionReply{Version: VersionString()})
+	}
+
+	fmt.Println(VersionString())
+	return nil
+}
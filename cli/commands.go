@@ -1,7 +1,7 @@
 package main
 
 import (
-	"fmt"
+	"errors"
 	"os"
 
 	"github.com/greenplum-db/gpupgrade/cli/commanders"
@@ -10,15 +10,32 @@ import (
 
 	"github.com/greenplum-db/gp-common-go-libs/gplog"
 	"github.com/spf13/cobra"
-	"google.golang.org/grpc"
 )
 
 var masterHost string
 var dbPort int
 var newClusterDbPort int
+var watch bool
 var oldDataDir, oldBinDir, newDataDir, newBinDir string
+var outputFormat string
+var logsHost string
+var logsContent int
+var follow bool
+
+var root = &cobra.Command{
+	Use: "gpupgrade",
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		err := commanders.SetOutputFormat(outputFormat)
+		if err != nil {
+			return err
+		}
 
-var root = &cobra.Command{Use: "gpupgrade"}
+		if commanders.IsMachineReadable() {
+			logToStderr()
+		}
+		return nil
+	},
+}
 
 var prepare = &cobra.Command{
 	Use:   "prepare",
@@ -43,7 +60,11 @@ var version = &cobra.Command{
 	Short: "Version of gpupgrade",
 	Long:  `Version of gpupgrade`,
 	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Println(commanders.VersionString())
+		err := commanders.PrintVersion()
+		if err != nil {
+			gplog.Error(err.Error())
+			os.Exit(1)
+		}
 	},
 }
 
@@ -65,7 +86,7 @@ var subStartHub = &cobra.Command{
 			os.Exit(1)
 		}
 
-		conn, connConfigErr := grpc.Dial("localhost:"+hubPort, grpc.WithInsecure())
+		conn, connConfigErr := dialHub()
 		if connConfigErr != nil {
 			gplog.Error(connConfigErr.Error())
 			os.Exit(1)
@@ -86,7 +107,7 @@ var subShutdownClusters = &cobra.Command{
 	Short: "shuts down both old and new cluster",
 	Long:  "Current assumptions is both clusters exist.",
 	Run: func(cmd *cobra.Command, args []string) {
-		conn, connConfigErr := grpc.Dial("localhost:"+hubPort, grpc.WithInsecure())
+		conn, connConfigErr := dialHub()
 		if connConfigErr != nil {
 			gplog.Error(connConfigErr.Error())
 			os.Exit(1)
@@ -106,7 +127,7 @@ var subStartAgents = &cobra.Command{
 	Short: "start agents on segment hosts",
 	Long:  "start agents on all segments",
 	Run: func(cmd *cobra.Command, args []string) {
-		conn, connConfigErr := grpc.Dial("localhost:"+hubPort, grpc.WithInsecure())
+		conn, connConfigErr := dialHub()
 		if connConfigErr != nil {
 			gplog.Error(connConfigErr.Error())
 			os.Exit(1)
@@ -121,12 +142,52 @@ var subStartAgents = &cobra.Command{
 	},
 }
 
+var subStopAgents = &cobra.Command{
+	Use:   "stop-agents",
+	Short: "stop agents on segment hosts",
+	Long:  "stop agents on all segments, reporting the hosts that confirmed shutdown",
+	Run: func(cmd *cobra.Command, args []string) {
+		conn, connConfigErr := dialHub()
+		if connConfigErr != nil {
+			gplog.Error(connConfigErr.Error())
+			os.Exit(1)
+		}
+		client := pb.NewCliToHubClient(conn)
+		preparer := commanders.NewPreparer(client)
+		err := preparer.StopAgents()
+		if err != nil {
+			gplog.Error(err.Error())
+			os.Exit(1)
+		}
+	},
+}
+
+var subStopHub = &cobra.Command{
+	Use:   "stop-hub",
+	Short: "stops the hub",
+	Long:  "stops the hub once it has finished the requests it is working on",
+	Run: func(cmd *cobra.Command, args []string) {
+		conn, connConfigErr := dialHub()
+		if connConfigErr != nil {
+			gplog.Error(connConfigErr.Error())
+			os.Exit(1)
+		}
+		client := pb.NewCliToHubClient(conn)
+		preparer := commanders.NewPreparer(client)
+		err := preparer.StopHub()
+		if err != nil {
+			gplog.Error(err.Error())
+			os.Exit(1)
+		}
+	},
+}
+
 var subInitCluster = &cobra.Command{
 	Use:   "init-cluster",
 	Short: "inits the cluster",
 	Long:  "Current assumptions is that the cluster already exists. And will only generate json config file for now.",
 	Run: func(cmd *cobra.Command, args []string) {
-		conn, connConfigErr := grpc.Dial("localhost:"+hubPort, grpc.WithInsecure())
+		conn, connConfigErr := dialHub()
 		if connConfigErr != nil {
 			gplog.Error(connConfigErr.Error())
 			os.Exit(1)
@@ -146,14 +207,19 @@ var subUpgrade = &cobra.Command{
 	Short: "the status of the upgrade",
 	Long:  "the status of the upgrade",
 	Run: func(cmd *cobra.Command, args []string) {
-		conn, connConfigErr := grpc.Dial("localhost:"+hubPort, grpc.WithInsecure())
+		conn, connConfigErr := dialHub()
 		if connConfigErr != nil {
 			gplog.Error(connConfigErr.Error())
 			os.Exit(1)
 		}
 		client := pb.NewCliToHubClient(conn)
 		reporter := commanders.NewReporter(client)
-		err := reporter.OverallUpgradeStatus()
+		var err error
+		if watch {
+			err = reporter.WatchUpgradeStatus()
+		} else {
+			err = reporter.OverallUpgradeStatus()
+		}
 		if err != nil {
 			gplog.Error(err.Error())
 			os.Exit(1)
@@ -167,8 +233,7 @@ var subVersion = &cobra.Command{
 	Long:    `validate current version is upgradable`,
 	Aliases: []string{"ver"},
 	RunE: func(cmd *cobra.Command, args []string) error {
-		conn, connConfigErr := grpc.Dial("localhost:"+hubPort,
-			grpc.WithInsecure())
+		conn, connConfigErr := dialHub()
 		if connConfigErr != nil {
 			gplog.Error(connConfigErr.Error())
 			os.Exit(1)
@@ -178,16 +243,46 @@ var subVersion = &cobra.Command{
 	},
 }
 
+var subAll = &cobra.Command{
+	Use:   "all",
+	Short: "run every pre-upgrade check",
+	Long: "Run every pre-upgrade check at once and report what each one found. " +
+		"The upgrade cannot proceed until the checks of error severity pass.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		conn, connConfigErr := dialHub()
+		if connConfigErr != nil {
+			gplog.Error(connConfigErr.Error())
+			os.Exit(1)
+		}
+		client := pb.NewCliToHubClient(conn)
+		return commanders.NewAllChecker(client).Execute(masterHost, dbPort)
+	},
+}
+
+var subAgents = &cobra.Command{
+	Use:   "agents",
+	Short: "check that the agents on all segment hosts are reachable",
+	Long:  "ping the agent on every segment host, and report its latency and version",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		conn, connConfigErr := dialHub()
+		if connConfigErr != nil {
+			gplog.Error(connConfigErr.Error())
+			os.Exit(1)
+		}
+		client := pb.NewCliToHubClient(conn)
+		return commanders.NewAgentsChecker(client).Execute()
+	},
+}
+
 var subObjectCount = &cobra.Command{
 	Use:     "object-count",
 	Short:   "count database objects and numeric objects",
 	Long:    "count database objects and numeric objects",
 	Aliases: []string{"oc"},
 	RunE: func(cmd *cobra.Command, args []string) error {
-		conn, connConfigErr := grpc.Dial("localhost:"+hubPort,
-			grpc.WithInsecure())
+		conn, connConfigErr := dialHub()
 		if connConfigErr != nil {
-			fmt.Println(connConfigErr)
+			gplog.Error(connConfigErr.Error())
 			os.Exit(1)
 		}
 		client := pb.NewCliToHubClient(conn)
@@ -197,12 +292,11 @@ var subObjectCount = &cobra.Command{
 
 var subDiskSpace = &cobra.Command{
 	Use:     "disk-space",
-	Short:   "check that disk space usage is less than 80% on all segments",
-	Long:    "check that disk space usage is less than 80% on all segments",
+	Short:   "check that each segment's new data dir has room for pg_upgrade",
+	Long:    "check that the filesystem of each primary segment's new data dir has room for what pg_upgrade will write there, in the configured upgradeMode",
 	Aliases: []string{"du"},
 	RunE: func(cmd *cobra.Command, args []string) error {
-		conn, connConfigErr := grpc.Dial("localhost:"+hubPort,
-			grpc.WithInsecure())
+		conn, connConfigErr := dialHub()
 		if connConfigErr != nil {
 			gplog.Error(connConfigErr.Error())
 			os.Exit(1)
@@ -217,7 +311,7 @@ var subConversion = &cobra.Command{
 	Short: "the status of the conversion",
 	Long:  "the status of the conversion",
 	Run: func(cmd *cobra.Command, args []string) {
-		conn, connConfigErr := grpc.Dial("localhost:"+hubPort, grpc.WithInsecure())
+		conn, connConfigErr := dialHub()
 		if connConfigErr != nil {
 			gplog.Error(connConfigErr.Error())
 			os.Exit(1)
@@ -232,13 +326,32 @@ var subConversion = &cobra.Command{
 	},
 }
 
+var subAgentsStatus = &cobra.Command{
+	Use:   "agents",
+	Short: "the health of the agents",
+	Long:  "the health of the agent on each segment host, from its registration and heartbeats",
+	Run: func(cmd *cobra.Command, args []string) {
+		conn, connConfigErr := dialHub()
+		if connConfigErr != nil {
+			gplog.Error(connConfigErr.Error())
+			os.Exit(1)
+		}
+		client := pb.NewCliToHubClient(conn)
+		reporter := commanders.NewReporter(client)
+		err := reporter.AgentsStatus()
+		if err != nil {
+			gplog.Error(err.Error())
+			os.Exit(1)
+		}
+	},
+}
+
 var subConfig = &cobra.Command{
 	Use:   "config",
 	Short: "gather cluster configuration",
 	Long:  "gather cluster configuration",
 	Run: func(cmd *cobra.Command, args []string) {
-		conn, connConfigErr := grpc.Dial("localhost:"+hubPort,
-			grpc.WithInsecure())
+		conn, connConfigErr := dialHub()
 		if connConfigErr != nil {
 			gplog.Error(connConfigErr.Error())
 			os.Exit(1)
@@ -258,7 +371,7 @@ var subSeginstall = &cobra.Command{
 	Long: "Running this command will validate that the new software is installed on all segments, " +
 		"and register successful or failed validation (available in `gpupgrade status upgrade`)",
 	Run: func(cmd *cobra.Command, args []string) {
-		conn, connConfigErr := grpc.Dial("localhost:"+hubPort, grpc.WithInsecure())
+		conn, connConfigErr := dialHub()
 		if connConfigErr != nil {
 			gplog.Error(connConfigErr.Error())
 			os.Exit(1)
@@ -271,7 +384,7 @@ var subSeginstall = &cobra.Command{
 			os.Exit(1)
 		}
 
-		fmt.Println("Seginstall is underway. Use command \"gpupgrade status upgrade\" " +
+		gplog.Info("Seginstall is underway. Use command \"gpupgrade status upgrade\" " +
 			"to check its current status, and/or hub logs for possible errors.")
 	},
 }
@@ -281,8 +394,7 @@ var subConvertMaster = &cobra.Command{
 	Short: "start upgrade process on master",
 	Long:  `start upgrade process on master`,
 	Run: func(cmd *cobra.Command, args []string) {
-		conn, connConfigErr := grpc.Dial("localhost:"+hubPort,
-			grpc.WithInsecure())
+		conn, connConfigErr := dialHub()
 		if connConfigErr != nil {
 			gplog.Error(connConfigErr.Error())
 			os.Exit(1)
@@ -302,8 +414,7 @@ var subConvertPrimaries = &cobra.Command{
 	Short: "start upgrade process on primary segments",
 	Long:  `start upgrade process on primary segments`,
 	Run: func(cmd *cobra.Command, args []string) {
-		conn, connConfigErr := grpc.Dial("localhost:"+hubPort,
-			grpc.WithInsecure())
+		conn, connConfigErr := dialHub()
 		if connConfigErr != nil {
 			gplog.Error(connConfigErr.Error())
 			os.Exit(1)
@@ -323,8 +434,7 @@ var subShareOids = &cobra.Command{
 	Short: "share oid files across cluster",
 	Long:  `share oid files generated by pg_upgrade on master, across cluster`,
 	Run: func(cmd *cobra.Command, args []string) {
-		conn, connConfigErr := grpc.Dial("localhost:"+hubPort,
-			grpc.WithInsecure())
+		conn, connConfigErr := dialHub()
 		if connConfigErr != nil {
 			gplog.Error(connConfigErr.Error())
 			os.Exit(1)
@@ -344,8 +454,7 @@ var subValidateStartCluster = &cobra.Command{
 	Short: "Attempt to start upgraded cluster",
 	Long:  `Use gpstart in order to validate that the new cluster can successfully transition from a stopped to running state`,
 	Run: func(cmd *cobra.Command, args []string) {
-		conn, connConfigErr := grpc.Dial("localhost:"+hubPort,
-			grpc.WithInsecure())
+		conn, connConfigErr := dialHub()
 		if connConfigErr != nil {
 			gplog.Error(connConfigErr.Error())
 			os.Exit(1)
@@ -365,8 +474,7 @@ var subReconfigurePorts = &cobra.Command{
 	Short: "Set master port on upgraded cluster to the value from the older cluster",
 	Long:  `Set master port on upgraded cluster to the value from the older cluster`,
 	Run: func(cmd *cobra.Command, args []string) {
-		conn, connConfigErr := grpc.Dial("localhost:"+hubPort,
-			grpc.WithInsecure())
+		conn, connConfigErr := dialHub()
 		if connConfigErr != nil {
 			gplog.Error(connConfigErr.Error())
 			os.Exit(1)
@@ -381,6 +489,107 @@ var subReconfigurePorts = &cobra.Command{
 	},
 }
 
+var subRun = &cobra.Command{
+	Use:   "run",
+	Short: "run every remaining upgrade step in order",
+	Long: "Run every upgrade step, in order, on the hub. Steps that have already completed are skipped, " +
+		"so after fixing a failure this command can be run again to resume the upgrade. " +
+		"Until the clusters have been shut down, it refuses to start unless gpupgrade check all has passed.",
+	Run: func(cmd *cobra.Command, args []string) {
+		conn, connConfigErr := dialHub()
+		if connConfigErr != nil {
+			gplog.Error(connConfigErr.Error())
+			os.Exit(1)
+		}
+
+		client := pb.NewCliToHubClient(conn)
+		err := commanders.NewUpgrader(client).Run(dbPort, oldBinDir, newClusterDbPort, newBinDir)
+		if err == nil && watch {
+			err = commanders.NewReporter(client).WatchUpgradeStatus()
+		}
+		if err != nil {
+			gplog.Error(err.Error())
+			os.Exit(1)
+		}
+	},
+}
+
+var revert = &cobra.Command{
+	Use:   "revert",
+	Short: "roll back to the old cluster",
+	Long: "Stop the new cluster, undo the changes made to it during the upgrade (deleting its data directories " +
+		"if pg_upgrade left them half-converted), and start the old cluster again. " +
+		"It is safe to run this command again if it fails.",
+	Run: func(cmd *cobra.Command, args []string) {
+		conn, connConfigErr := dialHub()
+		if connConfigErr != nil {
+			gplog.Error(connConfigErr.Error())
+			os.Exit(1)
+		}
+
+		client := pb.NewCliToHubClient(conn)
+		err := commanders.NewReverter(client).Revert()
+		if err != nil {
+			gplog.Error(err.Error())
+			os.Exit(1)
+		}
+	},
+}
+
+var cancel = &cobra.Command{
+	Use:   "cancel",
+	Short: "stop the upgrade steps that are running",
+	Long: "Stop the pg_upgrade, gpstop and gpstart processes started by the upgrade steps that are running, " +
+		"on the master and on the segment hosts, along with any servers pg_upgrade left running. " +
+		"The steps that are stopped are marked as failed, and can be run again.",
+	Run: func(cmd *cobra.Command, args []string) {
+		conn, connConfigErr := dialHub()
+		if connConfigErr != nil {
+			gplog.Error(connConfigErr.Error())
+			os.Exit(1)
+		}
+
+		client := pb.NewCliToHubClient(conn)
+		err := commanders.NewCanceller(client).Cancel()
+		if err != nil {
+			gplog.Error(err.Error())
+			os.Exit(1)
+		}
+	},
+}
+
+var logs = &cobra.Command{
+	Use:   "logs",
+	Short: "show the logs of the hub, an agent or pg_upgrade",
+	Long: "Show the hub's log. With --host, show the log of the agent on that host instead, or with --content, " +
+		"the logs that pg_upgrade wrote while converting that segment (-1 is the master). " +
+		"With --follow, keep showing the output as it is written.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		request := &pb.LogsRequest{Source: pb.LogSource_HUB, Follow: follow}
+		switch {
+		case cmd.Flags().Changed("host") && cmd.Flags().Changed("content"):
+			return errors.New("--host and --content cannot be used together")
+		case cmd.Flags().Changed("host"):
+			request.Source = pb.LogSource_AGENT
+			request.Hostname = logsHost
+		case cmd.Flags().Changed("content"):
+			request.Source = pb.LogSource_PG_UPGRADE
+			request.Content = int32(logsContent)
+		}
+
+		// The arguments are fine, so don't dump the usage on failure.
+		cmd.SilenceUsage = true
+
+		conn, connConfigErr := dialHub()
+		if connConfigErr != nil {
+			gplog.Error(connConfigErr.Error())
+			os.Exit(1)
+		}
+		client := pb.NewCliToHubClient(conn)
+		return commanders.NewLogsReader(client, os.Stdout).Execute(request)
+	},
+}
+
 var subInit = &cobra.Command{
 	Use:   "init",
 	Short: "Setup state dir and config file",
@@ -3,57 +3,126 @@ package main
 import (
 	"fmt"
 	"log"
+	"net"
 	"os"
 	"runtime/debug"
+	"strconv"
+	"time"
+
+	"github.com/greenplum-db/gpupgrade/certs"
+	"github.com/greenplum-db/gpupgrade/cli/commanders"
+	"github.com/greenplum-db/gpupgrade/config"
+	"github.com/greenplum-db/gpupgrade/hub/services"
+	"github.com/greenplum-db/gpupgrade/utils"
 
 	"github.com/greenplum-db/gp-common-go-libs/gplog"
 	_ "github.com/lib/pq"
+	"google.golang.org/grpc"
 )
 
+// The configuration in the state dir is only loaded once a command needs it,
+// and only the commands that dial the hub fail if it can't be, so that a
+// broken file doesn't keep version or help from working.
 var (
-	hubPort = "7527"
+	loadedConfig *config.Config
+	configErr    error
 )
 
-func main() {
-	upgradePort := os.Getenv("GPUPGRADE_HUB_PORT")
-	if upgradePort != "" {
-		hubPort = upgradePort
+func cliConfig() (*config.Config, error) {
+	if loadedConfig == nil && configErr == nil {
+		loadedConfig, configErr = config.Load(utils.GetStateDir())
 	}
+	return loadedConfig, configErr
+}
 
-	setUpLogging()
+func main() {
+	// Without a configuration, the CLI logs to the default directory.
+	logDir := ""
+	if conf, err := cliConfig(); err == nil {
+		logDir = conf.LogDir
+	}
+	setUpLogging(logDir)
 
 	addFlagOptions()
 
 	confirmValidCommand()
 
-	root.AddCommand(prepare, status, check, version, upgrade)
+	root.AddCommand(prepare, status, check, version, upgrade, revert, cancel, logs)
 
-	prepare.AddCommand(subStartHub, subInitCluster, subShutdownClusters, subStartAgents, subInit)
-	status.AddCommand(subUpgrade, subConversion)
-	check.AddCommand(subVersion, subObjectCount, subDiskSpace, subConfig, subSeginstall)
-	upgrade.AddCommand(subConvertMaster, subConvertPrimaries, subShareOids, subValidateStartCluster, subReconfigurePorts)
+	prepare.AddCommand(subStartHub, subStopHub, subInitCluster, subShutdownClusters, subStartAgents, subStopAgents, subInit)
+	status.AddCommand(subUpgrade, subConversion, subAgentsStatus)
+	check.AddCommand(subVersion, subObjectCount, subDiskSpace, subConfig, subSeginstall, subAgents, subAll)
+	upgrade.AddCommand(subConvertMaster, subConvertPrimaries, subShareOids, subValidateStartCluster, subReconfigurePorts, subRun)
 
 	err := root.Execute()
 	if err != nil {
+		out := os.Stdout
+		if commanders.IsMachineReadable() {
+			out = os.Stderr
+		}
 		// Use v to print the stack trace of an object errors.
-		fmt.Printf("%+v\n", err)
+		fmt.Fprintf(out, "%+v\n", err)
 		os.Exit(1)
 	}
 }
 
 func confirmValidCommand() {
 	if len(os.Args[1:]) < 1 {
-		log.Fatal("Please specify one command of: check, prepare, status, upgrade, or version")
+		log.Fatal("Please specify one command of: cancel, check, logs, prepare, revert, status, upgrade, or version")
 	}
 }
 
-func setUpLogging() {
+func setUpLogging(logdir string) {
 	debug.SetTraceback("all")
 	//empty logdir defaults to ~/gpAdminLogs
-	gplog.InitializeLogging("gpupgrade_cli", "")
+	gplog.InitializeLogging("gpupgrade_cli", logdir)
+}
+
+// logToStderr moves the console output of the logger to stderr, so that only
+// the documents written for --format end up on stdout.
+func logToStderr() {
+	logFileName := gplog.GetLogFilePath()
+	logFile, err := os.OpenFile(logFileName, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		gplog.Fatal(err, "could not reopen log file %s", logFileName)
+	}
+
+	gplog.SetLogger(gplog.NewLogger(os.Stderr, os.Stderr, logFile, logFileName, gplog.GetVerbosity(), "gpupgrade_cli"))
+}
+
+// dialHub connects to the hub with the CLI's certificate from the state dir.
+// The hub is reached over its socket in the state dir, unless a hub port has
+// been configured.
+func dialHub() (*grpc.ClientConn, error) {
+	conf, err := cliConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	stateDir := utils.GetStateDir()
+	creds, err := certs.ClientCredentials(stateDir, certs.CLI)
+	if err != nil {
+		return nil, err
+	}
+
+	if conf.HubPort != 0 {
+		return grpc.Dial(net.JoinHostPort("localhost", strconv.Itoa(conf.HubPort)), grpc.WithTransportCredentials(creds))
+	}
+
+	// The hub's certificate names localhost rather than the socket.
+	err = creds.OverrideServerName("localhost")
+	if err != nil {
+		return nil, err
+	}
+
+	return grpc.Dial(services.SocketPath(stateDir), grpc.WithTransportCredentials(creds),
+		grpc.WithDialer(func(path string, timeout time.Duration) (net.Conn, error) {
+			return net.DialTimeout("unix", path, timeout)
+		}))
 }
 
 func addFlagOptions() {
+	addFlagOptionsToRoot()
 	addFlagOptionsToShutdownClusters()
 	addFlagOptionsToInitCluster()
 	addFlagOptionsToCheck()
@@ -62,6 +131,14 @@ func addFlagOptions() {
 	addFlagOptionsToConvertPrimaries()
 	addFlagOptionsToConfig()
 	addFlagOptionsToInit()
+	addFlagOptionsToStatusUpgrade()
+	addFlagOptionsToRun()
+	addFlagOptionsToLogs()
+}
+
+func addFlagOptionsToRoot() {
+	root.PersistentFlags().StringVar(&outputFormat, "format", commanders.FormatText,
+		"output format: text for humans, or json or yaml to write each reply from the hub to stdout, with logs on stderr")
 }
 
 func addFlagOptionsToConvertMaster() {
@@ -110,7 +187,29 @@ func addFlagOptionsToShutdownClusters() {
 func addFlagOptionsToValidateStartCluster() {
 }
 
+func addFlagOptionsToStatusUpgrade() {
+	subUpgrade.Flags().BoolVar(&watch, "watch", false, "keep reporting step status changes until the upgrade completes or a step fails")
+}
+
 func addFlagOptionsToInit() {
 	subInit.PersistentFlags().StringVar(&oldBinDir, "old-bindir", "", "install directory for old gpdb version")
 	subInit.MarkPersistentFlagRequired("old-bindir")
 }
+
+func addFlagOptionsToRun() {
+	subRun.Flags().IntVar(&dbPort, "old-port", 0, "port for Greenplum on old master")
+	subRun.MarkFlagRequired("old-port")
+	subRun.Flags().StringVar(&oldBinDir, "old-bindir", "", "install directory for old gpdb version")
+	subRun.MarkFlagRequired("old-bindir")
+	subRun.Flags().IntVar(&newClusterDbPort, "new-port", -1, "port for Greenplum on new master")
+	subRun.MarkFlagRequired("new-port")
+	subRun.Flags().StringVar(&newBinDir, "new-bindir", "", "install directory for new gpdb version")
+	subRun.MarkFlagRequired("new-bindir")
+	subRun.Flags().BoolVar(&watch, "watch", false, "report step status changes until the upgrade completes or a step fails")
+}
+
+func addFlagOptionsToLogs() {
+	logs.Flags().StringVar(&logsHost, "host", "", "show the log of the agent on this host")
+	logs.Flags().IntVar(&logsContent, "content", -1, "show the pg_upgrade logs of the segment with this content ID; -1 is the master")
+	logs.Flags().BoolVarP(&follow, "follow", "f", false, "keep showing output as it is written, until interrupted")
+}
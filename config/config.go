@@ -0,0 +1,206 @@
+// Package config reads and writes the gpupgrade configuration file, which
+// the CLI, hub and agents all load from their state dirs.
+//
+// prepare init writes the file with the defaults below, and it may be edited
+// before the hub is started. The hub copies it to every segment host when it
+// starts the agents. Settings left out of the file keep their defaults, and
+// the environment and command line flags override the file.
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/greenplum-db/gpupgrade/utils"
+)
+
+// FileName is the name of the configuration file in the state dir.
+const FileName = "gpupgrade_config.json"
+
+// The values of RemoteBackend.
+const (
+	BackendSSH   = "ssh"
+	BackendLocal = "local"
+)
+
+// The values of UpgradeMode.
+const (
+	ModeCopy = "copy"
+	ModeLink = "link"
+)
+
+// HubPortEnv overrides the HubPort in the configuration file.
+const HubPortEnv = "GPUPGRADE_HUB_PORT"
+
+type Config struct {
+	// HubPort is the TCP port that the hub serves the CLI on. If it is 0, the
+	// CLI only reaches the hub over the socket in the state dir.
+	HubPort int `json:"hubPort"`
+
+	// HubBindAddress is the address the hub listens on when HubPort is set.
+	HubBindAddress string `json:"hubBindAddress"`
+
+	// AgentPort is the port that the agents serve the hub on.
+	AgentPort int `json:"agentPort"`
+
+	// AgentBindAddress is the address the agents listen on. Empty means every
+	// interface.
+	AgentBindAddress string `json:"agentBindAddress"`
+
+	// RegistrationPort is the port that the hub listens on, on every
+	// interface, for the agents to register and send heartbeats. If it is 0,
+	// the agents don't register.
+	RegistrationPort int `json:"registrationPort"`
+
+	// RemoteBackend is how the hub runs commands on the segment hosts: "ssh",
+	// or "local" for a cluster that lives entirely on the hub's host.
+	RemoteBackend string `json:"remoteBackend"`
+
+	// SSHUser is the user the hub logs in to the segment hosts as. Empty
+	// means the user running the hub.
+	SSHUser string `json:"sshUser"`
+
+	// SSHIdentityFile is the private key the hub logs in with. Empty means
+	// the keys ssh tries by default.
+	SSHIdentityFile string `json:"sshIdentityFile"`
+
+	// SSHKnownHostsFile is where ssh looks up, and records, the keys of the
+	// segment hosts. Empty means ~/.ssh/known_hosts.
+	SSHKnownHostsFile string `json:"sshKnownHostsFile"`
+
+	// SSHStrictHostKeyChecking is passed on to ssh: "yes" only logs in to
+	// hosts whose keys are known, "accept-new" records the keys of hosts it
+	// hasn't seen before, and "no" logs in to any host.
+	SSHStrictHostKeyChecking string `json:"sshStrictHostKeyChecking"`
+
+	// SSHOptions are any further options for ssh, each as given to its -o
+	// flag, such as "ConnectTimeout=10".
+	SSHOptions []string `json:"sshOptions"`
+
+	// LogDir is where the CLI, hub and agents write their logs. Empty means
+	// ~/gpAdminLogs.
+	LogDir string `json:"logDir"`
+
+	// Parallelism is the most hosts that the hub works on at once.
+	Parallelism int `json:"parallelism"`
+
+	// UpgradeMode is how pg_upgrade fills the new data dirs: "copy" copies
+	// the old data files, and "link" hard links them with --link, which needs
+	// far less space but leaves the old cluster unusable once the new one
+	// has been started. check disk-space measures what the mode needs.
+	UpgradeMode string `json:"upgradeMode"`
+
+	// DiskSpaceMarginPercent is how much free space check disk-space wants
+	// left over on each filesystem, as a percentage of what pg_upgrade will
+	// write there, so that the upgrade doesn't fill it to the brim.
+	DiskSpaceMarginPercent int `json:"diskSpaceMarginPercent"`
+}
+
+func Default() *Config {
+	return &Config{
+		HubBindAddress:           "localhost",
+		AgentPort:                6416,
+		RegistrationPort:         6417,
+		RemoteBackend:            BackendSSH,
+		SSHStrictHostKeyChecking: "no",
+		Parallelism:              16,
+		UpgradeMode:              ModeCopy,
+		DiskSpaceMarginPercent:   10,
+	}
+}
+
+// Path returns the path of the configuration file in stateDir.
+func Path(stateDir string) string {
+	return filepath.Join(stateDir, FileName)
+}
+
+// Load reads the configuration file in stateDir, applies the environment on
+// top of it, and validates the result. The defaults are used if the file
+// doesn't exist, as on segment hosts before the agents have been started.
+func Load(stateDir string) (*Config, error) {
+	c := Default()
+
+	path := Path(stateDir)
+	contents, err := utils.System.ReadFile(path)
+	if err != nil && !utils.System.IsNotExist(err) {
+		return nil, err
+	}
+	if err == nil {
+		err = json.Unmarshal(contents, c)
+		if err != nil {
+			return nil, fmt.Errorf("configuration file %s is corrupt: %s", path, err)
+		}
+	}
+
+	if port := utils.System.Getenv(HubPortEnv); port != "" {
+		c.HubPort, err = strconv.Atoi(port)
+		if err != nil {
+			return nil, fmt.Errorf("%s is not a port number: %q", HubPortEnv, port)
+		}
+	}
+
+	err = c.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("configuration file %s is invalid: %s", path, err)
+	}
+
+	return c, nil
+}
+
+// Write saves c as the configuration file in stateDir.
+func Write(stateDir string, c *Config) error {
+	contents, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return utils.System.WriteFile(Path(stateDir), append(contents, '\n'), 0600)
+}
+
+// Validate reports every setting in c that is out of range.
+func (c *Config) Validate() error {
+	var problems []string
+	if c.HubPort < 0 || c.HubPort > 65535 {
+		problems = append(problems, fmt.Sprintf("hubPort %d is not between 0 and 65535", c.HubPort))
+	}
+	if c.AgentPort < 1 || c.AgentPort > 65535 {
+		problems = append(problems, fmt.Sprintf("agentPort %d is not between 1 and 65535", c.AgentPort))
+	}
+	if c.RegistrationPort < 0 || c.RegistrationPort > 65535 {
+		problems = append(problems, fmt.Sprintf("registrationPort %d is not between 0 and 65535", c.RegistrationPort))
+	}
+	if c.HubPort == c.AgentPort {
+		problems = append(problems, fmt.Sprintf("hubPort and agentPort are both %d", c.HubPort))
+	}
+	if c.RegistrationPort != 0 && c.RegistrationPort == c.AgentPort {
+		problems = append(problems, fmt.Sprintf("registrationPort and agentPort are both %d", c.RegistrationPort))
+	}
+	if c.RegistrationPort != 0 && c.RegistrationPort == c.HubPort {
+		problems = append(problems, fmt.Sprintf("registrationPort and hubPort are both %d", c.RegistrationPort))
+	}
+	if c.RemoteBackend != BackendSSH && c.RemoteBackend != BackendLocal {
+		problems = append(problems, fmt.Sprintf("remoteBackend %q is not %q or %q", c.RemoteBackend, BackendSSH, BackendLocal))
+	}
+	switch c.SSHStrictHostKeyChecking {
+	case "yes", "no", "accept-new":
+	default:
+		problems = append(problems, fmt.Sprintf("sshStrictHostKeyChecking %q is not yes, no or accept-new", c.SSHStrictHostKeyChecking))
+	}
+	if c.Parallelism < 1 {
+		problems = append(problems, fmt.Sprintf("parallelism %d is less than 1", c.Parallelism))
+	}
+	if c.UpgradeMode != ModeCopy && c.UpgradeMode != ModeLink {
+		problems = append(problems, fmt.Sprintf("upgradeMode %q is not %q or %q", c.UpgradeMode, ModeCopy, ModeLink))
+	}
+	if c.DiskSpaceMarginPercent < 0 || c.DiskSpaceMarginPercent > 100 {
+		problems = append(problems, fmt.Sprintf("diskSpaceMarginPercent %d is not between 0 and 100", c.DiskSpaceMarginPercent))
+	}
+
+	if len(problems) != 0 {
+		return fmt.Errorf("%s", strings.Join(problems, "; "))
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package config_test
+
+import (
+	"io/ioutil"
+	"os"
+
+	"github.com/greenplum-db/gpupgrade/config"
+	"github.com/greenplum-db/gpupgrade/utils"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("config", func() {
+	var (
+		dir string
+		env map[string]string
+	)
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "")
+		Expect(err).ToNot(HaveOccurred())
+
+		env = map[string]string{}
+		utils.System.Getenv = func(key string) string {
+			return env[key]
+		}
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	writeFile := func(contents string) {
+		err := ioutil.WriteFile(config.Path(dir), []byte(contents), 0600)
+		Expect(err).ToNot(HaveOccurred())
+	}
+
+	It("uses the defaults when there is no configuration file", func() {
+		c, err := config.Load(dir)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(c).To(Equal(config.Default()))
+	})
+
+	It("reads back what was written", func() {
+		written := config.Default()
+		written.HubPort = 7527
+		written.SSHUser = "gpadmin"
+		written.SSHOptions = []string{"ConnectTimeout=10"}
+		written.LogDir = "/var/log/gpupgrade"
+		Expect(config.Write(dir, written)).To(Succeed())
+
+		c, err := config.Load(dir)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(c).To(Equal(written))
+	})
+
+	It("keeps the defaults of settings left out of the file", func() {
+		writeFile(`{"agentPort": 7000}`)
+
+		c, err := config.Load(dir)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(c.AgentPort).To(Equal(7000))
+		Expect(c.Parallelism).To(Equal(config.Default().Parallelism))
+	})
+
+	It("lets the environment override the hub port", func() {
+		writeFile(`{"hubPort": 7527}`)
+		env[config.HubPortEnv] = "8000"
+
+		c, err := config.Load(dir)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(c.HubPort).To(Equal(8000))
+	})
+
+	It("rejects a hub port in the environment that isn't a number", func() {
+		env[config.HubPortEnv] = "hub"
+
+		_, err := config.Load(dir)
+		Expect(err).To(MatchError(ContainSubstring(config.HubPortEnv)))
+	})
+
+	It("reports every invalid setting", func() {
+		writeFile(`{"hubPort": 70000, "parallelism": 0, "upgradeMode": "move", "diskSpaceMarginPercent": -5}`)
+
+		_, err := config.Load(dir)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("hubPort 70000"))
+		Expect(err.Error()).To(ContainSubstring("parallelism 0"))
+		Expect(err.Error()).To(ContainSubstring(`upgradeMode "move"`))
+		Expect(err.Error()).To(ContainSubstring("diskSpaceMarginPercent -5"))
+	})
+
+	It("rejects the same port for the hub and the agents", func() {
+		writeFile(`{"hubPort": 6416}`)
+
+		_, err := config.Load(dir)
+		Expect(err).To(MatchError(ContainSubstring("hubPort and agentPort are both 6416")))
+	})
+
+	It("rejects the same port for registration and the agents", func() {
+		writeFile(`{"registrationPort": 6416}`)
+
+		_, err := config.Load(dir)
+		Expect(err).To(MatchError(ContainSubstring("registrationPort and agentPort are both 6416")))
+	})
+
+	It("rejects unknown remote backends and host key checking", func() {
+		writeFile(`{"remoteBackend": "rsh", "sshStrictHostKeyChecking": "ask"}`)
+
+		_, err := config.Load(dir)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring(`remoteBackend "rsh"`))
+		Expect(err.Error()).To(ContainSubstring(`sshStrictHostKeyChecking "ask"`))
+	})
+
+	It("reports a corrupt file", func() {
+		writeFile(`{"hubPort": `)
+
+		_, err := config.Load(dir)
+		Expect(err).To(MatchError(ContainSubstring("is corrupt")))
+	})
+})
@@ -0,0 +1,138 @@
+package cluster_ssher
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/greenplum-db/gpupgrade/config"
+	"github.com/greenplum-db/gpupgrade/helpers"
+	"github.com/greenplum-db/gpupgrade/utils"
+)
+
+// Backend runs commands on the segment hosts, and copies files to them.
+type Backend interface {
+	// Run runs command, a line for the shell, on host.
+	Run(host string, command string) Result
+
+	// Copy copies the local files sources, which may be glob patterns, to
+	// destination on host, keeping their permissions and modification times.
+	// Directories are copied whole, or just their contents if their name ends
+	// in a slash, as with rsync.
+	Copy(host string, sources []string, destination string) Result
+}
+
+// Result is what became of running a command on a host.
+type Result struct {
+	Host string
+
+	// ExitCode is the exit code of the command, or -1 if it couldn't be run
+	// at all.
+	ExitCode int
+
+	// Output is what the command wrote to stdout and stderr.
+	Output string
+
+	// Err is nil if the command succeeded.
+	Err error
+}
+
+func (r Result) String() string {
+	if r.Err == nil {
+		return r.Host + ": ok"
+	}
+
+	s := fmt.Sprintf("%s: %s", r.Host, r.Err)
+	if r.ExitCode != -1 {
+		s = fmt.Sprintf("%s: exit code %d", r.Host, r.ExitCode)
+	}
+	if output := strings.TrimSpace(r.Output); output != "" {
+		s += ": " + output
+	}
+	return s
+}
+
+// NewBackend returns the backend named by kind, one of the RemoteBackend
+// values in the configuration file.
+func NewBackend(kind string, commandExecer helpers.CommandExecer, conf SSHConfig) (Backend, error) {
+	switch kind {
+	case config.BackendSSH:
+		return NewSSHBackend(commandExecer, conf), nil
+	case config.BackendLocal:
+		return NewLocalBackend(commandExecer), nil
+	default:
+		return nil, fmt.Errorf("unknown remote backend %q", kind)
+	}
+}
+
+// RunOnHosts runs command on each of hosts with backend, on up to parallelism
+// of them at once, and returns the results in the order of hosts.
+func RunOnHosts(backend Backend, hosts []string, command string, parallelism int) []Result {
+	if parallelism < 1 {
+		parallelism = 1
+	}
+
+	results := make([]Result, len(hosts))
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, parallelism)
+	for i, host := range hosts {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func(i int, host string) {
+			defer func() {
+				<-slots
+				wg.Done()
+			}()
+
+			results[i] = backend.Run(host, command)
+		}(i, host)
+	}
+	wg.Wait()
+
+	return results
+}
+
+// Failures returns the results that failed.
+func Failures(results []Result) []Result {
+	var failed []Result
+	for _, result := range results {
+		if result.Err != nil {
+			failed = append(failed, result)
+		}
+	}
+	return failed
+}
+
+// execute runs the command name with args for host and collects its result.
+func execute(commandExecer helpers.CommandExecer, host string, name string, args ...string) Result {
+	output, err := commandExecer(name, args...).CombinedOutput()
+	return Result{
+		Host:     host,
+		ExitCode: utils.ExitCode(err),
+		Output:   string(output),
+		Err:      err,
+	}
+}
+
+// expandSources replaces the glob patterns in sources with the files they
+// match, keeping any trailing slash. It fails if a pattern matches nothing.
+func expandSources(sources []string) ([]string, error) {
+	var expanded []string
+	for _, source := range sources {
+		matches, err := utils.System.FilePathGlob(source)
+		if err != nil {
+			return nil, err
+		}
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("no files match %s", source)
+		}
+
+		if strings.HasSuffix(source, "/") {
+			for i := range matches {
+				matches[i] = strings.TrimSuffix(matches[i], "/") + "/"
+			}
+		}
+		expanded = append(expanded, matches...)
+	}
+	return expanded, nil
+}
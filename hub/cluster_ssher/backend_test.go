@@ -0,0 +1,144 @@
+package cluster_ssher_test
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/greenplum-db/gpupgrade/helpers"
+	"github.com/greenplum-db/gpupgrade/hub/cluster_ssher"
+	"github.com/greenplum-db/gpupgrade/testutils"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("backends", func() {
+	var commandExecer *testutils.FakeCommandExecer
+
+	BeforeEach(func() {
+		commandExecer = &testutils.FakeCommandExecer{}
+		commandExecer.SetOutput(&testutils.FakeCommand{})
+	})
+
+	Describe("ssh", func() {
+		It("logs in with the configured user, key and host key checking", func() {
+			backend := cluster_ssher.NewSSHBackend(commandExecer.Exec, cluster_ssher.SSHConfig{
+				User:                  "gpadmin",
+				IdentityFile:          "/home/gpadmin/.ssh/upgrade_key",
+				KnownHostsFile:        "/home/gpadmin/.ssh/upgrade_hosts",
+				StrictHostKeyChecking: "yes",
+				Options:               []string{"ConnectTimeout=10"},
+			})
+
+			result := backend.Run("sdw1", "ls /tmp")
+			Expect(result.Err).ToNot(HaveOccurred())
+			Expect(result.Host).To(Equal("sdw1"))
+
+			Expect(commandExecer.Calls()).To(Equal([]string{
+				"ssh -o StrictHostKeyChecking=yes -o UserKnownHostsFile=/home/gpadmin/.ssh/upgrade_hosts " +
+					"-i /home/gpadmin/.ssh/upgrade_key -o ConnectTimeout=10 gpadmin@sdw1 ls /tmp",
+			}))
+		})
+
+		It("copies files with rsync over the same ssh", func() {
+			dir, err := ioutil.TempDir("", "")
+			Expect(err).ToNot(HaveOccurred())
+			defer os.RemoveAll(dir)
+			for _, name := range []string{"a_oids.sql", "b_oids.sql"} {
+				Expect(ioutil.WriteFile(filepath.Join(dir, name), nil, 0600)).To(Succeed())
+			}
+
+			backend := cluster_ssher.NewSSHBackend(commandExecer.Exec, cluster_ssher.SSHConfig{})
+			result := backend.Copy("sdw1", []string{filepath.Join(dir, "*_oids.sql")}, "/state")
+			Expect(result.Err).ToNot(HaveOccurred())
+
+			Expect(commandExecer.Command()).To(Equal("rsync"))
+			Expect(commandExecer.Args()).To(Equal([]string{
+				"-rpt", "-e", "'ssh' '-o' 'StrictHostKeyChecking=no'",
+				filepath.Join(dir, "a_oids.sql"), filepath.Join(dir, "b_oids.sql"), "sdw1:/state",
+			}))
+		})
+
+		It("quotes each of the ssh options it gives rsync", func() {
+			dir, err := ioutil.TempDir("", "")
+			Expect(err).ToNot(HaveOccurred())
+			defer os.RemoveAll(dir)
+			Expect(ioutil.WriteFile(filepath.Join(dir, "a_oids.sql"), nil, 0600)).To(Succeed())
+
+			backend := cluster_ssher.NewSSHBackend(commandExecer.Exec, cluster_ssher.SSHConfig{
+				IdentityFile: "/home/gp admin/.ssh/upgrade_key",
+				Options:      []string{"ProxyCommand=ssh -W %h:%p bastion"},
+			})
+			result := backend.Copy("sdw1", []string{filepath.Join(dir, "a_oids.sql")}, "/state")
+			Expect(result.Err).ToNot(HaveOccurred())
+
+			Expect(commandExecer.Args()[2]).To(Equal("'ssh' '-o' 'StrictHostKeyChecking=no' " +
+				"'-i' '/home/gp admin/.ssh/upgrade_key' '-o' 'ProxyCommand=ssh -W %h:%p bastion'"))
+		})
+
+		It("fails to copy files that don't exist", func() {
+			backend := cluster_ssher.NewSSHBackend(commandExecer.Exec, cluster_ssher.SSHConfig{})
+			result := backend.Copy("sdw1", []string{"/does/not/exist/*"}, "/state")
+
+			Expect(result.Err).To(MatchError("no files match /does/not/exist/*"))
+			Expect(result.ExitCode).To(Equal(-1))
+			Expect(commandExecer.Calls()).To(BeEmpty())
+		})
+	})
+
+	Describe("local", func() {
+		var backend *cluster_ssher.LocalBackend
+
+		BeforeEach(func() {
+			backend = cluster_ssher.NewLocalBackend(func(name string, args ...string) helpers.Command {
+				return exec.Command(name, args...)
+			})
+		})
+
+		It("runs commands on this host, and reports their exit codes and output", func() {
+			result := backend.Run("localhost", "echo checking; exit 3")
+
+			Expect(result.Err).To(HaveOccurred())
+			Expect(result.ExitCode).To(Equal(3))
+			Expect(result.Output).To(Equal("checking\n"))
+			Expect(result.String()).To(Equal("localhost: exit code 3: checking"))
+		})
+
+		It("refuses to run commands on other hosts", func() {
+			result := backend.Run("sdw1.invalid", "true")
+
+			Expect(result.Err).To(MatchError(ContainSubstring("sdw1.invalid is not this host")))
+			Expect(result.ExitCode).To(Equal(-1))
+		})
+
+		It("copies files with rsync", func() {
+			source, err := ioutil.TempDir("", "")
+			Expect(err).ToNot(HaveOccurred())
+			defer os.RemoveAll(source)
+
+			backend = cluster_ssher.NewLocalBackend(commandExecer.Exec)
+			result := backend.Copy("localhost", []string{source + "/"}, "/state/certs")
+			Expect(result.Err).ToNot(HaveOccurred())
+
+			Expect(commandExecer.Calls()).To(Equal([]string{"rsync -rpt " + source + "/ /state/certs"}))
+		})
+	})
+
+	It("runs on every host, and returns the results in order", func() {
+		backend := &stubBackend{results: map[string]cluster_ssher.Result{
+			"sdw1": {Host: "sdw1"},
+			"sdw2": {Host: "sdw2", ExitCode: 1},
+			"sdw3": {Host: "sdw3"},
+		}}
+
+		results := cluster_ssher.RunOnHosts(backend, []string{"sdw3", "sdw1", "sdw2"}, "true", 2)
+
+		Expect(results).To(Equal([]cluster_ssher.Result{
+			{Host: "sdw3"},
+			{Host: "sdw1"},
+			{Host: "sdw2", ExitCode: 1},
+		}))
+	})
+})
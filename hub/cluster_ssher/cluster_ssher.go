@@ -4,8 +4,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
-	"github.com/greenplum-db/gpupgrade/helpers"
 	"github.com/greenplum-db/gpupgrade/hub/upgradestatus"
 
 	"github.com/greenplum-db/gp-common-go-libs/gplog"
@@ -14,13 +14,20 @@ import (
 type ClusterSsher struct {
 	checklistWriter ChecklistWriter
 	AgentPinger     AgentPinger
-	commandExecer   helpers.CommandExecer
+	backend         Backend
+
+	// Parallelism is the most hosts that commands are run on at once.
+	Parallelism int
+
+	// HubAddress is passed on to the agents that are started, for them to
+	// register with the hub; empty means they don't.
+	HubAddress string
 }
 
 type ChecklistWriter interface {
 	MarkInProgress(string) error
 	ResetStateDir(string) error
-	MarkFailed(string) error
+	MarkFailed(string, error) error
 	MarkComplete(string) error
 }
 
@@ -28,80 +35,86 @@ type AgentPinger interface {
 	PingPollAgents() error
 }
 
-func NewClusterSsher(cw ChecklistWriter, ap AgentPinger, commandExecer helpers.CommandExecer) *ClusterSsher {
+// NewClusterSsher returns a ClusterSsher that reaches the hosts with backend.
+func NewClusterSsher(cw ChecklistWriter, ap AgentPinger, backend Backend) *ClusterSsher {
 	return &ClusterSsher{
 		checklistWriter: cw,
 		AgentPinger:     ap,
-		commandExecer:   commandExecer,
+		backend:         backend,
 	}
 }
 
 func (c *ClusterSsher) VerifySoftware(hostnames []string) {
 	agentPath := filepath.Join(os.Getenv("GPHOME"), "bin", "gpupgrade_agent")
-	anyFailed := c.remoteExec(hostnames, upgradestatus.SEGINSTALL, []string{"ls", agentPath})
-	handleStatusLogging(c, upgradestatus.SEGINSTALL, anyFailed)
+	err := c.remoteExec(hostnames, upgradestatus.SEGINSTALL, "ls "+agentPath)
+	handleStatusLogging(c, upgradestatus.SEGINSTALL, err)
 }
 
+// Start starts an agent on each of hostnames, and then waits for them all to
+// answer. The step fails with the exit code and output of every host that an
+// agent couldn't be started on.
 func (c *ClusterSsher) Start(hostnames []string) {
 	// ssh -o "StrictHostKeyChecking=no" hostname /path/to/gpupgrade_agent
-	statedir := "start-agents"
 	gphome := os.Getenv("GPHOME")
 	agentPath := filepath.Join(gphome, "bin", "gpupgrade_agent")
 	greenplumPath := filepath.Join(gphome, "greenplum_path.sh")
-	completeCommandString := fmt.Sprintf(`sh -c '. %s ; %s --daemonize'`, greenplumPath, agentPath)
-
-	// FIXME: don't ignore errors here, bubble them up!
-	_ = c.remoteExec(hostnames, statedir, []string{completeCommandString})
+	agentCommand := agentPath + " --daemonize"
+	if c.HubAddress != "" {
+		agentCommand += " --hub-address " + c.HubAddress
+	}
+	completeCommandString := fmt.Sprintf(`sh -c '. %s ; %s'`, greenplumPath, agentCommand)
 
-	//check that all the agents are running
-	var err error
-	err = c.AgentPinger.PingPollAgents()
-	anyFailed := err != nil
-	handleStatusLogging(c, upgradestatus.START_AGENTS, anyFailed)
+	err := c.remoteExec(hostnames, upgradestatus.START_AGENTS, completeCommandString)
+	if err == nil {
+		//check that all the agents are running
+		err = c.AgentPinger.PingPollAgents()
+	}
+	handleStatusLogging(c, upgradestatus.START_AGENTS, err)
 }
 
-func (c *ClusterSsher) remoteExec(hostnames []string, statedir string, command []string) bool {
+// remoteExec runs command on each of hostnames, and returns an error giving
+// the exit code and output of each host it failed on.
+func (c *ClusterSsher) remoteExec(hostnames []string, statedir string, command string) error {
 	err := c.checklistWriter.ResetStateDir(statedir)
 	if err != nil {
 		gplog.Error(err.Error())
 		//For MMVP, return here, but maybe should log more info
-		return true
+		return err
 	}
 	err = c.checklistWriter.MarkInProgress(statedir)
 	if err != nil {
 		gplog.Error(err.Error())
 		//For MMVP, return here, but maybe should log more info
-		return true
+		return err
 	}
 	//default assumption: GPDB is installed on the same path on all hosts in cluster
 	//we're looking for gpupgrade_agent as proof that the new binary is installed
 	//TODO: if this finds nothing, should we err out? do a fallback check based on $GPHOME?
-	var anyFailed = false
-	for _, hostname := range hostnames {
-		sshArgs := []string{"-o", "StrictHostKeyChecking=no", hostname}
-		sshArgs = append(sshArgs, command...)
-		output, err := c.commandExecer("ssh", sshArgs...).CombinedOutput()
-		if err != nil {
-			errText := "Couldn't run %s on %s:"
-			if output != nil {
-				errText += string(output)
-			}
-			gplog.Error(errText, command, hostname)
-			anyFailed = true
-		}
+	failed := Failures(RunOnHosts(c.backend, hostnames, command, c.Parallelism))
+	if len(failed) == 0 {
+		return nil
 	}
-	return anyFailed
+
+	var descriptions []string
+	for _, result := range failed {
+		gplog.Error("Couldn't run %s on %s", command, result)
+		descriptions = append(descriptions, result.String())
+	}
+	return fmt.Errorf("%s failed on %d of %d hosts: %s", statedir, len(failed), len(hostnames),
+		strings.Join(descriptions, "; "))
 }
 
-func handleStatusLogging(c *ClusterSsher, statedir string, anyFailed bool) {
-	if anyFailed {
-		err := c.checklistWriter.MarkFailed(statedir)
+// handleStatusLogging records err as the reason that the step failed, or that
+// the step is complete if err is nil.
+func handleStatusLogging(c *ClusterSsher, statedir string, err error) {
+	if err != nil {
+		err = c.checklistWriter.MarkFailed(statedir, err)
 		if err != nil {
 			gplog.Error(err.Error())
 		}
 		return
 	}
-	err := c.checklistWriter.MarkComplete(statedir)
+	err = c.checklistWriter.MarkComplete(statedir)
 	if err != nil {
 		gplog.Error(err.Error())
 	}
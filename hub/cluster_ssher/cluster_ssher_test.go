@@ -10,6 +10,7 @@ import (
 	. "github.com/onsi/gomega"
 
 	"github.com/greenplum-db/gpupgrade/hub/cluster_ssher"
+	"github.com/greenplum-db/gpupgrade/hub/upgradestatus"
 	"github.com/pkg/errors"
 )
 
@@ -18,6 +19,7 @@ var _ = Describe("ClusterSsher", func() {
 		errChan       chan error
 		outChan       chan []byte
 		commandExecer *testutils.FakeCommandExecer
+		backend       cluster_ssher.Backend
 	)
 	BeforeEach(func() {
 		errChan = make(chan error, 2)
@@ -27,6 +29,7 @@ var _ = Describe("ClusterSsher", func() {
 			Err: errChan,
 			Out: outChan,
 		})
+		backend = cluster_ssher.NewSSHBackend(commandExecer.Exec, cluster_ssher.SSHConfig{})
 	})
 
 	// TODO: The story that is in charge of removing the cluster_ssher
@@ -44,7 +47,7 @@ var _ = Describe("ClusterSsher", func() {
 			errChan <- errors.New("host not found")
 
 			cw := testutils.NewMockChecklistManager()
-			clusterSsher := cluster_ssher.NewClusterSsher(cw, newSpyAgentPinger(), commandExecer.Exec)
+			clusterSsher := cluster_ssher.NewClusterSsher(cw, newSpyAgentPinger(), backend)
 			Expect(cw.IsPending("seginstall")).To(BeTrue())
 			clusterSsher.VerifySoftware([]string{"doesnt matter"})
 
@@ -56,7 +59,7 @@ var _ = Describe("ClusterSsher", func() {
 			outChan <- []byte("completed")
 
 			cw := testutils.NewMockChecklistManager()
-			clusterSsher := cluster_ssher.NewClusterSsher(cw, newSpyAgentPinger(), commandExecer.Exec)
+			clusterSsher := cluster_ssher.NewClusterSsher(cw, newSpyAgentPinger(), backend)
 			Expect(cw.IsPending("seginstall")).To(BeTrue())
 			clusterSsher.VerifySoftware([]string{"doesnt matter"})
 
@@ -66,8 +69,7 @@ var _ = Describe("ClusterSsher", func() {
 				"-o",
 				"StrictHostKeyChecking=no",
 				"doesnt matter",
-				"ls",
-				pathToAgent,
+				"ls " + pathToAgent,
 			}))
 
 			Expect(cw.WasReset("seginstall")).To(BeTrue())
@@ -75,13 +77,33 @@ var _ = Describe("ClusterSsher", func() {
 		})
 	})
 
+	Describe("VerifySoftware failures", func() {
+		It("records the exit code and output of each host that failed", func() {
+			results := map[string]cluster_ssher.Result{
+				"sdw1": {Host: "sdw1"},
+				"sdw2": {Host: "sdw2", ExitCode: 2, Output: "ls: cannot access gpupgrade_agent\n", Err: errors.New("exit status 2")},
+				"sdw3": {Host: "sdw3", ExitCode: 255, Output: "ssh: connect to host sdw3: Connection refused\n", Err: errors.New("exit status 255")},
+			}
+
+			cw := testutils.NewMockChecklistManager()
+			clusterSsher := cluster_ssher.NewClusterSsher(cw, newSpyAgentPinger(), &stubBackend{results: results})
+			clusterSsher.Parallelism = 3
+			clusterSsher.VerifySoftware([]string{"sdw1", "sdw2", "sdw3"})
+
+			Expect(cw.IsFailed(upgradestatus.SEGINSTALL)).To(BeTrue())
+			Expect(cw.FailureCause(upgradestatus.SEGINSTALL)).To(MatchError("check-seginstall failed on 2 of 3 hosts: " +
+				"sdw2: exit code 2: ls: cannot access gpupgrade_agent; " +
+				"sdw3: exit code 255: ssh: connect to host sdw3: Connection refused"))
+		})
+	})
+
 	Describe("Start", func() {
 		It("starts the agents", func() {
 			outChan <- []byte("stdout/stderr message")
-			errChan <- errors.New("host not found")
+			errChan <- nil
 
 			cw := testutils.NewMockChecklistManager()
-			clusterSsher := cluster_ssher.NewClusterSsher(cw, newSpyAgentPinger(), commandExecer.Exec)
+			clusterSsher := cluster_ssher.NewClusterSsher(cw, newSpyAgentPinger(), backend)
 			Expect(cw.IsPending("start-agents")).To(BeTrue())
 			clusterSsher.Start([]string{"doesnt matter"})
 
@@ -98,15 +120,72 @@ var _ = Describe("ClusterSsher", func() {
 			Expect(cw.WasReset("start-agents")).To(BeTrue())
 			Expect(cw.IsComplete("start-agents")).To(BeTrue())
 		})
+
+		It("records the exit code and output of each host it couldn't start an agent on", func() {
+			results := map[string]cluster_ssher.Result{
+				"sdw1": {Host: "sdw1"},
+				"sdw2": {Host: "sdw2", ExitCode: 255, Output: "ssh: connect to host sdw2: Connection refused\n", Err: errors.New("exit status 255")},
+			}
+
+			cw := testutils.NewMockChecklistManager()
+			pinger := newSpyAgentPinger()
+			clusterSsher := cluster_ssher.NewClusterSsher(cw, pinger, &stubBackend{results: results})
+			clusterSsher.Start([]string{"sdw1", "sdw2"})
+
+			Expect(cw.IsFailed(upgradestatus.START_AGENTS)).To(BeTrue())
+			Expect(cw.FailureCause(upgradestatus.START_AGENTS)).To(MatchError("start-agents failed on 1 of 2 hosts: " +
+				"sdw2: exit code 255: ssh: connect to host sdw2: Connection refused"))
+			Expect(pinger.pinged).To(BeFalse())
+		})
+
+		It("logs in as the configured user", func() {
+			outChan <- []byte("stdout/stderr message")
+			errChan <- nil
+
+			cw := testutils.NewMockChecklistManager()
+			backend = cluster_ssher.NewSSHBackend(commandExecer.Exec, cluster_ssher.SSHConfig{User: "gpadmin"})
+			clusterSsher := cluster_ssher.NewClusterSsher(cw, newSpyAgentPinger(), backend)
+			clusterSsher.Start([]string{"hostone"})
+
+			Expect(commandExecer.Args()[2]).To(Equal("gpadmin@hostone"))
+		})
+
+		It("tells the agents where to register with the hub", func() {
+			outChan <- []byte("stdout/stderr message")
+			errChan <- nil
+
+			cw := testutils.NewMockChecklistManager()
+			clusterSsher := cluster_ssher.NewClusterSsher(cw, newSpyAgentPinger(), backend)
+			clusterSsher.HubAddress = "mdw:6417"
+			clusterSsher.Start([]string{"hostone"})
+
+			Expect(commandExecer.Args()[3]).To(HaveSuffix(" --daemonize --hub-address mdw:6417'"))
+		})
 	})
 })
 
-type spyAgentPinger struct{}
+// stubBackend returns a canned result for each host.
+type stubBackend struct {
+	results map[string]cluster_ssher.Result
+}
+
+func (s *stubBackend) Run(host string, command string) cluster_ssher.Result {
+	return s.results[host]
+}
+
+func (s *stubBackend) Copy(host string, sources []string, destination string) cluster_ssher.Result {
+	return s.results[host]
+}
+
+type spyAgentPinger struct {
+	pinged bool
+}
 
 func newSpyAgentPinger() *spyAgentPinger {
 	return &spyAgentPinger{}
 }
 
 func (s *spyAgentPinger) PingPollAgents() error {
+	s.pinged = true
 	return nil
 }
@@ -0,0 +1,59 @@
+package cluster_ssher
+
+import (
+	"fmt"
+
+	"github.com/greenplum-db/gpupgrade/helpers"
+	"github.com/greenplum-db/gpupgrade/utils"
+)
+
+// LocalBackend runs commands and copies files on the hub's own host, for
+// clusters that live entirely on it. It refuses to work on any other host.
+type LocalBackend struct {
+	commandExecer helpers.CommandExecer
+}
+
+func NewLocalBackend(commandExecer helpers.CommandExecer) *LocalBackend {
+	return &LocalBackend{commandExecer: commandExecer}
+}
+
+func (l *LocalBackend) Run(host string, command string) Result {
+	err := checkLocal(host)
+	if err != nil {
+		return Result{Host: host, ExitCode: -1, Err: err}
+	}
+
+	return execute(l.commandExecer, host, "bash", "-c", command)
+}
+
+func (l *LocalBackend) Copy(host string, sources []string, destination string) Result {
+	err := checkLocal(host)
+	if err != nil {
+		return Result{Host: host, ExitCode: -1, Err: err}
+	}
+
+	expanded, err := expandSources(sources)
+	if err != nil {
+		return Result{Host: host, ExitCode: -1, Err: err}
+	}
+
+	args := append([]string{"-rpt"}, expanded...)
+	args = append(args, destination)
+	return execute(l.commandExecer, host, "rsync", args...)
+}
+
+func checkLocal(host string) error {
+	switch host {
+	case "localhost", "127.0.0.1", "::1":
+		return nil
+	}
+
+	hostname, err := utils.GetHost()
+	if err != nil {
+		return err
+	}
+	if host != hostname {
+		return fmt.Errorf("%s is not this host (%s), so the local backend can't reach it", host, hostname)
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package cluster_ssher
+
+import (
+	"strings"
+
+	"github.com/greenplum-db/gpupgrade/helpers"
+	"github.com/greenplum-db/gpupgrade/utils"
+)
+
+// SSHConfig is how the SSHBackend logs in to the segment hosts. See the
+// configuration file for what each setting means.
+type SSHConfig struct {
+	User                  string
+	IdentityFile          string
+	KnownHostsFile        string
+	StrictHostKeyChecking string // "no" if empty
+	Options               []string
+}
+
+// SSHBackend runs commands on the segment hosts with ssh, and copies files to
+// them with rsync over ssh.
+type SSHBackend struct {
+	commandExecer helpers.CommandExecer
+	conf          SSHConfig
+}
+
+func NewSSHBackend(commandExecer helpers.CommandExecer, conf SSHConfig) *SSHBackend {
+	return &SSHBackend{
+		commandExecer: commandExecer,
+		conf:          conf,
+	}
+}
+
+func (s *SSHBackend) Run(host string, command string) Result {
+	args := append(s.options(), s.target(host), command)
+	return execute(s.commandExecer, host, "ssh", args...)
+}
+
+func (s *SSHBackend) Copy(host string, sources []string, destination string) Result {
+	expanded, err := expandSources(sources)
+	if err != nil {
+		return Result{Host: host, ExitCode: -1, Err: err}
+	}
+
+	// rsync splits the -e command into words the way a shell would, so each
+	// is quoted in case a path or option has spaces in it.
+	var shell []string
+	for _, word := range append([]string{"ssh"}, s.options()...) {
+		shell = append(shell, utils.ShellQuote(word))
+	}
+
+	args := []string{"-rpt", "-e", strings.Join(shell, " ")}
+	args = append(args, expanded...)
+	args = append(args, s.target(host)+":"+destination)
+	return execute(s.commandExecer, host, "rsync", args...)
+}
+
+// options returns the arguments that set up ssh, to go before the host.
+func (s *SSHBackend) options() []string {
+	checking := s.conf.StrictHostKeyChecking
+	if checking == "" {
+		checking = "no"
+	}
+
+	options := []string{"-o", "StrictHostKeyChecking=" + checking}
+	if s.conf.KnownHostsFile != "" {
+		options = append(options, "-o", "UserKnownHostsFile="+s.conf.KnownHostsFile)
+	}
+	if s.conf.IdentityFile != "" {
+		options = append(options, "-i", s.conf.IdentityFile)
+	}
+	for _, option := range s.conf.Options {
+		options = append(options, "-o", option)
+	}
+	return options
+}
+
+// target returns the destination to give ssh and rsync to reach host as the
+// configured user.
+func (s *SSHBackend) target(host string) string {
+	if s.conf.User == "" {
+		return host
+	}
+
+	return s.conf.User + "@" + host
+}
@@ -2,15 +2,19 @@ package main
 
 import (
 	"fmt"
+	"net"
 	"os"
 	"os/exec"
 	"runtime/debug"
+	"strconv"
 	"syscall"
 	"time"
 
 	"golang.org/x/crypto/ssh/terminal"
 
 	"github.com/greenplum-db/gp-common-go-libs/gplog"
+	"github.com/greenplum-db/gpupgrade/certs"
+	"github.com/greenplum-db/gpupgrade/config"
 	"github.com/greenplum-db/gpupgrade/helpers"
 	"github.com/greenplum-db/gpupgrade/hub/cluster_ssher"
 	"github.com/greenplum-db/gpupgrade/hub/services"
@@ -32,6 +36,15 @@ func main() {
 		Short: "Start the gpupgrade_hub (blocks)",
 		Long:  `Start the gpupgrade_hub (blocks)`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			stateDir := utils.GetStateDir()
+			gpupgradeConf, err := config.Load(stateDir)
+			if err != nil {
+				return err
+			}
+			if !cmd.Flags().Changed("log-directory") {
+				logdir = gpupgradeConf.LogDir
+			}
+
 			gplog.InitializeLogging("gpupgrade_hub", logdir)
 			debug.SetTraceback("all")
 
@@ -52,7 +65,7 @@ func main() {
 
 				command := exec.Command(os.Args[0], daemonArgs...)
 				// TODO: what's a good timeout?
-				err := utils.Daemonize(command, os.Stdout, os.Stderr, 2*time.Second)
+				err = utils.Daemonize(command, os.Stdout, os.Stderr, 2*time.Second)
 
 				if err != nil {
 					exitError, ok := err.(*exec.ExitError)
@@ -75,23 +88,78 @@ func main() {
 				return err
 			}
 
+			// The CLI talks to the hub over the socket, unless a port has been
+			// configured.
 			conf := &services.HubConfig{
-				CliToHubPort:   7527,
-				HubToAgentPort: 6416,
-				StateDir:       utils.GetStateDir(),
-				LogDir:         logdir,
+				CliToHubPort:           gpupgradeConf.HubPort,
+				HubToAgentPort:         gpupgradeConf.AgentPort,
+				StateDir:               stateDir,
+				LogDir:                 logdir,
+				SocketPath:             services.SocketPath(stateDir),
+				BindAddress:            gpupgradeConf.HubBindAddress,
+				RegistrationPort:       gpupgradeConf.RegistrationPort,
+				Parallelism:            gpupgradeConf.Parallelism,
+				UpgradeMode:            gpupgradeConf.UpgradeMode,
+				DiskSpaceMarginPercent: gpupgradeConf.DiskSpaceMarginPercent,
+			}
+
+			conf.ServerCredentials, err = certs.ServerCredentials(conf.StateDir, certs.Hub, certs.CLI)
+			if err != nil {
+				return err
 			}
+			conf.RegistrationCredentials, err = certs.ServerCredentials(conf.StateDir, certs.Hub, certs.Agent)
+			if err != nil {
+				return err
+			}
+			conf.AgentCredentials, err = certs.ClientCredentials(conf.StateDir, certs.Hub)
+			if err != nil {
+				return err
+			}
+
 			commandExecer := func(command string, vars ...string) helpers.Command {
 				return exec.Command(command, vars...)
 			}
+			conf.Backend, err = cluster_ssher.NewBackend(gpupgradeConf.RemoteBackend, commandExecer, cluster_ssher.SSHConfig{
+				User:                  gpupgradeConf.SSHUser,
+				IdentityFile:          gpupgradeConf.SSHIdentityFile,
+				KnownHostsFile:        gpupgradeConf.SSHKnownHostsFile,
+				StrictHostKeyChecking: gpupgradeConf.SSHStrictHostKeyChecking,
+				Options:               gpupgradeConf.SSHOptions,
+			})
+			if err != nil {
+				return err
+			}
+
 			cm := upgradestatus.NewChecklistManager(conf.StateDir)
-			clusterSsher := cluster_ssher.NewClusterSsher(
-				cm,
-				services.NewPingerManager(conf.StateDir, 500*time.Millisecond),
-				commandExecer,
-			)
+			// The hub itself tells when the agents it has started are up; see
+			// below.
+			clusterSsher := cluster_ssher.NewClusterSsher(cm, nil, conf.Backend)
+			clusterSsher.Parallelism = conf.Parallelism
+			if conf.RegistrationPort != 0 {
+				hostname, err := utils.GetHost()
+				if err != nil {
+					return err
+				}
+				clusterSsher.HubAddress = net.JoinHostPort(hostname, strconv.Itoa(conf.RegistrationPort))
+			}
+
+			// Pick up where a previous hub left off.
+			clusterPair := &services.ClusterPair{}
+			err = clusterPair.Init(conf.StateDir, "", "", commandExecer)
+			if err != nil {
+				return err
+			}
+
+			interrupted, err := upgradestatus.NewStateStore(conf.StateDir).MarkInterrupted()
+			if err != nil {
+				return err
+			}
+			for _, step := range interrupted {
+				gplog.Warn("%s was interrupted when the hub stopped, and must be run again", step)
+			}
 
-			hub := services.NewHub(&services.ClusterPair{}, grpc.DialContext, commandExecer, conf, clusterSsher, cm)
+			hub := services.NewHub(clusterPair, grpc.DialContext, commandExecer, conf, clusterSsher, cm)
+			clusterSsher.AgentPinger = hub
 			if daemon {
 				hub.MakeDaemon()
 			}
@@ -0,0 +1,107 @@
+package services
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	pb "github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/utils"
+)
+
+// HeartbeatInterval is how often the agents are told to send heartbeats once
+// they have registered.
+var HeartbeatInterval = 10 * time.Second
+
+// HeartbeatMisses is how many heartbeats in a row an agent may miss before it
+// is reported as stale.
+var HeartbeatMisses = 3
+
+// agentRecord is what an agent last told the hub about itself.
+type agentRecord struct {
+	version       string
+	stateDir      string
+	lastHeartbeat time.Time
+}
+
+// AgentRegistry keeps track of the agents that have registered with the hub,
+// and when each of them was last heard from. It only lives as long as the
+// hub; the agents register again when they find that the hub has forgotten
+// them.
+type AgentRegistry struct {
+	mu     sync.Mutex
+	agents map[string]agentRecord
+}
+
+func NewAgentRegistry() *AgentRegistry {
+	return &AgentRegistry{agents: make(map[string]agentRecord)}
+}
+
+// Register records that the agent on host is up, replacing anything known
+// about an earlier agent on the same host.
+func (r *AgentRegistry) Register(host string, version string, stateDir string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.agents[host] = agentRecord{
+		version:       version,
+		stateDir:      stateDir,
+		lastHeartbeat: utils.System.Now(),
+	}
+}
+
+// Heartbeat records that the agent on host is still up. It returns false,
+// and records nothing, if that agent hasn't registered.
+func (r *AgentRegistry) Heartbeat(host string, version string, stateDir string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.agents[host]; !ok {
+		return false
+	}
+
+	r.agents[host] = agentRecord{
+		version:       version,
+		stateDir:      stateDir,
+		lastHeartbeat: utils.System.Now(),
+	}
+	return true
+}
+
+// Health reports the agent on host as HEALTHY if it has sent a heartbeat
+// recently, STALE if it has missed HeartbeatMisses of them, and MISSING if it
+// has never registered.
+func (r *AgentRegistry) Health(host string) *pb.AgentHealthStatus {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	status := &pb.AgentHealthStatus{Hostname: host}
+	record, ok := r.agents[host]
+	if !ok {
+		return status
+	}
+
+	status.Version = record.version
+	status.StateDir = record.stateDir
+	status.LastHeartbeat = record.lastHeartbeat.Unix()
+	status.Health = pb.AgentHealth_HEALTHY
+	if utils.System.Now().Sub(record.lastHeartbeat) > time.Duration(HeartbeatMisses)*HeartbeatInterval {
+		status.Health = pb.AgentHealth_STALE
+	}
+	return status
+}
+
+// Describe names host along with what the registry says about its agent, if
+// that explains why the agent can't be reached.
+func (r *AgentRegistry) Describe(host string) string {
+	status := r.Health(host)
+	switch status.Health {
+	case pb.AgentHealth_MISSING:
+		return host + " (never registered)"
+	case pb.AgentHealth_STALE:
+		since := utils.System.Now().Sub(time.Unix(status.LastHeartbeat, 0)).Round(time.Second)
+		return fmt.Sprintf("%s (last heartbeat %s ago)", host, since)
+	default:
+		return host
+	}
+}
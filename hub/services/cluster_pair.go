@@ -21,20 +21,30 @@ type ClusterPair struct {
 	CommandExecer helpers.CommandExecer
 }
 
+// Init loads the cluster configs saved in baseDir, so that a restarted hub
+// knows about the clusters again. A config that hasn't been written yet (the
+// old one is written by check config, the new one by init-cluster) leaves its
+// cluster nil. OldBinDir and NewBinDir, if they aren't empty, override the bin
+// dirs saved in the configs.
 func (cp *ClusterPair) Init(baseDir, OldBinDir, NewBinDir string, execer helpers.CommandExecer) error {
 	var err error
 	cp.CommandExecer = execer
 
 	err = cp.ReadOldConfig(baseDir)
-	if err != nil {
+	if err != nil && !utils.System.IsNotExist(err) {
 		return fmt.Errorf("Couldn't read old config file: %+v", err)
 	}
 	err = cp.ReadNewConfig(baseDir)
-	if err != nil {
+	if err != nil && !utils.System.IsNotExist(err) {
 		return fmt.Errorf("Couldn't read new config file: %+v", err)
 	}
-	cp.OldBinDir = OldBinDir
-	cp.NewBinDir = NewBinDir
+
+	if OldBinDir != "" {
+		cp.OldBinDir = OldBinDir
+	}
+	if NewBinDir != "" {
+		cp.NewBinDir = NewBinDir
+	}
 	return nil
 }
 
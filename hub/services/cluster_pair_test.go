@@ -74,4 +74,39 @@ Duis volutpat libero sit amet hendrerit rhoncus. Praesent euismod facilisis elit
 			Expect(err).ToNot(HaveOccurred())
 		})
 	})
+	Describe("Init", func() {
+		It("loads both cluster configs from the state dir", func() {
+			Expect(services.WriteClusterConfig(services.GetConfigFilePath(testStateDir), clusterPair.OldCluster, "/old/bin")).To(Succeed())
+			Expect(services.WriteClusterConfig(services.GetNewConfigFilePath(testStateDir), clusterPair.NewCluster, "/new/bin")).To(Succeed())
+
+			loaded := &services.ClusterPair{}
+			err := loaded.Init(testStateDir, "", "", nil)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(loaded.OldCluster.GetDirForContent(-1)).To(Equal("/old/datadir"))
+			Expect(loaded.NewCluster.GetDirForContent(-1)).To(Equal("/new/datadir"))
+			Expect(loaded.OldBinDir).To(Equal("/old/bin"))
+			Expect(loaded.NewBinDir).To(Equal("/new/bin"))
+		})
+
+		It("leaves clusters whose config has not been written yet unset", func() {
+			Expect(services.WriteClusterConfig(services.GetConfigFilePath(testStateDir), clusterPair.OldCluster, "/old/bin")).To(Succeed())
+
+			loaded := &services.ClusterPair{}
+			err := loaded.Init(testStateDir, "", "/override/new/bin", nil)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(loaded.OldCluster).ToNot(BeNil())
+			Expect(loaded.NewCluster).To(BeNil())
+			Expect(loaded.NewBinDir).To(Equal("/override/new/bin"))
+		})
+
+		It("returns an error if a config is corrupt", func() {
+			err := ioutil.WriteFile(services.GetConfigFilePath(testStateDir), []byte("{"), 0600)
+			Expect(err).ToNot(HaveOccurred())
+
+			err = (&services.ClusterPair{}).Init(testStateDir, "", "", nil)
+			Expect(err).To(HaveOccurred())
+		})
+	})
 })
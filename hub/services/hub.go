@@ -2,27 +2,38 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net"
 	"os"
+	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/greenplum-db/gpupgrade/db"
 	"github.com/greenplum-db/gpupgrade/helpers"
 	"github.com/greenplum-db/gpupgrade/hub/cluster_ssher"
 	pb "github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/utils"
 
 	"github.com/greenplum-db/gp-common-go-libs/cluster"
+	"github.com/greenplum-db/gp-common-go-libs/dbconn"
 	"github.com/greenplum-db/gp-common-go-libs/gplog"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/reflection"
 )
 
 var DialTimeout = 3 * time.Second
 
+// ShutdownGracePeriod is how long a hub that has been asked to shut down waits
+// for the RPCs in flight to finish before stopping anyway.
+var ShutdownGracePeriod = 30 * time.Second
+
 type dialer func(ctx context.Context, target string, opts ...grpc.DialOption) (*grpc.ClientConn, error)
 
 type RemoteExecutor interface {
@@ -33,18 +44,31 @@ type RemoteExecutor interface {
 type Hub struct {
 	conf *HubConfig
 
-	agentConns      []*Connection
+	// connsMu guards agentConns, which the RPCs share.
+	connsMu    sync.Mutex
+	agentConns []*Connection
+
+	registry        *AgentRegistry
 	clusterPair     *ClusterPair
 	grpcDialer      dialer
 	commandExecer   helpers.CommandExecer
 	remoteExecutor  RemoteExecutor
+	backend         cluster_ssher.Backend
+	dbConn          func(host string, port int, dbname string) *dbconn.DBConn
 	checklistWriter cluster_ssher.ChecklistWriter
-
-	mu      sync.Mutex
-	server  *grpc.Server
-	lis     net.Listener
-	stopped chan struct{}
-	daemon  bool
+	processes       *utils.Processes
+	checkers        *CheckerRegistry
+
+	mu             sync.Mutex
+	server         *grpc.Server
+	agentServer    *grpc.Server
+	listeners      []net.Listener
+	stopped        chan struct{}
+	drained        chan struct{}
+	daemon         bool
+	upgradeRunning bool
+	reverting      bool
+	shuttingDown   bool
 }
 
 type Connection struct {
@@ -58,17 +82,77 @@ type HubConfig struct {
 	HubToAgentPort int
 	StateDir       string
 	LogDir         string
+
+	// SocketPath is the unix socket that the hub serves the CLI on. The hub
+	// only listens on CliToHubPort, at BindAddress, as well if it is set,
+	// since that makes it reachable from other hosts.
+	SocketPath  string
+	BindAddress string
+
+	// RegistrationPort is where the hub listens, on every interface, for the
+	// agents to register and send heartbeats; 0 means it doesn't.
+	RegistrationPort int
+
+	// Backend runs commands on the segment hosts and copies files to them; if
+	// it is nil, they are reached with ssh as the user running the hub.
+	// Parallelism is the most hosts worked on at once.
+	Backend     cluster_ssher.Backend
+	Parallelism int
+
+	// UpgradeMode is config.ModeCopy or config.ModeLink, which has pg_upgrade
+	// hard link the data files; empty means copy.
+	UpgradeMode string
+
+	// DiskSpaceMarginPercent is the free space that the disk space checks
+	// want on top of what pg_upgrade writes; see config.Config.
+	DiskSpaceMarginPercent int
+
+	// DBConn returns a connection, not yet connected, to the database dbname
+	// of the cluster whose master is at host and port; if it is nil, it is
+	// db.NewDBConn.
+	DBConn func(host string, port int, dbname string) *dbconn.DBConn
+
+	// Checkers are the checks that CheckAll runs; if it is nil, they are the
+	// ones DefaultCheckers returns.
+	Checkers *CheckerRegistry
+
+	// ServerCredentials secures the connections from the CLI,
+	// RegistrationCredentials those from the agents registering with the
+	// hub, and AgentCredentials the connections to the agents; see package
+	// certs. The hub executable always sets all of them. Connections are only
+	// left insecure in tests.
+	ServerCredentials       credentials.TransportCredentials
+	RegistrationCredentials credentials.TransportCredentials
+	AgentCredentials        credentials.TransportCredentials
 }
 
 func NewHub(pair *ClusterPair, grpcDialer dialer, execer helpers.CommandExecer, conf *HubConfig, executor RemoteExecutor, checklistWriter cluster_ssher.ChecklistWriter) *Hub {
 	h := &Hub{
 		stopped:         make(chan struct{}, 1),
+		drained:         make(chan struct{}),
 		conf:            conf,
+		registry:        NewAgentRegistry(),
 		clusterPair:     pair,
 		grpcDialer:      grpcDialer,
 		commandExecer:   execer,
 		remoteExecutor:  executor,
 		checklistWriter: checklistWriter,
+		processes:       utils.NewProcesses(processRecordPath(conf.StateDir)),
+	}
+
+	h.checkers = conf.Checkers
+	if h.checkers == nil {
+		h.checkers = DefaultCheckers()
+	}
+
+	h.backend = conf.Backend
+	if h.backend == nil {
+		h.backend = cluster_ssher.NewSSHBackend(execer, cluster_ssher.SSHConfig{})
+	}
+
+	h.dbConn = conf.DBConn
+	if h.dbConn == nil {
+		h.dbConn = db.NewDBConn
 	}
 
 	return h
@@ -81,15 +165,54 @@ func (h *Hub) MakeDaemon() {
 }
 
 func (h *Hub) Start() {
-	lis, err := net.Listen("tcp", ":"+strconv.Itoa(h.conf.CliToHubPort))
-	if err != nil {
-		gplog.Fatal(err, "failed to listen")
+	var listeners []net.Listener
+	if h.conf.SocketPath != "" {
+		lis, err := listenUnix(h.conf.SocketPath)
+		if err != nil {
+			gplog.Fatal(err, "failed to listen on %s", h.conf.SocketPath)
+		}
+		listeners = append(listeners, lis)
+	}
+	if h.conf.CliToHubPort != 0 {
+		lis, err := net.Listen("tcp", net.JoinHostPort(h.conf.BindAddress, strconv.Itoa(h.conf.CliToHubPort)))
+		if err != nil {
+			gplog.Fatal(err, "failed to listen")
+		}
+		listeners = append(listeners, lis)
+	}
+	if len(listeners) == 0 {
+		gplog.Fatal(errors.New("neither a socket path nor a port was configured"), "failed to listen")
+	}
+
+	var opts []grpc.ServerOption
+	if h.conf.ServerCredentials != nil {
+		opts = append(opts, grpc.Creds(h.conf.ServerCredentials))
+	}
+
+	// The agents are served separately, so that opening the registration
+	// port doesn't expose the CLI's RPCs to other hosts.
+	var agentServer *grpc.Server
+	var agentLis net.Listener
+	if h.conf.RegistrationPort != 0 {
+		var err error
+		agentLis, err = net.Listen("tcp", net.JoinHostPort("", strconv.Itoa(h.conf.RegistrationPort)))
+		if err != nil {
+			gplog.Fatal(err, "failed to listen for agents")
+		}
+
+		var agentOpts []grpc.ServerOption
+		if h.conf.RegistrationCredentials != nil {
+			agentOpts = append(agentOpts, grpc.Creds(h.conf.RegistrationCredentials))
+		}
+		agentServer = grpc.NewServer(agentOpts...)
+		pb.RegisterAgentToHubServer(agentServer, h)
 	}
 
-	server := grpc.NewServer()
+	server := grpc.NewServer(opts...)
 	h.mu.Lock()
 	h.server = server
-	h.lis = lis
+	h.agentServer = agentServer
+	h.listeners = listeners
 	h.mu.Unlock()
 
 	pb.RegisterCliToHubServer(server, h)
@@ -98,12 +221,19 @@ func (h *Hub) Start() {
 	// TODO: Research daemonize to see what else may need to be
 	// done for the child process to safely detach from the parent
 	if h.daemon {
-		fmt.Printf("Hub started on port %d (pid %d)\n", h.conf.CliToHubPort, os.Getpid())
+		fmt.Printf("Hub started on %s (pid %d)\n", h.address(), os.Getpid())
 		os.Stderr.Close()
 		os.Stdout.Close()
 	}
 
-	err = server.Serve(lis)
+	// Stopping the server closes every listener, so all of these return.
+	for _, lis := range listeners[1:] {
+		go server.Serve(lis)
+	}
+	if agentServer != nil {
+		go agentServer.Serve(agentLis)
+	}
+	err := server.Serve(listeners[0])
 	if err != nil {
 		gplog.Fatal(err, "failed to serve", err)
 	}
@@ -111,18 +241,83 @@ func (h *Hub) Start() {
 	h.stopped <- struct{}{}
 }
 
+// address describes where the hub is listening for the CLI.
+func (h *Hub) address() string {
+	var addresses []string
+	if h.conf.SocketPath != "" {
+		addresses = append(addresses, h.conf.SocketPath)
+	}
+	if h.conf.CliToHubPort != 0 {
+		addresses = append(addresses, fmt.Sprintf("port %d", h.conf.CliToHubPort))
+	}
+
+	return strings.Join(addresses, " and ")
+}
+
+// listenUnix listens on a unix socket at path that only the owner of the hub
+// can connect to. A socket left behind by a hub that has died is replaced, but
+// one that another hub is still serving on is not.
+func listenUnix(path string) (net.Listener, error) {
+	conn, err := net.Dial("unix", path)
+	if err == nil {
+		conn.Close()
+		return nil, fmt.Errorf("another hub is already listening on %s", path)
+	}
+
+	err = utils.System.Remove(path)
+	if err != nil && !utils.System.IsNotExist(err) {
+		return nil, err
+	}
+
+	lis, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+
+	err = os.Chmod(path, 0600)
+	if err != nil {
+		lis.Close()
+		return nil, err
+	}
+
+	return lis, nil
+}
+
+// SocketPath returns the path of the unix socket that the hub serves the CLI
+// on, in stateDir.
+func SocketPath(stateDir string) string {
+	return filepath.Join(stateDir, "hub.sock")
+}
+
+// processRecordPath returns where the hub keeps the records of the processes
+// it starts, in stateDir. Without a state directory they are not kept.
+func processRecordPath(stateDir string) string {
+	if stateDir == "" {
+		return ""
+	}
+	return filepath.Join(stateDir, "hub_processes.json")
+}
+
 func (h *Hub) Stop() {
 	h.mu.Lock()
 	defer h.mu.Unlock()
 
 	if h.server != nil {
 		h.closeConns()
+		if h.agentServer != nil {
+			h.agentServer.Stop()
+			h.agentServer = nil
+		}
 		h.server.Stop()
 		<-h.stopped
+		h.server = nil
 	}
 }
 
 func (h *Hub) AgentConns() ([]*Connection, error) {
+	h.connsMu.Lock()
+	defer h.connsMu.Unlock()
+
 	if h.agentConns != nil {
 		err := h.ensureConnsAreReady()
 		if err != nil {
@@ -135,28 +330,83 @@ func (h *Hub) AgentConns() ([]*Connection, error) {
 
 	hostnames := h.clusterPair.GetHostnames()
 
-	for _, host := range hostnames {
+	var mu sync.Mutex
+	var conns []*Connection
+	var down []string
+	h.forEachHost(hostnames, "connect to the agent on", func(host string) error {
 		ctx, cancelFunc := context.WithTimeout(context.Background(), DialTimeout)
 		// grpc.WithBlock() is potentially slowing down the tests. Leaving it in to keep tests green.
-		conn, err := h.grpcDialer(ctx, host+":"+strconv.Itoa(h.conf.HubToAgentPort), grpc.WithInsecure(), grpc.WithBlock())
+		conn, err := h.grpcDialer(ctx, host+":"+strconv.Itoa(h.conf.HubToAgentPort), h.agentDialOption(), grpc.WithBlock())
+
+		mu.Lock()
+		defer mu.Unlock()
+
 		if err != nil {
-			gplog.Error("grpcDialer failed: ", err)
 			cancelFunc()
-			return nil, err
+			down = append(down, host)
+			return err
 		}
-		h.agentConns = append(h.agentConns, &Connection{
+		conns = append(conns, &Connection{
 			Conn:          conn,
 			Hostname:      host,
 			CancelContext: cancelFunc,
 		})
+		return nil
+	})
+
+	if len(down) != 0 {
+		for _, conn := range conns {
+			conn.Conn.Close()
+			conn.CancelContext()
+		}
+		return nil, fmt.Errorf("could not connect to the agents on %s", h.describeHosts(down))
 	}
 
+	h.agentConns = conns
 	return h.agentConns, nil
 }
 
+// describeHosts lists hostnames, along with what the agent registry knows
+// about the agents on them if the agents register with the hub.
+func (h *Hub) describeHosts(hostnames []string) string {
+	sort.Strings(hostnames)
+
+	var described []string
+	for _, host := range hostnames {
+		if h.conf.RegistrationPort != 0 {
+			host = h.registry.Describe(host)
+		}
+		described = append(described, host)
+	}
+	return strings.Join(described, ", ")
+}
+
+// dialAgent connects to the agent on host on its own, rather than through the
+// connections shared by AgentConns, waiting until ctx is done for it to answer.
+func (h *Hub) dialAgent(ctx context.Context, host string) (*grpc.ClientConn, error) {
+	conn, err := h.grpcDialer(ctx, host+":"+strconv.Itoa(h.conf.HubToAgentPort), h.agentDialOption(), grpc.WithBlock())
+	if err != nil {
+		return nil, fmt.Errorf("could not connect to the agent: %s", err)
+	}
+
+	return conn, nil
+}
+
+// agentDialOption secures a connection to an agent with the hub's certificate.
+func (h *Hub) agentDialOption() grpc.DialOption {
+	if h.conf.AgentCredentials == nil {
+		return grpc.WithInsecure()
+	}
+
+	return grpc.WithTransportCredentials(h.conf.AgentCredentials)
+}
+
+// ensureConnsAreReady waits briefly for every connection in agentConns to be
+// ready. connsMu must be held.
 func (h *Hub) ensureConnsAreReady() error {
 	var hostnames []string
 	for i := 0; i < 3; i++ {
+		hostnames = nil
 		ready := 0
 		for _, conn := range h.agentConns {
 			if conn.Conn.GetState() == connectivity.Ready {
@@ -173,10 +423,15 @@ func (h *Hub) ensureConnsAreReady() error {
 		time.Sleep(500 * time.Millisecond)
 	}
 
-	return fmt.Errorf("the connections to the following hosts were not ready: %s", strings.Join(hostnames, ","))
+	return fmt.Errorf("the connections to the following hosts were not ready: %s", h.describeHosts(hostnames))
 }
 
+// closeConns closes the connections to the agents, which AgentConns makes
+// again when they are next needed.
 func (h *Hub) closeConns() {
+	h.connsMu.Lock()
+	defer h.connsMu.Unlock()
+
 	for _, conn := range h.agentConns {
 		defer conn.CancelContext()
 		err := conn.Conn.Close()
@@ -184,6 +439,90 @@ func (h *Hub) closeConns() {
 			gplog.Info(fmt.Sprintf("Error closing hub to agent connection. host: %s, err: %s", conn.Hostname, err.Error()))
 		}
 	}
+	h.agentConns = nil
+}
+
+// forEachHost calls f for each of hostnames, working on up to Parallelism of
+// them at once. Every failure is logged, and the error returned names the
+// hosts that failed along with why; what describes the work, as in
+// "could not <what> <host>: <why>".
+func (h *Hub) forEachHost(hostnames []string, what string, f func(host string) error) error {
+	parallelism := h.conf.Parallelism
+	if parallelism < 1 {
+		parallelism = 1
+	}
+
+	var mu sync.Mutex
+	failures := make(map[string]error)
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, parallelism)
+	for _, host := range hostnames {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func(host string) {
+			defer func() {
+				<-slots
+				wg.Done()
+			}()
+
+			err := f(host)
+			if err != nil {
+				gplog.Error("could not %s %s: %s", what, host, err)
+
+				mu.Lock()
+				failures[host] = err
+				mu.Unlock()
+			}
+		}(host)
+	}
+	wg.Wait()
+
+	if len(failures) != 0 {
+		var failedHosts []string
+		for host := range failures {
+			failedHosts = append(failedHosts, host)
+		}
+		sort.Strings(failedHosts)
+
+		var causes []string
+		for _, host := range failedHosts {
+			causes = append(causes, fmt.Sprintf("%s: %s", host, failures[host]))
+		}
+		return fmt.Errorf("could not %s %s", what, strings.Join(causes, "; "))
+	}
+	return nil
+}
+
+// agentConnsByHost returns the connection to the agent on each host, by
+// host.
+func (h *Hub) agentConnsByHost() (map[string]*Connection, error) {
+	conns, err := h.AgentConns()
+	if err != nil {
+		return nil, err
+	}
+
+	connsByHost := make(map[string]*Connection)
+	for _, conn := range conns {
+		connsByHost[conn.Hostname] = conn
+	}
+	return connsByHost, nil
+}
+
+// forEachAgent calls f, as forEachHost does, for the agent on every host
+// that the hub is connected to.
+func (h *Hub) forEachAgent(what string, f func(host string, client pb.AgentClient) error) error {
+	connsByHost, err := h.agentConnsByHost()
+	if err != nil {
+		return err
+	}
+
+	var hostnames []string
+	for host := range connsByHost {
+		hostnames = append(hostnames, host)
+	}
+	return h.forEachHost(hostnames, what, func(host string) error {
+		return f(host, pb.NewAgentClient(connsByHost[host].Conn))
+	})
 }
 
 func (h *Hub) segmentsByHost() map[string][]cluster.SegConfig {
@@ -0,0 +1,122 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/greenplum-db/gpupgrade/helpers"
+	"github.com/greenplum-db/gpupgrade/hub/upgradestatus"
+	pb "github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/utils"
+
+	"github.com/greenplum-db/gp-common-go-libs/cluster"
+	"github.com/greenplum-db/gp-common-go-libs/gplog"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Cancel stops the pg_upgrade, gpstop and gpstart processes that the running
+// upgrade steps have started on the master, and has the agents stop the
+// pg_upgrade processes converting the primaries. Once a process has been
+// terminated, the servers pg_upgrade left running are stopped and the step is
+// recorded as FAILED, with "cancelled" as the reason.
+func (h *Hub) Cancel(ctx context.Context, in *pb.CancelRequest) (*pb.CancelReply, error) {
+	gplog.Info("starting Cancel")
+
+	reply := &pb.CancelReply{}
+	for _, name := range h.processes.Terminate() {
+		gplog.Info("cancelled %s", name)
+		reply.Cancelled = append(reply.Cancelled, name)
+	}
+
+	// The steps that run gpstop and gpstart record themselves as cancelled;
+	// nothing is left waiting on pg_upgrade to do that.
+	for _, name := range reply.Cancelled {
+		if name == upgradestatus.CONVERT_MASTER {
+			h.markCancelled(upgradestatus.CONVERT_MASTER)
+		}
+	}
+
+	cancelled, err := h.cancelOnAgents()
+	if len(cancelled) != 0 {
+		h.markCancelled(upgradestatus.CONVERT_PRIMARY)
+		reply.Cancelled = append(reply.Cancelled, cancelled...)
+	}
+	if err != nil {
+		gplog.Error(err.Error())
+		return reply, status.Errorf(codes.Unavailable, "could not cancel the upgrade of the primaries: %s", err)
+	}
+
+	return reply, nil
+}
+
+// cancelOnAgents has every agent stop what it is running, once they have been
+// started. It returns what the agents stopped, as "<what> on <host>".
+func (h *Hub) cancelOnAgents() ([]string, error) {
+	startAgents := upgradestatus.NewStateCheck(h.conf.StateDir, upgradestatus.START_AGENTS, pb.UpgradeSteps_PREPARE_START_AGENTS)
+	if startAgents.GetStatus().Status != pb.StepStatus_COMPLETE {
+		return nil, nil
+	}
+
+	var mu sync.Mutex
+	var cancelled []string
+	err := h.forEachAgent("cancel the upgrade on", func(host string, client pb.AgentClient) error {
+		reply, err := client.Cancel(context.Background(), &pb.CancelAgentRequest{})
+		if err != nil {
+			return err
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		for _, name := range reply.Cancelled {
+			cancelled = append(cancelled, fmt.Sprintf("%s on %s", name, host))
+		}
+		return nil
+	})
+
+	sort.Strings(cancelled)
+	return cancelled, err
+}
+
+func (h *Hub) markCancelled(step string) {
+	err := h.checklistWriter.MarkFailed(step, utils.ErrCancelled)
+	if err != nil {
+		gplog.Error("failed to record %s as cancelled: %s", step, err)
+	}
+}
+
+// cancellable returns a copy of c whose local commands are run as processes
+// of step that Cancel can terminate; they return utils.ErrCancelled if it
+// does.
+func (h *Hub) cancellable(step string, c *cluster.Cluster) *cluster.Cluster {
+	copied := *c
+	copied.Executor = &cancellableExecutor{
+		Executor:      c.Executor,
+		processes:     h.processes,
+		step:          step,
+		commandExecer: h.commandExecer,
+	}
+	return &copied
+}
+
+// cancellableExecutor runs the local commands that the default executor of a
+// cluster would run, tracking them in processes. Commands for any other
+// executor, such as the ones tests use, are passed on to it untracked.
+type cancellableExecutor struct {
+	cluster.Executor
+
+	processes     *utils.Processes
+	step          string
+	commandExecer helpers.CommandExecer
+}
+
+func (e *cancellableExecutor) ExecuteLocalCommand(commandStr string) (string, error) {
+	if _, ok := e.Executor.(*cluster.GPDBExecutor); !ok {
+		return e.Executor.ExecuteLocalCommand(commandStr)
+	}
+
+	output, err := e.processes.CombinedOutput(e.step, e.commandExecer("bash", "-c", commandStr))
+	return string(output), err
+}
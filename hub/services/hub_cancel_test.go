@@ -0,0 +1,134 @@
+package services_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+
+	"github.com/greenplum-db/gpupgrade/hub/services"
+	"github.com/greenplum-db/gpupgrade/hub/upgradestatus"
+	pb "github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/testutils"
+	"github.com/greenplum-db/gpupgrade/utils"
+
+	"github.com/greenplum-db/gp-common-go-libs/cluster"
+	"github.com/greenplum-db/gp-common-go-libs/testhelper"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Cancel", func() {
+	var (
+		dir       string
+		hub       *services.Hub
+		mockAgent *testutils.MockAgentServer
+		cm        *testutils.MockChecklistManager
+	)
+
+	BeforeEach(func() {
+		testhelper.SetupTestLogger()
+
+		var port int
+		mockAgent, port = testutils.NewMockAgentServer()
+
+		var err error
+		dir, err = ioutil.TempDir("", "")
+		Expect(err).ToNot(HaveOccurred())
+		setStepStatus(dir, upgradestatus.SHUTDOWN_CLUSTERS, pb.StepStatus_COMPLETE)
+
+		clusterPair := testutils.CreateSampleClusterPair()
+		clusterPair.OldCluster.Segments = map[int]cluster.SegConfig{
+			-1: {ContentID: -1, Hostname: "localhost", DataDir: "/old/datadir", Port: 25437},
+			0:  {ContentID: 0, Hostname: "localhost", DataDir: "/old/datadir0", Port: 25438},
+		}
+
+		commandExecer := &testutils.FakeCommandExecer{}
+		commandExecer.SetOutput(&testutils.FakeCommand{})
+
+		cm = testutils.NewMockChecklistManager()
+		hub = services.NewHub(clusterPair, grpc.DialContext, commandExecer.Exec,
+			&services.HubConfig{StateDir: dir, HubToAgentPort: port}, nil, cm)
+	})
+
+	AfterEach(func() {
+		utils.System = utils.InitializeSystemFunctions()
+		mockAgent.Stop()
+		os.RemoveAll(dir)
+	})
+
+	convertMaster := func() {
+		_, err := hub.UpgradeConvertMaster(nil, &pb.UpgradeConvertMasterRequest{
+			OldBinDir:  "/old/bindir",
+			OldDataDir: "/old/datadir",
+			NewBinDir:  "/new/bindir",
+			NewDataDir: "/new/datadir",
+		})
+		Expect(err).ToNot(HaveOccurred())
+	}
+
+	It("stops pg_upgrade on the master and marks the step as cancelled", func() {
+		convertMaster()
+
+		reply, err := hub.Cancel(nil, &pb.CancelRequest{})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(reply.Cancelled).To(Equal([]string{upgradestatus.CONVERT_MASTER}))
+		Expect(cm.FailureCause(upgradestatus.CONVERT_MASTER)).To(Equal(utils.ErrCancelled))
+	})
+
+	It("reports that there was nothing to cancel", func() {
+		convertMaster()
+		_, err := hub.Cancel(nil, &pb.CancelRequest{})
+		Expect(err).ToNot(HaveOccurred())
+
+		reply, err := hub.Cancel(nil, &pb.CancelRequest{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(reply.Cancelled).To(BeEmpty())
+	})
+
+	It("doesn't contact the agents before they have been started", func() {
+		_, err := hub.Cancel(nil, &pb.CancelRequest{})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(mockAgent.NumberOfCalls()).To(Equal(0))
+	})
+
+	Context("once the agents have been started", func() {
+		BeforeEach(func() {
+			setStepStatus(dir, upgradestatus.START_AGENTS, pb.StepStatus_COMPLETE)
+		})
+
+		It("has the agents stop the upgrade of the primaries", func() {
+			mockAgent.Cancelled = []string{"convert-primary (segment 0)"}
+
+			reply, err := hub.Cancel(nil, &pb.CancelRequest{})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(reply.Cancelled).To(Equal([]string{"convert-primary (segment 0) on localhost"}))
+			Expect(cm.FailureCause(upgradestatus.CONVERT_PRIMARY)).To(Equal(utils.ErrCancelled))
+		})
+
+		It("doesn't mark the primaries as cancelled when the agents weren't running anything", func() {
+			_, err := hub.Cancel(nil, &pb.CancelRequest{})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(cm.FailureCause(upgradestatus.CONVERT_PRIMARY)).To(BeNil())
+		})
+
+		It("still cancels what runs on the master when an agent fails", func() {
+			convertMaster()
+			mockAgent.Err <- errors.New("agent is broken")
+
+			reply, err := hub.Cancel(nil, &pb.CancelRequest{})
+			Expect(status.Code(err)).To(Equal(codes.Unavailable))
+			Expect(err.Error()).To(ContainSubstring("could not cancel the upgrade on localhost"))
+
+			Expect(reply.Cancelled).To(Equal([]string{upgradestatus.CONVERT_MASTER}))
+			Expect(cm.FailureCause(upgradestatus.CONVERT_MASTER)).To(Equal(utils.ErrCancelled))
+		})
+	})
+})
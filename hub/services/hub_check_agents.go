@@ -0,0 +1,113 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	pb "github.com/greenplum-db/gpupgrade/idl"
+
+	"github.com/greenplum-db/gp-common-go-libs/gplog"
+)
+
+// AgentPingTimeout is how long the hub waits for each agent to answer a ping,
+// including the time taken to connect to it.
+var AgentPingTimeout = 3 * time.Second
+
+// AgentPollAttempts and AgentPollInterval are how many times, and how often,
+// PingPollAgents pings the agents before giving up on them.
+var (
+	AgentPollAttempts = 10
+	AgentPollInterval = 500 * time.Millisecond
+)
+
+// CheckAgents pings the agent on every segment host at once, and reports
+// whether each of them answered, how long it took, and which version of
+// gpupgrade it was built from.
+func (h *Hub) CheckAgents(ctx context.Context, in *pb.CheckAgentsRequest) (*pb.CheckAgentsReply, error) {
+	gplog.Info("starting CheckAgents")
+
+	statuses, err := h.pingAgents()
+	if err != nil {
+		gplog.Error(err.Error())
+	}
+
+	return &pb.CheckAgentsReply{Agents: statuses}, nil
+}
+
+// PingPollAgents waits for the agent on every segment host to answer a ping,
+// so that agents that have just been started can be told apart from ones that
+// failed to start. It gives up after AgentPollAttempts.
+func (h *Hub) PingPollAgents() error {
+	var err error
+	for i := 0; i < AgentPollAttempts; i++ {
+		gplog.Info("Pinging agents...")
+		_, err = h.pingAgents()
+		if err == nil {
+			return nil
+		}
+
+		time.Sleep(AgentPollInterval)
+	}
+
+	gplog.Info("Reached ping timeout")
+	return err
+}
+
+// pingAgents pings the agent on each segment host, up to Parallelism of them
+// at once, and returns the results in order of hostname. The error names the
+// hosts whose agents didn't answer.
+func (h *Hub) pingAgents() ([]*pb.AgentStatus, error) {
+	hostnames := h.clusterPair.GetHostnames()
+	sort.Strings(hostnames)
+
+	var mu sync.Mutex
+	byHost := make(map[string]*pb.AgentStatus)
+	err := h.forEachHost(hostnames, "ping the agent on", func(host string) error {
+		status := h.pingAgent(host)
+
+		mu.Lock()
+		byHost[host] = status
+		mu.Unlock()
+
+		if !status.Reachable {
+			return errors.New(status.Error)
+		}
+		return nil
+	})
+
+	var statuses []*pb.AgentStatus
+	for _, host := range hostnames {
+		statuses = append(statuses, byHost[host])
+	}
+	return statuses, err
+}
+
+// pingAgent pings the agent on host, giving it AgentPingTimeout to answer.
+func (h *Hub) pingAgent(host string) *pb.AgentStatus {
+	status := &pb.AgentStatus{Hostname: host}
+
+	ctx, cancel := context.WithTimeout(context.Background(), AgentPingTimeout)
+	defer cancel()
+
+	conn, err := h.dialAgent(ctx, host)
+	if err != nil {
+		status.Error = err.Error()
+		return status
+	}
+	defer conn.Close()
+
+	start := time.Now()
+	reply, err := pb.NewAgentClient(conn).PingAgents(ctx, &pb.PingAgentsRequest{})
+	if err != nil {
+		status.Error = err.Error()
+		return status
+	}
+
+	status.Reachable = true
+	status.LatencyMilliseconds = float64(time.Since(start)) / float64(time.Millisecond)
+	status.Version = reply.Version
+	return status
+}
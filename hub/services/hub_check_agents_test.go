@@ -0,0 +1,104 @@
+package services_test
+
+import (
+	"time"
+
+	"github.com/greenplum-db/gpupgrade/hub/services"
+	pb "github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/testutils"
+	"github.com/greenplum-db/gpupgrade/utils"
+
+	"github.com/greenplum-db/gp-common-go-libs/cluster"
+	"github.com/greenplum-db/gp-common-go-libs/testhelper"
+	"google.golang.org/grpc"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CheckAgents", func() {
+	var (
+		mockAgent   *testutils.MockAgentServer
+		clusterPair *services.ClusterPair
+		hub         *services.Hub
+		pingTimeout time.Duration
+	)
+
+	BeforeEach(func() {
+		testhelper.SetupTestLogger()
+
+		var port int
+		mockAgent, port = testutils.NewMockAgentServer()
+		mockAgent.Version = "1.2.3"
+
+		clusterPair = &services.ClusterPair{
+			OldCluster: testutils.CreateSampleCluster(-1, 25437, "localhost", "/old/datadir"),
+		}
+		hub = services.NewHub(clusterPair, grpc.DialContext, nil,
+			&services.HubConfig{HubToAgentPort: port, Parallelism: 4},
+			testutils.NewStubRemoteExecutor(), nil)
+
+		pingTimeout = services.AgentPingTimeout
+		services.AgentPingTimeout = 500 * time.Millisecond
+	})
+
+	AfterEach(func() {
+		utils.System = utils.InitializeSystemFunctions()
+		services.AgentPingTimeout = pingTimeout
+		mockAgent.Stop()
+	})
+
+	It("reports the latency and version of each agent", func() {
+		reply, err := hub.CheckAgents(nil, &pb.CheckAgentsRequest{})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(reply.Agents).To(HaveLen(1))
+		Expect(reply.Agents[0].Hostname).To(Equal("localhost"))
+		Expect(reply.Agents[0].Reachable).To(BeTrue())
+		Expect(reply.Agents[0].LatencyMilliseconds).To(BeNumerically(">", 0))
+		Expect(reply.Agents[0].Version).To(Equal("1.2.3"))
+		Expect(reply.Agents[0].Error).To(BeEmpty())
+	})
+
+	It("pings the agents at once, each with its own timeout", func() {
+		clusterPair.OldCluster.Segments[0] = cluster.SegConfig{Hostname: "unreachable1.invalid"}
+		clusterPair.OldCluster.Segments[1] = cluster.SegConfig{Hostname: "unreachable2.invalid"}
+
+		start := time.Now()
+		reply, err := hub.CheckAgents(nil, &pb.CheckAgentsRequest{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(time.Since(start)).To(BeNumerically("<", 2*services.AgentPingTimeout))
+
+		Expect(reply.Agents).To(HaveLen(3))
+		Expect(reply.Agents[0].Hostname).To(Equal("localhost"))
+		Expect(reply.Agents[0].Reachable).To(BeTrue())
+		for _, agent := range reply.Agents[1:] {
+			Expect(agent.Reachable).To(BeFalse())
+			Expect(agent.Error).To(ContainSubstring("could not connect to the agent"))
+		}
+	})
+
+	Describe("PingPollAgents", func() {
+		var attempts int
+
+		BeforeEach(func() {
+			attempts = services.AgentPollAttempts
+			services.AgentPollAttempts = 2
+		})
+
+		AfterEach(func() {
+			services.AgentPollAttempts = attempts
+		})
+
+		It("succeeds once every agent answers", func() {
+			Expect(hub.PingPollAgents()).To(Succeed())
+		})
+
+		It("names the agents that never answered", func() {
+			clusterPair.OldCluster.Segments[0] = cluster.SegConfig{Hostname: "unreachable.invalid"}
+
+			err := hub.PingPollAgents()
+			Expect(err).To(MatchError(HavePrefix("could not ping the agent on unreachable.invalid: ")))
+		})
+	})
+})
@@ -0,0 +1,186 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/greenplum-db/gpupgrade/hub/upgradestatus"
+	pb "github.com/greenplum-db/gpupgrade/idl"
+
+	"github.com/greenplum-db/gp-common-go-libs/gplog"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Checker is a pre-upgrade check, run by CheckAll along with every other
+// registered check.
+type Checker struct {
+	// Name identifies the check in the report, e.g. "disk-space".
+	Name string
+
+	// Severity is how much it matters when the check doesn't pass; only
+	// checks of ERROR severity stop the upgrade.
+	Severity pb.CheckSeverity
+
+	// Hosts returns the hosts that the check runs on.
+	Hosts func(h *Hub) []string
+
+	// Check runs the check on hosts. It returns what it found wrong, or an
+	// error if the check itself could not be run.
+	Check func(h *Hub, in *pb.CheckAllRequest, hosts []string) ([]*pb.CheckFinding, error)
+
+	// Report is used in place of Check by checks that only gather
+	// information. What it returns is reported as notes, which don't keep
+	// the check from passing.
+	Report func(h *Hub, in *pb.CheckAllRequest, hosts []string) ([]*pb.CheckFinding, error)
+}
+
+// CheckerRegistry holds the checks that CheckAll runs, in the order they are
+// reported in.
+type CheckerRegistry struct {
+	mu       sync.Mutex
+	checkers []Checker
+}
+
+func NewCheckerRegistry() *CheckerRegistry {
+	return &CheckerRegistry{}
+}
+
+// DefaultCheckers returns a registry of the checks that are built into the
+// hub.
+func DefaultCheckers() *CheckerRegistry {
+	r := NewCheckerRegistry()
+	r.Register(versionChecker)
+	r.Register(removedTypesChecker)
+	r.Register(seginstallChecker)
+	r.Register(objectCountChecker)
+	r.Register(diskSpaceChecker)
+	return r
+}
+
+// Register adds c to the registry, in place of any check already registered
+// under the same name.
+func (r *CheckerRegistry) Register(c Checker) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, registered := range r.checkers {
+		if registered.Name == c.Name {
+			r.checkers[i] = c
+			return
+		}
+	}
+	r.checkers = append(r.checkers, c)
+}
+
+// Checkers returns the registered checks, in the order they were registered.
+func (r *CheckerRegistry) Checkers() []Checker {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]Checker(nil), r.checkers...)
+}
+
+// CheckAll runs every registered check at once and reports what each one
+// found. The outcome is recorded under upgradestatus.CHECK_ALL, which the
+// upgrade steps require to be COMPLETE: it is only if a check of ERROR
+// severity didn't pass that it is FAILED.
+func (h *Hub) CheckAll(ctx context.Context, in *pb.CheckAllRequest) (*pb.CheckAllReply, error) {
+	gplog.Info("starting CheckAll")
+
+	// The checks find the hosts to run on in the configuration it saves.
+	config := upgradestatus.NewStateCheck(h.conf.StateDir, upgradestatus.CONFIG, pb.UpgradeSteps_CHECK_CONFIG)
+	if config.GetStatus().Status != pb.StepStatus_COMPLETE {
+		return &pb.CheckAllReply{}, status.Errorf(codes.FailedPrecondition,
+			"%s cannot run until %s has completed", upgradestatus.CHECK_ALL, upgradestatus.CONFIG)
+	}
+
+	err := h.checklistWriter.MarkInProgress(upgradestatus.CHECK_ALL)
+	if err != nil {
+		gplog.Error(err.Error())
+		return &pb.CheckAllReply{}, err
+	}
+
+	reply := &pb.CheckAllReply{Results: h.runCheckers(in), Passed: true}
+
+	var failed []string
+	for _, result := range reply.Results {
+		if result.Severity == pb.CheckSeverity_ERROR && !result.Passed {
+			failed = append(failed, result.Name)
+		}
+	}
+
+	if len(failed) != 0 {
+		reply.Passed = false
+		err = h.checklistWriter.MarkFailed(upgradestatus.CHECK_ALL, fmt.Errorf("checks failed: %s", strings.Join(failed, ", ")))
+	} else {
+		err = h.checklistWriter.MarkComplete(upgradestatus.CHECK_ALL)
+	}
+	if err != nil {
+		gplog.Error("failed to record the outcome of %s: %s", upgradestatus.CHECK_ALL, err)
+	}
+
+	return reply, nil
+}
+
+// runCheckers runs the registered checks concurrently, and returns their
+// results in the order the checks were registered.
+func (h *Hub) runCheckers(in *pb.CheckAllRequest) []*pb.CheckResult {
+	checkers := h.checkers.Checkers()
+	results := make([]*pb.CheckResult, len(checkers))
+
+	var wg sync.WaitGroup
+	for i, c := range checkers {
+		wg.Add(1)
+		go func(i int, c Checker) {
+			defer wg.Done()
+			results[i] = h.runChecker(c, in)
+		}(i, c)
+	}
+	wg.Wait()
+
+	return results
+}
+
+func (h *Hub) runChecker(c Checker, in *pb.CheckAllRequest) *pb.CheckResult {
+	result := &pb.CheckResult{Name: c.Name, Severity: c.Severity}
+	if c.Hosts != nil {
+		result.Hosts = c.Hosts(h)
+	}
+
+	var err error
+	if c.Report != nil {
+		result.Notes, err = c.Report(h, in, result.Hosts)
+	} else {
+		result.Findings, err = c.Check(h, in, result.Hosts)
+	}
+	if err != nil {
+		gplog.Error("could not run the %s check: %s", c.Name, err)
+		result.Error = err.Error()
+	}
+	for _, finding := range result.Findings {
+		gplog.Info("%s check: %s: %s", c.Name, finding.Host, finding.Message)
+	}
+	for _, note := range result.Notes {
+		gplog.Info("%s check: %s: %s", c.Name, note.Host, note.Message)
+	}
+
+	result.Passed = err == nil && len(result.Findings) == 0
+	return result
+}
+
+// masterHost is a Checker.Hosts for checks that run on the master.
+func masterHost(h *Hub) []string {
+	return []string{h.clusterPair.OldCluster.GetHostForContent(-1)}
+}
+
+// segmentHosts is a Checker.Hosts for checks that run on every host with a
+// segment of the old cluster.
+func segmentHosts(h *Hub) []string {
+	hostnames := h.clusterPair.GetHostnames()
+	sort.Strings(hostnames)
+	return hostnames
+}
@@ -0,0 +1,311 @@
+package services_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"regexp"
+
+	"github.com/greenplum-db/gpupgrade/hub/cluster_ssher"
+	"github.com/greenplum-db/gpupgrade/hub/services"
+	"github.com/greenplum-db/gpupgrade/hub/upgradestatus"
+	pb "github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/testutils"
+	"github.com/greenplum-db/gpupgrade/utils"
+
+	"github.com/greenplum-db/gp-common-go-libs/cluster"
+	"github.com/greenplum-db/gp-common-go-libs/dbconn"
+	"github.com/greenplum-db/gp-common-go-libs/testhelper"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CheckAll", func() {
+	var (
+		dir           string
+		hub           *services.Hub
+		clusterPair   *services.ClusterPair
+		checkers      *services.CheckerRegistry
+		cm            *testutils.MockChecklistManager
+		commandExecer *testutils.FakeCommandExecer
+		errChan       chan error
+		outChan       chan []byte
+	)
+
+	BeforeEach(func() {
+		testhelper.SetupTestLogger()
+
+		var err error
+		dir, err = ioutil.TempDir("", "")
+		Expect(err).ToNot(HaveOccurred())
+		setStepStatus(dir, upgradestatus.CONFIG, pb.StepStatus_COMPLETE)
+
+		errChan = make(chan error, 2)
+		outChan = make(chan []byte, 2)
+		commandExecer = &testutils.FakeCommandExecer{}
+		commandExecer.SetOutput(&testutils.FakeCommand{Err: errChan, Out: outChan})
+
+		checkers = services.NewCheckerRegistry()
+		cm = testutils.NewMockChecklistManager()
+		conf := &services.HubConfig{
+			StateDir: dir,
+			Checkers: checkers,
+			Backend:  cluster_ssher.NewSSHBackend(commandExecer.Exec, cluster_ssher.SSHConfig{}),
+		}
+		clusterPair = testutils.CreateSampleClusterPair()
+		hub = services.NewHub(clusterPair, grpc.DialContext, commandExecer.Exec, conf, nil, cm)
+	})
+
+	AfterEach(func() {
+		utils.System = utils.InitializeSystemFunctions()
+		os.RemoveAll(dir)
+	})
+
+	checker := func(name string, severity pb.CheckSeverity, findings []*pb.CheckFinding, err error) services.Checker {
+		return services.Checker{
+			Name:     name,
+			Severity: severity,
+			Hosts:    func(h *services.Hub) []string { return []string{"hostone"} },
+			Check: func(h *services.Hub, in *pb.CheckAllRequest, hosts []string) ([]*pb.CheckFinding, error) {
+				return findings, err
+			},
+		}
+	}
+
+	It("runs the checks at once and reports their results in the order they were registered", func() {
+		// Neither check can finish before the other has started.
+		first, second := make(chan struct{}), make(chan struct{})
+		checkers.Register(services.Checker{
+			Name:     "first",
+			Severity: pb.CheckSeverity_ERROR,
+			Check: func(h *services.Hub, in *pb.CheckAllRequest, hosts []string) ([]*pb.CheckFinding, error) {
+				close(first)
+				<-second
+				return nil, nil
+			},
+		})
+		checkers.Register(services.Checker{
+			Name:     "second",
+			Severity: pb.CheckSeverity_WARNING,
+			Check: func(h *services.Hub, in *pb.CheckAllRequest, hosts []string) ([]*pb.CheckFinding, error) {
+				close(second)
+				<-first
+				return []*pb.CheckFinding{{Host: "hostone", Message: "almost full"}}, nil
+			},
+		})
+
+		reply, err := hub.CheckAll(nil, &pb.CheckAllRequest{})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(reply.Results).To(Equal([]*pb.CheckResult{
+			{Name: "first", Severity: pb.CheckSeverity_ERROR, Passed: true},
+			{Name: "second", Severity: pb.CheckSeverity_WARNING, Findings: []*pb.CheckFinding{{Host: "hostone", Message: "almost full"}}},
+		}))
+		Expect(reply.Passed).To(BeTrue())
+		Expect(cm.IsComplete(upgradestatus.CHECK_ALL)).To(BeTrue())
+	})
+
+	It("fails if a check of error severity finds something, or can't be run", func() {
+		checkers.Register(checker("found", pb.CheckSeverity_ERROR, []*pb.CheckFinding{{Host: "hostone", Message: "too old"}}, nil))
+		checkers.Register(checker("broken", pb.CheckSeverity_ERROR, nil, errors.New("no connection")))
+		checkers.Register(checker("info", pb.CheckSeverity_INFO, []*pb.CheckFinding{{Host: "hostone", Message: "3 tables"}}, nil))
+
+		reply, err := hub.CheckAll(nil, &pb.CheckAllRequest{})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(reply.Passed).To(BeFalse())
+		Expect(reply.Results[0].Hosts).To(Equal([]string{"hostone"}))
+		Expect(reply.Results[1].Passed).To(BeFalse())
+		Expect(reply.Results[1].Error).To(Equal("no connection"))
+
+		Expect(cm.IsFailed(upgradestatus.CHECK_ALL)).To(BeTrue())
+		Expect(cm.FailureCause(upgradestatus.CHECK_ALL)).To(MatchError("checks failed: found, broken"))
+	})
+
+	It("reports what a check gathers for information as notes, which don't keep it from passing", func() {
+		checkers.Register(services.Checker{
+			Name:     "object-count",
+			Severity: pb.CheckSeverity_INFO,
+			Report: func(h *services.Hub, in *pb.CheckAllRequest, hosts []string) ([]*pb.CheckFinding, error) {
+				return []*pb.CheckFinding{{Host: "hostone", Message: "database postgres has 0 append-optimized and 3 heap tables"}}, nil
+			},
+		})
+
+		reply, err := hub.CheckAll(nil, &pb.CheckAllRequest{})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(reply.Results).To(Equal([]*pb.CheckResult{{
+			Name:     "object-count",
+			Severity: pb.CheckSeverity_INFO,
+			Passed:   true,
+			Notes:    []*pb.CheckFinding{{Host: "hostone", Message: "database postgres has 0 append-optimized and 3 heap tables"}},
+		}}))
+	})
+
+	It("replaces a check registered under the same name", func() {
+		checkers.Register(checker("version", pb.CheckSeverity_ERROR, []*pb.CheckFinding{{Host: "hostone", Message: "too old"}}, nil))
+		checkers.Register(checker("version", pb.CheckSeverity_ERROR, nil, nil))
+
+		reply, err := hub.CheckAll(nil, &pb.CheckAllRequest{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(reply.Results).To(HaveLen(1))
+		Expect(reply.Passed).To(BeTrue())
+	})
+
+	It("refuses to run before the configuration has been checked", func() {
+		setStepStatus(dir, upgradestatus.CONFIG, pb.StepStatus_FAILED)
+
+		_, err := hub.CheckAll(nil, &pb.CheckAllRequest{})
+		Expect(status.Code(err)).To(Equal(codes.FailedPrecondition))
+		Expect(cm.IsInProgress(upgradestatus.CHECK_ALL)).To(BeFalse())
+	})
+
+	It("checks the disk space for the master's new data dir as well as the primaries'", func() {
+		mockAgent, port := testutils.NewMockAgentServer()
+		defer mockAgent.Stop()
+		mockAgent.CheckDiskSpaceResponse = &pb.CheckDiskSpaceReplyFromAgent{DataDirSpaces: []*pb.DataDirSpace{
+			{Content: -1, NewDataDir: "/new/datadir", Required: 300, RequiredOnFilesystem: 300, Available: 200},
+			{Content: 0, NewDataDir: "/new/datadir0", Required: 100, RequiredOnFilesystem: 100, Available: 200, Sufficient: true},
+		}}
+
+		for _, c := range services.DefaultCheckers().Checkers() {
+			if c.Name == "disk-space" {
+				checkers.Register(c)
+			}
+		}
+
+		clusterPair.OldCluster = cluster.NewCluster([]cluster.SegConfig{
+			{ContentID: -1, Hostname: "localhost", Port: 15432, DataDir: "/old/datadir"},
+			{ContentID: 0, Hostname: "localhost", Port: 25432, DataDir: "/old/datadir0"},
+		})
+		clusterPair.NewCluster = cluster.NewCluster([]cluster.SegConfig{
+			{ContentID: -1, Hostname: "localhost", Port: 15433, DataDir: "/new/datadir"},
+			{ContentID: 0, Hostname: "localhost", Port: 25433, DataDir: "/new/datadir0"},
+		})
+		hub = services.NewHub(clusterPair, grpc.DialContext, commandExecer.Exec,
+			&services.HubConfig{StateDir: dir, HubToAgentPort: port, Checkers: checkers}, nil, cm)
+
+		reply, err := hub.CheckAll(nil, &pb.CheckAllRequest{})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(mockAgent.CheckDiskSpaceRequest.DataDirPairs).To(Equal([]*pb.DataDirPair{
+			{Content: -1, OldDataDir: "/old/datadir", NewDataDir: "/new/datadir", OldPort: 15432, NewPort: 15433},
+			{Content: 0, OldDataDir: "/old/datadir0", NewDataDir: "/new/datadir0", OldPort: 25432, NewPort: 25433},
+		}))
+		Expect(reply.Results[0].Findings).To(Equal([]*pb.CheckFinding{{
+			Host:    "localhost",
+			Message: "the master needs 300 bytes in /new/datadir for copy mode; 200 bytes are free",
+		}}))
+		Expect(reply.Passed).To(BeFalse())
+	})
+
+	It("checks that the software is installed on every segment host", func() {
+		for _, c := range services.DefaultCheckers().Checkers() {
+			if c.Name == "seginstall" {
+				checkers.Register(c)
+			}
+		}
+
+		clusterPair.OldCluster.Segments[0] = cluster.SegConfig{ContentID: 0, Hostname: "hosttwo", DataDir: "/old/datadir0"}
+
+		errChan <- nil
+		outChan <- nil
+		errChan <- errors.New("exit status 2")
+		outChan <- []byte("ls: cannot access gpupgrade_agent\n")
+
+		reply, err := hub.CheckAll(nil, &pb.CheckAllRequest{})
+		Expect(err).ToNot(HaveOccurred())
+
+		result := reply.Results[0]
+		Expect(result.Hosts).To(Equal([]string{"hostone", "hosttwo"}))
+		Expect(result.Findings).To(HaveLen(1))
+		Expect(result.Findings[0].Host).To(Equal("hosttwo"))
+		Expect(result.Findings[0].Message).To(HaveSuffix("gpupgrade_agent was not found: exit status 2: ls: cannot access gpupgrade_agent"))
+		Expect(reply.Passed).To(BeFalse())
+	})
+
+	Describe("the removed-types check", func() {
+		var expectTypeQueries func(mock sqlmock.Sqlmock)
+
+		BeforeEach(func() {
+			for _, c := range services.DefaultCheckers().Checkers() {
+				if c.Name == "removed-types" {
+					checkers.Register(c)
+				}
+			}
+
+			hub = services.NewHub(clusterPair, grpc.DialContext, commandExecer.Exec, &services.HubConfig{
+				StateDir: dir,
+				Checkers: checkers,
+				Backend:  cluster_ssher.NewSSHBackend(commandExecer.Exec, cluster_ssher.SSHConfig{}),
+				DBConn: func(host string, port int, dbname string) *dbconn.DBConn {
+					conn, mock := testhelper.CreateMockDBConn()
+					// once to connect, and once more to initialize the version
+					testhelper.ExpectVersionQuery(mock, "5.10.2")
+					testhelper.ExpectVersionQuery(mock, "5.10.2")
+					if dbname == "template1" {
+						mock.ExpectQuery("SELECT datname FROM pg_database").
+							WillReturnRows(sqlmock.NewRows([]string{"datname"}).AddRow("sales"))
+					} else {
+						expectTypeQueries(mock)
+					}
+					return conn
+				},
+			}, nil, cm)
+		})
+
+		newVersion := func(version string) {
+			errChan <- nil
+			outChan <- []byte("postgres (Greenplum Database) " + version + " build commit:b3c02f3acd880e2d676dacea36be015e4a3826d4\n")
+		}
+
+		It("only looks for the types that the new version refuses", func() {
+			newVersion("6.0.0")
+			expectTypeQueries = func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta("t.typname IN ('regproc', 'regprocedure', 'regoper', 'regoperator', " +
+					"'regconfig', 'regdictionary', 'regnamespace')")).
+					WillReturnRows(sqlmock.NewRows([]string{"oid", "typname"}))
+			}
+
+			reply, err := hub.CheckAll(nil, &pb.CheckAllRequest{DbPort: 15432})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(reply.Results[0].Error).To(BeEmpty())
+			Expect(reply.Results[0].Hosts).To(Equal([]string{"hostone"}))
+			Expect(reply.Passed).To(BeTrue())
+		})
+
+		It("finds the columns of removed types and of the types built on them", func() {
+			newVersion("7.0.0")
+			expectTypeQueries = func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta("t.typname IN ('abstime', ")).
+					WillReturnRows(sqlmock.NewRows([]string{"oid", "typname"}).AddRow(702, "abstime"))
+				// a domain over abstime, then a composite type of the domain
+				mock.ExpectQuery(`typbasetype IN \(702\)`).
+					WillReturnRows(sqlmock.NewRows([]string{"oid", "base"}).AddRow(16390, 702))
+				mock.ExpectQuery(`typbasetype IN \(16390\)`).
+					WillReturnRows(sqlmock.NewRows([]string{"oid", "base"}).AddRow(16395, 16390))
+				mock.ExpectQuery(`typbasetype IN \(16395\)`).
+					WillReturnRows(sqlmock.NewRows([]string{"oid", "base"}))
+				mock.ExpectQuery(`a.atttypid IN \(702, 16390, 16395\)`).
+					WillReturnRows(sqlmock.NewRows([]string{"nspname", "relname", "attname", "typname", "atttypid"}).
+						AddRow("public", "events", "happened", "abstime", 702).
+						AddRow("public", "events", "during", "happening", 16390).
+						AddRow("public", "schedules", "slot", "booking", 16395))
+			}
+
+			reply, err := hub.CheckAll(nil, &pb.CheckAllRequest{DbPort: 15432})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(reply.Results[0].Findings).To(Equal([]*pb.CheckFinding{
+				{Host: "hostone", Message: "database sales: public.events.happened is of type abstime, which Greenplum 7 no longer has"},
+				{Host: "hostone", Message: "database sales: public.events.during is of type happening, which is built on abstime, which Greenplum 7 no longer has"},
+				{Host: "hostone", Message: "database sales: public.schedules.slot is of type booking, which is built on abstime, which Greenplum 7 no longer has"},
+			}))
+			Expect(reply.Passed).To(BeFalse())
+		})
+	})
+})
@@ -33,7 +33,7 @@ func (h *Hub) CheckConfig(ctx context.Context, in *pb.CheckConfigRequest) (*pb.C
 	defer dbConnector.Close()
 	err = dbConnector.Connect(1)
 	if err != nil {
-		c.MarkFailed(checkConfigStep)
+		c.MarkFailed(checkConfigStep, err)
 		gplog.Error(err.Error())
 		return &pb.CheckConfigReply{}, utils.DatabaseConnectionError{Parent: err}
 	}
@@ -41,7 +41,7 @@ func (h *Hub) CheckConfig(ctx context.Context, in *pb.CheckConfigRequest) (*pb.C
 
 	err = SaveOldClusterConfig(h.clusterPair, dbConnector, h.conf.StateDir, in.OldBinDir)
 	if err != nil {
-		c.MarkFailed(checkConfigStep)
+		c.MarkFailed(checkConfigStep, err)
 		gplog.Error(err.Error())
 		return &pb.CheckConfigReply{}, err
 	}
@@ -2,64 +2,181 @@ package services
 
 import (
 	"fmt"
-	"strconv"
+	"sort"
+	"sync"
 
+	"github.com/greenplum-db/gpupgrade/config"
 	pb "github.com/greenplum-db/gpupgrade/idl"
 
 	"github.com/greenplum-db/gp-common-go-libs/gplog"
+	"github.com/pkg/errors"
 	"golang.org/x/net/context"
-	"google.golang.org/grpc"
-)
-
-const (
-	// todo generalize to any host
-	diskUsageWarningLimit = 80
 )
 
+// CheckDiskSpace reports, for the master and each primary, whether the
+// filesystem of its new data dir has room for what pg_upgrade will write
+// there. A host whose agent can't be asked is reported with the reason,
+// rather than failing the whole check.
 func (h *Hub) CheckDiskSpace(ctx context.Context,
 	in *pb.CheckDiskSpaceRequest) (*pb.CheckDiskSpaceReply, error) {
 
 	gplog.Info("starting CheckDiskSpace")
-	var replyMessages []string
-	hostnames := h.clusterPair.GetHostnames()
-	var clients []ClientAndHostname
-	for i := 0; i < len(hostnames); i++ {
-		conn, err := grpc.Dial(hostnames[i]+":"+strconv.Itoa(h.conf.HubToAgentPort), grpc.WithInsecure())
-		if err == nil {
-			clients = append(clients, ClientAndHostname{Client: pb.NewAgentClient(conn), Hostname: hostnames[i]})
-			defer conn.Close()
-		} else {
-			gplog.Error(err.Error())
-			replyMessages = append(replyMessages, "ERROR: couldn't get gRPC conn to "+hostnames[i])
-		}
+	dataDirPairs, err := h.diskSpaceDataDirPairs()
+	if err != nil {
+		gplog.Error(err.Error())
+		return &pb.CheckDiskSpaceReply{}, err
 	}
-	replyMessages = append(replyMessages, GetDiskSpaceFromSegmentHosts(clients)...)
 
-	return &pb.CheckDiskSpaceReply{SegmentFileSysUsage: replyMessages}, nil
-}
+	connsByHost, err := h.agentConnsByHost()
+	if err != nil {
+		gplog.Error(err.Error())
+		return &pb.CheckDiskSpaceReply{}, err
+	}
 
-func GetDiskSpaceFromSegmentHosts(clients []ClientAndHostname) []string {
-	replyMessages := []string{}
-	for i := 0; i < len(clients); i++ {
-		reply, err := clients[i].Client.CheckDiskSpaceOnAgents(context.Background(),
-			&pb.CheckDiskSpaceRequestToAgent{})
+	linkMode := h.linkMode()
+	hostnames := segmentHosts(h)
+	hosts := make([]*pb.HostDiskSpace, len(hostnames))
+	byHost := make(map[string]*pb.HostDiskSpace)
+	for i, host := range hostnames {
+		hosts[i] = &pb.HostDiskSpace{Hostname: host}
+		byHost[host] = hosts[i]
+	}
+
+	// Each host's failure is recorded in its entry, so forEachHost never
+	// fails.
+	h.forEachHost(hostnames, "get the disk space of", func(host string) error {
+		space := byHost[host]
+
+		conn, ok := connsByHost[host]
+		if !ok {
+			space.Error = "no agent is connected"
+			return nil
+		}
+
+		spaces, err := h.dataDirSpaces(pb.NewAgentClient(conn.Conn), dataDirPairs[host])
 		if err != nil {
-			gplog.Error(err.Error())
-			replyMessages = append(replyMessages, "Could not get disk usage from: "+clients[i].Hostname)
-			continue
+			gplog.Error("could not get the disk space of %s: %s", host, err)
+			space.Error = err.Error()
+			return nil
 		}
-		foundAnyTooFull := false
-		for _, line := range reply.ListOfFileSysUsage {
-			if line.Usage >= diskUsageWarningLimit {
-				replyMessages = append(replyMessages, fmt.Sprintf("diskspace check - %s - WARNING %s %.1f use",
-					clients[i].Hostname, line.Filesystem, line.Usage))
-				foundAnyTooFull = true
-			}
+		space.DataDirSpaces = spaces
+		return nil
+	})
+
+	return &pb.CheckDiskSpaceReply{Hosts: hosts, LinkMode: linkMode}, nil
+}
+
+// diskSpaceChecker fails for the master and each primary whose new data dir
+// is on a filesystem without room for what pg_upgrade will write there.
+var diskSpaceChecker = Checker{
+	Name:     "disk-space",
+	Severity: pb.CheckSeverity_ERROR,
+	Hosts:    segmentHosts,
+	Check: func(h *Hub, in *pb.CheckAllRequest, hosts []string) ([]*pb.CheckFinding, error) {
+		dataDirPairs, err := h.diskSpaceDataDirPairs()
+		if err != nil {
+			return nil, err
 		}
-		if !foundAnyTooFull {
-			replyMessages = append(replyMessages, fmt.Sprintf("diskspace check - %s - OK", clients[i].Hostname))
+
+		connsByHost, err := h.agentConnsByHost()
+		if err != nil {
+			return nil, err
 		}
+
+		linkMode := h.linkMode()
+		var mu sync.Mutex
+		var findings []*pb.CheckFinding
+		err = h.forEachHost(hosts, "get the disk space of", func(host string) error {
+			conn, ok := connsByHost[host]
+			if !ok {
+				return fmt.Errorf("no agent is connected on %s", host)
+			}
+
+			spaces, err := h.dataDirSpaces(pb.NewAgentClient(conn.Conn), dataDirPairs[host])
+			if err != nil {
+				return err
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			for _, space := range spaces {
+				if !space.Sufficient {
+					findings = append(findings, &pb.CheckFinding{Host: host, Message: describeDataDirSpace(space, linkMode)})
+				}
+			}
+			return nil
+		})
+
+		sort.SliceStable(findings, func(i, j int) bool { return findings[i].Host < findings[j].Host })
+		return findings, err
+	},
+}
+
+// diskSpaceDataDirPairs returns the old and new data dirs of the master and
+// the primaries on each host, which are only known once the new cluster has
+// been initialized.
+func (h *Hub) diskSpaceDataDirPairs() (map[string][]*pb.DataDirPair, error) {
+	if h.clusterPair.NewCluster == nil || len(h.clusterPair.NewCluster.ContentIDs) == 0 {
+		return nil, errors.New("the new cluster's data dirs are not known until prepare init-cluster has run")
 	}
 
-	return replyMessages
+	dataDirPairs, err := h.getDataDirPairs()
+	if err != nil {
+		return nil, err
+	}
+
+	oldDataDir, newDataDir := h.clusterPair.GetMasterDataDirs()
+	oldPort, newPort := h.clusterPair.GetMasterPorts()
+	master := h.clusterPair.OldCluster.GetHostForContent(-1)
+	dataDirPairs[master] = append([]*pb.DataDirPair{{
+		OldDataDir: oldDataDir,
+		NewDataDir: newDataDir,
+		OldPort:    int32(oldPort),
+		NewPort:    int32(newPort),
+		Content:    -1,
+	}}, dataDirPairs[master]...)
+
+	return dataDirPairs, nil
+}
+
+// linkMode returns whether pg_upgrade is to hard link the data files rather
+// than copy them.
+func (h *Hub) linkMode() bool {
+	return h.conf.UpgradeMode == config.ModeLink
+}
+
+// dataDirSpaces asks the agent at client whether the filesystems of the new
+// data dirs in pairs have room for them, with the configured margin.
+func (h *Hub) dataDirSpaces(client pb.AgentClient, pairs []*pb.DataDirPair) ([]*pb.DataDirSpace, error) {
+	reply, err := client.CheckDiskSpaceOnAgents(context.Background(), &pb.CheckDiskSpaceRequestToAgent{
+		DataDirPairs:  pairs,
+		LinkMode:      h.linkMode(),
+		MarginPercent: int32(h.conf.DiskSpaceMarginPercent),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return reply.DataDirSpaces, nil
+}
+
+// describeDataDirSpace explains how much room a segment needs and has.
+func describeDataDirSpace(space *pb.DataDirSpace, linkMode bool) string {
+	segment := fmt.Sprintf("segment %d", space.Content)
+	if space.Content == -1 {
+		segment = "the master"
+	}
+	if space.Error != "" {
+		return fmt.Sprintf("%s: %s", segment, space.Error)
+	}
+
+	mode := config.ModeCopy
+	if linkMode {
+		mode = config.ModeLink
+	}
+	description := fmt.Sprintf("%s needs %d bytes in %s for %s mode", segment, space.Required, space.NewDataDir, mode)
+	if space.RequiredOnFilesystem != space.Required {
+		description += fmt.Sprintf(", and %d bytes with the segments that share its filesystem", space.RequiredOnFilesystem)
+	}
+	return description + fmt.Sprintf("; %d bytes are free", space.Available)
 }
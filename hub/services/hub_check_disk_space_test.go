@@ -1,84 +1,107 @@
 package services_test
 
 import (
-	pb "github.com/greenplum-db/gpupgrade/idl"
-	mockpb "github.com/greenplum-db/gpupgrade/mock_idl"
+	"errors"
+	"io/ioutil"
+	"os"
 
+	"github.com/greenplum-db/gpupgrade/config"
 	"github.com/greenplum-db/gpupgrade/hub/services"
+	pb "github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/testutils"
+	"github.com/greenplum-db/gpupgrade/utils"
 
-	"github.com/golang/mock/gomock"
+	"github.com/greenplum-db/gp-common-go-libs/cluster"
+	"github.com/greenplum-db/gp-common-go-libs/testhelper"
+	"google.golang.org/grpc"
 
-	"github.com/greenplum-db/gpupgrade/utils"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
-	"github.com/pkg/errors"
 )
 
-var _ = Describe("object count tests", func() {
+var _ = Describe("CheckDiskSpace", func() {
 	var (
-		client *mockpb.MockAgentClient
-		ctrl   *gomock.Controller
+		dir         string
+		mockAgent   *testutils.MockAgentServer
+		clusterPair *services.ClusterPair
+		conf        *services.HubConfig
 	)
 
 	BeforeEach(func() {
-		ctrl = gomock.NewController(GinkgoT())
-		client = mockpb.NewMockAgentClient(ctrl)
+		testhelper.SetupTestLogger()
+
+		var err error
+		dir, err = ioutil.TempDir("", "")
+		Expect(err).ToNot(HaveOccurred())
+
+		var port int
+		mockAgent, port = testutils.NewMockAgentServer()
+
+		clusterPair = &services.ClusterPair{
+			OldCluster: cluster.NewCluster([]cluster.SegConfig{
+				{ContentID: -1, Hostname: "localhost", Port: 15432, DataDir: "/old/datadir"},
+				{ContentID: 0, Hostname: "localhost", Port: 25432, DataDir: "/old/datadir0"},
+			}),
+			NewCluster: cluster.NewCluster([]cluster.SegConfig{
+				{ContentID: -1, Hostname: "localhost", Port: 15433, DataDir: "/new/datadir"},
+				{ContentID: 0, Hostname: "localhost", Port: 25433, DataDir: "/new/datadir0"},
+			}),
+		}
+		conf = &services.HubConfig{StateDir: dir, HubToAgentPort: port}
 	})
 
 	AfterEach(func() {
+		mockAgent.Stop()
 		utils.System = utils.InitializeSystemFunctions()
-		ctrl.Finish()
+		os.RemoveAll(dir)
+	})
+
+	checkDiskSpace := func() (*pb.CheckDiskSpaceReply, error) {
+		hub := services.NewHub(clusterPair, grpc.DialContext, nil, conf, nil, nil)
+		return hub.CheckDiskSpace(nil, &pb.CheckDiskSpaceRequest{})
+	}
+
+	It("reports the space for the new data dirs of every segment on each host", func() {
+		spaces := []*pb.DataDirSpace{
+			{Content: -1, NewDataDir: "/new/datadir", Required: 300, RequiredOnFilesystem: 300, Available: 200},
+			{Content: 0, NewDataDir: "/new/datadir0", Required: 100, RequiredOnFilesystem: 100, Available: 2000, Sufficient: true},
+		}
+		mockAgent.CheckDiskSpaceResponse = &pb.CheckDiskSpaceReplyFromAgent{DataDirSpaces: spaces}
+		conf.UpgradeMode = config.ModeLink
+		conf.DiskSpaceMarginPercent = 10
+
+		reply, err := checkDiskSpace()
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(mockAgent.CheckDiskSpaceRequest.DataDirPairs).To(HaveLen(2))
+		Expect(mockAgent.CheckDiskSpaceRequest.LinkMode).To(BeTrue())
+		Expect(mockAgent.CheckDiskSpaceRequest.MarginPercent).To(Equal(int32(10)))
+		Expect(reply.LinkMode).To(BeTrue())
+		Expect(reply.Hosts).To(HaveLen(1))
+		Expect(reply.Hosts[0].Hostname).To(Equal("localhost"))
+		Expect(reply.Hosts[0].Error).To(BeEmpty())
+		Expect(reply.Hosts[0].DataDirSpaces).To(HaveLen(2))
+		Expect(reply.Hosts[0].DataDirSpaces[0].Content).To(Equal(int32(-1)))
+		Expect(reply.Hosts[0].DataDirSpaces[0].Sufficient).To(BeFalse())
+		Expect(reply.Hosts[0].DataDirSpaces[1].Available).To(Equal(uint64(2000)))
 	})
 
-	Describe("GetDiskUsageFromSegmentHosts", func() {
-		It("returns err msg when unable to call CheckDiskSpaceOnAgents on segment host", func() {
-			var clients []services.ClientAndHostname
-
-			client.EXPECT().CheckDiskSpaceOnAgents(
-				gomock.Any(),
-				&pb.CheckDiskSpaceRequestToAgent{},
-			).Return(&pb.CheckDiskSpaceReplyFromAgent{}, errors.New("couldn't connect to hub"))
-			clients = append(clients, services.ClientAndHostname{Client: client, Hostname: "doesnotexist"})
-
-			messages := services.GetDiskSpaceFromSegmentHosts(clients)
-			Expect(len(messages)).To(Equal(1))
-			Expect(messages[0]).To(ContainSubstring("Could not get disk usage from: "))
-		})
-
-		It("lists filesystems above usage threshold", func() {
-			var clients []services.ClientAndHostname
-
-			var expectedFilesystemsUsage []*pb.FileSysUsage
-			expectedFilesystemsUsage = append(expectedFilesystemsUsage, &pb.FileSysUsage{Filesystem: "first filesystem", Usage: 90.4})
-			expectedFilesystemsUsage = append(expectedFilesystemsUsage, &pb.FileSysUsage{Filesystem: "/second/filesystem", Usage: 24.2})
-
-			client.EXPECT().CheckDiskSpaceOnAgents(
-				gomock.Any(),
-				&pb.CheckDiskSpaceRequestToAgent{},
-			).Return(&pb.CheckDiskSpaceReplyFromAgent{ListOfFileSysUsage: expectedFilesystemsUsage}, nil)
-			clients = append(clients, services.ClientAndHostname{Client: client, Hostname: "doesnotexist"})
-
-			messages := services.GetDiskSpaceFromSegmentHosts(clients)
-			Expect(len(messages)).To(Equal(1))
-			Expect(messages[0]).To(ContainSubstring("diskspace check - doesnotexist - WARNING first filesystem 90.4 use"))
-		})
-
-		It("lists hosts for which all filesystems are below usage threshold", func() {
-			var clients []services.ClientAndHostname
-
-			var expectedFilesystemsUsage []*pb.FileSysUsage
-			expectedFilesystemsUsage = append(expectedFilesystemsUsage, &pb.FileSysUsage{Filesystem: "first filesystem", Usage: 70.4})
-			expectedFilesystemsUsage = append(expectedFilesystemsUsage, &pb.FileSysUsage{Filesystem: "/second/filesystem", Usage: 24.2})
-
-			client.EXPECT().CheckDiskSpaceOnAgents(
-				gomock.Any(),
-				&pb.CheckDiskSpaceRequestToAgent{},
-			).Return(&pb.CheckDiskSpaceReplyFromAgent{ListOfFileSysUsage: expectedFilesystemsUsage}, nil)
-			clients = append(clients, services.ClientAndHostname{Client: client, Hostname: "doesnotexist"})
-
-			messages := services.GetDiskSpaceFromSegmentHosts(clients)
-			Expect(len(messages)).To(Equal(1))
-			Expect(messages[0]).To(ContainSubstring("diskspace check - doesnotexist - OK"))
-		})
+	It("reports a host whose agent could not be asked, rather than failing", func() {
+		mockAgent.Err <- errors.New("statfs failed")
+
+		reply, err := checkDiskSpace()
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(reply.Hosts).To(HaveLen(1))
+		Expect(reply.Hosts[0].Hostname).To(Equal("localhost"))
+		Expect(reply.Hosts[0].Error).To(ContainSubstring("statfs failed"))
+		Expect(reply.Hosts[0].DataDirSpaces).To(BeEmpty())
+	})
+
+	It("fails before the new cluster has been initialized", func() {
+		clusterPair.NewCluster = nil
+
+		_, err := checkDiskSpace()
+		Expect(err).To(MatchError(ContainSubstring("prepare init-cluster")))
 	})
 })
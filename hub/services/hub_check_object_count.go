@@ -1,7 +1,8 @@
 package services
 
 import (
-	"github.com/greenplum-db/gpupgrade/db"
+	"fmt"
+
 	pb "github.com/greenplum-db/gpupgrade/idl"
 	"github.com/greenplum-db/gpupgrade/utils"
 
@@ -16,42 +17,98 @@ func (h *Hub) CheckObjectCount(ctx context.Context,
 
 	gplog.Info("starting CheckObjectCount")
 
-	dbConnector := db.NewDBConn("localhost", int(in.DbPort), "template1")
+	results, err := h.countObjects(in.DbPort)
+	if err != nil {
+		return &pb.CheckObjectCountReply{}, err
+	}
+
+	successReply := &pb.CheckObjectCountReply{ListOfCounts: results}
+	return successReply, nil
+}
+
+// objectCountChecker reports how many append-optimized and heap tables each
+// database has, which is what pg_upgrade's running time depends on.
+var objectCountChecker = Checker{
+	Name:     "object-count",
+	Severity: pb.CheckSeverity_INFO,
+	Hosts:    masterHost,
+	Report: func(h *Hub, in *pb.CheckAllRequest, hosts []string) ([]*pb.CheckFinding, error) {
+		counts, err := h.countObjects(in.DbPort)
+		if err != nil {
+			return nil, err
+		}
+
+		var notes []*pb.CheckFinding
+		for _, count := range counts {
+			notes = append(notes, &pb.CheckFinding{
+				Host: hosts[0],
+				Message: fmt.Sprintf("database %s has %d append-optimized and %d heap tables",
+					count.DbName, count.AoCount, count.HeapCount),
+			})
+		}
+		return notes, nil
+	},
+}
+
+// countObjects counts the append-optimized and heap tables in each database
+// of the cluster whose master listens on port.
+func (h *Hub) countObjects(port int32) ([]*pb.CountPerDb, error) {
+	var results []*pb.CountPerDb
+	err := h.forEachDatabase(port, func(name string, dbConnector *dbconn.DBConn) error {
+		aocount, heapcount, errFromCounts := GetCountsForDb(dbConnector)
+		if errFromCounts != nil {
+			gplog.Error(errFromCounts.Error())
+			return errors.New(errFromCounts.Error())
+		}
+		results = append(results, &pb.CountPerDb{DbName: name, AoCount: aocount, HeapCount: heapcount})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// forEachDatabase connects to each database but template0 of the cluster
+// whose master listens on port, in turn, and calls f with its connection. It
+// stops at the first error.
+func (h *Hub) forEachDatabase(port int32, f func(name string, dbConnector *dbconn.DBConn) error) error {
+	dbConnector := h.dbConn("localhost", int(port), "template1")
 	defer dbConnector.Close()
 	err := dbConnector.Connect(1)
 	if err != nil {
 		gplog.Error(err.Error())
-		return &pb.CheckObjectCountReply{}, utils.DatabaseConnectionError{Parent: err}
+		return utils.DatabaseConnectionError{Parent: err}
 	}
 	dbConnector.Version.Initialize(dbConnector)
 	names, err := dbconn.SelectStringSlice(dbConnector, GET_DATABASE_NAMES)
 	if err != nil {
 		gplog.Error(err.Error())
-		return &pb.CheckObjectCountReply{}, errors.New(err.Error())
+		return errors.New(err.Error())
 	}
 
-	var results []*pb.CountPerDb
-	for i := 0; i < len(names); i++ {
-
-		dbConnector = db.NewDBConn("localhost", int(in.DbPort), names[i])
-		defer dbConnector.Close()
-		err = dbConnector.Connect(1)
+	for _, name := range names {
+		err = h.forDatabase(port, name, f)
 		if err != nil {
-			gplog.Error(err.Error())
-			return &pb.CheckObjectCountReply{}, errors.New(err.Error())
+			return err
 		}
-		dbConnector.Version.Initialize(dbConnector)
+	}
 
-		aocount, heapcount, errFromCounts := GetCountsForDb(dbConnector)
-		if errFromCounts != nil {
-			gplog.Error(err.Error())
-			return &pb.CheckObjectCountReply{}, errors.New(errFromCounts.Error())
-		}
-		results = append(results, &pb.CountPerDb{DbName: names[i], AoCount: aocount, HeapCount: heapcount})
+	return nil
+}
+
+func (h *Hub) forDatabase(port int32, name string, f func(name string, dbConnector *dbconn.DBConn) error) error {
+	dbConnector := h.dbConn("localhost", int(port), name)
+	defer dbConnector.Close()
+	err := dbConnector.Connect(1)
+	if err != nil {
+		gplog.Error(err.Error())
+		return errors.New(err.Error())
 	}
+	dbConnector.Version.Initialize(dbConnector)
 
-	successReply := &pb.CheckObjectCountReply{ListOfCounts: results}
-	return successReply, nil
+	return f(name, dbConnector)
 }
 
 func GetCountsForDb(dbConnector *dbconn.DBConn) (int32, int32, error) {
@@ -0,0 +1,242 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	pb "github.com/greenplum-db/gpupgrade/idl"
+
+	"github.com/greenplum-db/gp-common-go-libs/dbconn"
+	"github.com/greenplum-db/gp-common-go-libs/gplog"
+	"github.com/pkg/errors"
+)
+
+// removedTypes are the built-in types that pg_upgrade refuses to carry over in
+// a user table, and the reason it refuses. Those with a Target are only
+// refused when upgrading to that major version or later; the rest always are.
+var removedTypes = []struct {
+	Name   string
+	Target string
+	Reason string
+}{
+	{Name: "abstime", Target: "7", Reason: "which Greenplum 7 no longer has"},
+	{Name: "reltime", Target: "7", Reason: "which Greenplum 7 no longer has"},
+	{Name: "tinterval", Target: "7", Reason: "which Greenplum 7 no longer has"},
+	{Name: "unknown", Target: "7", Reason: "which Greenplum 7 does not allow in tables"},
+	{Name: "regproc", Reason: "whose values are OIDs that pg_upgrade does not preserve"},
+	{Name: "regprocedure", Reason: "whose values are OIDs that pg_upgrade does not preserve"},
+	{Name: "regoper", Reason: "whose values are OIDs that pg_upgrade does not preserve"},
+	{Name: "regoperator", Reason: "whose values are OIDs that pg_upgrade does not preserve"},
+	{Name: "regconfig", Reason: "whose values are OIDs that pg_upgrade does not preserve"},
+	{Name: "regdictionary", Reason: "whose values are OIDs that pg_upgrade does not preserve"},
+	{Name: "regnamespace", Reason: "whose values are OIDs that pg_upgrade does not preserve"},
+}
+
+// removedTypeReasons returns, by name, the removedTypes that pg_upgrade
+// refuses when upgrading to target, and the reason it refuses each.
+func removedTypeReasons(target dbconn.GPDBVersion) map[string]string {
+	reasons := make(map[string]string)
+	for _, t := range removedTypes {
+		if t.Target == "" || target.AtLeast(t.Target) {
+			reasons[t.Name] = t.Reason
+		}
+	}
+	return reasons
+}
+
+// removedTypesChecker fails if a user table in any database has a column of
+// a type that pg_upgrade cannot carry over to the new binaries' version,
+// which it would otherwise only find after the clusters had been shut down.
+var removedTypesChecker = Checker{
+	Name:     "removed-types",
+	Severity: pb.CheckSeverity_ERROR,
+	Hosts:    masterHost,
+	Check: func(h *Hub, in *pb.CheckAllRequest, hosts []string) ([]*pb.CheckFinding, error) {
+		if h.clusterPair.NewBinDir == "" {
+			return nil, errors.New("the new binaries are not known until prepare init-cluster has run")
+		}
+
+		target, err := h.binaryVersion(hosts[0], h.clusterPair.NewBinDir)
+		if err != nil {
+			return nil, err
+		}
+
+		reasons := removedTypeReasons(target)
+		var names []string
+		for _, t := range removedTypes {
+			if _, ok := reasons[t.Name]; ok {
+				names = append(names, t.Name)
+			}
+		}
+
+		var findings []*pb.CheckFinding
+		err = h.forEachDatabase(in.DbPort, func(name string, dbConnector *dbconn.DBConn) error {
+			columns, err := GetRemovedTypeColumns(dbConnector, names)
+			if err != nil {
+				return err
+			}
+
+			for _, c := range columns {
+				typ := c.Type
+				if c.RemovedType != c.Type {
+					typ += ", which is built on " + c.RemovedType
+				}
+				findings = append(findings, &pb.CheckFinding{
+					Host: hosts[0],
+					Message: fmt.Sprintf("database %s: %s.%s.%s is of type %s, %s",
+						name, c.Schema, c.Table, c.Column, typ, reasons[c.RemovedType]),
+				})
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		return findings, nil
+	},
+}
+
+// RemovedTypeColumn is a user table column whose type is one of the removed
+// types, or an array, domain or composite type built on one.
+type RemovedTypeColumn struct {
+	Schema      string `db:"nspname"`
+	Table       string `db:"relname"`
+	Column      string `db:"attname"`
+	Type        string `db:"typname"`
+	TypeOid     uint32 `db:"atttypid"`
+	RemovedType string `db:"-"`
+}
+
+// GetRemovedTypeColumns returns the user table columns whose types are built
+// on the built-in types named in removed. It follows the types built on those
+// a level at a time, since the old cluster can't be relied on to run recursive
+// queries.
+func GetRemovedTypeColumns(dbConnector *dbconn.DBConn, removed []string) ([]RemovedTypeColumn, error) {
+	var quoted []string
+	for _, name := range removed {
+		quoted = append(quoted, "'"+name+"'")
+	}
+
+	var found []struct {
+		Oid  uint32 `db:"oid"`
+		Name string `db:"typname"`
+	}
+	err := dbConnector.Select(&found, fmt.Sprintf(REMOVED_TYPES_QUERY, strings.Join(quoted, ", ")))
+	if err != nil {
+		gplog.Error(err.Error())
+		return nil, errors.New(err.Error())
+	}
+
+	// builtOn maps each type found so far to the removed type it is built on.
+	builtOn := make(map[uint32]string)
+	var level []uint32
+	for _, t := range found {
+		builtOn[t.Oid] = t.Name
+		level = append(level, t.Oid)
+	}
+
+	for len(level) > 0 {
+		var derived []struct {
+			Oid  uint32 `db:"oid"`
+			Base uint32 `db:"base"`
+		}
+		err = dbConnector.Select(&derived, fmt.Sprintf(DERIVED_TYPES_QUERY, oidList(level)))
+		if err != nil {
+			gplog.Error(err.Error())
+			return nil, errors.New(err.Error())
+		}
+
+		level = nil
+		for _, t := range derived {
+			if _, ok := builtOn[t.Oid]; !ok {
+				builtOn[t.Oid] = builtOn[t.Base]
+				level = append(level, t.Oid)
+			}
+		}
+	}
+
+	if len(builtOn) == 0 {
+		return nil, nil
+	}
+
+	var all []uint32
+	for oid := range builtOn {
+		all = append(all, oid)
+	}
+
+	var columns []RemovedTypeColumn
+	err = dbConnector.Select(&columns, fmt.Sprintf(REMOVED_TYPE_COLUMNS_QUERY, oidList(all)))
+	if err != nil {
+		gplog.Error(err.Error())
+		return nil, errors.New(err.Error())
+	}
+
+	for i := range columns {
+		columns[i].RemovedType = builtOn[columns[i].TypeOid]
+	}
+	return columns, nil
+}
+
+// oidList formats oids for an IN list, in ascending order so that the same
+// oids always make the same query.
+func oidList(oids []uint32) string {
+	sort.Slice(oids, func(i, j int) bool { return oids[i] < oids[j] })
+
+	var list []string
+	for _, oid := range oids {
+		list = append(list, strconv.FormatUint(uint64(oid), 10))
+	}
+	return strings.Join(list, ", ")
+}
+
+const (
+	REMOVED_TYPES_QUERY = `
+	SELECT t.oid, t.typname
+	  FROM pg_type t
+	  JOIN pg_namespace n ON t.typnamespace = n.oid
+	WHERE n.nspname = 'pg_catalog'
+	  AND t.typname IN (%s);
+	`
+
+	// The types built directly on any of a list of types: the domains over
+	// them, the arrays of them, and the composite types, including the row
+	// types of tables, that have attributes of them.
+	DERIVED_TYPES_QUERY = `
+	SELECT t.oid, t.typbasetype AS base
+	  FROM pg_type t
+	WHERE t.typtype = cast('d' as CHAR)                           -- domains
+	  AND t.typbasetype IN (%[1]s)
+	UNION
+	SELECT t.oid, t.typelem AS base
+	  FROM pg_type t
+	WHERE t.typlen = -1                                          -- arrays
+	  AND t.typelem IN (%[1]s)
+	UNION
+	SELECT t.oid, a.atttypid AS base
+	  FROM pg_type t
+	  JOIN pg_attribute a ON a.attrelid = t.typrelid
+	WHERE t.typtype = cast('c' as CHAR)                           -- composites
+	  AND NOT a.attisdropped
+	  AND a.attnum > 0
+	  AND a.atttypid IN (%[1]s);
+	`
+
+	REMOVED_TYPE_COLUMNS_QUERY = `
+	SELECT n.nspname, c.relname, a.attname, t.typname, a.atttypid
+	  FROM pg_attribute a
+	  JOIN pg_class c ON a.attrelid = c.oid
+	  JOIN pg_namespace n ON c.relnamespace = n.oid
+	  JOIN pg_type t ON a.atttypid = t.oid
+	WHERE c.relkind = cast('r' as CHAR)                           -- All tables (including partitions)
+	  AND c.oid >= 16384                                          -- No system tables
+	  AND n.nspname NOT IN ('pg_catalog', 'information_schema', 'gp_toolkit')
+	  AND n.nspname NOT LIKE 'pg_temp_%%'                         -- not temp tables
+	  AND NOT a.attisdropped
+	  AND a.attnum > 0                                            -- No system columns
+	  AND a.atttypid IN (%s)
+	ORDER BY n.nspname, c.relname, a.attnum;
+	`
+)
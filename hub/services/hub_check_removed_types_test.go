@@ -0,0 +1,72 @@
+package services_test
+
+import (
+	"errors"
+	"regexp"
+
+	"github.com/greenplum-db/gpupgrade/hub/services"
+
+	"github.com/greenplum-db/gp-common-go-libs/dbconn"
+	"github.com/greenplum-db/gp-common-go-libs/testhelper"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GetRemovedTypeColumns", func() {
+	var (
+		dbConnector *dbconn.DBConn
+		mock        sqlmock.Sqlmock
+	)
+
+	BeforeEach(func() {
+		testhelper.SetupTestLogger()
+		dbConnector, mock = testhelper.CreateAndConnectMockDB(1)
+	})
+
+	AfterEach(func() {
+		dbConnector.Close()
+	})
+
+	It("returns the user columns of removed types and of the types built on them", func() {
+		mock.ExpectQuery(regexp.QuoteMeta("t.typname IN ('abstime', 'regproc')")).
+			WillReturnRows(sqlmock.NewRows([]string{"oid", "typname"}).
+				AddRow(702, "abstime").
+				AddRow(24, "regproc"))
+		mock.ExpectQuery(`typbasetype IN \(24, 702\)`).
+			WillReturnRows(sqlmock.NewRows([]string{"oid", "base"}).AddRow(1024, 702))
+		mock.ExpectQuery(`typbasetype IN \(1024\)`).
+			WillReturnRows(sqlmock.NewRows([]string{"oid", "base"}))
+		mock.ExpectQuery(`a.atttypid IN \(24, 702, 1024\)`).
+			WillReturnRows(sqlmock.NewRows([]string{"nspname", "relname", "attname", "typname", "atttypid"}).
+				AddRow("public", "events", "happened", "_abstime", 1024).
+				AddRow("sales", "ledger", "func", "regproc", 24))
+
+		columns, err := services.GetRemovedTypeColumns(dbConnector, []string{"abstime", "regproc"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(columns).To(Equal([]services.RemovedTypeColumn{
+			{Schema: "public", Table: "events", Column: "happened", Type: "_abstime", TypeOid: 1024, RemovedType: "abstime"},
+			{Schema: "sales", Table: "ledger", Column: "func", Type: "regproc", TypeOid: 24, RemovedType: "regproc"},
+		}))
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	It("doesn't look for columns if none of the types exist", func() {
+		mock.ExpectQuery(".*t.typname IN.*").
+			WillReturnRows(sqlmock.NewRows([]string{"oid", "typname"}))
+
+		columns, err := services.GetRemovedTypeColumns(dbConnector, []string{"regnamespace"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(columns).To(BeEmpty())
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	It("returns an error if the catalog can't be queried", func() {
+		mock.ExpectQuery(".*t.typname IN.*").
+			WillReturnError(errors.New("connection lost"))
+
+		_, err := services.GetRemovedTypeColumns(dbConnector, []string{"abstime"})
+		Expect(err).To(MatchError("connection lost"))
+	})
+})
@@ -2,6 +2,12 @@ package services
 
 import (
 	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
 
 	"github.com/greenplum-db/gp-common-go-libs/gplog"
 	pb "github.com/greenplum-db/gpupgrade/idl"
@@ -12,7 +18,46 @@ import (
 func (h *Hub) CheckSeginstall(ctx context.Context, in *pb.CheckSeginstallRequest) (*pb.CheckSeginstallReply, error) {
 	gplog.Info("starting CheckSeginstall()")
 
+	err := h.checkPrerequisites(pb.UpgradeSteps_SEGINSTALL)
+	if err != nil {
+		gplog.Error(err.Error())
+		return &pb.CheckSeginstallReply{}, err
+	}
+
 	go h.remoteExecutor.VerifySoftware(h.clusterPair.GetHostnames())
 
 	return &pb.CheckSeginstallReply{}, nil
 }
+
+// seginstallChecker fails for each segment host that the new gpupgrade
+// software hasn't been installed on.
+var seginstallChecker = Checker{
+	Name:     "seginstall",
+	Severity: pb.CheckSeverity_ERROR,
+	Hosts:    segmentHosts,
+	Check: func(h *Hub, in *pb.CheckAllRequest, hosts []string) ([]*pb.CheckFinding, error) {
+		agentPath := filepath.Join(os.Getenv("GPHOME"), "bin", "gpupgrade_agent")
+
+		var mu sync.Mutex
+		var findings []*pb.CheckFinding
+		h.forEachHost(hosts, "look for the software on", func(host string) error {
+			result := h.backend.Run(host, "ls "+agentPath)
+			if result.Err == nil {
+				return nil
+			}
+
+			message := fmt.Sprintf("%s was not found: %s", agentPath, result.Err)
+			if output := strings.TrimSpace(result.Output); output != "" {
+				message += ": " + output
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			findings = append(findings, &pb.CheckFinding{Host: host, Message: message})
+			return nil
+		})
+
+		sort.Slice(findings, func(i, j int) bool { return findings[i].Host < findings[j].Host })
+		return findings, nil
+	},
+}
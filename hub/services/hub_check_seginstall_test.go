@@ -4,6 +4,7 @@ import (
 	_ "github.com/greenplum-db/gpupgrade/hub/services"
 
 	"github.com/greenplum-db/gpupgrade/hub/services"
+	"github.com/greenplum-db/gpupgrade/hub/upgradestatus"
 	pb "github.com/greenplum-db/gpupgrade/idl"
 
 	"io/ioutil"
@@ -29,6 +30,8 @@ var _ = Describe("PrepareSeginstall", func() {
 		var err error
 		dir, err = ioutil.TempDir("", "")
 		Expect(err).ToNot(HaveOccurred())
+		setStepStatus(dir, upgradestatus.CONFIG, pb.StepStatus_COMPLETE)
+
 		conf := &services.HubConfig{
 			StateDir: dir,
 		}
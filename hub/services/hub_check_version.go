@@ -1,11 +1,18 @@
 package services
 
 import (
-	"github.com/greenplum-db/gpupgrade/db"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
 	pb "github.com/greenplum-db/gpupgrade/idl"
 	"github.com/greenplum-db/gpupgrade/utils"
 
+	"github.com/greenplum-db/gp-common-go-libs/dbconn"
 	"github.com/greenplum-db/gp-common-go-libs/gplog"
+	"github.com/pkg/errors"
 	"golang.org/x/net/context"
 )
 
@@ -17,15 +24,152 @@ func (h *Hub) CheckVersion(ctx context.Context,
 	in *pb.CheckVersionRequest) (*pb.CheckVersionReply, error) {
 
 	gplog.Info("starting CheckVersion")
-	dbConnector := db.NewDBConn(in.Host, int(in.DbPort), "template1")
+	if h.clusterPair.NewBinDir == "" {
+		err := errors.New("the new binaries are not known until prepare init-cluster has run")
+		gplog.Error(err.Error())
+		return &pb.CheckVersionReply{}, err
+	}
+
+	source, err := h.clusterVersion(in.Host, in.DbPort)
+	if err != nil {
+		gplog.Error(err.Error())
+		return &pb.CheckVersionReply{}, err
+	}
+
+	target, err := h.binaryVersion(in.Host, h.clusterPair.NewBinDir)
+	if err != nil {
+		gplog.Error(err.Error())
+		return &pb.CheckVersionReply{}, err
+	}
+
+	reason := CheckUpgrade(source, target)
+	return &pb.CheckVersionReply{IsVersionCompatible: reason == "", Reason: reason}, nil
+}
+
+// supportedUpgrades is the table of the upgrades that gpupgrade supports:
+// from Source, or any later release of its major version, to any release of
+// the major version Target.
+var supportedUpgrades = []struct {
+	Source string
+	Target string
+}{
+	{Source: MINIMUM_VERSION, Target: "5"},
+	{Source: "5.0.0", Target: "6"},
+}
+
+// versionChecker fails if the old cluster's version, and the version of the
+// new binaries on each host, are not a pair in supportedUpgrades, or if the
+// hosts don't all have the same new binaries as the master.
+var versionChecker = Checker{
+	Name:     "version",
+	Severity: pb.CheckSeverity_ERROR,
+	Hosts:    segmentHosts,
+	Check: func(h *Hub, in *pb.CheckAllRequest, hosts []string) ([]*pb.CheckFinding, error) {
+		if h.clusterPair.NewBinDir == "" {
+			return nil, errors.New("the new binaries are not known until prepare init-cluster has run")
+		}
+
+		source, err := h.clusterVersion(in.MasterHost, in.DbPort)
+		if err != nil {
+			return nil, err
+		}
+
+		var mu sync.Mutex
+		targets := make(map[string]dbconn.GPDBVersion)
+		err = h.forEachHost(hosts, "get the new version from", func(host string) error {
+			target, err := h.binaryVersion(host, h.clusterPair.NewBinDir)
+			if err != nil {
+				return err
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			targets[host] = target
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		master, haveMaster := targets[masterHost(h)[0]]
+		var findings []*pb.CheckFinding
+		for _, host := range hosts {
+			target := targets[host]
+			if haveMaster && !target.Is(master.SemVer.String()) {
+				findings = append(findings, &pb.CheckFinding{
+					Host:    host,
+					Message: fmt.Sprintf("the new binaries are Greenplum %s, but on the master they are %s", target.SemVer, master.SemVer),
+				})
+				continue
+			}
+
+			if message := CheckUpgrade(source, target); message != "" {
+				findings = append(findings, &pb.CheckFinding{Host: host, Message: message})
+			}
+		}
+		return findings, nil
+	},
+}
+
+// CheckUpgrade returns why source cannot be upgraded to target, or "" if it
+// can.
+func CheckUpgrade(source, target dbconn.GPDBVersion) string {
+	if target.Before(source.SemVer.String()) {
+		return fmt.Sprintf("Greenplum %s cannot be downgraded to %s", source.SemVer, target.SemVer)
+	}
+
+	var supported []string
+	for _, upgrade := range supportedUpgrades {
+		major := strings.Split(upgrade.Source, ".")[0]
+		if source.Is(major) && source.AtLeast(upgrade.Source) && target.Is(upgrade.Target) {
+			return ""
+		}
+		supported = append(supported, fmt.Sprintf("%s or later %s.x to %s.x", upgrade.Source, major, upgrade.Target))
+	}
+
+	return fmt.Sprintf("upgrading Greenplum %s to %s is not supported; gpupgrade upgrades %s",
+		source.SemVer, target.SemVer, strings.Join(supported, ", or "))
+}
+
+// binaryVersion returns the version of the Greenplum binaries in binDir on
+// host, from postgres --gp-version.
+func (h *Hub) binaryVersion(host string, binDir string) (dbconn.GPDBVersion, error) {
+	postgres := filepath.Join(binDir, "postgres")
+	result := h.backend.Run(host, utils.ShellQuote(postgres)+" --gp-version")
+	if result.Err != nil {
+		return dbconn.GPDBVersion{}, fmt.Errorf("%s --gp-version failed: %s: %s", postgres, result.Err, strings.TrimSpace(result.Output))
+	}
+
+	version, err := ParseGPVersion(result.Output)
+	if err != nil {
+		return dbconn.GPDBVersion{}, fmt.Errorf("%s --gp-version: %s", postgres, err)
+	}
+	return version, nil
+}
+
+// ParseGPVersion parses the output of postgres --gp-version, which is like
+// "postgres (Greenplum Database) 5.10.2 build commit:...".
+func ParseGPVersion(output string) (dbconn.GPDBVersion, error) {
+	version := semverPattern.FindString(output)
+	if version == "" {
+		return dbconn.GPDBVersion{}, fmt.Errorf("no version in %q", strings.TrimSpace(output))
+	}
+
+	return dbconn.NewVersion(version), nil
+}
+
+var semverPattern = regexp.MustCompile(`\d+\.\d+\.\d+`)
+
+// clusterVersion returns the version of the cluster whose master is at host
+// and port.
+func (h *Hub) clusterVersion(host string, port int32) (dbconn.GPDBVersion, error) {
+	dbConnector := h.dbConn(host, int(port), "template1")
 	defer dbConnector.Close()
 	err := dbConnector.Connect(1)
 	if err != nil {
-		gplog.Error(err.Error())
-		return &pb.CheckVersionReply{}, utils.DatabaseConnectionError{Parent: err}
+		return dbconn.GPDBVersion{}, utils.DatabaseConnectionError{Parent: err}
 	}
 	dbConnector.Version.Initialize(dbConnector)
 
-	isVersionCompatible := dbConnector.Version.AtLeast(MINIMUM_VERSION)
-	return &pb.CheckVersionReply{IsVersionCompatible: isVersionCompatible}, nil
+	return dbConnector.Version, nil
 }
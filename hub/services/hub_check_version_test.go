@@ -0,0 +1,196 @@
+package services_test
+
+import (
+	"errors"
+
+	"github.com/greenplum-db/gpupgrade/hub/cluster_ssher"
+	"github.com/greenplum-db/gpupgrade/hub/services"
+	pb "github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/testutils"
+
+	"github.com/greenplum-db/gp-common-go-libs/cluster"
+	"github.com/greenplum-db/gp-common-go-libs/dbconn"
+	"github.com/greenplum-db/gp-common-go-libs/testhelper"
+	"google.golang.org/grpc"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("version compatibility", func() {
+	Describe("CheckVersion", func() {
+		var (
+			hub         *services.Hub
+			clusterPair *services.ClusterPair
+			execer      *testutils.FakeCommandExecer
+			outChan     chan []byte
+			errChan     chan error
+		)
+
+		BeforeEach(func() {
+			testhelper.SetupTestLogger()
+
+			errChan = make(chan error, 1)
+			outChan = make(chan []byte, 1)
+			execer = &testutils.FakeCommandExecer{}
+			execer.SetOutput(&testutils.FakeCommand{Err: errChan, Out: outChan})
+
+			clusterPair = testutils.CreateSampleClusterPair()
+			hub = services.NewHub(clusterPair, grpc.DialContext, execer.Exec, &services.HubConfig{
+				Backend: cluster_ssher.NewSSHBackend(execer.Exec, cluster_ssher.SSHConfig{}),
+				DBConn: func(host string, port int, dbname string) *dbconn.DBConn {
+					conn, mock := testhelper.CreateMockDBConn()
+					testhelper.ExpectVersionQuery(mock, "5.10.2")
+					testhelper.ExpectVersionQuery(mock, "5.10.2")
+					return conn
+				},
+			}, nil, nil)
+		})
+
+		It("is compatible if the old cluster can be upgraded to the new binaries", func() {
+			errChan <- nil
+			outChan <- []byte("postgres (Greenplum Database) 6.0.0 build commit:b3c02f3acd880e2d676dacea36be015e4a3826d4\n")
+
+			reply, err := hub.CheckVersion(nil, &pb.CheckVersionRequest{Host: "localhost", DbPort: 15432})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(reply).To(Equal(&pb.CheckVersionReply{IsVersionCompatible: true}))
+			Expect(execer.Args()).To(ContainElement(ContainSubstring("'/new/bindir/postgres' --gp-version")))
+		})
+
+		It("explains why the upgrade isn't supported", func() {
+			errChan <- nil
+			outChan <- []byte("postgres (Greenplum Database) 5.11.0 build commit:b3c02f3acd880e2d676dacea36be015e4a3826d4\n")
+
+			reply, err := hub.CheckVersion(nil, &pb.CheckVersionRequest{Host: "localhost", DbPort: 15432})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(reply.IsVersionCompatible).To(BeFalse())
+			Expect(reply.Reason).To(ContainSubstring("upgrading Greenplum 5.10.2 to 5.11.0 is not supported"))
+		})
+
+		It("fails before the new binaries are known", func() {
+			clusterPair.NewBinDir = ""
+
+			_, err := hub.CheckVersion(nil, &pb.CheckVersionRequest{Host: "localhost", DbPort: 15432})
+			Expect(err).To(MatchError(ContainSubstring("prepare init-cluster")))
+		})
+	})
+
+	Describe("the version check", func() {
+		var (
+			hub         *services.Hub
+			backend     *testutils.FakeBackend
+			versionOf   services.Checker
+			newVersions func(versions map[string]string)
+		)
+
+		BeforeEach(func() {
+			testhelper.SetupTestLogger()
+
+			clusterPair := testutils.CreateSampleClusterPair()
+			clusterPair.OldCluster = cluster.NewCluster([]cluster.SegConfig{
+				{ContentID: -1, Hostname: "mdw", DataDir: "/old/datadir"},
+				{ContentID: 0, Hostname: "sdw1", DataDir: "/old/datadir0"},
+				{ContentID: 1, Hostname: "sdw2", DataDir: "/old/datadir1"},
+			})
+
+			backend = testutils.NewFakeBackend()
+			hub = services.NewHub(clusterPair, grpc.DialContext, nil, &services.HubConfig{
+				Backend: backend,
+				DBConn: func(host string, port int, dbname string) *dbconn.DBConn {
+					conn, mock := testhelper.CreateMockDBConn()
+					testhelper.ExpectVersionQuery(mock, "5.10.2")
+					testhelper.ExpectVersionQuery(mock, "5.10.2")
+					return conn
+				},
+			}, nil, nil)
+
+			for _, c := range services.DefaultCheckers().Checkers() {
+				if c.Name == "version" {
+					versionOf = c
+				}
+			}
+
+			newVersions = func(versions map[string]string) {
+				for host, version := range versions {
+					backend.Results[host] = cluster_ssher.Result{
+						Output: "postgres (Greenplum Database) " + version + " build commit:b3c02f3acd880e2d676dacea36be015e4a3826d4\n",
+					}
+				}
+			}
+		})
+
+		check := func() []*pb.CheckFinding {
+			findings, err := versionOf.Check(hub, &pb.CheckAllRequest{MasterHost: "mdw", DbPort: 15432}, versionOf.Hosts(hub))
+			Expect(err).ToNot(HaveOccurred())
+			return findings
+		}
+
+		It("gets the version of the new binaries on every host", func() {
+			newVersions(map[string]string{"mdw": "6.0.0", "sdw1": "6.0.0", "sdw2": "6.0.0"})
+
+			Expect(check()).To(BeEmpty())
+			for _, host := range []string{"mdw", "sdw1", "sdw2"} {
+				Expect(backend.Commands[host]).To(Equal([]string{"'/new/bindir/postgres' --gp-version"}))
+			}
+		})
+
+		It("finds the hosts whose new binaries don't match the master's", func() {
+			newVersions(map[string]string{"mdw": "6.0.0", "sdw1": "6.0.0", "sdw2": "6.1.0"})
+
+			Expect(check()).To(Equal([]*pb.CheckFinding{{
+				Host:    "sdw2",
+				Message: "the new binaries are Greenplum 6.1.0, but on the master they are 6.0.0",
+			}}))
+		})
+
+		It("finds every host whose new binaries the old cluster can't be upgraded to", func() {
+			newVersions(map[string]string{"mdw": "5.11.0", "sdw1": "5.11.0", "sdw2": "5.11.0"})
+
+			findings := check()
+			Expect(findings).To(HaveLen(3))
+			for _, finding := range findings {
+				Expect(finding.Message).To(HavePrefix("upgrading Greenplum 5.10.2 to 5.11.0 is not supported"))
+			}
+		})
+
+		It("fails if the version can't be gotten from a host", func() {
+			newVersions(map[string]string{"mdw": "6.0.0", "sdw1": "6.0.0"})
+			backend.Results["sdw2"] = cluster_ssher.Result{ExitCode: 127, Output: "bash: postgres: command not found\n", Err: errors.New("exit status 127")}
+
+			_, err := versionOf.Check(hub, &pb.CheckAllRequest{MasterHost: "mdw", DbPort: 15432}, versionOf.Hosts(hub))
+			Expect(err).To(MatchError(ContainSubstring("sdw2")))
+		})
+	})
+
+	DescribeTable("CheckUpgrade allows only the supported upgrades",
+		func(source, target, problem string) {
+			message := services.CheckUpgrade(dbconn.NewVersion(source), dbconn.NewVersion(target))
+			if problem == "" {
+				Expect(message).To(BeEmpty())
+			} else {
+				Expect(message).To(ContainSubstring(problem))
+			}
+		},
+		Entry("from the oldest supported 4.3", "4.3.9", "5.0.0", ""),
+		Entry("from a later 4.3", "4.3.33", "5.10.2", ""),
+		Entry("from 5", "5.10.2", "6.0.0", ""),
+		Entry("from a 4.3 that's too old", "4.3.8", "5.0.0", "upgrading Greenplum 4.3.8 to 5.0.0 is not supported"),
+		Entry("across two major versions", "4.3.9", "6.0.0", "is not supported"),
+		Entry("within a major version", "5.10.2", "5.11.0", "is not supported"),
+		Entry("to an older version", "6.1.0", "5.10.2", "Greenplum 6.1.0 cannot be downgraded to 5.10.2"),
+	)
+
+	Describe("ParseGPVersion", func() {
+		It("finds the version in the output of postgres --gp-version", func() {
+			version, err := services.ParseGPVersion("postgres (Greenplum Database) 5.10.2 build commit:b3c02f3acd880e2d676dacea36be015e4a3826d4\n")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(version.Is("5.10.2")).To(BeTrue())
+		})
+
+		It("fails if there isn't a version", func() {
+			_, err := services.ParseGPVersion("bash: postgres: command not found\n")
+			Expect(err).To(MatchError(ContainSubstring("no version")))
+		})
+	})
+})
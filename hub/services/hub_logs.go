@@ -0,0 +1,124 @@
+package services
+
+import (
+	"context"
+	"io"
+	"path/filepath"
+
+	pb "github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/utils"
+
+	"github.com/greenplum-db/gp-common-go-libs/gplog"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// errClusterNotLoaded is returned for the logs of segments and agents before
+// the hub knows where they are.
+var errClusterNotLoaded = status.Error(codes.FailedPrecondition,
+	"the segments and their hosts are not known until check config has run")
+
+// Logs streams the hub's log, or the pg_upgrade logs of the master, from the
+// hub's own host, and relays the agent logs and the pg_upgrade logs of the
+// segments from the agent on their host. If Follow is set, the stream keeps
+// going until the client goes away.
+func (h *Hub) Logs(in *pb.LogsRequest, stream pb.CliToHub_LogsServer) error {
+	switch {
+	case in.Source == pb.LogSource_HUB:
+		return h.sendLocalLogs(stream, []string{gplog.GetLogFilePath()}, "hub log", in.Follow)
+
+	case in.Source == pb.LogSource_PG_UPGRADE && in.Content == -1:
+		dir := filepath.Join(h.conf.StateDir, "pg_upgrade")
+		return h.sendLocalLogs(stream, utils.PGUpgradeLogPatterns(dir), "master's pg_upgrade logs in "+dir, in.Follow)
+
+	case in.Source == pb.LogSource_PG_UPGRADE:
+		if h.clusterPair.OldCluster == nil {
+			return errClusterNotLoaded
+		}
+		segment, ok := h.clusterPair.OldCluster.Segments[int(in.Content)]
+		if !ok {
+			return status.Errorf(codes.NotFound, "there is no segment with content %d", in.Content)
+		}
+		return h.relayAgentLogs(stream, segment.Hostname, &pb.TailLogsRequest{
+			Source:  pb.LogSource_PG_UPGRADE,
+			Content: in.Content,
+			Follow:  in.Follow,
+		})
+
+	case in.Source == pb.LogSource_AGENT:
+		if h.clusterPair.OldCluster == nil {
+			return errClusterNotLoaded
+		}
+		if !h.isSegmentHost(in.Hostname) {
+			return status.Errorf(codes.NotFound, "%s is not a host in the cluster", in.Hostname)
+		}
+		return h.relayAgentLogs(stream, in.Hostname, &pb.TailLogsRequest{
+			Source: pb.LogSource_AGENT,
+			Follow: in.Follow,
+		})
+
+	default:
+		return status.Errorf(codes.InvalidArgument, "unknown log source %s", in.Source)
+	}
+}
+
+func (h *Hub) sendLocalLogs(stream pb.CliToHub_LogsServer, patterns []string, what string, follow bool) error {
+	host, err := utils.GetHost()
+	if err != nil {
+		return err
+	}
+
+	err = utils.StreamLogs(stream.Context(), patterns, follow, func(path string, data []byte) error {
+		return stream.Send(&pb.LogChunk{Hostname: host, Path: path, Data: data})
+	})
+	if err == utils.ErrNoLogs {
+		return status.Errorf(codes.NotFound, "there is no %s on %s", what, host)
+	}
+	if err != nil {
+		gplog.Error("could not send the %s: %s", what, err)
+	}
+	return err
+}
+
+// relayAgentLogs passes the chunks streamed by the agent on host on to the
+// client, along with any error the agent returns.
+func (h *Hub) relayAgentLogs(stream pb.CliToHub_LogsServer, host string, in *pb.TailLogsRequest) error {
+	ctx := stream.Context()
+
+	dialCtx, cancelDial := context.WithTimeout(ctx, DialTimeout)
+	defer cancelDial()
+	conn, err := h.dialAgent(dialCtx, host)
+	if err != nil {
+		return status.Errorf(codes.Unavailable, "could not get logs from %s: %s", host, err)
+	}
+	defer conn.Close()
+
+	agentStream, err := pb.NewAgentClient(conn).TailLogs(ctx, in)
+	if err != nil {
+		return err
+	}
+
+	for {
+		chunk, err := agentStream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		err = stream.Send(chunk)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (h *Hub) isSegmentHost(host string) bool {
+	for _, hostname := range h.clusterPair.GetHostnames() {
+		if hostname == host {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,154 @@
+package services_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/greenplum-db/gpupgrade/hub/services"
+	pb "github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/testutils"
+	"github.com/greenplum-db/gpupgrade/utils"
+
+	"github.com/greenplum-db/gp-common-go-libs/cluster"
+	"github.com/greenplum-db/gp-common-go-libs/testhelper"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type spyLogsServer struct {
+	grpc.ServerStream
+
+	sent []*pb.LogChunk
+}
+
+func (s *spyLogsServer) Send(chunk *pb.LogChunk) error {
+	s.sent = append(s.sent, chunk)
+	return nil
+}
+
+func (s *spyLogsServer) Context() context.Context {
+	return context.Background()
+}
+
+var _ = Describe("Logs", func() {
+	var (
+		mockAgent   *testutils.MockAgentServer
+		hub         *services.Hub
+		dir         string
+		stream      *spyLogsServer
+		dialTimeout time.Duration
+	)
+
+	BeforeEach(func() {
+		testhelper.SetupTestLogger()
+
+		var err error
+		dir, err = ioutil.TempDir("", "")
+		Expect(err).ToNot(HaveOccurred())
+
+		var port int
+		mockAgent, port = testutils.NewMockAgentServer()
+
+		clusterPair := &services.ClusterPair{
+			OldCluster: testutils.CreateSampleCluster(-1, 25437, "localhost", "/old/datadir"),
+		}
+		clusterPair.OldCluster.Segments[0] = cluster.SegConfig{ContentID: 0, Hostname: "localhost"}
+		hub = services.NewHub(clusterPair, grpc.DialContext, nil,
+			&services.HubConfig{HubToAgentPort: port, StateDir: dir},
+			testutils.NewStubRemoteExecutor(), nil)
+		stream = &spyLogsServer{}
+
+		dialTimeout = services.DialTimeout
+		services.DialTimeout = 500 * time.Millisecond
+	})
+
+	AfterEach(func() {
+		utils.System = utils.InitializeSystemFunctions()
+		services.DialTimeout = dialTimeout
+		mockAgent.Stop()
+		os.RemoveAll(dir)
+	})
+
+	It("sends the master's pg_upgrade logs from the state dir", func() {
+		pgUpgradeDir := filepath.Join(dir, "pg_upgrade")
+		Expect(os.MkdirAll(pgUpgradeDir, 0700)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(pgUpgradeDir, "pg_upgrade_master.log"), []byte("upgrading\n"), 0600)).To(Succeed())
+
+		err := hub.Logs(&pb.LogsRequest{Source: pb.LogSource_PG_UPGRADE, Content: -1}, stream)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(stream.sent).To(HaveLen(1))
+		Expect(stream.sent[0].Path).To(Equal(filepath.Join(pgUpgradeDir, "pg_upgrade_master.log")))
+		Expect(stream.sent[0].Data).To(Equal([]byte("upgrading\n")))
+	})
+
+	It("relays the pg_upgrade logs of a segment from the agent on its host", func() {
+		chunk := &pb.LogChunk{Hostname: "localhost", Path: "/state/pg_upgrade/seg-0/pg_upgrade_internal.log", Data: []byte("checking\n")}
+		mockAgent.LogChunks = []*pb.LogChunk{chunk}
+
+		err := hub.Logs(&pb.LogsRequest{Source: pb.LogSource_PG_UPGRADE, Content: 0, Follow: true}, stream)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(mockAgent.TailLogsRequest).To(Equal(&pb.TailLogsRequest{Source: pb.LogSource_PG_UPGRADE, Content: 0, Follow: true}))
+		Expect(stream.sent).To(HaveLen(1))
+		Expect(stream.sent[0].Data).To(Equal(chunk.Data))
+	})
+
+	It("relays the agent log", func() {
+		mockAgent.LogChunks = []*pb.LogChunk{{Hostname: "localhost", Data: []byte("pinged\n")}}
+
+		err := hub.Logs(&pb.LogsRequest{Source: pb.LogSource_AGENT, Hostname: "localhost"}, stream)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(mockAgent.TailLogsRequest.Source).To(Equal(pb.LogSource_AGENT))
+		Expect(stream.sent).To(HaveLen(1))
+	})
+
+	It("passes on errors from the agent", func() {
+		mockAgent.Err <- status.Error(codes.NotFound, "there are no logs")
+
+		err := hub.Logs(&pb.LogsRequest{Source: pb.LogSource_AGENT, Hostname: "localhost"}, stream)
+
+		s, ok := status.FromError(err)
+		Expect(ok).To(BeTrue())
+		Expect(s.Code()).To(Equal(codes.NotFound))
+		Expect(s.Message()).To(Equal("there are no logs"))
+	})
+
+	It("fails for hosts and segments that aren't in the cluster", func() {
+		err := hub.Logs(&pb.LogsRequest{Source: pb.LogSource_AGENT, Hostname: "sdw9"}, stream)
+		s, _ := status.FromError(err)
+		Expect(s.Code()).To(Equal(codes.NotFound))
+
+		err = hub.Logs(&pb.LogsRequest{Source: pb.LogSource_PG_UPGRADE, Content: 9}, stream)
+		s, _ = status.FromError(err)
+		Expect(s.Code()).To(Equal(codes.NotFound))
+
+		Expect(mockAgent.NumberOfCalls()).To(Equal(0))
+	})
+
+	It("fails for segments and agents before the cluster's configuration is known", func() {
+		hub = services.NewHub(&services.ClusterPair{}, grpc.DialContext, nil,
+			&services.HubConfig{StateDir: dir}, testutils.NewStubRemoteExecutor(), nil)
+
+		err := hub.Logs(&pb.LogsRequest{Source: pb.LogSource_AGENT, Hostname: "localhost"}, stream)
+		Expect(status.Code(err)).To(Equal(codes.FailedPrecondition))
+
+		err = hub.Logs(&pb.LogsRequest{Source: pb.LogSource_PG_UPGRADE, Content: 0}, stream)
+		Expect(status.Code(err)).To(Equal(codes.FailedPrecondition))
+	})
+
+	It("reports agents that can't be reached", func() {
+		mockAgent.Stop()
+
+		err := hub.Logs(&pb.LogsRequest{Source: pb.LogSource_AGENT, Hostname: "localhost"}, stream)
+		Expect(err).To(MatchError(ContainSubstring("could not get logs from localhost")))
+	})
+})
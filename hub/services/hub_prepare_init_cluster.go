@@ -37,14 +37,20 @@ func SaveTargetClusterConfig(clusterPair *ClusterPair, dbConnector *dbconn.DBCon
 func (h *Hub) PrepareInitCluster(ctx context.Context, in *pb.PrepareInitClusterRequest) (*pb.PrepareInitClusterReply, error) {
 	gplog.Info("starting PrepareInitCluster()")
 
+	err := h.checkPrerequisites(pb.UpgradeSteps_PREPARE_INIT_CLUSTER)
+	if err != nil {
+		gplog.Error(err.Error())
+		return &pb.PrepareInitClusterReply{}, err
+	}
+
 	h.checklistWriter.MarkInProgress(upgradestatus.INIT_CLUSTER)
 
 	dbConnector := db.NewDBConn("localhost", int(in.DbPort), "template1")
 	defer dbConnector.Close()
-	err := dbConnector.Connect(1)
+	err = dbConnector.Connect(1)
 	if err != nil {
 		gplog.Error(err.Error())
-		h.checklistWriter.MarkFailed(upgradestatus.INIT_CLUSTER)
+		h.checklistWriter.MarkFailed(upgradestatus.INIT_CLUSTER, err)
 		return &pb.PrepareInitClusterReply{}, utils.DatabaseConnectionError{Parent: err}
 	}
 	dbConnector.Version.Initialize(dbConnector)
@@ -52,7 +58,7 @@ func (h *Hub) PrepareInitCluster(ctx context.Context, in *pb.PrepareInitClusterR
 	err = SaveTargetClusterConfig(h.clusterPair, dbConnector, h.conf.StateDir, in.NewBinDir)
 	if err != nil {
 		gplog.Error(err.Error())
-		h.checklistWriter.MarkFailed(upgradestatus.INIT_CLUSTER)
+		h.checklistWriter.MarkFailed(upgradestatus.INIT_CLUSTER, err)
 		return &pb.PrepareInitClusterReply{}, err
 	}
 
@@ -5,6 +5,7 @@ import (
 
 	"github.com/greenplum-db/gpupgrade/hub/upgradestatus"
 	pb "github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/utils"
 
 	"golang.org/x/net/context"
 
@@ -15,6 +16,12 @@ import (
 func (h *Hub) PrepareShutdownClusters(ctx context.Context, in *pb.PrepareShutdownClustersRequest) (*pb.PrepareShutdownClustersReply, error) {
 	gplog.Info("starting PrepareShutdownClusters()")
 
+	err := h.checkPrerequisites(pb.UpgradeSteps_STOPPED_CLUSTER)
+	if err != nil {
+		gplog.Error(err.Error())
+		return &pb.PrepareShutdownClustersReply{}, err
+	}
+
 	go h.ShutdownClusters()
 
 	return &pb.PrepareShutdownClustersReply{}, nil
@@ -27,25 +34,46 @@ func (h *Hub) ShutdownClusters() {
 	h.checklistWriter.MarkInProgress(step)
 
 	var errOld error
-	errOld = StopCluster(h.clusterPair.OldCluster, h.clusterPair.OldBinDir)
+	errOld = StopCluster(h.cancellable(step, h.clusterPair.OldCluster), h.clusterPair.OldBinDir)
+	if errOld == utils.ErrCancelled {
+		h.checklistWriter.MarkFailed(step, errOld)
+		return
+	}
 	if errOld != nil {
 		gplog.Error(errOld.Error())
 	}
 
 	var errNew error
-	errNew = StopCluster(h.clusterPair.NewCluster, h.clusterPair.NewBinDir)
+	errNew = StopCluster(h.cancellable(step, h.clusterPair.NewCluster), h.clusterPair.NewBinDir)
+	if errNew == utils.ErrCancelled {
+		h.checklistWriter.MarkFailed(step, errNew)
+		return
+	}
 	if errNew != nil {
 		gplog.Error(errNew.Error())
 	}
 
 	if errOld != nil || errNew != nil {
-		h.checklistWriter.MarkFailed(step)
+		h.checklistWriter.MarkFailed(step, stopClustersError(errOld, errNew))
 		return
 	}
 
 	h.checklistWriter.MarkComplete(step)
 }
 
+// stopClustersError folds the errors from stopping the old and new clusters
+// into the single error recorded for the step.
+func stopClustersError(errOld, errNew error) error {
+	switch {
+	case errOld != nil && errNew != nil:
+		return fmt.Errorf("failed to stop old cluster: %s; failed to stop new cluster: %s", errOld, errNew)
+	case errOld != nil:
+		return fmt.Errorf("failed to stop old cluster: %s", errOld)
+	default:
+		return fmt.Errorf("failed to stop new cluster: %s", errNew)
+	}
+}
+
 func StopCluster(c *cluster.Cluster, binDir string) error {
 	if !IsPostmasterRunning(c) {
 		return nil
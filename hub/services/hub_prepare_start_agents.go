@@ -2,8 +2,14 @@ package services
 
 import (
 	"context"
+	"fmt"
 
+	"github.com/greenplum-db/gpupgrade/certs"
+	"github.com/greenplum-db/gpupgrade/config"
 	pb "github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/utils"
+
+	"github.com/greenplum-db/gp-common-go-libs/gplog"
 )
 
 // grpc generated function signature requires ctx and in params.
@@ -11,7 +17,49 @@ import (
 func (h *Hub) PrepareStartAgents(ctx context.Context,
 	in *pb.PrepareStartAgentsRequest) (*pb.PrepareStartAgentsReply, error) {
 
-	go h.remoteExecutor.Start(h.clusterPair.GetHostnames())
+	err := h.checkPrerequisites(pb.UpgradeSteps_PREPARE_START_AGENTS)
+	if err != nil {
+		gplog.Error(err.Error())
+		return &pb.PrepareStartAgentsReply{}, err
+	}
+
+	hostnames := h.clusterPair.GetHostnames()
+	err = h.distributeAgentFiles(hostnames)
+	if err != nil {
+		gplog.Error(err.Error())
+		return &pb.PrepareStartAgentsReply{}, err
+	}
+
+	go h.remoteExecutor.Start(hostnames)
 
 	return &pb.PrepareStartAgentsReply{}, nil
 }
+
+// distributeAgentFiles copies what the agents need to start to each host: the
+// certificate that the agent serves with and the CA certificate, into the
+// certs directory of the host's state dir, and the configuration file.
+func (h *Hub) distributeAgentFiles(hostnames []string) error {
+	certsDir := certs.Dir(h.conf.StateDir)
+	configPath := config.Path(h.conf.StateDir)
+	_, err := utils.System.Stat(configPath)
+	copyConfig := err == nil
+
+	return h.forEachHost(hostnames, "copy agent files to", func(host string) error {
+		source, err := certs.IssueAgentCertificate(h.conf.StateDir, host)
+		if err != nil {
+			return fmt.Errorf("could not issue a certificate for the agent: %s", err)
+		}
+
+		result := h.backend.Run(host, "mkdir -p -m 0700 "+certsDir)
+		if result.Err == nil {
+			result = h.backend.Copy(host, []string{source + "/"}, certsDir)
+		}
+		if result.Err == nil && copyConfig {
+			result = h.backend.Copy(host, []string{configPath}, configPath)
+		}
+		if result.Err != nil {
+			return fmt.Errorf("%s: %s", result.Err, result.Output)
+		}
+		return nil
+	})
+}
@@ -1,9 +1,17 @@
 package services_test
 
 import (
+	"errors"
+	"fmt"
+	"path/filepath"
+
 	_ "github.com/greenplum-db/gpupgrade/hub/services"
 
+	"github.com/greenplum-db/gpupgrade/certs"
+	"github.com/greenplum-db/gpupgrade/config"
 	"github.com/greenplum-db/gpupgrade/hub/services"
+	"github.com/greenplum-db/gpupgrade/hub/upgradestatus"
+	pb "github.com/greenplum-db/gpupgrade/idl"
 
 	"io/ioutil"
 
@@ -28,6 +36,9 @@ var _ = Describe("PrepareStartAgents", func() {
 		var err error
 		dir, err = ioutil.TempDir("", "")
 		Expect(err).ToNot(HaveOccurred())
+		setStepStatus(dir, upgradestatus.SEGINSTALL, pb.StepStatus_COMPLETE)
+		Expect(certs.CreateAuthority(dir)).To(Succeed())
+
 		conf := &services.HubConfig{
 			StateDir: dir,
 		}
@@ -51,6 +62,38 @@ var _ = Describe("PrepareStartAgents", func() {
 			Expect(reply).ToNot(BeNil())
 			Eventually(stubRemoteExecutor.StartHosts).Should(Receive(Equal([]string{"hostone"})))
 		})
+
+		It("copies the agent certificate to each host before starting the agents", func() {
+			_, err := hub.PrepareStartAgents(nil, nil)
+			Expect(err).ToNot(HaveOccurred())
+
+			certsDir := certs.Dir(dir)
+			Expect(commandExecer.Calls()).To(Equal([]string{
+				fmt.Sprintf("ssh -o StrictHostKeyChecking=no hostone mkdir -p -m 0700 %s", certsDir),
+				fmt.Sprintf("rsync -rpt -e 'ssh' '-o' 'StrictHostKeyChecking=no' %s/ hostone:%s", certs.AgentDir(dir, "hostone"), certsDir),
+			}))
+			Expect(filepath.Join(certs.AgentDir(dir, "hostone"), "agent.crt")).To(BeAnExistingFile())
+			Eventually(stubRemoteExecutor.StartHosts).Should(Receive())
+		})
+
+		It("copies the configuration file along with the certificate", func() {
+			Expect(config.Write(dir, config.Default())).To(Succeed())
+
+			_, err := hub.PrepareStartAgents(nil, nil)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(commandExecer.Calls()).To(ContainElement(
+				fmt.Sprintf("rsync -rpt -e 'ssh' '-o' 'StrictHostKeyChecking=no' %s hostone:%s", config.Path(dir), config.Path(dir)),
+			))
+		})
+
+		It("does not start the agents if the certificates can't be copied", func() {
+			errChan <- errors.New("ssh: connect to host hostone: Connection refused")
+
+			_, err := hub.PrepareStartAgents(nil, nil)
+			Expect(err).To(MatchError(ContainSubstring("hostone")))
+			Consistently(stubRemoteExecutor.StartHosts).ShouldNot(Receive())
+		})
 	})
 
 })
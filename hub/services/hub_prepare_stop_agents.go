@@ -0,0 +1,87 @@
+package services
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	pb "github.com/greenplum-db/gpupgrade/idl"
+
+	"github.com/greenplum-db/gp-common-go-libs/gplog"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// PrepareStopAgents asks the agent on each segment host to shut down, and
+// reports which of them confirmed it. Each agent is dialed on its own, so that
+// one that can't be reached doesn't keep the others from being stopped. It
+// refuses while an upgrade run, a revert or any step, which need the agents,
+// is in progress.
+func (h *Hub) PrepareStopAgents(ctx context.Context, in *pb.PrepareStopAgentsRequest) (*pb.PrepareStopAgentsReply, error) {
+	gplog.Info("starting PrepareStopAgents")
+
+	h.mu.Lock()
+	busy := h.upgradeRunning || h.reverting
+	h.mu.Unlock()
+	if busy {
+		err := status.Error(codes.FailedPrecondition, "cannot stop the agents while an upgrade run or a revert is in progress")
+		gplog.Error(err.Error())
+		return &pb.PrepareStopAgentsReply{}, err
+	}
+	if running := h.runningStep(); running != "" {
+		err := status.Errorf(codes.FailedPrecondition, "cannot stop the agents while %s is running", running)
+		gplog.Error(err.Error())
+		return &pb.PrepareStopAgentsReply{}, err
+	}
+
+	hostnames := h.clusterPair.GetHostnames()
+	sort.Strings(hostnames)
+
+	var mu sync.Mutex
+	statuses := make(map[string]*pb.AgentShutdownStatus)
+	err := h.forEachHost(hostnames, "stop the agent on", func(host string) error {
+		status := &pb.AgentShutdownStatus{Hostname: host}
+		err := h.stopAgent(host)
+		if err != nil {
+			status.Error = err.Error()
+		} else {
+			status.Stopped = true
+		}
+
+		mu.Lock()
+		statuses[host] = status
+		mu.Unlock()
+		return err
+	})
+	if err != nil {
+		gplog.Error(err.Error())
+	}
+
+	// The connections to the agents that were stopped are no use any more;
+	// they are made again once the agents have been restarted.
+	h.closeConns()
+
+	reply := &pb.PrepareStopAgentsReply{}
+	for _, host := range hostnames {
+		reply.Agents = append(reply.Agents, statuses[host])
+	}
+	return reply, nil
+}
+
+// stopAgent asks the agent on host to shut down.
+func (h *Hub) stopAgent(host string) error {
+	dialCtx, cancelDial := context.WithTimeout(context.Background(), DialTimeout)
+	defer cancelDial()
+
+	conn, err := h.dialAgent(dialCtx, host)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), DialTimeout)
+	defer cancel()
+
+	_, err = pb.NewAgentClient(conn).Shutdown(ctx, &pb.ShutdownAgentRequest{})
+	return err
+}
@@ -0,0 +1,105 @@
+package services_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"time"
+
+	"github.com/greenplum-db/gpupgrade/hub/services"
+	"github.com/greenplum-db/gpupgrade/hub/upgradestatus"
+	pb "github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/testutils"
+	"github.com/greenplum-db/gpupgrade/utils"
+
+	"github.com/greenplum-db/gp-common-go-libs/cluster"
+	"github.com/greenplum-db/gp-common-go-libs/testhelper"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PrepareStopAgents", func() {
+	var (
+		mockAgent   *testutils.MockAgentServer
+		clusterPair *services.ClusterPair
+		hub         *services.Hub
+		port        int
+		dialTimeout time.Duration
+	)
+
+	BeforeEach(func() {
+		testhelper.SetupTestLogger()
+
+		mockAgent, port = testutils.NewMockAgentServer()
+
+		clusterPair = &services.ClusterPair{
+			OldCluster: testutils.CreateSampleCluster(-1, 25437, "localhost", "/old/datadir"),
+		}
+		hub = services.NewHub(clusterPair, grpc.DialContext, nil, &services.HubConfig{HubToAgentPort: port},
+			testutils.NewStubRemoteExecutor(), nil)
+
+		dialTimeout = services.DialTimeout
+		services.DialTimeout = 500 * time.Millisecond
+	})
+
+	AfterEach(func() {
+		utils.System = utils.InitializeSystemFunctions()
+		services.DialTimeout = dialTimeout
+		mockAgent.Stop()
+	})
+
+	It("reports the agents that confirmed shutdown", func() {
+		reply, err := hub.PrepareStopAgents(nil, &pb.PrepareStopAgentsRequest{})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(reply.Agents).To(HaveLen(1))
+		Expect(reply.Agents[0].Hostname).To(Equal("localhost"))
+		Expect(reply.Agents[0].Stopped).To(BeTrue())
+		Expect(reply.Agents[0].Error).To(BeEmpty())
+		Expect(mockAgent.NumberOfCalls()).To(Equal(1))
+	})
+
+	It("reports the agents that refused to shut down", func() {
+		mockAgent.Err <- errors.New("not now")
+
+		reply, err := hub.PrepareStopAgents(nil, &pb.PrepareStopAgentsRequest{})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(reply.Agents).To(HaveLen(1))
+		Expect(reply.Agents[0].Stopped).To(BeFalse())
+		Expect(reply.Agents[0].Error).To(ContainSubstring("not now"))
+	})
+
+	It("stops the agents it can reach when others can't be reached", func() {
+		clusterPair.OldCluster.Segments[1] = cluster.SegConfig{Hostname: "unreachable.invalid"}
+
+		reply, err := hub.PrepareStopAgents(nil, &pb.PrepareStopAgentsRequest{})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(reply.Agents).To(HaveLen(2))
+		Expect(reply.Agents[0].Hostname).To(Equal("localhost"))
+		Expect(reply.Agents[0].Stopped).To(BeTrue())
+		Expect(reply.Agents[1].Hostname).To(Equal("unreachable.invalid"))
+		Expect(reply.Agents[1].Stopped).To(BeFalse())
+		Expect(reply.Agents[1].Error).To(ContainSubstring("could not connect to the agent"))
+	})
+	It("refuses to stop the agents while a step is running", func() {
+		dir, err := ioutil.TempDir("", "")
+		Expect(err).ToNot(HaveOccurred())
+		defer os.RemoveAll(dir)
+		setStepStatus(dir, upgradestatus.SHARE_OIDS, pb.StepStatus_RUNNING)
+
+		hub = services.NewHub(clusterPair, grpc.DialContext, nil,
+			&services.HubConfig{StateDir: dir, HubToAgentPort: port},
+			testutils.NewStubRemoteExecutor(), nil)
+
+		_, err = hub.PrepareStopAgents(nil, &pb.PrepareStopAgentsRequest{})
+		Expect(status.Code(err)).To(Equal(codes.FailedPrecondition))
+		Expect(err.Error()).To(ContainSubstring("share-oids is running"))
+		Expect(mockAgent.NumberOfCalls()).To(Equal(0))
+	})
+})
@@ -0,0 +1,65 @@
+package services
+
+import (
+	"context"
+
+	pb "github.com/greenplum-db/gpupgrade/idl"
+
+	"github.com/greenplum-db/gp-common-go-libs/gplog"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// RegisterAgent adds the agent to the registry, and tells it how often to
+// send heartbeats.
+func (h *Hub) RegisterAgent(ctx context.Context, in *pb.RegisterAgentRequest) (*pb.RegisterAgentReply, error) {
+	err := verifyAgentHost(ctx, in.Hostname)
+	if err != nil {
+		gplog.Error(err.Error())
+		return &pb.RegisterAgentReply{}, err
+	}
+
+	gplog.Info("agent on %s registered (version %q, state dir %s)", in.Hostname, in.Version, in.StateDir)
+	h.registry.Register(in.Hostname, in.Version, in.StateDir)
+
+	return &pb.RegisterAgentReply{HeartbeatIntervalSeconds: int64(HeartbeatInterval.Seconds())}, nil
+}
+
+// Heartbeat records that the agent is still up. Agents that the hub doesn't
+// know about, such as ones that registered with a hub that has since been
+// restarted, are told to register again with a NotFound error.
+func (h *Hub) Heartbeat(ctx context.Context, in *pb.HeartbeatRequest) (*pb.HeartbeatReply, error) {
+	err := verifyAgentHost(ctx, in.Hostname)
+	if err != nil {
+		gplog.Error(err.Error())
+		return &pb.HeartbeatReply{}, err
+	}
+
+	if !h.registry.Heartbeat(in.Hostname, in.Version, in.StateDir) {
+		return &pb.HeartbeatReply{}, status.Errorf(codes.NotFound, "the agent on %s has not registered", in.Hostname)
+	}
+
+	return &pb.HeartbeatReply{}, nil
+}
+
+// verifyAgentHost makes sure that an agent only speaks for the host that its
+// certificate was issued to. Agents without a certificate, which only happens
+// in tests, are taken at their word.
+func verifyAgentHost(ctx context.Context, host string) error {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return nil
+	}
+	info, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(info.State.PeerCertificates) == 0 {
+		return nil
+	}
+
+	err := info.State.PeerCertificates[0].VerifyHostname(host)
+	if err != nil {
+		return status.Errorf(codes.PermissionDenied, "the agent's certificate is not for %s: %s", host, err)
+	}
+	return nil
+}
@@ -0,0 +1,212 @@
+package services_test
+
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"os"
+	"time"
+
+	"github.com/greenplum-db/gpupgrade/certs"
+	"github.com/greenplum-db/gpupgrade/hub/services"
+	pb "github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/testutils"
+	"github.com/greenplum-db/gpupgrade/utils"
+
+	"github.com/greenplum-db/gp-common-go-libs/cluster"
+	"github.com/greenplum-db/gp-common-go-libs/testhelper"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("agent registration", func() {
+	var (
+		clusterPair *services.ClusterPair
+		hub         *services.Hub
+		now         time.Time
+		dialTimeout time.Duration
+	)
+
+	BeforeEach(func() {
+		testhelper.SetupTestLogger()
+
+		dialTimeout = services.DialTimeout
+		services.DialTimeout = 500 * time.Millisecond
+
+		now = time.Unix(1000, 0)
+		utils.System.Now = func() time.Time { return now }
+
+		clusterPair = &services.ClusterPair{
+			OldCluster: testutils.CreateSampleCluster(-1, 25437, "localhost", "/old/datadir"),
+		}
+		clusterPair.OldCluster.Segments = map[int]cluster.SegConfig{
+			-1: {ContentID: -1, Hostname: "mdw"},
+			0:  {ContentID: 0, Hostname: "sdw1"},
+			1:  {ContentID: 1, Hostname: "sdw2"},
+		}
+		hub = services.NewHub(clusterPair, grpc.DialContext, nil,
+			&services.HubConfig{RegistrationPort: 6417},
+			testutils.NewStubRemoteExecutor(), nil)
+	})
+
+	AfterEach(func() {
+		utils.System = utils.InitializeSystemFunctions()
+		services.DialTimeout = dialTimeout
+	})
+
+	register := func(host string) {
+		reply, err := hub.RegisterAgent(context.Background(), &pb.RegisterAgentRequest{
+			Hostname: host,
+			Version:  "1.2.3",
+			StateDir: "/state/dir",
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(reply.HeartbeatIntervalSeconds).To(Equal(int64(services.HeartbeatInterval.Seconds())))
+	}
+
+	It("reports registered agents as healthy, and the others as missing", func() {
+		register("sdw1")
+
+		reply, err := hub.StatusAgents(nil, &pb.StatusAgentsRequest{})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(reply.Agents).To(HaveLen(3))
+		Expect(reply.Agents[0]).To(Equal(&pb.AgentHealthStatus{
+			Hostname: "mdw",
+			Health:   pb.AgentHealth_MISSING,
+		}))
+		Expect(reply.Agents[1]).To(Equal(&pb.AgentHealthStatus{
+			Hostname:      "sdw1",
+			Health:        pb.AgentHealth_HEALTHY,
+			Version:       "1.2.3",
+			StateDir:      "/state/dir",
+			LastHeartbeat: 1000,
+		}))
+		Expect(reply.Agents[2]).To(Equal(&pb.AgentHealthStatus{
+			Hostname: "sdw2",
+			Health:   pb.AgentHealth_MISSING,
+		}))
+	})
+
+	It("reports agents that have stopped sending heartbeats as stale", func() {
+		register("sdw1")
+
+		now = now.Add(time.Duration(services.HeartbeatMisses) * services.HeartbeatInterval)
+		reply, err := hub.StatusAgents(nil, &pb.StatusAgentsRequest{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(reply.Agents[1].Health).To(Equal(pb.AgentHealth_HEALTHY))
+
+		now = now.Add(time.Second)
+		reply, err = hub.StatusAgents(nil, &pb.StatusAgentsRequest{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(reply.Agents[1].Health).To(Equal(pb.AgentHealth_STALE))
+
+		_, err = hub.Heartbeat(context.Background(), &pb.HeartbeatRequest{Hostname: "sdw1", Version: "1.2.4"})
+		Expect(err).ToNot(HaveOccurred())
+
+		reply, err = hub.StatusAgents(nil, &pb.StatusAgentsRequest{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(reply.Agents[1].Health).To(Equal(pb.AgentHealth_HEALTHY))
+		Expect(reply.Agents[1].Version).To(Equal("1.2.4"))
+		Expect(reply.Agents[1].LastHeartbeat).To(Equal(now.Unix()))
+	})
+
+	It("tells agents that have not registered to register", func() {
+		_, err := hub.Heartbeat(context.Background(), &pb.HeartbeatRequest{Hostname: "sdw1"})
+
+		s, ok := status.FromError(err)
+		Expect(ok).To(BeTrue())
+		Expect(s.Code()).To(Equal(codes.NotFound))
+	})
+
+	It("names the agents that have never registered when they can't be reached", func() {
+		clusterPair.OldCluster.Segments[1] = cluster.SegConfig{ContentID: 1, Hostname: "unreachable.invalid"}
+		hub = services.NewHub(clusterPair, grpc.DialContext, nil,
+			&services.HubConfig{HubToAgentPort: 6416, RegistrationPort: 6417, Parallelism: 2},
+			testutils.NewStubRemoteExecutor(), nil)
+
+		_, err := hub.AgentConns()
+		Expect(err).To(MatchError(ContainSubstring("unreachable.invalid (never registered)")))
+	})
+
+	Context("over TLS", func() {
+		var (
+			dir    string
+			server *grpc.Server
+			addr   string
+		)
+
+		BeforeEach(func() {
+			utils.System = utils.InitializeSystemFunctions()
+
+			var err error
+			dir, err = ioutil.TempDir("", "")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(certs.CreateAuthority(dir)).To(Succeed())
+
+			hubCreds, err := certs.ServerCredentials(dir, certs.Hub, certs.Agent)
+			Expect(err).ToNot(HaveOccurred())
+
+			lis, err := net.Listen("tcp", "localhost:0")
+			Expect(err).ToNot(HaveOccurred())
+			addr = lis.Addr().String()
+
+			server = grpc.NewServer(grpc.Creds(hubCreds))
+			pb.RegisterAgentToHubServer(server, hub)
+			go server.Serve(lis)
+		})
+
+		AfterEach(func() {
+			server.Stop()
+			os.RemoveAll(dir)
+		})
+
+		registerAs := func(certHost string, host string) error {
+			agentCreds, err := testutils.AgentClientCredentials(dir, certHost)
+			Expect(err).ToNot(HaveOccurred())
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			conn, err := grpc.DialContext(ctx, addr, grpc.WithTransportCredentials(agentCreds), grpc.WithBlock())
+			Expect(err).ToNot(HaveOccurred())
+			defer conn.Close()
+
+			_, err = pb.NewAgentToHubClient(conn).RegisterAgent(ctx, &pb.RegisterAgentRequest{Hostname: host})
+			return err
+		}
+
+		It("accepts agents registering for the host in their certificate", func() {
+			Expect(registerAs("sdw1", "sdw1")).To(Succeed())
+		})
+
+		It("refuses agents registering for another host", func() {
+			err := registerAs("sdw1", "sdw2")
+
+			s, ok := status.FromError(err)
+			Expect(ok).To(BeTrue())
+			Expect(s.Code()).To(Equal(codes.PermissionDenied))
+
+			reply, err := hub.StatusAgents(nil, &pb.StatusAgentsRequest{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(reply.Agents[2].Health).To(Equal(pb.AgentHealth_MISSING))
+		})
+
+		It("refuses clients other than the agents", func() {
+			cliCreds, err := certs.ClientCredentials(dir, certs.CLI)
+			Expect(err).ToNot(HaveOccurred())
+
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+			conn, err := grpc.DialContext(ctx, addr, grpc.WithTransportCredentials(cliCreds))
+			Expect(err).ToNot(HaveOccurred())
+			defer conn.Close()
+
+			_, err = pb.NewAgentToHubClient(conn).RegisterAgent(ctx, &pb.RegisterAgentRequest{Hostname: "sdw1"})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,314 @@
+package services
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/greenplum-db/gpupgrade/hub/upgradestatus"
+	pb "github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/utils"
+
+	"github.com/greenplum-db/gp-common-go-libs/cluster"
+	"github.com/greenplum-db/gp-common-go-libs/gplog"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// revertAction is a single undo action performed by Revert. Every action must
+// be safe to perform again after it has already succeeded.
+type revertAction struct {
+	name string
+	run  func(h *Hub) error
+}
+
+// Revert rolls an upgrade back to the old cluster. It stops the new cluster,
+// restores the master port that reconfigure-ports changed, deletes the new
+// cluster's data directories if pg_upgrade left them half-converted, and
+// starts the old cluster again. Each undo action is recorded in the checklist;
+// since all of them can be repeated, a Revert that fails part way through can
+// be run again once the problem is fixed. Once it succeeds, the steps it
+// undid are recorded as PENDING. The step RPCs refuse to run while it does,
+// and it refuses to run while any step is running, or in link mode once
+// pg_upgrade has run on the master.
+func (h *Hub) Revert(ctx context.Context, in *pb.RevertRequest) (*pb.RevertReply, error) {
+	gplog.Info("starting Revert")
+
+	h.mu.Lock()
+	if h.upgradeRunning || h.reverting {
+		h.mu.Unlock()
+		err := status.Error(codes.FailedPrecondition, "cannot revert while an upgrade run or another revert is in progress")
+		gplog.Error(err.Error())
+		return &pb.RevertReply{}, err
+	}
+	if h.shuttingDown {
+		h.mu.Unlock()
+		err := status.Error(codes.FailedPrecondition, "cannot revert while the hub is shutting down")
+		gplog.Error(err.Error())
+		return &pb.RevertReply{}, err
+	}
+	h.reverting = true
+	h.mu.Unlock()
+
+	defer func() {
+		h.mu.Lock()
+		h.reverting = false
+		h.mu.Unlock()
+	}()
+
+	err := h.revert()
+	if err != nil {
+		gplog.Error(err.Error())
+		return &pb.RevertReply{}, err
+	}
+
+	return &pb.RevertReply{}, nil
+}
+
+func (h *Hub) revert() error {
+	err := h.loadClusterConfigs()
+	if err != nil {
+		return err
+	}
+
+	steps := make(map[pb.UpgradeSteps]Step)
+	for _, s := range upgradeSteps() {
+		steps[s.StepCode] = s
+	}
+
+	shutdown := steps[pb.UpgradeSteps_STOPPED_CLUSTER]
+	if shutdown.Status(h).Status == pb.StepStatus_PENDING {
+		return status.Errorf(codes.FailedPrecondition, "there is nothing to revert: %s has not been run", shutdown.Name)
+	}
+
+	if running := h.runningStep(); running != "" {
+		return status.Errorf(codes.FailedPrecondition, "cannot revert while %s is running", running)
+	}
+
+	masterStatus := steps[pb.UpgradeSteps_MASTERUPGRADE].Status(h).Status
+	primariesStatus := steps[pb.UpgradeSteps_CONVERT_PRIMARIES].Status(h).Status
+
+	// In link mode pg_upgrade hard links the old data files into the new
+	// data dirs and disables the old cluster's pg_control, so there is no
+	// old cluster left to start once it has run.
+	if h.linkMode() && masterStatus != pb.StepStatus_PENDING {
+		return status.Errorf(codes.FailedPrecondition,
+			"cannot revert an upgrade in link mode once %s has run: pg_upgrade has linked the old cluster's data files into the new cluster, so the old cluster has to be restored from a backup",
+			steps[pb.UpgradeSteps_MASTERUPGRADE].Name)
+	}
+
+	actions := []revertAction{
+		{name: upgradestatus.REVERT_STOP_NEW_CLUSTER, run: stopNewCluster},
+		{name: upgradestatus.REVERT_RESTORE_PORTS, run: restorePorts},
+	}
+
+	// Once pg_upgrade has touched the new cluster it can't be used again
+	// unless every segment was converted.
+	halfConverted := masterStatus != pb.StepStatus_PENDING &&
+		!(masterStatus == pb.StepStatus_COMPLETE && primariesStatus == pb.StepStatus_COMPLETE)
+	if halfConverted {
+		actions = append(actions, revertAction{name: upgradestatus.REVERT_DELETE_NEW_DATADIRS, run: deleteNewDataDirs})
+	}
+
+	actions = append(actions, revertAction{name: upgradestatus.REVERT_START_OLD_CLUSTER, run: startOldCluster})
+
+	err = h.checklistWriter.MarkInProgress(upgradestatus.REVERT)
+	if err != nil {
+		return err
+	}
+
+	for _, action := range actions {
+		err = h.runRevertAction(action)
+		if err != nil {
+			cmErr := h.checklistWriter.MarkFailed(upgradestatus.REVERT, err)
+			if cmErr != nil {
+				gplog.Error("failed to record failed for %s: %s", upgradestatus.REVERT, cmErr)
+			}
+			return err
+		}
+	}
+
+	// The steps that were undone have to be run again.
+	undone := []string{
+		upgradestatus.SHUTDOWN_CLUSTERS,
+		upgradestatus.VALIDATE_START_CLUSTER,
+		upgradestatus.RECONFIGURE_PORTS,
+	}
+	if halfConverted {
+		undone = append(undone,
+			upgradestatus.INIT_CLUSTER,
+			upgradestatus.CONVERT_MASTER,
+			upgradestatus.SHARE_OIDS,
+			upgradestatus.CONVERT_PRIMARY,
+		)
+	}
+	for _, step := range undone {
+		err = h.checklistWriter.ResetStateDir(step)
+		if err != nil {
+			return err
+		}
+	}
+
+	return h.checklistWriter.MarkComplete(upgradestatus.REVERT)
+}
+
+func (h *Hub) runRevertAction(action revertAction) error {
+	gplog.Info("running %s", action.name)
+
+	err := h.checklistWriter.MarkInProgress(action.name)
+	if err != nil {
+		return err
+	}
+
+	err = action.run(h)
+	if err != nil {
+		err = fmt.Errorf("%s failed: %s", action.name, err)
+		cmErr := h.checklistWriter.MarkFailed(action.name, err)
+		if cmErr != nil {
+			gplog.Error("failed to record failed for %s: %s", action.name, cmErr)
+		}
+		return err
+	}
+
+	return h.checklistWriter.MarkComplete(action.name)
+}
+
+// loadClusterConfigs fills in whichever cluster the hub doesn't know about yet
+// from the configs saved in the state dir. The new cluster config is optional,
+// since it only exists once init-cluster has been run.
+func (h *Hub) loadClusterConfigs() error {
+	if h.clusterPair.OldCluster == nil {
+		err := h.clusterPair.ReadOldConfig(h.conf.StateDir)
+		if err != nil {
+			return fmt.Errorf("could not read the old cluster config: %s", err)
+		}
+	}
+
+	if h.clusterPair.NewCluster == nil {
+		err := h.clusterPair.ReadNewConfig(h.conf.StateDir)
+		if err != nil && !utils.System.IsNotExist(err) {
+			return fmt.Errorf("could not read the new cluster config: %s", err)
+		}
+	}
+
+	return nil
+}
+
+/*
+ * revertAction Implementations
+ */
+
+func stopNewCluster(h *Hub) error {
+	if h.clusterPair.NewCluster == nil {
+		return nil
+	}
+
+	return StopCluster(h.clusterPair.NewCluster, h.clusterPair.NewBinDir)
+}
+
+// restorePorts puts back the master postgresql.conf of the new cluster that
+// UpgradeReconfigurePorts saved before rewriting its port.
+func restorePorts(h *Hub) error {
+	if h.clusterPair.NewCluster == nil {
+		return nil
+	}
+
+	newMasterDataDir := h.clusterPair.NewCluster.GetDirForContent(-1)
+	backup := filepath.Join(newMasterDataDir, "postgresql.conf.bak")
+	if _, err := utils.System.Stat(backup); utils.System.IsNotExist(err) {
+		return nil
+	}
+
+	return utils.System.Rename(backup, filepath.Join(newMasterDataDir, "postgresql.conf"))
+}
+
+// deleteNewDataDirs removes the data directories of the new cluster, along
+// with the pg_upgrade working directories, on the master and the agents' hosts,
+// the records of the pg_upgrade processes, and the new cluster config that
+// refer to them. The new cluster has to be initialized again before retrying
+// the upgrade.
+func deleteNewDataDirs(h *Hub) error {
+	newCluster := h.clusterPair.NewCluster
+	if newCluster == nil {
+		return nil
+	}
+
+	for _, contentID := range newCluster.ContentIDs {
+		newDir := newCluster.GetDirForContent(contentID)
+		if h.clusterPair.OldCluster.GetDirForContent(contentID) == newDir {
+			return fmt.Errorf("refusing to delete %s for content %d: the old cluster uses the same data directory", newDir, contentID)
+		}
+	}
+
+	commandMap := newCluster.GenerateSSHCommandMapForSegments(true, func(contentID int) string {
+		return fmt.Sprintf("rm -rf %s", newCluster.GetDirForContent(contentID))
+	})
+	remoteOutput := newCluster.ExecuteClusterCommand(cluster.ON_SEGMENTS_AND_MASTER, commandMap)
+	if remoteOutput.NumErrors > 0 {
+		var contentIDs []int
+		for contentID := range remoteOutput.Errors {
+			contentIDs = append(contentIDs, contentID)
+		}
+		sort.Ints(contentIDs)
+
+		var failed []string
+		for _, contentID := range contentIDs {
+			failed = append(failed, strconv.Itoa(contentID))
+		}
+		return fmt.Errorf("could not delete the data directories for contents %s", strings.Join(failed, ", "))
+	}
+
+	err := h.revertOnAgents()
+	if err != nil {
+		return err
+	}
+
+	err = h.processes.Forget()
+	if err != nil {
+		return err
+	}
+
+	err = utils.System.RemoveAll(filepath.Join(h.conf.StateDir, "pg_upgrade"))
+	if err != nil {
+		return err
+	}
+
+	err = utils.System.Remove(GetNewConfigFilePath(h.conf.StateDir))
+	if err != nil && !utils.System.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
+
+// revertOnAgents has every agent forget the conversion of its primaries, once
+// the agents have been started.
+func (h *Hub) revertOnAgents() error {
+	startAgents := upgradestatus.NewStateCheck(h.conf.StateDir, upgradestatus.START_AGENTS, pb.UpgradeSteps_PREPARE_START_AGENTS)
+	if startAgents.GetStatus().Status != pb.StepStatus_COMPLETE {
+		return nil
+	}
+
+	return h.forEachAgent("revert the upgrade on", func(host string, client pb.AgentClient) error {
+		_, err := client.Revert(context.Background(), &pb.RevertAgentRequest{})
+		return err
+	})
+}
+
+func startOldCluster(h *Hub) error {
+	oldCluster := h.clusterPair.OldCluster
+	if IsPostmasterRunning(oldCluster) {
+		return nil
+	}
+
+	oldBinDir := h.clusterPair.OldBinDir
+	gpstartShellArgs := fmt.Sprintf("source %[1]s/../greenplum_path.sh; %[1]s/gpstart -a -d %[2]s",
+		oldBinDir, oldCluster.GetDirForContent(-1))
+
+	gplog.Info("gpstart args: %+v", gpstartShellArgs)
+	_, err := oldCluster.ExecuteLocalCommand(gpstartShellArgs)
+	return err
+}
@@ -0,0 +1,290 @@
+package services_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/greenplum-db/gpupgrade/config"
+	"github.com/greenplum-db/gpupgrade/hub/services"
+	"github.com/greenplum-db/gpupgrade/hub/upgradestatus"
+	pb "github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/testutils"
+	"github.com/greenplum-db/gpupgrade/utils"
+
+	"github.com/greenplum-db/gp-common-go-libs/cluster"
+	"github.com/greenplum-db/gp-common-go-libs/testhelper"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Revert", func() {
+	var (
+		hub           *services.Hub
+		dir           string
+		newMasterDir  string
+		commandExecer *testutils.FakeCommandExecer
+		oldExecutor   *testhelper.TestExecutor
+		newExecutor   *testhelper.TestExecutor
+		store         *upgradestatus.StateStore
+		clusterPair   *services.ClusterPair
+	)
+
+	BeforeEach(func() {
+		testhelper.SetupTestLogger()
+
+		var err error
+		dir, err = ioutil.TempDir("", "")
+		Expect(err).ToNot(HaveOccurred())
+		store = upgradestatus.NewStateStore(dir)
+
+		newMasterDir = filepath.Join(dir, "new_master")
+		Expect(os.MkdirAll(newMasterDir, 0700)).To(Succeed())
+
+		oldCluster := cluster.NewCluster([]cluster.SegConfig{
+			newSegment(-1, "localhost", "/old/datadir", 25437),
+			newSegment(0, "localhost", "/old/datadir0", 25438),
+		})
+		newCluster := cluster.NewCluster([]cluster.SegConfig{
+			newSegment(-1, "localhost", newMasterDir, 35437),
+			newSegment(0, "localhost", "/new/datadir0", 35438),
+		})
+
+		// The old cluster is down, so the check for its postmaster fails and
+		// gpstart succeeds. The new cluster is still up.
+		oldExecutor = &testhelper.TestExecutor{
+			LocalError:     errors.New("exit status 1"),
+			ErrorOnExecNum: 1,
+		}
+		oldCluster.Executor = oldExecutor
+		newExecutor = &testhelper.TestExecutor{
+			ClusterOutput: &cluster.RemoteOutput{},
+		}
+		newCluster.Executor = newExecutor
+
+		clusterPair = &services.ClusterPair{
+			OldCluster: oldCluster,
+			NewCluster: newCluster,
+			OldBinDir:  "/old/bindir",
+			NewBinDir:  "/new/bindir",
+		}
+
+		commandExecer = &testutils.FakeCommandExecer{}
+		commandExecer.SetOutput(&testutils.FakeCommand{})
+
+		hub = services.NewHub(clusterPair, grpc.DialContext, commandExecer.Exec, &services.HubConfig{StateDir: dir},
+			testutils.NewStubRemoteExecutor(), upgradestatus.NewChecklistManager(dir))
+
+		setStepStatus(dir, upgradestatus.SHUTDOWN_CLUSTERS, pb.StepStatus_COMPLETE)
+
+		f, err := os.Create(services.GetNewConfigFilePath(dir))
+		Expect(err).ToNot(HaveOccurred())
+		f.Close()
+	})
+
+	AfterEach(func() {
+		utils.System = utils.InitializeSystemFunctions()
+		os.RemoveAll(dir)
+	})
+
+	stepStatus := func(step string) pb.StepStatus {
+		status, err := store.Status(step)
+		Expect(err).ToNot(HaveOccurred())
+		return status
+	}
+
+	It("refuses to revert if the clusters were never shut down", func() {
+		setStepStatus(dir, upgradestatus.SHUTDOWN_CLUSTERS, pb.StepStatus_PENDING)
+
+		_, err := hub.Revert(nil, &pb.RevertRequest{})
+		Expect(status.Code(err)).To(Equal(codes.FailedPrecondition))
+
+		Expect(oldExecutor.NumExecutions).To(Equal(0))
+		Expect(newExecutor.NumExecutions).To(Equal(0))
+	})
+
+	It("refuses to revert while pg_upgrade is running", func() {
+		setMasterUpgradeComplete(dir)
+		outChan := make(chan []byte, 1)
+		outChan <- []byte("1234")
+		commandExecer.SetOutput(&testutils.FakeCommand{Out: outChan})
+
+		_, err := hub.Revert(nil, &pb.RevertRequest{})
+		Expect(status.Code(err)).To(Equal(codes.FailedPrecondition))
+		Expect(err.Error()).To(ContainSubstring("pg_upgrade is running"))
+
+		Expect(newExecutor.NumExecutions).To(Equal(0))
+	})
+
+	It("refuses to revert while any step is running", func() {
+		setStepStatus(dir, upgradestatus.SHARE_OIDS, pb.StepStatus_RUNNING)
+
+		_, err := hub.Revert(nil, &pb.RevertRequest{})
+		Expect(status.Code(err)).To(Equal(codes.FailedPrecondition))
+		Expect(err.Error()).To(ContainSubstring("share-oids is running"))
+
+		Expect(newExecutor.NumExecutions).To(Equal(0))
+	})
+
+	It("refuses to revert in link mode once pg_upgrade has run on the master", func() {
+		hub = services.NewHub(clusterPair, grpc.DialContext, commandExecer.Exec,
+			&services.HubConfig{StateDir: dir, UpgradeMode: config.ModeLink},
+			testutils.NewStubRemoteExecutor(), upgradestatus.NewChecklistManager(dir))
+		setMasterUpgradeComplete(dir)
+
+		_, err := hub.Revert(nil, &pb.RevertRequest{})
+		Expect(status.Code(err)).To(Equal(codes.FailedPrecondition))
+		Expect(err.Error()).To(ContainSubstring("link mode"))
+
+		Expect(oldExecutor.NumExecutions).To(Equal(0))
+		Expect(newExecutor.NumExecutions).To(Equal(0))
+		Expect(stepStatus(upgradestatus.REVERT)).To(Equal(pb.StepStatus_PENDING))
+	})
+
+	It("reverts in link mode before pg_upgrade has run", func() {
+		hub = services.NewHub(clusterPair, grpc.DialContext, commandExecer.Exec,
+			&services.HubConfig{StateDir: dir, UpgradeMode: config.ModeLink},
+			testutils.NewStubRemoteExecutor(), upgradestatus.NewChecklistManager(dir))
+
+		_, err := hub.Revert(nil, &pb.RevertRequest{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(stepStatus(upgradestatus.REVERT)).To(Equal(pb.StepStatus_COMPLETE))
+	})
+
+	It("stops the new cluster, restores its master port and starts the old cluster", func() {
+		setStepStatus(dir, upgradestatus.VALIDATE_START_CLUSTER, pb.StepStatus_COMPLETE)
+		setStepStatus(dir, upgradestatus.RECONFIGURE_PORTS, pb.StepStatus_COMPLETE)
+		confPath := filepath.Join(newMasterDir, "postgresql.conf")
+		Expect(ioutil.WriteFile(confPath, []byte("port=25437\n"), 0600)).To(Succeed())
+		Expect(ioutil.WriteFile(confPath+".bak", []byte("port=35437\n"), 0600)).To(Succeed())
+
+		_, err := hub.Revert(nil, &pb.RevertRequest{})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(newExecutor.LocalCommands).To(HaveLen(2))
+		Expect(newExecutor.LocalCommands[1]).To(ContainSubstring("/new/bindir/gpstop -a -d " + newMasterDir))
+
+		contents, err := ioutil.ReadFile(confPath)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(contents)).To(Equal("port=35437\n"))
+		Expect(confPath + ".bak").ToNot(BeAnExistingFile())
+
+		Expect(oldExecutor.LocalCommands).To(HaveLen(2))
+		Expect(oldExecutor.LocalCommands[1]).To(ContainSubstring("/old/bindir/gpstart -a -d /old/datadir"))
+
+		// pg_upgrade never ran, so the new cluster is left in place.
+		Expect(newExecutor.ClusterCommands).To(BeEmpty())
+		Expect(services.GetNewConfigFilePath(dir)).To(BeAnExistingFile())
+
+		Expect(stepStatus(upgradestatus.REVERT_STOP_NEW_CLUSTER)).To(Equal(pb.StepStatus_COMPLETE))
+		Expect(stepStatus(upgradestatus.REVERT_RESTORE_PORTS)).To(Equal(pb.StepStatus_COMPLETE))
+		Expect(stepStatus(upgradestatus.REVERT_DELETE_NEW_DATADIRS)).To(Equal(pb.StepStatus_PENDING))
+		Expect(stepStatus(upgradestatus.REVERT_START_OLD_CLUSTER)).To(Equal(pb.StepStatus_COMPLETE))
+		Expect(stepStatus(upgradestatus.REVERT)).To(Equal(pb.StepStatus_COMPLETE))
+
+		// The clusters have to be shut down, and the new one started, again.
+		Expect(stepStatus(upgradestatus.SHUTDOWN_CLUSTERS)).To(Equal(pb.StepStatus_PENDING))
+		Expect(stepStatus(upgradestatus.VALIDATE_START_CLUSTER)).To(Equal(pb.StepStatus_PENDING))
+		Expect(stepStatus(upgradestatus.RECONFIGURE_PORTS)).To(Equal(pb.StepStatus_PENDING))
+	})
+
+	It("deletes the new data directories when pg_upgrade did not finish", func() {
+		// pg_upgrade started on the master but left no done file behind.
+		setStepStatus(dir, upgradestatus.CONFIG, pb.StepStatus_COMPLETE)
+		pgUpgradeDir := filepath.Join(dir, "pg_upgrade")
+		Expect(os.MkdirAll(pgUpgradeDir, 0700)).To(Succeed())
+
+		_, err := hub.Revert(nil, &pb.RevertRequest{})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(newExecutor.ClusterCommands).To(HaveLen(1))
+		commands := newExecutor.ClusterCommands[0]
+		Expect(commands).To(HaveLen(2))
+		Expect(commands[-1]).To(ContainElement("rm -rf " + newMasterDir))
+		Expect(commands[0]).To(ContainElement("rm -rf /new/datadir0"))
+
+		Expect(pgUpgradeDir).ToNot(BeAnExistingFile())
+		Expect(services.GetNewConfigFilePath(dir)).ToNot(BeAnExistingFile())
+		Expect(stepStatus(upgradestatus.REVERT_DELETE_NEW_DATADIRS)).To(Equal(pb.StepStatus_COMPLETE))
+		Expect(stepStatus(upgradestatus.REVERT)).To(Equal(pb.StepStatus_COMPLETE))
+		Expect(stepStatus(upgradestatus.CONVERT_MASTER)).To(Equal(pb.StepStatus_PENDING))
+	})
+
+	It("has the agents forget the conversion of the primaries when pg_upgrade did not finish", func() {
+		mockAgent, port := testutils.NewMockAgentServer()
+		defer mockAgent.Stop()
+		mockAgent.StatusConversionResponse = &pb.CheckConversionStatusReply{
+			Statuses: []*pb.SegmentConversionStatus{{Role: pb.SegmentRole_PRIMARY, Status: pb.StepStatus_FAILED}},
+		}
+
+		hub = services.NewHub(clusterPair, grpc.DialContext, commandExecer.Exec,
+			&services.HubConfig{StateDir: dir, HubToAgentPort: port},
+			testutils.NewStubRemoteExecutor(), upgradestatus.NewChecklistManager(dir))
+		setMasterUpgradeComplete(dir)
+		setStepStatus(dir, upgradestatus.START_AGENTS, pb.StepStatus_COMPLETE)
+		setStepStatus(dir, upgradestatus.SHARE_OIDS, pb.StepStatus_COMPLETE)
+
+		_, err := hub.Revert(nil, &pb.RevertRequest{})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(mockAgent.Reverted).To(BeTrue())
+		Expect(newExecutor.ClusterCommands).To(HaveLen(1))
+		Expect(stepStatus(upgradestatus.SHARE_OIDS)).To(Equal(pb.StepStatus_PENDING))
+		Expect(stepStatus(upgradestatus.CONVERT_PRIMARY)).To(Equal(pb.StepStatus_PENDING))
+	})
+
+	It("keeps the upgrade steps from running while it is in progress", func() {
+		mockAgent, port := testutils.NewMockAgentServer()
+		defer mockAgent.Stop()
+		mockAgent.StatusConversionResponse = &pb.CheckConversionStatusReply{
+			Statuses: []*pb.SegmentConversionStatus{{Role: pb.SegmentRole_PRIMARY, Status: pb.StepStatus_FAILED}},
+		}
+		mockAgent.RevertGate = make(chan struct{})
+
+		hub = services.NewHub(clusterPair, grpc.DialContext, commandExecer.Exec,
+			&services.HubConfig{StateDir: dir, HubToAgentPort: port},
+			testutils.NewStubRemoteExecutor(), upgradestatus.NewChecklistManager(dir))
+		setMasterUpgradeComplete(dir)
+		setStepStatus(dir, upgradestatus.START_AGENTS, pb.StepStatus_COMPLETE)
+
+		reverted := make(chan error)
+		go func() {
+			_, err := hub.Revert(nil, &pb.RevertRequest{})
+			reverted <- err
+		}()
+		Eventually(func() pb.StepStatus { return stepStatus(upgradestatus.REVERT_DELETE_NEW_DATADIRS) }).
+			Should(Equal(pb.StepStatus_RUNNING))
+
+		_, err := hub.UpgradeConvertMaster(nil, &pb.UpgradeConvertMasterRequest{})
+		Expect(status.Code(err)).To(Equal(codes.FailedPrecondition))
+		Expect(err.Error()).To(ContainSubstring("cannot run while a revert is in progress"))
+
+		close(mockAgent.RevertGate)
+		Expect(<-reverted).To(Succeed())
+	})
+
+	It("records the action that failed, and succeeds when run again", func() {
+		oldExecutor.ErrorOnExecNum = 0 // gpstart fails too
+
+		_, err := hub.Revert(nil, &pb.RevertRequest{})
+		Expect(err).To(MatchError(ContainSubstring(upgradestatus.REVERT_START_OLD_CLUSTER)))
+
+		Expect(stepStatus(upgradestatus.REVERT_STOP_NEW_CLUSTER)).To(Equal(pb.StepStatus_COMPLETE))
+		Expect(stepStatus(upgradestatus.REVERT_START_OLD_CLUSTER)).To(Equal(pb.StepStatus_FAILED))
+		Expect(stepStatus(upgradestatus.REVERT)).To(Equal(pb.StepStatus_FAILED))
+
+		// Someone started the old cluster by hand.
+		oldExecutor.LocalError = nil
+
+		_, err = hub.Revert(nil, &pb.RevertRequest{})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(stepStatus(upgradestatus.REVERT_START_OLD_CLUSTER)).To(Equal(pb.StepStatus_COMPLETE))
+		Expect(stepStatus(upgradestatus.REVERT)).To(Equal(pb.StepStatus_COMPLETE))
+	})
+})
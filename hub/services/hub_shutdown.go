@@ -0,0 +1,108 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	pb "github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/utils"
+
+	"github.com/greenplum-db/gp-common-go-libs/gplog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Shutdown stops the hub once it has replied. The hub stops accepting RPCs
+// straight away, and gives the ones in flight ShutdownGracePeriod to finish.
+// It refuses to shut down while an upgrade run, a revert or any step is in
+// progress, since they would be left half done; the steps refuse to start
+// once the hub is shutting down.
+func (h *Hub) Shutdown(ctx context.Context, in *pb.ShutdownRequest) (*pb.ShutdownReply, error) {
+	gplog.Info("got a request to shut down the hub")
+
+	host, err := utils.GetHost()
+	if err != nil {
+		gplog.Error("could not determine hostname: %s", err)
+	}
+
+	h.mu.Lock()
+	if h.upgradeRunning || h.reverting {
+		h.mu.Unlock()
+		err := status.Error(codes.FailedPrecondition, "cannot shut down the hub while an upgrade run or a revert is in progress")
+		gplog.Error(err.Error())
+		return &pb.ShutdownReply{}, err
+	}
+	if h.shuttingDown {
+		h.mu.Unlock()
+		return &pb.ShutdownReply{Hostname: host}, nil
+	}
+	h.shuttingDown = true
+	h.mu.Unlock()
+
+	running, err := h.stepInProgress()
+	if err == nil && running != "" {
+		err = status.Errorf(codes.FailedPrecondition, "cannot shut down the hub while %s is running", running)
+	}
+	if err != nil {
+		h.mu.Lock()
+		h.shuttingDown = false
+		h.mu.Unlock()
+
+		gplog.Error(err.Error())
+		return &pb.ShutdownReply{}, err
+	}
+
+	h.mu.Lock()
+	if h.server != nil {
+		go h.drain(h.server)
+	}
+	h.mu.Unlock()
+
+	return &pb.ShutdownReply{Hostname: host}, nil
+}
+
+// stepInProgress returns the name of the step that is running, if any. Until
+// check config has written the old cluster's config, no step that outlives its
+// RPC can have been started, and drain waits for the RPCs.
+func (h *Hub) stepInProgress() (string, error) {
+	if h.clusterPair.OldCluster == nil {
+		_, err := utils.System.Stat(GetConfigFilePath(h.conf.StateDir))
+		if utils.System.IsNotExist(err) {
+			return "", nil
+		}
+	}
+
+	err := h.loadClusterConfigs()
+	if err != nil {
+		return "", err
+	}
+
+	return h.runningStep(), nil
+}
+
+// drain waits for the RPCs in flight to finish, for up to
+// ShutdownGracePeriod, and then stops the hub.
+func (h *Hub) drain(server *grpc.Server) {
+	drained := make(chan struct{})
+	go func() {
+		server.GracefulStop()
+		close(drained)
+	}()
+
+	select {
+	case <-drained:
+	case <-time.After(ShutdownGracePeriod):
+		gplog.Warn("RPCs were still running %s after the hub was asked to shut down; stopping anyway", ShutdownGracePeriod)
+	}
+
+	h.Stop()
+	gplog.Info("hub shut down")
+	close(h.drained)
+}
+
+// Drained returns a channel that is closed once the hub has stopped after
+// being asked to shut down.
+func (h *Hub) Drained() <-chan struct{} {
+	return h.drained
+}
@@ -0,0 +1,125 @@
+package services_test
+
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"os"
+	"time"
+
+	"github.com/greenplum-db/gpupgrade/hub/services"
+	"github.com/greenplum-db/gpupgrade/hub/upgradestatus"
+	pb "github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/testutils"
+	"github.com/greenplum-db/gpupgrade/utils"
+
+	"github.com/greenplum-db/gp-common-go-libs/testhelper"
+	"google.golang.org/grpc"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Shutdown", func() {
+	var (
+		dir        string
+		socketPath string
+		hub        *services.Hub
+		stopped    chan struct{}
+		shutDown   bool
+	)
+
+	BeforeEach(func() {
+		testhelper.SetupTestLogger()
+
+		var err error
+		dir, err = ioutil.TempDir("", "")
+		Expect(err).ToNot(HaveOccurred())
+		socketPath = services.SocketPath(dir)
+
+		hub = services.NewHub(testutils.CreateSampleClusterPair(), grpc.DialContext, nil,
+			&services.HubConfig{SocketPath: socketPath, StateDir: dir}, testutils.NewStubRemoteExecutor(), nil)
+
+		done := make(chan struct{})
+		stopped = done
+		shutDown = false
+		go func() {
+			hub.Start()
+			close(done)
+		}()
+	})
+
+	AfterEach(func() {
+		utils.System = utils.InitializeSystemFunctions()
+		hub.Stop()
+		if shutDown {
+			Eventually(hub.Drained()).Should(BeClosed())
+		}
+		os.RemoveAll(dir)
+	})
+
+	shutdown := func() (*pb.ShutdownReply, error) {
+		conn, err := grpc.Dial(socketPath, grpc.WithInsecure(), grpc.WithBlock(), grpc.WithTimeout(2*time.Second),
+			grpc.WithDialer(func(path string, timeout time.Duration) (net.Conn, error) {
+				return net.DialTimeout("unix", path, timeout)
+			}))
+		if err != nil {
+			return nil, err
+		}
+		defer conn.Close()
+
+		reply, err := pb.NewCliToHubClient(conn).Shutdown(context.Background(), &pb.ShutdownRequest{})
+		shutDown = shutDown || err == nil
+		return reply, err
+	}
+
+	It("stops the hub once it has replied", func() {
+		var reply *pb.ShutdownReply
+		Eventually(func() (err error) {
+			reply, err = shutdown()
+			return err
+		}).Should(Succeed())
+
+		host, err := os.Hostname()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(reply.Hostname).To(Equal(host))
+
+		Eventually(stopped).Should(BeClosed())
+		Expect(socketPath).ToNot(BeAnExistingFile())
+	})
+
+	It("refuses to start an upgrade run once it is shutting down", func() {
+		Eventually(func() error {
+			_, err := shutdown()
+			return err
+		}).Should(Succeed())
+
+		_, err := hub.UpgradeRun(nil, &pb.UpgradeRunRequest{})
+		Expect(err).To(MatchError(ContainSubstring("shutting down")))
+	})
+
+	It("refuses to start a step once it is shutting down", func() {
+		Eventually(func() error {
+			_, err := shutdown()
+			return err
+		}).Should(Succeed())
+
+		_, err := hub.UpgradeShareOids(nil, &pb.UpgradeShareOidsRequest{})
+		Expect(err).To(MatchError(ContainSubstring("share-oids cannot run while the hub is shutting down")))
+	})
+
+	It("refuses to shut down while a step is running", func() {
+		setStepStatus(dir, upgradestatus.SHARE_OIDS, pb.StepStatus_RUNNING)
+
+		Eventually(func() error {
+			_, err := shutdown()
+			return err
+		}).Should(MatchError(ContainSubstring("cannot shut down the hub while share-oids is running")))
+		Consistently(stopped).ShouldNot(BeClosed())
+
+		setStepStatus(dir, upgradestatus.SHARE_OIDS, pb.StepStatus_COMPLETE)
+		_, err := shutdown()
+		Expect(err).ToNot(HaveOccurred())
+		Eventually(stopped).Should(BeClosed())
+	})
+})
@@ -0,0 +1,22 @@
+package services
+
+import (
+	"context"
+	"sort"
+
+	pb "github.com/greenplum-db/gpupgrade/idl"
+)
+
+// StatusAgents reports the health of the agent on every segment host, as
+// known from their registrations and heartbeats.
+func (h *Hub) StatusAgents(ctx context.Context, in *pb.StatusAgentsRequest) (*pb.StatusAgentsReply, error) {
+	hostnames := h.clusterPair.GetHostnames()
+	sort.Strings(hostnames)
+
+	reply := &pb.StatusAgentsReply{}
+	for _, host := range hostnames {
+		reply.Agents = append(reply.Agents, h.registry.Health(host))
+	}
+
+	return reply, nil
+}
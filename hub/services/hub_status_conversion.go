@@ -16,7 +16,7 @@ func (h *Hub) StatusConversion(ctx context.Context, in *pb.StatusConversionReque
 
 	segments := h.segmentsByHost()
 
-	var statuses []string
+	var statuses []*pb.SegmentConversionStatus
 	for _, conn := range conns {
 		var agentSegments []*pb.SegmentInfo
 		for _, segment := range segments[conn.Hostname] {
@@ -53,15 +53,22 @@ var _ = Describe("hub", func() {
 	})
 
 	It("receives conversion statuses from the agent and returns all as single message", func() {
-		statusMessages := []string{"status", "status"}
 		agentA.StatusConversionResponse = &pb.CheckConversionStatusReply{
-			Statuses: statusMessages,
+			Statuses: []*pb.SegmentConversionStatus{
+				{Dbid: 2, Content: 0, Role: pb.SegmentRole_PRIMARY, Hostname: "localhost", Status: pb.StepStatus_COMPLETE},
+				{Dbid: 3, Content: 1, Role: pb.SegmentRole_PRIMARY, Hostname: "localhost", Status: pb.StepStatus_RUNNING},
+			},
 		}
 
 		status, err := hub.StatusConversion(nil, &pb.StatusConversionRequest{})
 		Expect(err).ToNot(HaveOccurred())
 
-		Expect(status.GetConversionStatuses()).To(Equal([]string{"status", "status"}))
+		statuses := status.GetConversionStatuses()
+		Expect(statuses).To(HaveLen(2))
+		Expect(statuses[0].GetDbid()).To(Equal(int32(2)))
+		Expect(statuses[0].GetStatus()).To(Equal(pb.StepStatus_COMPLETE))
+		Expect(statuses[1].GetDbid()).To(Equal(int32(3)))
+		Expect(statuses[1].GetStatus()).To(Equal(pb.StepStatus_RUNNING))
 		Expect(agentA.StatusConversionRequest.GetHostname()).To(Equal("localhost"))
 		Expect(agentA.StatusConversionRequest.GetSegments()).To(ConsistOf([]*pb.SegmentInfo{
 			{
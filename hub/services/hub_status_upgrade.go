@@ -2,7 +2,6 @@ package services
 
 import (
 	"path/filepath"
-	"strings"
 
 	"github.com/greenplum-db/gpupgrade/hub/upgradestatus"
 	pb "github.com/greenplum-db/gpupgrade/idl"
@@ -25,12 +24,21 @@ type Step struct {
 
 	// getStatus is the private implementation of the Status method.
 	getStatus func(s Step, h *Hub) *pb.UpgradeStepStatus
+
+	// run performs the Step as part of an upgrade run, and returns once the
+	// Step has finished (see UpgradeRun).
+	run func(s Step, h *Hub, in *pb.UpgradeRunRequest) error
 }
 
 // Status retrieves the UpgradeStepStatus (failed, completed, etc.) for this
-// Step on a given Hub.
+// Step on a given Hub, along with its timing. Steps that aren't timed by their
+// getStatus implementation are timed using the upgrade journal.
 func (s Step) Status(h *Hub) *pb.UpgradeStepStatus {
-	return s.getStatus(s, h)
+	status := s.getStatus(s, h)
+	if status.StartTime == 0 {
+		addJournalTiming(status, s.Name, h)
+	}
+	return status
 }
 
 // StatePath returns the directory where the state for this Step is kept (if
@@ -46,10 +54,10 @@ func (s Step) StatePath(h *Hub) string {
  * getStatus() Implementations
  */
 
-// stateCheckStatus uses a NewStateCheck object to retrieve status; it's the
-// most general getStatus() implementation.
+// stateCheckStatus uses a NewStateCheck object to retrieve the status recorded
+// in the upgrade journal; it's the most general getStatus() implementation.
 func stateCheckStatus(s Step, h *Hub) *pb.UpgradeStepStatus {
-	state := upgradestatus.NewStateCheck(s.StatePath(h), s.StepCode)
+	state := upgradestatus.NewStateCheck(h.conf.StateDir, s.Name, s.StepCode)
 	return state.GetStatus()
 }
 
@@ -60,25 +68,32 @@ func initStatus(s Step, h *Hub) *pb.UpgradeStepStatus {
 	return status
 }
 
-// shutdownStatus checks whether all clusters have been stopped.
-func shutdownStatus(s Step, h *Hub) *pb.UpgradeStepStatus {
-	state := upgradestatus.NewShutDownClusters(s.StatePath(h), h.commandExecer)
-	return state.GetStatus()
-}
-
-// pgUpgradeStatus checks the pg_upgrade progress files for its status.
+// pgUpgradeStatus checks the record of pg_upgrade and its progress files for
+// its status.
 func pgUpgradeStatus(s Step, h *Hub) *pb.UpgradeStepStatus {
 	status := &pb.UpgradeStepStatus{
 		Step: s.StepCode,
 	}
 	// We don't need to check the pg_upgrade status if there's no configuration yet
-	checkConfigStep := Step{upgradestatus.CONFIG, pb.UpgradeSteps_CHECK_CONFIG, stateCheckStatus}
+	checkConfigStep := Step{Name: upgradestatus.CONFIG, StepCode: pb.UpgradeSteps_CHECK_CONFIG, getStatus: stateCheckStatus}
 	if checkConfigStep.getStatus(checkConfigStep, h).Status != pb.StepStatus_COMPLETE {
 		status.Status = pb.StepStatus_PENDING
 		return status
 	}
 	state := upgradestatus.NewPGUpgradeStatusChecker(s.StatePath(h), h.clusterPair.OldCluster.GetDirForContent(-1), h.commandExecer)
-	return state.GetStatus()
+	state.UseRecord(h.processes.Record(upgradestatus.CONVERT_MASTER))
+	status = state.GetStatus()
+	if status.Status == pb.StepStatus_PENDING {
+		return status
+	}
+
+	status.Progress = state.GetProgress(status.Status)
+	status.StartTime, status.EndTime = state.GetTiming(status.Status)
+	setDuration(status)
+	if status.Progress != nil {
+		estimateRemaining(status, int64(status.Progress.PercentComplete), 100)
+	}
+	return status
 }
 
 // conversionStatus queries all segments for their upgrade status and
@@ -88,7 +103,7 @@ func conversionStatus(s Step, h *Hub) *pb.UpgradeStepStatus {
 		Step: s.StepCode,
 	}
 	// We can't check the status of agent processes if the agents haven't been started yet
-	startAgentsStep := Step{upgradestatus.START_AGENTS, pb.UpgradeSteps_PREPARE_START_AGENTS, stateCheckStatus}
+	startAgentsStep := Step{Name: upgradestatus.START_AGENTS, StepCode: pb.UpgradeSteps_PREPARE_START_AGENTS, getStatus: stateCheckStatus}
 	if startAgentsStep.getStatus(startAgentsStep, h).Status != pb.StepStatus_COMPLETE {
 		status.Status = pb.StepStatus_PENDING
 		return status
@@ -100,45 +115,126 @@ func conversionStatus(s Step, h *Hub) *pb.UpgradeStepStatus {
 		status.Status = pb.StepStatus_PENDING
 		return status
 	}
-	statuses := strings.Join(conversionStatus.GetConversionStatuses(), "\n")
-	if strings.Contains(statuses, "FAILED") {
-		status.Status = pb.StepStatus_FAILED
-	} else if strings.Contains(statuses, "RUNNING") {
-		status.Status = pb.StepStatus_RUNNING
-	} else if strings.Contains(statuses, "COMPLETE") {
-		status.Status = pb.StepStatus_COMPLETE
-	} else {
-		status.Status = pb.StepStatus_PENDING
-	}
+	status.Status = aggregateConversionStatus(conversionStatus.GetConversionStatuses())
+	addConversionTiming(status, conversionStatus.GetConversionStatuses())
 	return status
 }
 
+// aggregateConversionStatus consolidates the statuses of the primary segments
+// into the status of the convert-primaries step. The master is converted by a
+// separate step, so it is not taken into account.
+func aggregateConversionStatus(segments []*pb.SegmentConversionStatus) pb.StepStatus {
+	var failed, running bool
+	complete := 0
+	primaries := 0
+	for _, segment := range segments {
+		if segment.GetRole() != pb.SegmentRole_PRIMARY {
+			continue
+		}
+		primaries++
+
+		switch segment.GetStatus() {
+		case pb.StepStatus_FAILED:
+			failed = true
+		case pb.StepStatus_RUNNING:
+			running = true
+		case pb.StepStatus_COMPLETE:
+			complete++
+		}
+	}
+
+	switch {
+	case failed:
+		return pb.StepStatus_FAILED
+	case running:
+		return pb.StepStatus_RUNNING
+	case primaries > 0 && complete == primaries:
+		return pb.StepStatus_COMPLETE
+	default:
+		return pb.StepStatus_PENDING
+	}
+}
+
+// addConversionTiming times the conversion of the primary segments from the
+// first segment to start until the last one to finish. The remaining time is
+// estimated from the pg_upgrade phases finished across all of the segments.
+func addConversionTiming(status *pb.UpgradeStepStatus, segments []*pb.SegmentConversionStatus) {
+	var done, total int64
+	for _, segment := range segments {
+		if segment.GetRole() != pb.SegmentRole_PRIMARY {
+			continue
+		}
+
+		if start := segment.GetStartTime(); start != 0 && (status.StartTime == 0 || start < status.StartTime) {
+			status.StartTime = start
+		}
+		if segment.GetEndTime() > status.EndTime {
+			status.EndTime = segment.GetEndTime()
+		}
+
+		if progress := segment.GetProgress(); progress != nil {
+			done += int64(progress.GetPhasesDone())
+			total += int64(progress.GetTotalPhases())
+		} else {
+			total += int64(upgradestatus.TotalPGUpgradePhases)
+		}
+	}
+
+	if status.Status != pb.StepStatus_COMPLETE && status.Status != pb.StepStatus_FAILED {
+		status.EndTime = 0
+	}
+	setDuration(status)
+	estimateRemaining(status, done, total)
+}
+
 func (h *Hub) StatusUpgrade(ctx context.Context, in *pb.StatusUpgradeRequest) (*pb.StatusUpgradeReply, error) {
 	gplog.Info("starting StatusUpgrade")
 
-	steps := [...]Step{
-		{"check-config", pb.UpgradeSteps_CHECK_CONFIG, stateCheckStatus},
-		{"seginstall", pb.UpgradeSteps_SEGINSTALL, stateCheckStatus},
-		{"init-cluster", pb.UpgradeSteps_PREPARE_INIT_CLUSTER, initStatus},
-		{"gpstop", pb.UpgradeSteps_STOPPED_CLUSTER, shutdownStatus},
-		{"pg_upgrade", pb.UpgradeSteps_MASTERUPGRADE, pgUpgradeStatus},
-		{"start-agents", pb.UpgradeSteps_PREPARE_START_AGENTS, stateCheckStatus},
-		{"share-oids", pb.UpgradeSteps_SHARE_OIDS, stateCheckStatus},
-		{"validate-start-cluster", pb.UpgradeSteps_VALIDATE_START_CLUSTER, stateCheckStatus},
-		{"convert-primaries", pb.UpgradeSteps_CONVERT_PRIMARIES, conversionStatus},
-		{"reconfigure-ports", pb.UpgradeSteps_RECONFIGURE_PORTS, stateCheckStatus},
+	return &pb.StatusUpgradeReply{
+		ListOfUpgradeStepStatuses: h.upgradeStepStatuses(),
+	}, nil
+}
+
+// upgradeSteps returns every Step, in the order the Steps are performed during
+// an upgrade.
+func upgradeSteps() []Step {
+	return []Step{
+		{upgradestatus.CONFIG, pb.UpgradeSteps_CHECK_CONFIG, stateCheckStatus, runCheckConfig},
+		{upgradestatus.SEGINSTALL, pb.UpgradeSteps_SEGINSTALL, stateCheckStatus, runSeginstall},
+		{upgradestatus.INIT_CLUSTER, pb.UpgradeSteps_PREPARE_INIT_CLUSTER, initStatus, runInitCluster},
+		{upgradestatus.SHUTDOWN_CLUSTERS, pb.UpgradeSteps_STOPPED_CLUSTER, stateCheckStatus, runShutdownClusters},
+		{"pg_upgrade", pb.UpgradeSteps_MASTERUPGRADE, pgUpgradeStatus, runConvertMaster},
+		{upgradestatus.START_AGENTS, pb.UpgradeSteps_PREPARE_START_AGENTS, stateCheckStatus, runStartAgents},
+		{upgradestatus.SHARE_OIDS, pb.UpgradeSteps_SHARE_OIDS, stateCheckStatus, runShareOids},
+		{"convert-primaries", pb.UpgradeSteps_CONVERT_PRIMARIES, conversionStatus, runConvertPrimaries},
+		{upgradestatus.VALIDATE_START_CLUSTER, pb.UpgradeSteps_VALIDATE_START_CLUSTER, stateCheckStatus, runValidateStartCluster},
+		{upgradestatus.RECONFIGURE_PORTS, pb.UpgradeSteps_RECONFIGURE_PORTS, stateCheckStatus, runReconfigurePorts},
 	}
+}
 
+// upgradeStepStatuses retrieves the current status of every Step, in the order
+// the Steps are performed during an upgrade.
+func (h *Hub) upgradeStepStatuses() []*pb.UpgradeStepStatus {
+	steps := upgradeSteps()
 	statuses := make([]*pb.UpgradeStepStatus, len(steps))
 
 	for i, desc := range steps {
-		gplog.Info("Checking %s...", desc.Name)
+		gplog.Debug("Checking %s...", desc.Name)
 		statuses[i] = desc.Status(h)
 	}
 
-	return &pb.StatusUpgradeReply{
-		ListOfUpgradeStepStatuses: statuses,
-	}, nil
+	return statuses
+}
+
+// runningStep returns the name of the first Step that is running, or "" if
+// none is.
+func (h *Hub) runningStep() string {
+	for _, s := range upgradeSteps() {
+		if s.Status(h).Status == pb.StepStatus_RUNNING {
+			return s.Name
+		}
+	}
+	return ""
 }
 
 func (h *Hub) GetPrepareNewClusterConfigStatus() *pb.UpgradeStepStatus {
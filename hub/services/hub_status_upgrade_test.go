@@ -2,12 +2,15 @@ package services_test
 
 import (
 	"errors"
+	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/greenplum-db/gpupgrade/hub/services"
+	"github.com/greenplum-db/gpupgrade/hub/upgradestatus"
 	pb "github.com/greenplum-db/gpupgrade/idl"
 	"github.com/greenplum-db/gpupgrade/testutils"
 	"github.com/greenplum-db/gpupgrade/utils"
@@ -66,13 +69,16 @@ var _ = Describe("status upgrade", func() {
 	// This is probably wonky because the convert primaries state check mechanism is
 	// using the MASTERUPGRADE step when upgrading primaries and needs to be fixed
 	XIt("responds with the statuses of the steps based on files on disk", func() {
-		setStateFile(dir, "check-config", "completed")
-		setStateFile(dir, "seginstall", "completed")
-		setStateFile(dir, "start-agents", "completed")
-		setStateFile(dir, "share-oids", "failed")
+		setStepStatus(dir, "check-config", pb.StepStatus_COMPLETE)
+		setStepStatus(dir, "check-seginstall", pb.StepStatus_COMPLETE)
+		setStepStatus(dir, "start-agents", pb.StepStatus_COMPLETE)
+		setStepStatus(dir, "share-oids", pb.StepStatus_FAILED)
 
 		mockAgent.StatusConversionResponse = &pb.CheckConversionStatusReply{
-			Statuses: []string{"RUNNING", "PENDING"},
+			Statuses: []*pb.SegmentConversionStatus{
+				{Role: pb.SegmentRole_PRIMARY, Status: pb.StepStatus_RUNNING},
+				{Role: pb.SegmentRole_PRIMARY, Status: pb.StepStatus_PENDING},
+			},
 		}
 
 		resp, err := hub.StatusUpgrade(nil, &pb.StatusUpgradeRequest{})
@@ -148,40 +154,28 @@ var _ = Describe("status upgrade", func() {
 		})
 
 		It("reports that prepare start-agents is running and then complete", func() {
-			var numInvocations int
-			utils.System.FilePathGlob = func(input string) ([]string, error) {
-				numInvocations += 1
-				if numInvocations == 1 {
-					return []string{filepath.Join(filepath.Dir(input), "in.progress")}, nil
-				}
-				return []string{filepath.Join(filepath.Dir(input), "completed")}, nil
-			}
-			utils.System.Stat = func(name string) (os.FileInfo, error) {
-				return nil, nil
-			}
-			pollStatusUpgrade := func() pb.StepStatus {
-				response, _ := hub.StatusUpgrade(nil, &pb.StatusUpgradeRequest{})
-
-				stepStatuses := response.GetListOfUpgradeStepStatuses()
-
-				var stepStatusSaved *pb.UpgradeStepStatus
-				for _, stepStatus := range stepStatuses {
+			getStartAgentsStatus := func() pb.StepStatus {
+				response, err := hub.StatusUpgrade(nil, &pb.StatusUpgradeRequest{})
+				Expect(err).ToNot(HaveOccurred())
 
+				for _, stepStatus := range response.GetListOfUpgradeStepStatuses() {
 					if stepStatus.GetStep() == pb.UpgradeSteps_PREPARE_START_AGENTS {
-						stepStatusSaved = stepStatus
+						return stepStatus.GetStatus()
 					}
 				}
-				return stepStatusSaved.GetStatus()
-
+				return pb.StepStatus_PENDING
 			}
 
-			//Expect(stepStatusSaved.GetStep()).ToNot(BeZero())
-			Eventually(pollStatusUpgrade).Should(Equal(pb.StepStatus_COMPLETE))
+			setStepStatus(dir, "start-agents", pb.StepStatus_RUNNING)
+			Expect(getStartAgentsStatus()).To(Equal(pb.StepStatus_RUNNING))
+
+			setStepStatus(dir, "start-agents", pb.StepStatus_COMPLETE)
+			Expect(getStartAgentsStatus()).To(Equal(pb.StepStatus_COMPLETE))
 		})
 
 		Context("master upgrade status checking requires check config to have been run", func() {
 			BeforeEach(func() {
-				setStateFile(dir, "check-config", "completed")
+				setStepStatus(dir, "check-config", pb.StepStatus_COMPLETE)
 			})
 			It("reports that master upgrade is pending when pg_upgrade dir does not exist", func() {
 				utils.System.IsNotExist = func(error) bool {
@@ -208,9 +202,7 @@ var _ = Describe("status upgrade", func() {
 					return false
 				}
 				utils.System.FilePathGlob = func(name string) ([]string, error) {
-					if strings.Contains(name, "check-config") {
-						return []string{filepath.Join(dir, "check-config", "completed")}, nil
-					} else if strings.Contains(name, "gpstop") {
+					if strings.Contains(name, "gpstop") {
 						// Not relevant to this test directly, but makes the output correct when printing the status
 						return []string{}, nil
 					} else {
@@ -237,9 +229,7 @@ var _ = Describe("status upgrade", func() {
 					return false
 				}
 				utils.System.FilePathGlob = func(name string) ([]string, error) {
-					if strings.Contains(name, "check-config") {
-						return []string{filepath.Join(dir, "check-config", "completed")}, nil
-					} else if strings.Contains(name, "done") {
+					if strings.Contains(name, "done") {
 						return []string{filepath.Join(dir, "pg_upgrade", "done")}, nil
 					} else {
 						return nil, nil
@@ -285,9 +275,7 @@ var _ = Describe("status upgrade", func() {
 					return false
 				}
 				utils.System.FilePathGlob = func(glob string) ([]string, error) {
-					if strings.Contains(glob, "check-config") {
-						return []string{filepath.Join(dir, "check-config", "completed")}, nil
-					} else if strings.Contains(glob, "inprogress") {
+					if strings.Contains(glob, "inprogress") {
 						return nil, errors.New("fake error")
 					} else if strings.Contains(glob, "done") {
 						return []string{"found something"}, nil
@@ -321,6 +309,128 @@ var _ = Describe("status upgrade", func() {
 		})
 	})
 
+	Describe("Status of ConvertPrimaries", func() {
+		convertPrimariesStatus := func() pb.StepStatus {
+			response, err := hub.StatusUpgrade(nil, &pb.StatusUpgradeRequest{})
+			Expect(err).ToNot(HaveOccurred())
+
+			return findStep(response, pb.UpgradeSteps_CONVERT_PRIMARIES).GetStatus()
+		}
+
+		BeforeEach(func() {
+			setStepStatus(dir, "start-agents", pb.StepStatus_COMPLETE)
+
+			master := clusterPair.OldCluster.Segments[-1]
+			master.Hostname = "localhost"
+			clusterPair.OldCluster.Segments[-1] = master
+		})
+
+		It("ignores the master when deciding whether the primaries are converted", func() {
+			mockAgent.StatusConversionResponse = &pb.CheckConversionStatusReply{
+				Statuses: []*pb.SegmentConversionStatus{
+					{Role: pb.SegmentRole_MASTER, Status: pb.StepStatus_COMPLETE},
+					{Role: pb.SegmentRole_PRIMARY, Status: pb.StepStatus_PENDING},
+				},
+			}
+
+			Expect(convertPrimariesStatus()).To(Equal(pb.StepStatus_PENDING))
+		})
+
+		It("is complete only once every primary is complete", func() {
+			mockAgent.StatusConversionResponse = &pb.CheckConversionStatusReply{
+				Statuses: []*pb.SegmentConversionStatus{
+					{Role: pb.SegmentRole_PRIMARY, Status: pb.StepStatus_COMPLETE},
+					{Role: pb.SegmentRole_PRIMARY, Status: pb.StepStatus_COMPLETE},
+				},
+			}
+
+			Expect(convertPrimariesStatus()).To(Equal(pb.StepStatus_COMPLETE))
+		})
+
+		It("times the conversion across the primaries and estimates the time remaining", func() {
+			utils.System.Now = func() time.Time { return time.Unix(1200, 0) }
+			mockAgent.StatusConversionResponse = &pb.CheckConversionStatusReply{
+				Statuses: []*pb.SegmentConversionStatus{
+					{Role: pb.SegmentRole_PRIMARY, Status: pb.StepStatus_COMPLETE, StartTime: 1000, EndTime: 1100,
+						Progress: &pb.PgUpgradeProgress{PhasesDone: 10, TotalPhases: 10}},
+					{Role: pb.SegmentRole_PRIMARY, Status: pb.StepStatus_RUNNING, StartTime: 1050,
+						Progress: &pb.PgUpgradeProgress{PhasesDone: 5, TotalPhases: 10}},
+					{Role: pb.SegmentRole_PRIMARY, Status: pb.StepStatus_PENDING},
+				},
+			}
+
+			response, err := hub.StatusUpgrade(nil, &pb.StatusUpgradeRequest{})
+			Expect(err).ToNot(HaveOccurred())
+
+			status := findStep(response, pb.UpgradeSteps_CONVERT_PRIMARIES)
+			Expect(status.GetStatus()).To(Equal(pb.StepStatus_RUNNING))
+			Expect(status.GetStartTime()).To(Equal(int64(1000)))
+			Expect(status.GetEndTime()).To(BeZero())
+			Expect(status.GetDurationSeconds()).To(Equal(int64(200)))
+			// 15 of 10+10+TotalPGUpgradePhases phases took 200 seconds.
+			total := int64(20 + upgradestatus.TotalPGUpgradePhases)
+			Expect(status.GetEstimatedSecondsRemaining()).To(Equal(200 * (total - 15) / 15))
+		})
+
+		It("is failed if any primary failed, even while others are running", func() {
+			mockAgent.StatusConversionResponse = &pb.CheckConversionStatusReply{
+				Statuses: []*pb.SegmentConversionStatus{
+					{Role: pb.SegmentRole_PRIMARY, Status: pb.StepStatus_RUNNING},
+					{Role: pb.SegmentRole_PRIMARY, Status: pb.StepStatus_FAILED},
+				},
+			}
+
+			Expect(convertPrimariesStatus()).To(Equal(pb.StepStatus_FAILED))
+		})
+	})
+
+	Describe("step timing", func() {
+		recordAt := func(seconds int64, step string, status pb.StepStatus) {
+			utils.System.Now = func() time.Time { return time.Unix(seconds, 0) }
+			setStepStatus(dir, step, status)
+		}
+
+		It("reports when a journaled step started and finished", func() {
+			recordAt(1000, upgradestatus.SHARE_OIDS, pb.StepStatus_RUNNING)
+			recordAt(1090, upgradestatus.SHARE_OIDS, pb.StepStatus_COMPLETE)
+
+			response, err := hub.StatusUpgrade(nil, &pb.StatusUpgradeRequest{})
+			Expect(err).ToNot(HaveOccurred())
+
+			status := findStep(response, pb.UpgradeSteps_SHARE_OIDS)
+			Expect(status.GetStartTime()).To(Equal(int64(1000)))
+			Expect(status.GetEndTime()).To(Equal(int64(1090)))
+			Expect(status.GetDurationSeconds()).To(Equal(int64(90)))
+			Expect(status.GetEstimatedSecondsRemaining()).To(BeZero())
+		})
+
+		It("estimates the time remaining for a running step from its previous run", func() {
+			recordAt(1000, upgradestatus.SHARE_OIDS, pb.StepStatus_RUNNING)
+			recordAt(1300, upgradestatus.SHARE_OIDS, pb.StepStatus_COMPLETE)
+			recordAt(2000, upgradestatus.SHARE_OIDS, pb.StepStatus_RUNNING)
+			utils.System.Now = func() time.Time { return time.Unix(2100, 0) }
+
+			response, err := hub.StatusUpgrade(nil, &pb.StatusUpgradeRequest{})
+			Expect(err).ToNot(HaveOccurred())
+
+			status := findStep(response, pb.UpgradeSteps_SHARE_OIDS)
+			Expect(status.GetStatus()).To(Equal(pb.StepStatus_RUNNING))
+			Expect(status.GetEndTime()).To(BeZero())
+			Expect(status.GetDurationSeconds()).To(Equal(int64(100)))
+			Expect(status.GetPreviousDurationSeconds()).To(Equal([]int64{300}))
+			Expect(status.GetEstimatedSecondsRemaining()).To(Equal(int64(200)))
+		})
+
+		It("does not time steps that have not started", func() {
+			response, err := hub.StatusUpgrade(nil, &pb.StatusUpgradeRequest{})
+			Expect(err).ToNot(HaveOccurred())
+
+			status := findStep(response, pb.UpgradeSteps_SHARE_OIDS)
+			Expect(status.GetStartTime()).To(BeZero())
+			Expect(status.GetDurationSeconds()).To(BeZero())
+		})
+	})
+
 	Describe("Status of PrepareNewClusterConfig", func() {
 		It("marks this step pending if there's no new cluster config file", func() {
 			utils.System.Stat = func(filename string) (os.FileInfo, error) {
@@ -361,11 +471,21 @@ var _ = Describe("status upgrade", func() {
 	})
 })
 
-func setStateFile(dir string, step string, state string) {
-	err := os.MkdirAll(filepath.Join(dir, step), os.ModePerm)
+// setStepStatus records a transition of step to status in the upgrade journal
+// kept in dir.
+func setStepStatus(dir string, step string, status pb.StepStatus) {
+	err := upgradestatus.NewStateStore(dir).Record(step, status, nil)
 	Expect(err).ToNot(HaveOccurred())
+}
 
-	f, err := os.Create(filepath.Join(dir, step, state))
-	Expect(err).ToNot(HaveOccurred())
-	f.Close()
+// findStep returns the status of step from a StatusUpgrade reply.
+func findStep(reply *pb.StatusUpgradeReply, step pb.UpgradeSteps) *pb.UpgradeStepStatus {
+	for _, status := range reply.GetListOfUpgradeStepStatuses() {
+		if status.GetStep() == step {
+			return status
+		}
+	}
+
+	Fail(fmt.Sprintf("no status was reported for %s", step))
+	return nil
 }
@@ -0,0 +1,81 @@
+package services
+
+import (
+	"strings"
+
+	"github.com/greenplum-db/gpupgrade/hub/upgradestatus"
+	pb "github.com/greenplum-db/gpupgrade/idl"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// stepPrerequisites declares, for each upgrade step, the steps that must be
+// COMPLETE before it may run. Only direct prerequisites are listed; since each
+// of those was itself checked before it ran, the earlier steps are implied.
+var stepPrerequisites = map[pb.UpgradeSteps][]pb.UpgradeSteps{
+	pb.UpgradeSteps_SEGINSTALL:             {pb.UpgradeSteps_CHECK_CONFIG},
+	pb.UpgradeSteps_PREPARE_INIT_CLUSTER:   {pb.UpgradeSteps_CHECK_CONFIG},
+	pb.UpgradeSteps_STOPPED_CLUSTER:        {pb.UpgradeSteps_PREPARE_INIT_CLUSTER},
+	pb.UpgradeSteps_MASTERUPGRADE:          {pb.UpgradeSteps_STOPPED_CLUSTER},
+	pb.UpgradeSteps_PREPARE_START_AGENTS:   {pb.UpgradeSteps_SEGINSTALL},
+	pb.UpgradeSteps_SHARE_OIDS:             {pb.UpgradeSteps_MASTERUPGRADE},
+	pb.UpgradeSteps_CONVERT_PRIMARIES:      {pb.UpgradeSteps_SHARE_OIDS, pb.UpgradeSteps_PREPARE_START_AGENTS},
+	pb.UpgradeSteps_VALIDATE_START_CLUSTER: {pb.UpgradeSteps_CONVERT_PRIMARIES},
+	pb.UpgradeSteps_RECONFIGURE_PORTS:      {pb.UpgradeSteps_VALIDATE_START_CLUSTER},
+}
+
+// checkedSteps are the steps that may not run until CheckAll has found
+// nothing wrong that would stop the upgrade. Shutting down the clusters is the
+// first step that can't simply be retried, and the checks need the old
+// cluster up; the steps after it are held back as well, by their
+// prerequisites.
+var checkedSteps = map[pb.UpgradeSteps]bool{
+	pb.UpgradeSteps_STOPPED_CLUSTER: true,
+}
+
+// checkPrerequisites returns a gRPC FailedPrecondition error naming every
+// prerequisite of step that has not completed yet, or saying that a revert is
+// in progress or that the hub is shutting down, or nil if step may run.
+func (h *Hub) checkPrerequisites(step pb.UpgradeSteps) error {
+	steps := make(map[pb.UpgradeSteps]Step)
+	for _, s := range upgradeSteps() {
+		steps[s.StepCode] = s
+	}
+
+	h.mu.Lock()
+	reverting, shuttingDown := h.reverting, h.shuttingDown
+	h.mu.Unlock()
+	if reverting {
+		return status.Errorf(codes.FailedPrecondition, "%s cannot run while a revert is in progress", steps[step].Name)
+	}
+	if shuttingDown {
+		return status.Errorf(codes.FailedPrecondition, "%s cannot run while the hub is shutting down", steps[step].Name)
+	}
+
+	var missing []string
+	for _, prerequisite := range stepPrerequisites[step] {
+		s := steps[prerequisite]
+		if s.Status(h).Status != pb.StepStatus_COMPLETE {
+			missing = append(missing, s.Name)
+		}
+	}
+
+	if checkedSteps[step] && !h.checksPassed() {
+		missing = append(missing, upgradestatus.CHECK_ALL)
+	}
+
+	if len(missing) == 0 {
+		return nil
+	}
+
+	return status.Errorf(codes.FailedPrecondition, "%s cannot run until the following steps have completed: %s",
+		steps[step].Name, strings.Join(missing, ", "))
+}
+
+// checksPassed returns whether CheckAll has last found nothing that would stop
+// the upgrade.
+func (h *Hub) checksPassed() bool {
+	checks := upgradestatus.NewStateCheck(h.conf.StateDir, upgradestatus.CHECK_ALL, pb.UpgradeSteps_UNKNOWN_STEP)
+	return checks.GetStatus().Status == pb.StepStatus_COMPLETE
+}
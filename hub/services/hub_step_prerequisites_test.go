@@ -0,0 +1,118 @@
+package services_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/greenplum-db/gpupgrade/hub/services"
+	"github.com/greenplum-db/gpupgrade/hub/upgradestatus"
+	pb "github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/testutils"
+	"github.com/greenplum-db/gpupgrade/utils"
+
+	"github.com/greenplum-db/gp-common-go-libs/testhelper"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("step prerequisites", func() {
+	var (
+		hub           *services.Hub
+		dir           string
+		commandExecer *testutils.FakeCommandExecer
+		mockAgent     *testutils.MockAgentServer
+	)
+
+	BeforeEach(func() {
+		testhelper.SetupTestLogger()
+
+		var port int
+		mockAgent, port = testutils.NewMockAgentServer()
+
+		var err error
+		dir, err = ioutil.TempDir("", "")
+		Expect(err).ToNot(HaveOccurred())
+
+		commandExecer = &testutils.FakeCommandExecer{}
+		commandExecer.SetOutput(&testutils.FakeCommand{})
+
+		conf := &services.HubConfig{
+			StateDir:       dir,
+			HubToAgentPort: port,
+		}
+		hub = services.NewHub(testutils.CreateSampleClusterPair(), grpc.DialContext, commandExecer.Exec, conf,
+			testutils.NewStubRemoteExecutor(), testutils.NewMockChecklistManager())
+	})
+
+	AfterEach(func() {
+		mockAgent.Stop()
+		utils.System = utils.InitializeSystemFunctions()
+		os.RemoveAll(dir)
+	})
+
+	It("returns FailedPrecondition naming every prerequisite that has not completed", func() {
+		_, err := hub.UpgradeConvertPrimaries(nil, &pb.UpgradeConvertPrimariesRequest{})
+		Expect(status.Code(err)).To(Equal(codes.FailedPrecondition))
+		Expect(status.Convert(err).Message()).To(Equal(
+			"convert-primaries cannot run until the following steps have completed: share-oids, start-agents"))
+
+		Expect(mockAgent.NumberOfCalls()).To(Equal(0))
+	})
+
+	It("names only the prerequisites that have not completed", func() {
+		setStepStatus(dir, upgradestatus.SHARE_OIDS, pb.StepStatus_COMPLETE)
+		setStepStatus(dir, upgradestatus.START_AGENTS, pb.StepStatus_FAILED)
+
+		_, err := hub.UpgradeConvertPrimaries(nil, &pb.UpgradeConvertPrimariesRequest{})
+		Expect(status.Code(err)).To(Equal(codes.FailedPrecondition))
+		Expect(status.Convert(err).Message()).To(Equal(
+			"convert-primaries cannot run until the following steps have completed: start-agents"))
+	})
+
+	It("does not reconfigure ports before the new cluster has been started", func() {
+		setStepStatus(dir, upgradestatus.VALIDATE_START_CLUSTER, pb.StepStatus_RUNNING)
+
+		_, err := hub.UpgradeReconfigurePorts(nil, &pb.UpgradeReconfigurePortsRequest{})
+		Expect(status.Code(err)).To(Equal(codes.FailedPrecondition))
+		Expect(status.Convert(err).Message()).To(ContainSubstring("validate-start-cluster"))
+
+		Expect(commandExecer.GetNumInvocations()).To(Equal(0))
+	})
+
+	It("does not share OID files before pg_upgrade has completed on the master", func() {
+		setStepStatus(dir, upgradestatus.CONFIG, pb.StepStatus_COMPLETE)
+
+		_, err := hub.UpgradeShareOids(nil, &pb.UpgradeShareOidsRequest{})
+		Expect(status.Code(err)).To(Equal(codes.FailedPrecondition))
+		Expect(status.Convert(err).Message()).To(Equal(
+			"share-oids cannot run until the following steps have completed: pg_upgrade"))
+	})
+
+	It("does not shut down the clusters until the pre-upgrade checks have passed", func() {
+		Expect(ioutil.WriteFile(services.GetNewConfigFilePath(dir), []byte("{}"), 0600)).To(Succeed())
+		setStepStatus(dir, upgradestatus.CHECK_ALL, pb.StepStatus_FAILED)
+
+		_, err := hub.PrepareShutdownClusters(nil, &pb.PrepareShutdownClustersRequest{})
+		Expect(status.Code(err)).To(Equal(codes.FailedPrecondition))
+		Expect(status.Convert(err).Message()).To(Equal(
+			"shutdown-clusters cannot run until the following steps have completed: check-all"))
+
+		Expect(commandExecer.GetNumInvocations()).To(Equal(0))
+	})
+})
+
+// setMasterUpgradeComplete leaves the state dir looking as if pg_upgrade has
+// finished successfully on the master.
+func setMasterUpgradeComplete(dir string) {
+	setStepStatus(dir, upgradestatus.CONFIG, pb.StepStatus_COMPLETE)
+
+	pgUpgradeDir := filepath.Join(dir, "pg_upgrade")
+	Expect(os.MkdirAll(pgUpgradeDir, 0700)).To(Succeed())
+	err := ioutil.WriteFile(filepath.Join(pgUpgradeDir, "1.done"), []byte("Upgrade complete\n"), 0600)
+	Expect(err).ToNot(HaveOccurred())
+}
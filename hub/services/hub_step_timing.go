@@ -0,0 +1,73 @@
+package services
+
+import (
+	"github.com/greenplum-db/gpupgrade/hub/upgradestatus"
+	pb "github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/utils"
+
+	"github.com/greenplum-db/gp-common-go-libs/gplog"
+)
+
+// addJournalTiming fills in when the step recorded under name in the upgrade
+// journal started and finished, and how long its earlier runs took. While the
+// step is running, the duration of its last completed run is used to estimate
+// how much longer it will take.
+func addJournalTiming(status *pb.UpgradeStepStatus, name string, h *Hub) {
+	if status.Status == pb.StepStatus_PENDING {
+		return
+	}
+
+	store := upgradestatus.NewStateStore(h.conf.StateDir)
+	timing, err := store.Timing(name)
+	if err != nil {
+		gplog.Debug("could not read timing of %s from %s: %s", name, store.Path(), err)
+		return
+	}
+	if timing == nil {
+		return
+	}
+
+	status.StartTime = timing.Start.Unix()
+	if !timing.End.IsZero() {
+		status.EndTime = timing.End.Unix()
+	}
+	setDuration(status)
+
+	for _, duration := range timing.PreviousDurations {
+		status.PreviousDurationSeconds = append(status.PreviousDurationSeconds, int64(duration.Seconds()))
+	}
+
+	if status.Status == pb.StepStatus_RUNNING && len(status.PreviousDurationSeconds) > 0 {
+		last := status.PreviousDurationSeconds[len(status.PreviousDurationSeconds)-1]
+		if remaining := last - status.DurationSeconds; remaining > 0 {
+			status.EstimatedSecondsRemaining = remaining
+		}
+	}
+}
+
+// setDuration works out DurationSeconds from StartTime and EndTime. A step
+// that hasn't finished has been running until now.
+func setDuration(status *pb.UpgradeStepStatus) {
+	if status.StartTime == 0 {
+		return
+	}
+
+	end := status.EndTime
+	if end == 0 {
+		end = utils.System.Now().Unix()
+	}
+	if end > status.StartTime {
+		status.DurationSeconds = end - status.StartTime
+	}
+}
+
+// estimateRemaining extrapolates how much longer a running step will take
+// from the fraction of its work, done out of total, finished so far. The
+// estimate is left at 0 until some work has been finished.
+func estimateRemaining(status *pb.UpgradeStepStatus, done, total int64) {
+	if status.Status != pb.StepStatus_RUNNING || done <= 0 || total <= done {
+		return
+	}
+
+	status.EstimatedSecondsRemaining = status.DurationSeconds * (total - done) / done
+}
@@ -1,8 +1,16 @@
 package services_test
 
 import (
+	"context"
+	"io/ioutil"
+	"net"
+	"os"
+	"time"
+
 	"github.com/greenplum-db/gp-common-go-libs/cluster"
+	"github.com/greenplum-db/gpupgrade/certs"
 	"github.com/greenplum-db/gpupgrade/hub/services"
+	pb "github.com/greenplum-db/gpupgrade/idl"
 	"github.com/greenplum-db/gpupgrade/testutils"
 
 	"google.golang.org/grpc"
@@ -36,8 +44,13 @@ var _ = Describe("Hub", func() {
 
 	It("closes open connections when shutting down", func(done Done) {
 		defer close(done)
+		dir, err := ioutil.TempDir("", "")
+		Expect(err).ToNot(HaveOccurred())
+		defer os.RemoveAll(dir)
+
 		hubConfig := &services.HubConfig{
 			HubToAgentPort: port,
+			SocketPath:     services.SocketPath(dir),
 		}
 		hub := services.NewHub(clusterPair, grpc.DialContext, nil, hubConfig,
 			stubRemoteExecutor, nil)
@@ -56,6 +69,100 @@ var _ = Describe("Hub", func() {
 		Eventually(func() connectivity.State { return conns[0].Conn.GetState() }).Should(Equal(connectivity.Shutdown))
 	})
 
+	Describe("serving the CLI", func() {
+		var (
+			dir        string
+			socketPath string
+			hub        *services.Hub
+		)
+
+		BeforeEach(func() {
+			var err error
+			dir, err = ioutil.TempDir("", "")
+			Expect(err).ToNot(HaveOccurred())
+			socketPath = services.SocketPath(dir)
+
+			hub = services.NewHub(clusterPair, grpc.DialContext, nil, &services.HubConfig{SocketPath: socketPath},
+				stubRemoteExecutor, nil)
+		})
+
+		AfterEach(func() {
+			os.RemoveAll(dir)
+		})
+
+		ping := func() error {
+			conn, err := grpc.Dial(socketPath, grpc.WithInsecure(), grpc.WithBlock(), grpc.WithTimeout(2*time.Second),
+				grpc.WithDialer(func(path string, timeout time.Duration) (net.Conn, error) {
+					return net.DialTimeout("unix", path, timeout)
+				}))
+			if err != nil {
+				return err
+			}
+			defer conn.Close()
+
+			_, err = pb.NewCliToHubClient(conn).Ping(context.Background(), &pb.PingRequest{})
+			return err
+		}
+
+		It("listens on a socket that only its owner can use", func() {
+			go hub.Start()
+			defer hub.Stop()
+
+			Eventually(ping).Should(Succeed())
+
+			fi, err := os.Stat(socketPath)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(fi.Mode() & os.ModeSocket).ToNot(BeZero())
+			Expect(fi.Mode().Perm()).To(Equal(os.FileMode(0600)))
+		})
+
+		It("replaces a socket left behind by a hub that has died", func() {
+			Expect(ioutil.WriteFile(socketPath, nil, 0600)).To(Succeed())
+
+			go hub.Start()
+			defer hub.Stop()
+
+			Eventually(ping).Should(Succeed())
+		})
+
+		It("removes the socket when it stops", func() {
+			go hub.Start()
+			Eventually(ping).Should(Succeed())
+
+			hub.Stop()
+			Expect(socketPath).ToNot(BeAnExistingFile())
+		})
+	})
+
+	It("connects to the agents with its certificate", func() {
+		dir, err := ioutil.TempDir("", "")
+		Expect(err).ToNot(HaveOccurred())
+		defer os.RemoveAll(dir)
+
+		Expect(certs.CreateAuthority(dir)).To(Succeed())
+		agentCreds, err := testutils.AgentCredentials(dir, "localhost")
+		Expect(err).ToNot(HaveOccurred())
+		hubCreds, err := certs.ClientCredentials(dir, certs.Hub)
+		Expect(err).ToNot(HaveOccurred())
+
+		secureAgent, securePort := testutils.NewMockAgentServer(grpc.Creds(agentCreds))
+		defer secureAgent.Stop()
+
+		hubConfig := &services.HubConfig{
+			HubToAgentPort:   securePort,
+			AgentCredentials: hubCreds,
+		}
+		hub := services.NewHub(clusterPair, grpc.DialContext, nil, hubConfig,
+			stubRemoteExecutor, nil)
+
+		conns, err := hub.AgentConns()
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = pb.NewAgentClient(conns[0].Conn).PingAgents(context.Background(), &pb.PingAgentsRequest{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(secureAgent.NumberOfCalls()).To(Equal(1))
+	})
+
 	It("retrieves the agent connections from the config file reader", func() {
 		clusterPair.OldCluster.Segments[1] = cluster.SegConfig{Hostname: "localhost"}
 		hubConfig := &services.HubConfig{
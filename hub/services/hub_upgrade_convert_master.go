@@ -6,6 +6,7 @@ import (
 	"os/exec"
 	"path/filepath"
 
+	"github.com/greenplum-db/gpupgrade/hub/upgradestatus"
 	pb "github.com/greenplum-db/gpupgrade/idl"
 	"github.com/greenplum-db/gpupgrade/utils"
 
@@ -16,9 +17,15 @@ import (
 
 func (h *Hub) UpgradeConvertMaster(ctx context.Context, in *pb.UpgradeConvertMasterRequest) (*pb.UpgradeConvertMasterReply, error) {
 	gplog.Info("Starting master upgrade")
+
+	err := h.checkPrerequisites(pb.UpgradeSteps_MASTERUPGRADE)
+	if err != nil {
+		gplog.Error(err.Error())
+		return &pb.UpgradeConvertMasterReply{}, err
+	}
 	//need to remember where we ran, i.e. pathToUpgradeWD, b/c pg_upgrade generates some files that need to be copied to QE nodes later
 	//this is also where the 1.done, 2.inprogress ... files will be written
-	err := h.convertMaster(in)
+	err = h.convertMaster(in)
 	if err != nil {
 		gplog.Error("%v", err)
 		return &pb.UpgradeConvertMasterReply{}, err
@@ -44,6 +51,8 @@ func (h *Hub) convertMaster(in *pb.UpgradeConvertMasterRequest) error {
 		gplog.Error(errMsg)
 		return errors.New(errMsg)
 	}
+	// Once pg_upgrade has started, its process has its own copy of the log.
+	defer f.Close()
 
 	oldMasterPort, newMasterPort := h.clusterPair.GetMasterPorts()
 
@@ -51,6 +60,9 @@ func (h *Hub) convertMaster(in *pb.UpgradeConvertMasterRequest) error {
 		"--old-bindir=%s --old-datadir=%s --new-bindir=%s --new-datadir=%s --old-port=%d --new-port=%d --dispatcher-mode --progress",
 		pathToUpgradeWD, filepath.Join(in.NewBinDir, "pg_upgrade"),
 		in.OldBinDir, in.OldDataDir, in.NewBinDir, in.NewDataDir, oldMasterPort, newMasterPort)
+	if h.linkMode() {
+		upgradeCmdArgs += " --link"
+	}
 
 	//export ENV VARS instead of passing on cmd line?
 	upgradeCommand := h.commandExecer("bash", "-c", upgradeCmdArgs)
@@ -60,8 +72,16 @@ func (h *Hub) convertMaster(in *pb.UpgradeConvertMasterRequest) error {
 		cmd.Stderr = f
 	}
 
-	//TODO check the rc on this? keep a pid?
-	err = upgradeCommand.Start()
+	//TODO check the rc on this?
+	err = h.processes.Start(upgradestatus.CONVERT_MASTER, pgUpgradeLog, upgradeCommand, func() error {
+		// pg_upgrade leaves the servers it started running if it is stopped.
+		oldErr := utils.StopPostgres(h.commandExecer, in.OldBinDir, in.OldDataDir)
+		newErr := utils.StopPostgres(h.commandExecer, in.NewBinDir, in.NewDataDir)
+		if oldErr != nil {
+			return oldErr
+		}
+		return newErr
+	})
 	if err != nil {
 		errMsg := fmt.Sprint("pg_upgrade failed to run: ", err)
 		gplog.Error(errMsg)
@@ -6,8 +6,10 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/greenplum-db/gpupgrade/config"
 	"github.com/greenplum-db/gpupgrade/hub/cluster_ssher"
 	"github.com/greenplum-db/gpupgrade/hub/services"
+	"github.com/greenplum-db/gpupgrade/hub/upgradestatus"
 	pb "github.com/greenplum-db/gpupgrade/idl"
 	"github.com/greenplum-db/gpupgrade/testutils"
 	"github.com/greenplum-db/gpupgrade/utils"
@@ -27,13 +29,16 @@ var _ = Describe("ConvertMasterHub", func() {
 		errChan       chan error
 		clusterPair   *services.ClusterPair
 		cm            *testutils.MockChecklistManager
+		conf          *services.HubConfig
 	)
 
 	BeforeEach(func() {
 		var err error
 		dir, err = ioutil.TempDir("", "")
 		Expect(err).ToNot(HaveOccurred())
-		conf := &services.HubConfig{
+		setStepStatus(dir, upgradestatus.SHUTDOWN_CLUSTERS, pb.StepStatus_COMPLETE)
+
+		conf = &services.HubConfig{
 			StateDir: dir,
 		}
 
@@ -75,6 +80,39 @@ var _ = Describe("ConvertMasterHub", func() {
 		}))
 	})
 
+	It("has pg_upgrade hard link the data files in link mode", func() {
+		conf.UpgradeMode = config.ModeLink
+
+		_, err := hub.UpgradeConvertMaster(nil, &pb.UpgradeConvertMasterRequest{
+			OldBinDir:  "/old/path/bin",
+			OldDataDir: "old/data/dir",
+			NewBinDir:  "/new/path/bin",
+			NewDataDir: "new/data/dir",
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(commandExecer.Args()[1]).To(HaveSuffix("--dispatcher-mode --progress --link"))
+	})
+
+	It("closes the pg_upgrade log once pg_upgrade has started", func() {
+		var log *os.File
+		utils.System.OpenFile = func(name string, flag int, perm os.FileMode) (*os.File, error) {
+			var err error
+			log, err = os.OpenFile(name, flag, perm)
+			return log, err
+		}
+
+		_, err := hub.UpgradeConvertMaster(nil, &pb.UpgradeConvertMasterRequest{
+			OldBinDir:  "/old/path/bin",
+			OldDataDir: "old/data/dir",
+			NewBinDir:  "/new/path/bin",
+			NewDataDir: "new/data/dir",
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(log).ToNot(BeNil())
+		Expect(log.Close()).To(HaveOccurred(), "the log should already have been closed")
+	})
+
 	It("returns an error when convert master fails", func() {
 		errChan <- errors.New("upgrade failed")
 
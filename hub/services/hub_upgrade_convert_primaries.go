@@ -12,6 +12,12 @@ import (
 )
 
 func (h *Hub) UpgradeConvertPrimaries(ctx context.Context, in *pb.UpgradeConvertPrimariesRequest) (*pb.UpgradeConvertPrimariesReply, error) {
+
+	err := h.checkPrerequisites(pb.UpgradeSteps_CONVERT_PRIMARIES)
+	if err != nil {
+		gplog.Error(err.Error())
+		return &pb.UpgradeConvertPrimariesReply{}, err
+	}
 	conns, err := h.AgentConns()
 	if err != nil {
 		gplog.Error("Error connecting to the agents. Err: %v", err)
@@ -35,6 +41,7 @@ func (h *Hub) UpgradeConvertPrimaries(ctx context.Context, in *pb.UpgradeConvert
 				OldBinDir:    in.OldBinDir,
 				NewBinDir:    in.NewBinDir,
 				DataDirPairs: dataDirPair[c.Hostname],
+				LinkMode:     h.linkMode(),
 			})
 
 			if err != nil {
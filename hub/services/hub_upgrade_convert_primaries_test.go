@@ -6,6 +6,7 @@ import (
 
 	"github.com/greenplum-db/gpupgrade/hub/cluster_ssher"
 	"github.com/greenplum-db/gpupgrade/hub/services"
+	"github.com/greenplum-db/gpupgrade/hub/upgradestatus"
 	pb "github.com/greenplum-db/gpupgrade/idl"
 	"github.com/greenplum-db/gpupgrade/testutils"
 
@@ -40,6 +41,9 @@ var _ = Describe("hub.UpgradeConvertPrimaries()", func() {
 		var err error
 		dir, err = ioutil.TempDir("", "")
 		Expect(err).ToNot(HaveOccurred())
+		setStepStatus(dir, upgradestatus.SHARE_OIDS, pb.StepStatus_COMPLETE)
+		setStepStatus(dir, upgradestatus.START_AGENTS, pb.StepStatus_COMPLETE)
+
 		conf := &services.HubConfig{
 			StateDir:       dir,
 			HubToAgentPort: port,
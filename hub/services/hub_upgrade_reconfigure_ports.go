@@ -19,7 +19,13 @@ const (
 func (h *Hub) UpgradeReconfigurePorts(ctx context.Context, in *pb.UpgradeReconfigurePortsRequest) (*pb.UpgradeReconfigurePortsReply, error) {
 	gplog.Info("Started processing reconfigure-ports request")
 
-	err := h.checklistWriter.ResetStateDir(upgradestatus.RECONFIGURE_PORTS)
+	err := h.checkPrerequisites(pb.UpgradeSteps_RECONFIGURE_PORTS)
+	if err != nil {
+		gplog.Error(err.Error())
+		return &pb.UpgradeReconfigurePortsReply{}, err
+	}
+
+	err = h.checklistWriter.ResetStateDir(upgradestatus.RECONFIGURE_PORTS)
 	if err != nil {
 		gplog.Error("error from ResetStateDir " + err.Error())
 	}
@@ -40,7 +46,7 @@ func (h *Hub) UpgradeReconfigurePorts(ctx context.Context, in *pb.UpgradeReconfi
 		}
 		gplog.Error("reconfigure-ports failed %s: %s", out, err)
 
-		h.checklistWriter.MarkFailed(upgradestatus.RECONFIGURE_PORTS)
+		h.checklistWriter.MarkFailed(upgradestatus.RECONFIGURE_PORTS, err)
 		return nil, err
 	}
 
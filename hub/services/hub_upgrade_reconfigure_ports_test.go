@@ -8,6 +8,7 @@ import (
 
 	"github.com/greenplum-db/gp-common-go-libs/cluster"
 	"github.com/greenplum-db/gpupgrade/hub/services"
+	"github.com/greenplum-db/gpupgrade/hub/upgradestatus"
 	pb "github.com/greenplum-db/gpupgrade/idl"
 	"github.com/greenplum-db/gpupgrade/testutils"
 
@@ -35,15 +36,7 @@ var _ = Describe("UpgradeReconfigurePorts", func() {
 		dir, err = ioutil.TempDir("", "")
 		Expect(err).ToNot(HaveOccurred())
 
-		numInvocations := 0
-		utils.System.ReadFile = func(filename string) ([]byte, error) {
-			if numInvocations == 0 {
-				numInvocations++
-				return []byte(testutils.MASTER_ONLY_JSON), nil
-			} else {
-				return []byte(testutils.NEW_MASTER_JSON), nil
-			}
-		}
+		setStepStatus(dir, upgradestatus.VALIDATE_START_CLUSTER, pb.StepStatus_COMPLETE)
 
 		errChan = make(chan error, 2)
 		outChan = make(chan []byte, 2)
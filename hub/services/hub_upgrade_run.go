@@ -0,0 +1,245 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/greenplum-db/gpupgrade/hub/upgradestatus"
+	pb "github.com/greenplum-db/gpupgrade/idl"
+
+	"github.com/greenplum-db/gp-common-go-libs/gplog"
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// UpgradeRunInterval is how often an upgrade run re-checks the status of an
+// asynchronous step (such as pg_upgrade on the master) while waiting for it to
+// finish.
+var UpgradeRunInterval = 5 * time.Second
+
+// UpgradeStepTimeout is how long an upgrade run waits for an asynchronous step
+// to finish before giving up on it, so that a step whose process has died
+// without recording an outcome doesn't hold up the run, and the hub, forever.
+var UpgradeStepTimeout = 24 * time.Hour
+
+// errStepUnfinished is returned by stepResult for a Step that is neither
+// COMPLETE nor FAILED.
+var errStepUnfinished = errors.New("step has not finished")
+
+// UpgradeRun kicks off every upgrade step, in order, in the background. Steps
+// that are already COMPLETE are skipped, so after a failure the user can fix
+// the problem and call UpgradeRun again to resume from the failed step.
+// Progress can be followed with StatusUpgrade or WatchUpgradeStatus.
+//
+// The run doesn't perform the pre-upgrade checks itself, since they need the
+// agents that it only starts after shutting down the clusters. Until the
+// clusters have been shut down, it refuses to start unless CheckAll has
+// passed, rather than failing part way through.
+func (h *Hub) UpgradeRun(ctx context.Context, in *pb.UpgradeRunRequest) (*pb.UpgradeRunReply, error) {
+	gplog.Info("starting UpgradeRun")
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.upgradeRunning {
+		return &pb.UpgradeRunReply{}, errors.New("an upgrade run is already in progress")
+	}
+	if h.reverting {
+		return &pb.UpgradeRunReply{}, errors.New("cannot start an upgrade run while a revert is in progress")
+	}
+	if h.shuttingDown {
+		return &pb.UpgradeRunReply{}, errors.New("cannot start an upgrade run while the hub is shutting down")
+	}
+
+	steps := make(map[pb.UpgradeSteps]Step)
+	for _, s := range upgradeSteps() {
+		steps[s.StepCode] = s
+	}
+	shutdown := steps[pb.UpgradeSteps_STOPPED_CLUSTER]
+	if shutdown.Status(h).Status != pb.StepStatus_COMPLETE && !h.checksPassed() {
+		err := status.Errorf(codes.FailedPrecondition,
+			"cannot start an upgrade run until the pre-upgrade checks have passed: run gpupgrade check all, fix what it reports, and run the upgrade again")
+		gplog.Error(err.Error())
+		return &pb.UpgradeRunReply{}, err
+	}
+	h.upgradeRunning = true
+
+	go func() {
+		err := h.runUpgrade(in)
+		if err != nil {
+			gplog.Error("upgrade run failed: %s", err)
+		}
+
+		h.mu.Lock()
+		h.upgradeRunning = false
+		h.mu.Unlock()
+	}()
+
+	return &pb.UpgradeRunReply{}, nil
+}
+
+// runUpgrade performs every Step that isn't already COMPLETE, stopping at the
+// first one that fails or whose prerequisites haven't completed. The outcome
+// of the run as a whole is recorded under upgradestatus.UPGRADE_RUN.
+func (h *Hub) runUpgrade(in *pb.UpgradeRunRequest) error {
+	err := h.checklistWriter.MarkInProgress(upgradestatus.UPGRADE_RUN)
+	if err != nil {
+		return err
+	}
+
+	// The request is the source of truth for the binary directories; they
+	// won't be filled in yet if the steps that record them are skipped.
+	h.clusterPair.OldBinDir = in.OldBinDir
+	h.clusterPair.NewBinDir = in.NewBinDir
+
+	for _, step := range upgradeSteps() {
+		if step.Status(h).Status == pb.StepStatus_COMPLETE {
+			gplog.Info("skipping %s: already complete", step.Name)
+			continue
+		}
+
+		gplog.Info("running %s", step.Name)
+		err = h.checkPrerequisites(step.StepCode)
+		if err == nil {
+			err = step.run(step, h, in)
+		}
+		if err != nil {
+			err = errors.Wrapf(err, "%s failed", step.Name)
+			cmErr := h.checklistWriter.MarkFailed(upgradestatus.UPGRADE_RUN, err)
+			if cmErr != nil {
+				gplog.Error("failed to record failed for %s: %s", upgradestatus.UPGRADE_RUN, cmErr)
+			}
+			return err
+		}
+		gplog.Info("finished %s", step.Name)
+	}
+
+	return h.checklistWriter.MarkComplete(upgradestatus.UPGRADE_RUN)
+}
+
+// stepResult returns nil if the Step is COMPLETE, an error if it FAILED, and
+// errStepUnfinished otherwise.
+func stepResult(s Step, h *Hub) error {
+	switch s.Status(h).Status {
+	case pb.StepStatus_COMPLETE:
+		return nil
+	case pb.StepStatus_FAILED:
+		return fmt.Errorf("%s reported failure; see the hub log for details", s.Name)
+	default:
+		return errStepUnfinished
+	}
+}
+
+// waitForStep polls the status of an asynchronous Step until it has either
+// completed or failed, or UpgradeStepTimeout has passed.
+func waitForStep(s Step, h *Hub) error {
+	deadline := time.Now().Add(UpgradeStepTimeout)
+	for {
+		err := stepResult(s, h)
+		if err != errStepUnfinished {
+			return err
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("%s did not finish within %s", s.Name, UpgradeStepTimeout)
+		}
+
+		time.Sleep(UpgradeRunInterval)
+	}
+}
+
+/*
+ * run() Implementations
+ */
+
+func runCheckConfig(s Step, h *Hub, in *pb.UpgradeRunRequest) error {
+	_, err := h.CheckConfig(context.Background(), &pb.CheckConfigRequest{
+		DbPort:    in.OldDbPort,
+		OldBinDir: in.OldBinDir,
+	})
+	if err != nil {
+		return err
+	}
+
+	return stepResult(s, h)
+}
+
+func runSeginstall(s Step, h *Hub, in *pb.UpgradeRunRequest) error {
+	h.remoteExecutor.VerifySoftware(h.clusterPair.GetHostnames())
+	return stepResult(s, h)
+}
+
+func runInitCluster(s Step, h *Hub, in *pb.UpgradeRunRequest) error {
+	_, err := h.PrepareInitCluster(context.Background(), &pb.PrepareInitClusterRequest{
+		DbPort:    in.NewDbPort,
+		NewBinDir: in.NewBinDir,
+	})
+	if err != nil {
+		return err
+	}
+
+	return stepResult(s, h)
+}
+
+func runShutdownClusters(s Step, h *Hub, in *pb.UpgradeRunRequest) error {
+	h.ShutdownClusters()
+	return stepResult(s, h)
+}
+
+func runConvertMaster(s Step, h *Hub, in *pb.UpgradeRunRequest) error {
+	oldDataDir, newDataDir := h.clusterPair.GetMasterDataDirs()
+	err := h.convertMaster(&pb.UpgradeConvertMasterRequest{
+		OldBinDir:  in.OldBinDir,
+		OldDataDir: oldDataDir,
+		NewBinDir:  in.NewBinDir,
+		NewDataDir: newDataDir,
+	})
+	if err != nil {
+		return err
+	}
+
+	return waitForStep(s, h)
+}
+
+func runStartAgents(s Step, h *Hub, in *pb.UpgradeRunRequest) error {
+	hostnames := h.clusterPair.GetHostnames()
+	err := h.distributeAgentFiles(hostnames)
+	if err != nil {
+		return err
+	}
+
+	h.remoteExecutor.Start(hostnames)
+	return stepResult(s, h)
+}
+
+func runShareOids(s Step, h *Hub, in *pb.UpgradeRunRequest) error {
+	h.shareOidFiles()
+	return stepResult(s, h)
+}
+
+func runConvertPrimaries(s Step, h *Hub, in *pb.UpgradeRunRequest) error {
+	_, err := h.UpgradeConvertPrimaries(context.Background(), &pb.UpgradeConvertPrimariesRequest{
+		OldBinDir: in.OldBinDir,
+		NewBinDir: in.NewBinDir,
+	})
+	if err != nil {
+		return err
+	}
+
+	return waitForStep(s, h)
+}
+
+func runValidateStartCluster(s Step, h *Hub, in *pb.UpgradeRunRequest) error {
+	h.startNewCluster()
+	return stepResult(s, h)
+}
+
+func runReconfigurePorts(s Step, h *Hub, in *pb.UpgradeRunRequest) error {
+	_, err := h.UpgradeReconfigurePorts(context.Background(), &pb.UpgradeReconfigurePortsRequest{})
+	if err != nil {
+		return err
+	}
+
+	return stepResult(s, h)
+}
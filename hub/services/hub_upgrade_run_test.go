@@ -0,0 +1,248 @@
+package services_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/greenplum-db/gpupgrade/hub/services"
+	"github.com/greenplum-db/gpupgrade/hub/upgradestatus"
+	pb "github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/testutils"
+	"github.com/greenplum-db/gpupgrade/utils"
+
+	"github.com/greenplum-db/gp-common-go-libs/testhelper"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("UpgradeRun", func() {
+	var (
+		hub           *services.Hub
+		dir           string
+		mockAgent     *testutils.MockAgentServer
+		commandExecer *testutils.FakeCommandExecer
+		clusterPair   *services.ClusterPair
+		newExecutor   *testhelper.TestExecutor
+		store         *upgradestatus.StateStore
+		request       *pb.UpgradeRunRequest
+	)
+
+	BeforeEach(func() {
+		testhelper.SetupTestLogger()
+
+		var port int
+		mockAgent, port = testutils.NewMockAgentServer()
+		mockAgent.StatusConversionResponse = &pb.CheckConversionStatusReply{
+			Statuses: []*pb.SegmentConversionStatus{{Role: pb.SegmentRole_PRIMARY, Status: pb.StepStatus_COMPLETE}},
+		}
+
+		var err error
+		dir, err = ioutil.TempDir("", "")
+		Expect(err).ToNot(HaveOccurred())
+		store = upgradestatus.NewStateStore(dir)
+
+		commandExecer = &testutils.FakeCommandExecer{}
+		commandExecer.SetOutput(&testutils.FakeCommand{})
+
+		clusterPair = testutils.CreateSampleClusterPair()
+		for content, segment := range clusterPair.OldCluster.Segments {
+			segment.Hostname = "localhost"
+			clusterPair.OldCluster.Segments[content] = segment
+		}
+		newExecutor = &testhelper.TestExecutor{}
+		clusterPair.NewCluster.Executor = newExecutor
+
+		conf := &services.HubConfig{
+			HubToAgentPort: port,
+			StateDir:       dir,
+		}
+		hub = services.NewHub(clusterPair, grpc.DialContext, commandExecer.Exec, conf,
+			testutils.NewStubRemoteExecutor(), upgradestatus.NewChecklistManager(dir))
+
+		request = &pb.UpgradeRunRequest{
+			OldDbPort: 15432,
+			OldBinDir: "/old/bindir",
+			NewDbPort: 25432,
+			NewBinDir: "/new/bindir",
+		}
+
+		// Everything up to (but not including) validate-start-cluster has
+		// already been done.
+		for _, step := range []string{
+			upgradestatus.CONFIG,
+			upgradestatus.SEGINSTALL,
+			upgradestatus.SHUTDOWN_CLUSTERS,
+			upgradestatus.START_AGENTS,
+			upgradestatus.SHARE_OIDS,
+		} {
+			Expect(store.Record(step, pb.StepStatus_COMPLETE, nil)).To(Succeed())
+		}
+
+		f, err := os.Create(services.GetNewConfigFilePath(dir))
+		Expect(err).ToNot(HaveOccurred())
+		f.Close()
+
+		Expect(os.MkdirAll(filepath.Join(dir, "pg_upgrade"), 0700)).To(Succeed())
+		err = ioutil.WriteFile(filepath.Join(dir, "pg_upgrade", "1.done"), []byte("Upgrade complete\n"), 0600)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		mockAgent.Stop()
+		utils.System = utils.InitializeSystemFunctions()
+		os.RemoveAll(dir)
+	})
+
+	runStatus := func() pb.StepStatus {
+		status, err := store.Status(upgradestatus.UPGRADE_RUN)
+		Expect(err).ToNot(HaveOccurred())
+		return status
+	}
+
+	callsMatching := func(substring string) []string {
+		var matches []string
+		for _, call := range commandExecer.Calls() {
+			if strings.Contains(call, substring) {
+				matches = append(matches, call)
+			}
+		}
+		return matches
+	}
+
+	It("skips completed steps and runs the remaining ones in order", func() {
+		_, err := hub.UpgradeRun(nil, request)
+		Expect(err).ToNot(HaveOccurred())
+
+		Eventually(runStatus).Should(Equal(pb.StepStatus_COMPLETE))
+
+		Expect(newExecutor.LocalCommands).To(HaveLen(1))
+		Expect(newExecutor.LocalCommands[0]).To(ContainSubstring("gpstart"))
+		Expect(callsMatching("sed")).To(HaveLen(1))
+		Expect(callsMatching("rsync")).To(BeEmpty())
+		Expect(callsMatching("nohup")).To(BeEmpty())
+
+		status, err := store.Status(upgradestatus.RECONFIGURE_PORTS)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(status).To(Equal(pb.StepStatus_COMPLETE))
+	})
+
+	It("stops at a failed step and resumes from it when run again", func() {
+		newExecutor.LocalError = errors.New("gpstart failed")
+
+		_, err := hub.UpgradeRun(nil, request)
+		Expect(err).ToNot(HaveOccurred())
+
+		Eventually(runStatus).Should(Equal(pb.StepStatus_FAILED))
+		latest, err := store.Latest(upgradestatus.UPGRADE_RUN)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(latest.Error).To(ContainSubstring(upgradestatus.VALIDATE_START_CLUSTER))
+		Expect(callsMatching("sed")).To(BeEmpty())
+
+		newExecutor.LocalError = nil
+		Eventually(func() error {
+			_, err := hub.UpgradeRun(nil, request)
+			return err
+		}).Should(Succeed())
+
+		Eventually(runStatus).Should(Equal(pb.StepStatus_COMPLETE))
+		Expect(newExecutor.NumExecutions).To(Equal(2))
+		Expect(callsMatching("sed")).To(HaveLen(1))
+	})
+
+	It("gives up on a step that doesn't finish in time", func() {
+		interval, timeout := services.UpgradeRunInterval, services.UpgradeStepTimeout
+		defer func() { services.UpgradeRunInterval, services.UpgradeStepTimeout = interval, timeout }()
+		services.UpgradeRunInterval = 10 * time.Millisecond
+		services.UpgradeStepTimeout = 50 * time.Millisecond
+
+		mockAgent.StatusConversionResponse = &pb.CheckConversionStatusReply{
+			Statuses: []*pb.SegmentConversionStatus{{Role: pb.SegmentRole_PRIMARY, Status: pb.StepStatus_RUNNING}},
+		}
+
+		_, err := hub.UpgradeRun(nil, request)
+		Expect(err).ToNot(HaveOccurred())
+
+		Eventually(runStatus).Should(Equal(pb.StepStatus_FAILED))
+		latest, err := store.Latest(upgradestatus.UPGRADE_RUN)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(latest.Error).To(ContainSubstring("convert-primaries did not finish within 50ms"))
+	})
+
+	It("refuses to start until the pre-upgrade checks have passed, if the clusters haven't been shut down", func() {
+		Expect(store.Record(upgradestatus.SHUTDOWN_CLUSTERS, pb.StepStatus_PENDING, nil)).To(Succeed())
+
+		_, err := hub.UpgradeRun(nil, request)
+		Expect(status.Code(err)).To(Equal(codes.FailedPrecondition))
+		Expect(err.Error()).To(ContainSubstring("gpupgrade check all"))
+
+		Consistently(runStatus, "100ms").Should(Equal(pb.StepStatus_PENDING))
+		Expect(clusterPair.OldCluster.Executor.(*testhelper.TestExecutor).NumExecutions).To(Equal(0))
+
+		Expect(store.Record(upgradestatus.CHECK_ALL, pb.StepStatus_FAILED, nil)).To(Succeed())
+		_, err = hub.UpgradeRun(nil, request)
+		Expect(status.Code(err)).To(Equal(codes.FailedPrecondition))
+	})
+
+	It("shuts down the clusters once the pre-upgrade checks have passed", func() {
+		Expect(store.Record(upgradestatus.SHUTDOWN_CLUSTERS, pb.StepStatus_PENDING, nil)).To(Succeed())
+		Expect(store.Record(upgradestatus.CHECK_ALL, pb.StepStatus_COMPLETE, nil)).To(Succeed())
+
+		_, err := hub.UpgradeRun(nil, request)
+		Expect(err).ToNot(HaveOccurred())
+
+		Eventually(runStatus).Should(Or(Equal(pb.StepStatus_COMPLETE), Equal(pb.StepStatus_FAILED)))
+		shutdown, err := store.Status(upgradestatus.SHUTDOWN_CLUSTERS)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(shutdown).ToNot(Equal(pb.StepStatus_PENDING))
+	})
+
+	It("refuses to start a second run while one is in progress", func() {
+		trigger := make(chan struct{})
+		commandExecer.SetTrigger(trigger)
+
+		_, err := hub.UpgradeRun(nil, request)
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = hub.UpgradeRun(nil, request)
+		Expect(err).To(HaveOccurred())
+
+		close(trigger)
+		Eventually(runStatus).Should(Equal(pb.StepStatus_COMPLETE))
+	})
+
+	It("keeps the hub from shutting down while it is in progress", func() {
+		trigger := make(chan struct{})
+		commandExecer.SetTrigger(trigger)
+
+		_, err := hub.UpgradeRun(nil, request)
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = hub.Shutdown(nil, &pb.ShutdownRequest{})
+		Expect(err).To(MatchError(ContainSubstring("cannot shut down the hub while an upgrade run or a revert is in progress")))
+
+		close(trigger)
+		Eventually(runStatus).Should(Equal(pb.StepStatus_COMPLETE))
+	})
+
+	It("keeps the agents from being stopped while it is in progress", func() {
+		trigger := make(chan struct{})
+		commandExecer.SetTrigger(trigger)
+
+		_, err := hub.UpgradeRun(nil, request)
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = hub.PrepareStopAgents(nil, &pb.PrepareStopAgentsRequest{})
+		Expect(err).To(MatchError(ContainSubstring("cannot stop the agents while an upgrade run or a revert is in progress")))
+
+		close(trigger)
+		Eventually(runStatus).Should(Equal(pb.StepStatus_COMPLETE))
+	})
+})
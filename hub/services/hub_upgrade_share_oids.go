@@ -1,10 +1,15 @@
 package services
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
 	"path/filepath"
-	"strings"
+	"time"
 
 	pb "github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/utils"
 
 	"github.com/greenplum-db/gpupgrade/hub/upgradestatus"
 
@@ -15,6 +20,12 @@ import (
 func (h *Hub) UpgradeShareOids(ctx context.Context, in *pb.UpgradeShareOidsRequest) (*pb.UpgradeShareOidsReply, error) {
 	gplog.Info("Started processing share-oids request")
 
+	err := h.checkPrerequisites(pb.UpgradeSteps_SHARE_OIDS)
+	if err != nil {
+		gplog.Error(err.Error())
+		return &pb.UpgradeShareOidsReply{}, err
+	}
+
 	go h.shareOidFiles()
 
 	return &pb.UpgradeShareOidsReply{}, nil
@@ -33,40 +44,116 @@ func (h *Hub) shareOidFiles() {
 		return
 	}
 
-	hostnames := h.clusterPair.GetHostnames()
+	err = h.pushOidFiles()
+	if err != nil {
+		err = h.checklistWriter.MarkFailed(upgradestatus.SHARE_OIDS, err)
+		if err != nil {
+			gplog.Error("error from MarkFailed " + err.Error())
+		}
+	} else {
+		err = h.checklistWriter.MarkComplete(upgradestatus.SHARE_OIDS)
+		if err != nil {
+			gplog.Error("error from MarkComplete " + err.Error())
+		}
+	}
+
+}
 
-	user := "gpadmin"
-	rsyncFlags := "-rzpogt"
-	sourceDir := filepath.Join(h.conf.StateDir, "pg_upgrade")
+// fileChunkSize is the most of a file that pushFiles sends at once.
+const fileChunkSize = 32 * 1024
 
-	anyFailed := false
-	for _, host := range hostnames {
-		destinationDirectory := user + "@" + host + ":" + filepath.Join(h.conf.StateDir, "pg_upgrade")
+// PushFilesTimeout is how long pushFiles gives an agent to receive and write
+// the files, so that an agent that stops reading can't hang the step.
+var PushFilesTimeout = 5 * time.Minute
 
-		rsyncArgs := strings.Join([]string{"rsync", rsyncFlags, filepath.Join(sourceDir, "pg_upgrade_dump_*_oids.sql"), destinationDirectory}, " ")
-		rsyncCommand := h.commandExecer("bash", "-c", rsyncArgs)
-		gplog.Info("share oids command: %+v", rsyncCommand)
+// pushOidFiles sends the OID files that pg_upgrade dumped on the master to
+// the agent on every host, for the upgrade of the primaries to use.
+func (h *Hub) pushOidFiles() error {
+	oidFiles, err := utils.System.FilePathGlob(filepath.Join(h.conf.StateDir, "pg_upgrade", "pg_upgrade_dump_*_oids.sql"))
+	if err != nil {
+		return err
+	}
+	if len(oidFiles) == 0 {
+		return fmt.Errorf("pg_upgrade left no OID files in %s", filepath.Join(h.conf.StateDir, "pg_upgrade"))
+	}
 
-		output, err := rsyncCommand.CombinedOutput()
+	return h.forEachAgent("copy OID files to", func(host string, client pb.AgentClient) error {
+		err := h.pushFiles(client, oidFiles)
 		if err != nil {
-			var out string
-			if len(output) != 0 {
-				out = string(output)
-			}
-			gplog.Error("share oids failed %s: %s", out, err)
-			anyFailed = true
+			return err
 		}
+
+		gplog.Info("copied %d OID files to %s", len(oidFiles), host)
+		return nil
+	})
+}
+
+// pushFiles sends paths, which are in the state directory, to the agent that
+// client talks to, to be written to the same place in its own state
+// directory. The agent checks each file against its checksum.
+func (h *Hub) pushFiles(client pb.AgentClient, paths []string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), PushFilesTimeout)
+	defer cancel()
+
+	stream, err := client.PushFiles(ctx)
+	if err != nil {
+		return err
 	}
-	if anyFailed {
-		h.checklistWriter.MarkFailed(upgradestatus.SHARE_OIDS)
+
+	for _, path := range paths {
+		relPath, err := filepath.Rel(h.conf.StateDir, path)
 		if err != nil {
-			gplog.Error("error from MarkFailed " + err.Error())
+			return err
+		}
+
+		err = sendFile(stream, path, relPath)
+		if err == io.EOF {
+			// The agent gave up on the stream; CloseAndRecv says why.
+			break
 		}
-	} else {
-		h.checklistWriter.MarkComplete(upgradestatus.SHARE_OIDS)
 		if err != nil {
-			gplog.Error("error from MarkComplete " + err.Error())
+			stream.CloseSend()
+			return err
+		}
+	}
+
+	reply, err := stream.CloseAndRecv()
+	if err != nil {
+		return err
+	}
+	if len(reply.Paths) != len(paths) {
+		return fmt.Errorf("the agent wrote %d of the %d files", len(reply.Paths), len(paths))
+	}
+	return nil
+}
+
+// sendFile streams the file at path in chunks named relPath, followed by its
+// checksum.
+func sendFile(stream pb.Agent_PushFilesClient, path string, relPath string) error {
+	file, err := utils.System.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	buf := make([]byte, fileChunkSize)
+	for {
+		n, err := file.Read(buf)
+		if n > 0 {
+			hash.Write(buf[:n])
+			sendErr := stream.Send(&pb.FileChunk{Path: relPath, Data: buf[:n]})
+			if sendErr != nil {
+				return sendErr
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
 		}
 	}
 
+	return stream.Send(&pb.FileChunk{Path: relPath, Checksum: hex.EncodeToString(hash.Sum(nil))})
 }
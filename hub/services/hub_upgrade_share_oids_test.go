@@ -1,16 +1,21 @@
 package services_test
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
-	"fmt"
 	"io/ioutil"
 	"os"
+	"path/filepath"
+	"time"
 
-	"github.com/greenplum-db/gp-common-go-libs/cluster"
 	"github.com/greenplum-db/gpupgrade/hub/services"
+	"github.com/greenplum-db/gpupgrade/hub/upgradestatus"
 	pb "github.com/greenplum-db/gpupgrade/idl"
 	"github.com/greenplum-db/gpupgrade/testutils"
 
+	"github.com/greenplum-db/gp-common-go-libs/cluster"
 	"google.golang.org/grpc"
 
 	"github.com/greenplum-db/gpupgrade/utils"
@@ -20,69 +25,111 @@ import (
 
 var _ = Describe("UpgradeShareOids", func() {
 	var (
-		hub                *services.Hub
-		dir                string
-		commandExecer      *testutils.FakeCommandExecer
-		errChan            chan error
-		outChan            chan []byte
-		stubRemoteExecutor *testutils.StubRemoteExecutor
-		clusterPair        *services.ClusterPair
-		cm                 *testutils.MockChecklistManager
+		hub           *services.Hub
+		dir           string
+		commandExecer *testutils.FakeCommandExecer
+		mockAgent     *testutils.MockAgentServer
+		cm            *testutils.MockChecklistManager
+		oidFiles      map[string][]byte
 	)
 
 	BeforeEach(func() {
-		clusterPair = testutils.CreateSampleClusterPair()
-		clusterPair.OldCluster.Segments[1] = cluster.SegConfig{Hostname: "hosttwo"}
+		var port int
+		mockAgent, port = testutils.NewMockAgentServer()
+
+		clusterPair := testutils.CreateSampleClusterPair()
+		clusterPair.OldCluster.Segments = map[int]cluster.SegConfig{
+			-1: {ContentID: -1, Hostname: "localhost", DataDir: "/old/datadir"},
+			0:  {ContentID: 0, Hostname: "localhost", DataDir: "/old/datadir0"},
+		}
 
 		var err error
 		dir, err = ioutil.TempDir("", "")
 		Expect(err).ToNot(HaveOccurred())
+		setMasterUpgradeComplete(dir)
 
-		errChan = make(chan error, 2)
-		outChan = make(chan []byte, 2)
-		commandExecer = &testutils.FakeCommandExecer{}
-		commandExecer.SetOutput(&testutils.FakeCommand{
-			Err: errChan,
-			Out: outChan,
-		})
-		stubRemoteExecutor = testutils.NewStubRemoteExecutor()
-		hubConfig := &services.HubConfig{
-			StateDir: dir,
+		// The second file is big enough to be sent in several chunks.
+		oidFiles = map[string][]byte{
+			"pg_upgrade/pg_upgrade_dump_1_oids.sql": []byte("SELECT binary_upgrade.set_next_pg_type_oid('16385'::pg_catalog.oid);\n"),
+			"pg_upgrade/pg_upgrade_dump_2_oids.sql": bytes.Repeat([]byte("SELECT 1;\n"), 10000),
+		}
+		for name, contents := range oidFiles {
+			Expect(ioutil.WriteFile(filepath.Join(dir, name), contents, 0600)).To(Succeed())
 		}
+
+		commandExecer = &testutils.FakeCommandExecer{}
+		commandExecer.SetOutput(&testutils.FakeCommand{})
+
 		cm = testutils.NewMockChecklistManager()
 		hub = services.NewHub(clusterPair, grpc.DialContext, commandExecer.Exec,
-			hubConfig, stubRemoteExecutor, cm)
+			&services.HubConfig{StateDir: dir, HubToAgentPort: port}, nil, cm)
 	})
 
 	AfterEach(func() {
 		utils.System = utils.InitializeSystemFunctions()
+		mockAgent.Stop()
 		os.RemoveAll(dir)
 	})
 
-	It("copies files to each host", func() {
+	It("pushes the OID files to the agents along with their checksums", func() {
 		_, err := hub.UpgradeShareOids(nil, &pb.UpgradeShareOidsRequest{})
 		Expect(err).ToNot(HaveOccurred())
 
-		hostnames := clusterPair.GetHostnames()
-		Expect(err).ToNot(HaveOccurred())
+		Eventually(func() bool { return cm.IsComplete(upgradestatus.SHARE_OIDS) }).Should(BeTrue())
+
+		received := make(map[string][]byte)
+		checksums := make(map[string]string)
+		for _, chunk := range mockAgent.ReceivedFileChunks() {
+			received[chunk.Path] = append(received[chunk.Path], chunk.Data...)
+			if chunk.Checksum != "" {
+				checksums[chunk.Path] = chunk.Checksum
+			}
+		}
 
-		Eventually(commandExecer.GetNumInvocations).Should(Equal(len(hostnames)))
+		Expect(received).To(HaveLen(len(oidFiles)))
+		for name, contents := range oidFiles {
+			sum := sha256.Sum256(contents)
+			Expect(received[name]).To(Equal(contents))
+			Expect(checksums[name]).To(Equal(hex.EncodeToString(sum[:])))
+		}
+	})
+
+	It("fails the step, naming the hosts and why, if an agent can't write the files", func() {
+		mockAgent.Err <- errors.New("disk full")
+
+		_, err := hub.UpgradeShareOids(nil, &pb.UpgradeShareOidsRequest{})
+		Expect(err).ToNot(HaveOccurred())
 
-		Expect(commandExecer.Calls()).To(ConsistOf([]string{
-			fmt.Sprintf("bash -c rsync -rzpogt %s/pg_upgrade/pg_upgrade_dump_*_oids.sql gpadmin@hostone:%s/pg_upgrade", dir, dir),
-			fmt.Sprintf("bash -c rsync -rzpogt %s/pg_upgrade/pg_upgrade_dump_*_oids.sql gpadmin@hosttwo:%s/pg_upgrade", dir, dir),
-		}))
+		Eventually(func() bool { return cm.IsFailed(upgradestatus.SHARE_OIDS) }).Should(BeTrue())
+		Expect(cm.FailureCause(upgradestatus.SHARE_OIDS)).To(MatchError(ContainSubstring("could not copy OID files to localhost: ")))
+		Expect(cm.FailureCause(upgradestatus.SHARE_OIDS)).To(MatchError(ContainSubstring("disk full")))
 	})
 
-	It("copies all files even if rsync fails for a host", func() {
-		errChan <- errors.New("failure")
+	It("gives up on an agent that doesn't take the files in time", func() {
+		timeout := services.PushFilesTimeout
+		services.PushFilesTimeout = 50 * time.Millisecond
+		defer func() { services.PushFilesTimeout = timeout }()
+
+		mockAgent.PushFilesGate = make(chan struct{})
+		defer close(mockAgent.PushFilesGate)
 
 		_, err := hub.UpgradeShareOids(nil, &pb.UpgradeShareOidsRequest{})
 		Expect(err).ToNot(HaveOccurred())
 
-		hostnames := clusterPair.GetHostnames()
+		Eventually(func() bool { return cm.IsFailed(upgradestatus.SHARE_OIDS) }).Should(BeTrue())
+		Expect(cm.FailureCause(upgradestatus.SHARE_OIDS)).To(MatchError(ContainSubstring("DeadlineExceeded")))
+	})
+
+	It("fails the step if pg_upgrade left no OID files", func() {
+		for name := range oidFiles {
+			Expect(os.Remove(filepath.Join(dir, name))).To(Succeed())
+		}
+
+		_, err := hub.UpgradeShareOids(nil, &pb.UpgradeShareOidsRequest{})
 		Expect(err).ToNot(HaveOccurred())
 
-		Eventually(commandExecer.GetNumInvocations).Should(Equal(len(hostnames)))
+		Eventually(func() bool { return cm.IsFailed(upgradestatus.SHARE_OIDS) }).Should(BeTrue())
+		Expect(cm.FailureCause(upgradestatus.SHARE_OIDS)).To(MatchError(ContainSubstring("pg_upgrade left no OID files")))
+		Expect(mockAgent.NumberOfCalls()).To(Equal(0))
 	})
 })
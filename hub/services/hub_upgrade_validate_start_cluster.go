@@ -13,6 +13,12 @@ import (
 func (h *Hub) UpgradeValidateStartCluster(ctx context.Context, in *pb.UpgradeValidateStartClusterRequest) (*pb.UpgradeValidateStartClusterReply, error) {
 	gplog.Info("Started processing validate-start-cluster request")
 
+	err := h.checkPrerequisites(pb.UpgradeSteps_VALIDATE_START_CLUSTER)
+	if err != nil {
+		gplog.Error(err.Error())
+		return &pb.UpgradeValidateStartClusterReply{}, err
+	}
+
 	go h.startNewCluster()
 
 	return &pb.UpgradeValidateStartClusterReply{}, nil
@@ -37,10 +43,11 @@ func (h *Hub) startNewCluster() {
 
 	newBinDir := h.clusterPair.NewBinDir
 	newDataDir := h.clusterPair.NewCluster.GetDirForContent(-1)
-	_, err = h.clusterPair.NewCluster.ExecuteLocalCommand(fmt.Sprintf("source %s/../greenplum_path.sh; %s/gpstart -a -d %s", newBinDir, newBinDir, newDataDir))
+	newCluster := h.cancellable(upgradestatus.VALIDATE_START_CLUSTER, h.clusterPair.NewCluster)
+	_, err = newCluster.ExecuteLocalCommand(fmt.Sprintf("source %s/../greenplum_path.sh; %s/gpstart -a -d %s", newBinDir, newBinDir, newDataDir))
 	if err != nil {
 		gplog.Error(err.Error())
-		cmErr := c.MarkFailed(upgradestatus.VALIDATE_START_CLUSTER)
+		cmErr := c.MarkFailed(upgradestatus.VALIDATE_START_CLUSTER, err)
 		if cmErr != nil {
 			gplog.Error("failed to record failed for validate-start-cluster")
 		}
@@ -29,6 +29,7 @@ var _ = Describe("upgrade validate start cluster", func() {
 		clusterPair   *services.ClusterPair
 		testExecutor  *testhelper.TestExecutor
 		cm            *testutils.MockChecklistManager
+		mockAgent     *testutils.MockAgentServer
 	)
 
 	BeforeEach(func() {
@@ -36,6 +37,15 @@ var _ = Describe("upgrade validate start cluster", func() {
 		dir, err = ioutil.TempDir("", "")
 		Expect(err).ToNot(HaveOccurred())
 
+		// The primaries must have been converted before the new cluster can
+		// be started.
+		var port int
+		mockAgent, port = testutils.NewMockAgentServer()
+		mockAgent.StatusConversionResponse = &pb.CheckConversionStatusReply{
+			Statuses: []*pb.SegmentConversionStatus{{Role: pb.SegmentRole_PRIMARY, Status: pb.StepStatus_COMPLETE}},
+		}
+		setStepStatus(dir, upgradestatus.START_AGENTS, pb.StepStatus_COMPLETE)
+
 		errChan = make(chan error, 1)
 		outChan = make(chan []byte, 1)
 
@@ -46,16 +56,21 @@ var _ = Describe("upgrade validate start cluster", func() {
 		})
 
 		clusterPair = testutils.CreateSampleClusterPair()
+		master := clusterPair.OldCluster.Segments[-1]
+		master.Hostname = "localhost"
+		clusterPair.OldCluster.Segments[-1] = master
 		testExecutor = &testhelper.TestExecutor{}
 		clusterPair.NewCluster.Executor = testExecutor
 		cm = testutils.NewMockChecklistManager()
 		clusterSsher := cluster_ssher.NewClusterSsher(cm, nil, nil)
 		hub = services.NewHub(clusterPair, grpc.DialContext, commandExecer.Exec, &services.HubConfig{
-			StateDir: dir,
+			StateDir:       dir,
+			HubToAgentPort: port,
 		}, clusterSsher, cm)
 	})
 
 	AfterEach(func() {
+		mockAgent.Stop()
 		utils.System = utils.InitializeSystemFunctions()
 		os.RemoveAll(dir)
 	})
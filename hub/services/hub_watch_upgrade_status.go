@@ -0,0 +1,80 @@
+package services
+
+import (
+	"time"
+
+	pb "github.com/greenplum-db/gpupgrade/idl"
+
+	"github.com/greenplum-db/gp-common-go-libs/gplog"
+)
+
+// WatchUpgradeInterval is how often WatchUpgradeStatus re-checks the step
+// statuses for changes.
+var WatchUpgradeInterval = 1 * time.Second
+
+// WatchUpgradeStatus sends the status of every step to the client, and then
+// another status each time a step changes state or pg_upgrade moves on to
+// another phase, until every step is COMPLETE, any step has FAILED, or the
+// client goes away.
+func (h *Hub) WatchUpgradeStatus(in *pb.WatchUpgradeStatusRequest, stream pb.CliToHub_WatchUpgradeStatusServer) error {
+	gplog.Info("starting WatchUpgradeStatus")
+
+	lastSent := make(map[pb.UpgradeSteps]watchedStatus)
+	ticker := time.NewTicker(WatchUpgradeInterval)
+	defer ticker.Stop()
+
+	for {
+		statuses := h.upgradeStepStatuses()
+
+		for _, status := range statuses {
+			watched := watchedStatus{status.Status, status.GetProgress().GetPhase()}
+			if last, ok := lastSent[status.Step]; ok && last == watched {
+				continue
+			}
+
+			err := stream.Send(status)
+			if err != nil {
+				gplog.Error("failed to send status of %s: %s", status.Step, err)
+				return err
+			}
+			lastSent[status.Step] = watched
+		}
+
+		if upgradeFinished(statuses) {
+			gplog.Info("finished WatchUpgradeStatus")
+			return nil
+		}
+
+		select {
+		case <-stream.Context().Done():
+			gplog.Info("client stopped watching upgrade status")
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// watchedStatus is the part of an UpgradeStepStatus that WatchUpgradeStatus
+// reports changes to.
+type watchedStatus struct {
+	status pb.StepStatus
+	phase  string
+}
+
+// upgradeFinished returns true when no further status changes are expected
+// without user intervention: either every step is COMPLETE, or one of them has
+// FAILED.
+func upgradeFinished(statuses []*pb.UpgradeStepStatus) bool {
+	allComplete := true
+	for _, status := range statuses {
+		switch status.Status {
+		case pb.StepStatus_FAILED:
+			return true
+		case pb.StepStatus_COMPLETE:
+		default:
+			allComplete = false
+		}
+	}
+
+	return allComplete
+}
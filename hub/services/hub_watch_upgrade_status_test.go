@@ -0,0 +1,160 @@
+package services_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/greenplum-db/gpupgrade/hub/services"
+	pb "github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/testutils"
+	"github.com/greenplum-db/gpupgrade/utils"
+
+	"github.com/greenplum-db/gp-common-go-libs/testhelper"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type spyWatchUpgradeStatusServer struct {
+	grpc.ServerStream
+
+	ctx     context.Context
+	sendErr error
+
+	mu   sync.Mutex
+	sent []*pb.UpgradeStepStatus
+}
+
+func (s *spyWatchUpgradeStatusServer) Send(status *pb.UpgradeStepStatus) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.sendErr != nil {
+		return s.sendErr
+	}
+	s.sent = append(s.sent, status)
+	return nil
+}
+
+func (s *spyWatchUpgradeStatusServer) Context() context.Context {
+	return s.ctx
+}
+
+func (s *spyWatchUpgradeStatusServer) Sent() []*pb.UpgradeStepStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]*pb.UpgradeStepStatus{}, s.sent...)
+}
+
+func (s *spyWatchUpgradeStatusServer) SentFor(step pb.UpgradeSteps) []pb.StepStatus {
+	var statuses []pb.StepStatus
+	for _, status := range s.Sent() {
+		if status.Step == step {
+			statuses = append(statuses, status.Status)
+		}
+	}
+	return statuses
+}
+
+var _ = Describe("WatchUpgradeStatus", func() {
+	var (
+		hub            *services.Hub
+		dir            string
+		stream         *spyWatchUpgradeStatusServer
+		cancel         func()
+		savedInterval  time.Duration
+		numberOfSteps  int
+		commandExecer  *testutils.FakeCommandExecer
+		remoteExecutor *testutils.StubRemoteExecutor
+	)
+
+	BeforeEach(func() {
+		testhelper.SetupTestLogger()
+
+		var err error
+		dir, err = ioutil.TempDir("", "")
+		Expect(err).ToNot(HaveOccurred())
+
+		commandExecer = &testutils.FakeCommandExecer{}
+		commandExecer.SetOutput(&testutils.FakeCommand{})
+		remoteExecutor = testutils.NewStubRemoteExecutor()
+
+		conf := &services.HubConfig{StateDir: dir}
+		hub = services.NewHub(testutils.CreateSampleClusterPair(), grpc.DialContext, commandExecer.Exec,
+			conf, remoteExecutor, nil)
+
+		reply, err := hub.StatusUpgrade(nil, &pb.StatusUpgradeRequest{})
+		Expect(err).ToNot(HaveOccurred())
+		numberOfSteps = len(reply.GetListOfUpgradeStepStatuses())
+
+		var ctx context.Context
+		ctx, cancel = context.WithCancel(context.Background())
+		stream = &spyWatchUpgradeStatusServer{ctx: ctx}
+
+		savedInterval = services.WatchUpgradeInterval
+		services.WatchUpgradeInterval = 10 * time.Millisecond
+	})
+
+	AfterEach(func() {
+		cancel()
+		services.WatchUpgradeInterval = savedInterval
+		utils.System = utils.InitializeSystemFunctions()
+		os.RemoveAll(dir)
+	})
+
+	It("sends the status of every step and stops once a step has failed", func() {
+		setStepStatus(dir, "check-config", pb.StepStatus_FAILED)
+
+		err := hub.WatchUpgradeStatus(&pb.WatchUpgradeStatusRequest{}, stream)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(stream.Sent()).To(HaveLen(numberOfSteps))
+		Expect(stream.SentFor(pb.UpgradeSteps_CHECK_CONFIG)).To(Equal([]pb.StepStatus{pb.StepStatus_FAILED}))
+	})
+
+	It("only sends a step's status again after it changes", func() {
+		setStepStatus(dir, "check-config", pb.StepStatus_RUNNING)
+
+		errChan := make(chan error, 1)
+		go func() {
+			errChan <- hub.WatchUpgradeStatus(&pb.WatchUpgradeStatusRequest{}, stream)
+		}()
+
+		Eventually(stream.Sent).Should(HaveLen(numberOfSteps))
+		Consistently(stream.Sent, 50*time.Millisecond).Should(HaveLen(numberOfSteps))
+
+		setStepStatus(dir, "check-config", pb.StepStatus_FAILED)
+
+		Eventually(errChan).Should(Receive(BeNil()))
+		Expect(stream.Sent()).To(HaveLen(numberOfSteps + 1))
+		Expect(stream.SentFor(pb.UpgradeSteps_CHECK_CONFIG)).To(Equal([]pb.StepStatus{
+			pb.StepStatus_RUNNING,
+			pb.StepStatus_FAILED,
+		}))
+	})
+
+	It("stops when the client goes away", func() {
+		errChan := make(chan error, 1)
+		go func() {
+			errChan <- hub.WatchUpgradeStatus(&pb.WatchUpgradeStatusRequest{}, stream)
+		}()
+
+		Eventually(stream.Sent).Should(HaveLen(numberOfSteps))
+		cancel()
+
+		Eventually(errChan).Should(Receive(BeNil()))
+	})
+
+	It("returns an error if a status cannot be sent", func() {
+		stream.sendErr = errors.New("stream closed")
+
+		err := hub.WatchUpgradeStatus(&pb.WatchUpgradeStatusRequest{}, stream)
+		Expect(err).To(MatchError("stream closed"))
+	})
+})
@@ -3,16 +3,15 @@ package upgradestatus
 import (
 	"path"
 
+	pb "github.com/greenplum-db/gpupgrade/idl"
 	"github.com/greenplum-db/gpupgrade/utils"
-
-	"os"
-	"path/filepath"
 )
 
 const (
 	CONFIG                 = "check-config"
 	VERSION                = "check-version"
 	SEGINSTALL             = "check-seginstall"
+	CHECK_ALL              = "check-all"
 	START_AGENTS           = "start-agents"
 	INIT_CLUSTER           = "init-cluster"
 	SHUTDOWN_CLUSTERS      = "shutdown-clusters"
@@ -21,61 +20,43 @@ const (
 	CONVERT_PRIMARY        = "convert-primary"
 	VALIDATE_START_CLUSTER = "validate-start-cluster"
 	RECONFIGURE_PORTS      = "reconfigure-ports"
+	UPGRADE_RUN            = "upgrade-run"
+
+	REVERT                     = "revert"
+	REVERT_STOP_NEW_CLUSTER    = "revert-stop-new-cluster"
+	REVERT_RESTORE_PORTS       = "revert-restore-ports"
+	REVERT_DELETE_NEW_DATADIRS = "revert-delete-new-datadirs"
+	REVERT_START_OLD_CLUSTER   = "revert-start-old-cluster"
 )
 
+// ChecklistManager records step transitions in the upgrade journal (see
+// StateStore) and manages the per-step scratch directories in the state dir.
 type ChecklistManager struct {
 	pathToStateDir string
-	inProgress     string
-	failed         string
-	completed      string
+	store          *StateStore
 }
 
 func NewChecklistManager(stateDirPath string) *ChecklistManager {
 	return &ChecklistManager{
 		pathToStateDir: stateDirPath,
-		inProgress:     "in.progress",
-		failed:         "failed",
-		completed:      "completed",
+		store:          NewStateStore(stateDirPath),
 	}
 }
 
-func (c *ChecklistManager) MarkFailed(step string) error {
-	err := utils.System.Remove(filepath.Join(c.pathToStateDir, step, c.inProgress))
-	if err != nil {
-		return err
-	}
-
-	_, err = utils.System.OpenFile(path.Join(c.pathToStateDir, step, c.failed), os.O_CREATE, 0700)
-	if err != nil {
-		return err
-	}
-
-	return nil
+func (c *ChecklistManager) MarkFailed(step string, cause error) error {
+	return c.store.Record(step, pb.StepStatus_FAILED, cause)
 }
 
 func (c *ChecklistManager) MarkComplete(step string) error {
-	err := utils.System.Remove(filepath.Join(c.pathToStateDir, step, c.inProgress))
-	if err != nil {
-		return err
-	}
-
-	_, err = utils.System.OpenFile(path.Join(c.pathToStateDir, step, c.completed), os.O_CREATE, 0700)
-	if err != nil {
-		return err
-	}
-
-	return nil
+	return c.store.Record(step, pb.StepStatus_COMPLETE, nil)
 }
 
 func (c *ChecklistManager) MarkInProgress(step string) error {
-	_, err := utils.System.OpenFile(path.Join(c.pathToStateDir, step, c.inProgress), os.O_CREATE, 0700)
-	if err != nil {
-		return err
-	}
-
-	return nil
+	return c.store.Record(step, pb.StepStatus_RUNNING, nil)
 }
 
+// ResetStateDir empties the scratch directory for the step and records the
+// step as PENDING again. Earlier transitions stay in the journal.
 func (c *ChecklistManager) ResetStateDir(step string) error {
 	stepSpecificStateDir := path.Join(c.pathToStateDir, step)
 	err := utils.System.RemoveAll(stepSpecificStateDir)
@@ -88,5 +69,5 @@ func (c *ChecklistManager) ResetStateDir(step string) error {
 		return err
 	}
 
-	return nil
+	return c.store.Record(step, pb.StepStatus_PENDING, nil)
 }
@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 
 	"github.com/greenplum-db/gpupgrade/hub/upgradestatus"
+	pb "github.com/greenplum-db/gpupgrade/idl"
 	"github.com/greenplum-db/gpupgrade/utils"
 
 	. "github.com/onsi/ginkgo"
@@ -14,45 +15,55 @@ import (
 )
 
 var _ = Describe("upgradestatus/ChecklistManager", func() {
+	var (
+		tempdir string
+		cm      *upgradestatus.ChecklistManager
+		store   *upgradestatus.StateStore
+	)
+
+	BeforeEach(func() {
+		var err error
+		tempdir, err = ioutil.TempDir("", "")
+		Expect(err).ToNot(HaveOccurred())
+
+		cm = upgradestatus.NewChecklistManager(filepath.Join(tempdir, ".gpupgrade"))
+		store = upgradestatus.NewStateStore(filepath.Join(tempdir, ".gpupgrade"))
+	})
+
 	AfterEach(func() {
 		utils.System = utils.InitializeSystemFunctions()
+		os.RemoveAll(tempdir)
 	})
 
-	Describe("MarkInProgress", func() {
-		It("Leaves an in-progress file in the state dir", func() {
-			tempdir, _ := ioutil.TempDir("", "")
+	expectStatus := func(step string, expected pb.StepStatus) {
+		status, err := store.Status(step)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(status).To(Equal(expected))
+	}
 
-			cm := upgradestatus.NewChecklistManager(filepath.Join(tempdir, ".gpupgrade"))
+	Describe("MarkInProgress", func() {
+		It("records the step as running", func() {
 			cm.ResetStateDir("fancy_step")
 			err := cm.MarkInProgress("fancy_step")
 			Expect(err).ToNot(HaveOccurred())
-			expectedFile := filepath.Join(tempdir, ".gpupgrade", "fancy_step", "in.progress")
-			_, err = os.Stat(expectedFile)
-			Expect(err).ToNot(HaveOccurred())
-		})
 
-		It("still succeeds if file already exists", func() {
-			tempdir, _ := ioutil.TempDir("", "")
+			expectStatus("fancy_step", pb.StepStatus_RUNNING)
+		})
 
-			cm := upgradestatus.NewChecklistManager(filepath.Join(tempdir, ".gpupgrade"))
+		It("still succeeds if the step is already running", func() {
 			cm.ResetStateDir("fancy_step")
-			cm.MarkInProgress("fancy_step") // lay the file down once
+			cm.MarkInProgress("fancy_step")
 			err := cm.MarkInProgress("fancy_step")
 			Expect(err).ToNot(HaveOccurred())
-			expectedFile := filepath.Join(tempdir, ".gpupgrade", "fancy_step", "in.progress")
-			_, err = os.Stat(expectedFile)
-			Expect(err).ToNot(HaveOccurred())
+
+			expectStatus("fancy_step", pb.StepStatus_RUNNING)
 		})
 
-		It("errors if file opening fails, e.g. disk full", func() {
+		It("errors if the journal can't be written, e.g. disk full", func() {
 			utils.System.OpenFile = func(_ string, _ int, _ os.FileMode) (*os.File, error) {
 				return nil, errors.New("Disk full or something")
 			}
 
-			tempdir, _ := ioutil.TempDir("", "")
-
-			cm := upgradestatus.NewChecklistManager(filepath.Join(tempdir, ".gpupgrade"))
-			cm.ResetStateDir("fancy_step")
 			err := cm.MarkInProgress("fancy_step")
 			Expect(err).To(HaveOccurred())
 		})
@@ -79,82 +90,67 @@ var _ = Describe("upgradestatus/ChecklistManager", func() {
 			err := cm.ResetStateDir("fancy_step")
 			Expect(err).To(HaveOccurred())
 		})
-		It("succeeds as long as we assume the file system calls do their job", func() {
-			utils.System.RemoveAll = func(name string) error {
-				return nil
-			}
-			utils.System.MkdirAll = func(string, os.FileMode) error {
-				return nil
-			}
-			cm := upgradestatus.NewChecklistManager("/some/random/dir")
+
+		It("recreates the step directory and records the step as pending", func() {
+			cm.MarkInProgress("fancy_step")
+			cm.MarkComplete("fancy_step")
+
 			err := cm.ResetStateDir("fancy_step")
 			Expect(err).ToNot(HaveOccurred())
+
+			_, err = os.Stat(filepath.Join(tempdir, ".gpupgrade", "fancy_step"))
+			Expect(err).ToNot(HaveOccurred())
+			expectStatus("fancy_step", pb.StepStatus_PENDING)
+		})
+
+		It("keeps the history of earlier runs", func() {
+			cm.MarkInProgress("fancy_step")
+			cm.MarkFailed("fancy_step", errors.New("it broke"))
+			cm.ResetStateDir("fancy_step")
+
+			history, err := store.History()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(history).To(HaveLen(3))
+			Expect(history[1].Error).To(Equal("it broke"))
 		})
 	})
 
 	Describe("MarkFailed", func() {
-		It("errors if in.progress file can't be removed", func() {
-			utils.System.Remove = func(string) error {
-				return errors.New("remove failed")
-			}
-			cm := upgradestatus.NewChecklistManager("/some/random/dir")
-			err := cm.MarkFailed("step")
-			Expect(err.Error()).To(ContainSubstring("remove failed"))
-		})
-		It("errors if failed file can't be created", func() {
-			utils.System.Remove = func(string) error {
-				return nil
-			}
-			utils.System.OpenFile = func(string, int, os.FileMode) (*os.File, error) {
-				return nil, errors.New("open file failed")
-			}
-			cm := upgradestatus.NewChecklistManager("/some/random/dir")
-			err := cm.MarkFailed("step")
-			Expect(err.Error()).To(ContainSubstring("open file failed"))
+		It("records the step as failed, along with the cause", func() {
+			cm.MarkInProgress("step")
+			err := cm.MarkFailed("step", errors.New("gpstart exited 1"))
+			Expect(err).ToNot(HaveOccurred())
+
+			expectStatus("step", pb.StepStatus_FAILED)
+			latest, err := store.Latest("step")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(latest.Error).To(Equal("gpstart exited 1"))
 		})
-		It("returns nil if nothing fails", func() {
-			utils.System.Remove = func(string) error {
-				return nil
-			}
-			utils.System.OpenFile = func(string, int, os.FileMode) (*os.File, error) {
-				return nil, nil
+
+		It("errors if the journal can't be written", func() {
+			utils.System.Rename = func(string, string) error {
+				return errors.New("rename failed")
 			}
-			cm := upgradestatus.NewChecklistManager("/some/random/dir")
-			err := cm.MarkFailed("step")
-			Expect(err).ToNot(HaveOccurred())
+			err := cm.MarkFailed("step", nil)
+			Expect(err).To(MatchError("rename failed"))
 		})
 	})
 
 	Describe("MarkComplete", func() {
-		It("errors if in.progress file can't be removed", func() {
-			utils.System.Remove = func(string) error {
-				return errors.New("remove failed")
-			}
-			cm := upgradestatus.NewChecklistManager("/some/random/dir")
-			err := cm.MarkFailed("step")
-			Expect(err.Error()).To(ContainSubstring("remove failed"))
-		})
-		It("errors if completed file can't be created", func() {
-			utils.System.Remove = func(string) error {
-				return nil
-			}
-			utils.System.OpenFile = func(string, int, os.FileMode) (*os.File, error) {
-				return nil, errors.New("open file failed")
-			}
-			cm := upgradestatus.NewChecklistManager("/some/random/dir")
+		It("goes straight from running to complete", func() {
+			cm.MarkInProgress("step")
 			err := cm.MarkComplete("step")
-			Expect(err.Error()).To(ContainSubstring("open file failed"))
+			Expect(err).ToNot(HaveOccurred())
+
+			expectStatus("step", pb.StepStatus_COMPLETE)
 		})
-		It("returns nil if nothing fails", func() {
-			utils.System.Remove = func(string) error {
-				return nil
-			}
+
+		It("errors if the journal can't be written", func() {
 			utils.System.OpenFile = func(string, int, os.FileMode) (*os.File, error) {
-				return nil, nil
+				return nil, errors.New("open file failed")
 			}
-			cm := upgradestatus.NewChecklistManager("/some/random/dir")
 			err := cm.MarkComplete("step")
-			Expect(err).ToNot(HaveOccurred())
+			Expect(err).To(MatchError("open file failed"))
 		})
 	})
 })
@@ -0,0 +1,174 @@
+package upgradestatus
+
+import (
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	pb "github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/utils"
+
+	"github.com/greenplum-db/gp-common-go-libs/gplog"
+)
+
+// TotalPGUpgradePhases is the number of progress reports that a complete run
+// of pg_upgrade --progress writes. It is only used to estimate how far along a
+// running pg_upgrade is.
+var TotalPGUpgradePhases = 14
+
+// progressReport is a single <n>.inprogress or <n>.done file written by
+// pg_upgrade --progress. Each line in it has the form
+//
+//	<seconds since the epoch>;<message>;
+type progressReport struct {
+	sequence  int
+	done      bool
+	timestamp int64
+	message   string
+}
+
+// GetProgress parses the progress files that pg_upgrade has written so far
+// into the phase it is in, how many phases it has finished and how long it
+// has been running. status is the status returned by GetStatus; it decides
+// whether the elapsed time is still growing. nil is returned if pg_upgrade
+// hasn't reported any progress.
+func (c *ConvertMaster) GetProgress(status pb.StepStatus) *pb.PgUpgradeProgress {
+	reports := readProgressReports(c.pgUpgradePath)
+	if len(reports) == 0 {
+		return nil
+	}
+
+	progress := &pb.PgUpgradeProgress{}
+
+	var current *progressReport
+	for i := range reports {
+		report := &reports[i]
+		if report.done {
+			progress.PhasesDone++
+		}
+		if current == nil || current.done || !report.done {
+			current = report
+		}
+	}
+	progress.Phase = current.message
+
+	progress.TotalPhases = int32(TotalPGUpgradePhases)
+	if progress.PhasesDone > progress.TotalPhases {
+		progress.TotalPhases = progress.PhasesDone
+	}
+
+	if status == pb.StepStatus_COMPLETE {
+		progress.TotalPhases = progress.PhasesDone
+		progress.PercentComplete = 100
+	} else {
+		// Don't claim to be finished before pg_upgrade says so.
+		progress.PercentComplete = progress.PhasesDone * 100 / progress.TotalPhases
+		if progress.PercentComplete > 99 {
+			progress.PercentComplete = 99
+		}
+	}
+
+	start, end := reportTimes(reports)
+	if status == pb.StepStatus_RUNNING {
+		end = utils.System.Now().Unix()
+	}
+	if start != 0 && end > start {
+		progress.ElapsedSeconds = end - start
+	}
+
+	return progress
+}
+
+// GetTiming returns when pg_upgrade started and, once it has stopped running,
+// when it stopped, in seconds since the epoch. They come from its record if
+// there is one, and otherwise from its first and last progress reports. Either
+// is zero if it isn't known.
+func (c *ConvertMaster) GetTiming(status pb.StepStatus) (start int64, end int64) {
+	if c.record != nil {
+		start = c.record.StartTime.Unix()
+		if !c.record.EndTime.IsZero() {
+			end = c.record.EndTime.Unix()
+		}
+	} else {
+		start, end = reportTimes(readProgressReports(c.pgUpgradePath))
+	}
+	if status != pb.StepStatus_COMPLETE && status != pb.StepStatus_FAILED {
+		end = 0
+	}
+
+	return start, end
+}
+
+// reportTimes returns the earliest and latest timestamps of reports.
+func reportTimes(reports []progressReport) (start int64, end int64) {
+	for _, report := range reports {
+		if report.timestamp != 0 && (start == 0 || report.timestamp < start) {
+			start = report.timestamp
+		}
+		if report.timestamp > end {
+			end = report.timestamp
+		}
+	}
+
+	return start, end
+}
+
+// readProgressReports returns the progress reports in pgUpgradePath, in the
+// order pg_upgrade wrote them.
+func readProgressReports(pgUpgradePath string) []progressReport {
+	var reports []progressReport
+	for _, suffix := range []string{".inprogress", ".done"} {
+		files, err := utils.System.FilePathGlob(filepath.Join(pgUpgradePath, "*"+suffix))
+		if err != nil {
+			gplog.Error("could not list pg_upgrade progress files in %s: %s", pgUpgradePath, err)
+			continue
+		}
+
+		for _, file := range files {
+			contents, err := utils.System.ReadFile(file)
+			if err != nil {
+				gplog.Debug("could not read pg_upgrade progress file %s: %s", file, err)
+				continue
+			}
+
+			report := parseProgressReport(string(contents))
+			report.done = suffix == ".done"
+			report.sequence, err = strconv.Atoi(strings.TrimSuffix(filepath.Base(file), suffix))
+			if err != nil {
+				report.sequence = -1
+			}
+			reports = append(reports, report)
+		}
+	}
+
+	sort.SliceStable(reports, func(i, j int) bool {
+		return reports[i].sequence < reports[j].sequence
+	})
+
+	return reports
+}
+
+// parseProgressReport extracts the timestamp and message from the last line
+// of a progress file. Lines that don't start with a timestamp are taken to be
+// just a message.
+func parseProgressReport(contents string) progressReport {
+	var report progressReport
+
+	lines := strings.Split(strings.TrimSpace(contents), "\n")
+	fields := strings.Split(strings.TrimSpace(lines[len(lines)-1]), ";")
+
+	timestamp, err := strconv.ParseInt(fields[0], 10, 64)
+	if err == nil && len(fields) > 1 {
+		report.timestamp = timestamp
+		fields = fields[1:]
+	}
+
+	for _, field := range fields {
+		if field = strings.TrimSpace(field); field != "" {
+			report.message = field
+		}
+	}
+
+	return report
+}
@@ -0,0 +1,109 @@
+package upgradestatus_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/greenplum-db/gpupgrade/hub/upgradestatus"
+	pb "github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/testutils"
+	"github.com/greenplum-db/gpupgrade/utils"
+
+	"github.com/greenplum-db/gp-common-go-libs/testhelper"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("pg_upgrade progress", func() {
+	var (
+		dir     string
+		subject upgradestatus.ConvertMaster
+	)
+
+	BeforeEach(func() {
+		testhelper.SetupTestLogger()
+
+		var err error
+		dir, err = ioutil.TempDir("", "")
+		Expect(err).ToNot(HaveOccurred())
+
+		commandExecer := &testutils.FakeCommandExecer{}
+		commandExecer.SetOutput(&testutils.FakeCommand{})
+		subject = upgradestatus.NewPGUpgradeStatusChecker(dir, "/old/datadir", commandExecer.Exec)
+
+		utils.System.Now = func() time.Time {
+			return time.Unix(1000, 0)
+		}
+	})
+
+	AfterEach(func() {
+		utils.System = utils.InitializeSystemFunctions()
+		os.RemoveAll(dir)
+	})
+
+	writeReport := func(name string, contents string) {
+		err := ioutil.WriteFile(filepath.Join(dir, name), []byte(contents), 0600)
+		Expect(err).ToNot(HaveOccurred())
+	}
+
+	It("returns nil when pg_upgrade has not reported any progress", func() {
+		Expect(subject.GetProgress(pb.StepStatus_RUNNING)).To(BeNil())
+	})
+
+	It("reports the phase in progress, the phases done and the time since pg_upgrade started", func() {
+		writeReport("1.done", "900;Performing Consistency Checks;\n")
+		writeReport("2.done", "910;Analyzing all rows in the new cluster;\n")
+		writeReport("3.inprogress", "950;Copying user relation files;\n")
+
+		progress := subject.GetProgress(pb.StepStatus_RUNNING)
+		Expect(progress.GetPhase()).To(Equal("Copying user relation files"))
+		Expect(progress.GetPhasesDone()).To(Equal(int32(2)))
+		Expect(progress.GetTotalPhases()).To(Equal(int32(upgradestatus.TotalPGUpgradePhases)))
+		Expect(progress.GetPercentComplete()).To(Equal(int32(2 * 100 / upgradestatus.TotalPGUpgradePhases)))
+		Expect(progress.GetElapsedSeconds()).To(Equal(int64(100)))
+	})
+
+	It("orders the reports by their number rather than by name", func() {
+		writeReport("9.done", "900;Checking cluster compatibility;\n")
+		writeReport("10.done", "910;Creating catalog dump;\n")
+
+		progress := subject.GetProgress(pb.StepStatus_FAILED)
+		Expect(progress.GetPhase()).To(Equal("Creating catalog dump"))
+		Expect(progress.GetElapsedSeconds()).To(Equal(int64(10)))
+	})
+
+	It("reports 100% once pg_upgrade is complete", func() {
+		writeReport("1.done", "900;Performing Consistency Checks;\n")
+		writeReport("2.done", "960;Upgrade complete;\n")
+
+		progress := subject.GetProgress(pb.StepStatus_COMPLETE)
+		Expect(progress.GetPhase()).To(Equal("Upgrade complete"))
+		Expect(progress.GetPhasesDone()).To(Equal(int32(2)))
+		Expect(progress.GetTotalPhases()).To(Equal(int32(2)))
+		Expect(progress.GetPercentComplete()).To(Equal(int32(100)))
+		Expect(progress.GetElapsedSeconds()).To(Equal(int64(60)))
+	})
+
+	It("does not report 100% before pg_upgrade is complete", func() {
+		for i := 1; i <= upgradestatus.TotalPGUpgradePhases+2; i++ {
+			writeReport(fmt.Sprintf("%d.done", i), "900;phase;\n")
+		}
+
+		progress := subject.GetProgress(pb.StepStatus_RUNNING)
+		Expect(progress.GetPhasesDone()).To(Equal(int32(upgradestatus.TotalPGUpgradePhases + 2)))
+		Expect(progress.GetTotalPhases()).To(Equal(progress.GetPhasesDone()))
+		Expect(progress.GetPercentComplete()).To(Equal(int32(99)))
+	})
+
+	It("uses the whole line as the phase when there is no timestamp", func() {
+		writeReport(".inprogress", "Restoring global objects in the new cluster\n")
+
+		progress := subject.GetProgress(pb.StepStatus_RUNNING)
+		Expect(progress.GetPhase()).To(Equal("Restoring global objects in the new cluster"))
+		Expect(progress.GetElapsedSeconds()).To(Equal(int64(0)))
+	})
+})
@@ -19,6 +19,7 @@ type ConvertMaster struct {
 	pgUpgradePath string
 	oldDataDir    string
 	commandExecer helpers.CommandExecer
+	record        *utils.ProcessRecord
 }
 
 func NewPGUpgradeStatusChecker(pgUpgradePath, oldDataDir string, execer helpers.CommandExecer) ConvertMaster {
@@ -29,6 +30,13 @@ func NewPGUpgradeStatusChecker(pgUpgradePath, oldDataDir string, execer helpers.
 	}
 }
 
+// UseRecord has the checker answer from the record that the process
+// supervisor kept of pg_upgrade, rather than from pgrep and the progress files
+// alone. A nil record is ignored.
+func (c *ConvertMaster) UseRecord(record *utils.ProcessRecord) {
+	c.record = record
+}
+
 /*
  assumptions here are:
 	- pg_upgrade will not fail without error before writing an inprogress file
@@ -46,7 +54,14 @@ func (c *ConvertMaster) GetStatus() *pb.UpgradeStepStatus {
 		return masterUpgradeStatus
 	}
 
-	if c.pgUpgradeRunning() {
+	if c.record != nil {
+		if status, ok := c.recordedStatus(); ok {
+			return &pb.UpgradeStepStatus{
+				Step:   pb.UpgradeSteps_MASTERUPGRADE,
+				Status: status,
+			}
+		}
+	} else if c.pgUpgradeRunning() {
 		masterUpgradeStatus = &pb.UpgradeStepStatus{
 			Step:   pb.UpgradeSteps_MASTERUPGRADE,
 			Status: pb.StepStatus_RUNNING,
@@ -69,10 +84,26 @@ func (c *ConvertMaster) GetStatus() *pb.UpgradeStepStatus {
 	return masterUpgradeStatus
 }
 
+// recordedStatus returns the status that the record shows, if it shows one. A
+// record of a pg_upgrade that exited while nobody was waiting for it, which
+// leaves its exit code unknown, doesn't.
+func (c *ConvertMaster) recordedStatus() (pb.StepStatus, bool) {
+	switch {
+	case c.record.Running():
+		return pb.StepStatus_RUNNING, true
+	case c.record.EndTime.IsZero():
+		return pb.StepStatus_PENDING, false
+	case c.record.ExitCode == 0 && !c.record.Cancelled:
+		return pb.StepStatus_COMPLETE, true
+	default:
+		return pb.StepStatus_FAILED, true
+	}
+}
+
 func (c *ConvertMaster) pgUpgradeRunning() bool {
 	//if pgrep doesnt find target, ExecCmdOutput will return empty byte array and err.Error()="exit status 1"
-	command := fmt.Sprintf("pg_upgrade | grep --old-datadir=%s", c.oldDataDir)
-	pgUpgradePids, err := c.commandExecer("pgrep", command).Output()
+	pattern := fmt.Sprintf("pg_upgrade.*--old-datadir=%s", c.oldDataDir)
+	pgUpgradePids, err := c.commandExecer("pgrep", "-f", pattern).Output()
 	if err == nil && len(pgUpgradePids) != 0 {
 		return true
 	}
@@ -3,7 +3,10 @@ package upgradestatus_test
 import (
 	"io/ioutil"
 	"os"
+	"os/exec"
 	"strings"
+	"syscall"
+	"time"
 
 	"github.com/greenplum-db/gpupgrade/hub/upgradestatus"
 	pb "github.com/greenplum-db/gpupgrade/idl"
@@ -110,7 +113,7 @@ var _ = Describe("pg_upgrade status checker", func() {
 		status := subject.GetStatus()
 		Expect(status.Status).To(Equal(pb.StepStatus_COMPLETE))
 
-		Expect(commandExecer.Calls()).To(Equal([]string{"pgrep pg_upgrade | grep --old-datadir=/data/dir"}))
+		Expect(commandExecer.Calls()).To(Equal([]string{"pgrep -f pg_upgrade.*--old-datadir=/data/dir"}))
 	})
 
 	// We are assuming that no inprogress actually exists in the path we're using,
@@ -130,4 +133,53 @@ var _ = Describe("pg_upgrade status checker", func() {
 		status := subject.GetStatus()
 		Expect(status.Status).To(Equal(pb.StepStatus_FAILED))
 	})
+
+	Context("with the record of pg_upgrade", func() {
+		BeforeEach(func() {
+			utils.System.Stat = func(name string) (os.FileInfo, error) {
+				return nil, nil
+			}
+			utils.System.IsNotExist = func(error) bool {
+				return false
+			}
+		})
+
+		statusFor := func(record *utils.ProcessRecord) pb.StepStatus {
+			subject := upgradestatus.NewPGUpgradeStatusChecker("/tmp", "/data/dir", commandExecer.Exec)
+			subject.UseRecord(record)
+			return subject.GetStatus().Status
+		}
+
+		It("answers from how pg_upgrade exited, without looking for it with pgrep", func() {
+			ended := time.Now()
+
+			Expect(statusFor(&utils.ProcessRecord{EndTime: ended, ExitCode: 0})).To(Equal(pb.StepStatus_COMPLETE))
+			Expect(statusFor(&utils.ProcessRecord{EndTime: ended, ExitCode: 1})).To(Equal(pb.StepStatus_FAILED))
+			Expect(statusFor(&utils.ProcessRecord{EndTime: ended, ExitCode: -1, Cancelled: true})).To(Equal(pb.StepStatus_FAILED))
+
+			Expect(commandExecer.GetNumInvocations()).To(Equal(0))
+		})
+
+		It("is RUNNING while pg_upgrade runs", func() {
+			cmd := exec.Command("sleep", "60")
+			cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+			Expect(cmd.Start()).To(Succeed())
+			defer func() {
+				cmd.Process.Kill()
+				cmd.Wait()
+			}()
+
+			Expect(statusFor(&utils.ProcessRecord{PID: cmd.Process.Pid})).To(Equal(pb.StepStatus_RUNNING))
+		})
+
+		It("falls back to the progress files when pg_upgrade exited unobserved", func() {
+			utils.System.FilePathGlob = func(glob string) ([]string, error) {
+				return nil, nil
+			}
+
+			// A PID that no process has.
+			Expect(statusFor(&utils.ProcessRecord{PID: 1 << 30})).To(Equal(pb.StepStatus_FAILED))
+			Expect(commandExecer.GetNumInvocations()).To(Equal(0))
+		})
+	})
 })
@@ -11,7 +11,7 @@ import (
 
 	"github.com/greenplum-db/gp-common-go-libs/testhelper"
 
-	"fmt"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 
@@ -19,84 +19,65 @@ import (
 )
 
 var _ = Describe("Upgradestatus/Seginstall", func() {
-	var testLog *gbytes.Buffer
+	var (
+		testLog *gbytes.Buffer
+		dir     string
+		store   *upgradestatus.StateStore
+	)
 
 	BeforeEach(func() {
 		// FIXME: redirect stdout/err to GingkoWriter instead of swallowing it
 		_, _, testLog = testhelper.SetupTestLogger()
+
+		var err error
+		dir, err = ioutil.TempDir("", "")
+		Expect(err).ToNot(HaveOccurred())
+		store = upgradestatus.NewStateStore(dir)
 	})
 	AfterEach(func() {
 		utils.System = utils.InitializeSystemFunctions()
+		os.RemoveAll(dir)
 	})
 
-	It("Reports PENDING if no directory exists", func() {
-		stateChecker := upgradestatus.NewStateCheck("/fake/path", pb.UpgradeSteps_SEGINSTALL)
+	It("Reports PENDING if no journal exists", func() {
+		stateChecker := upgradestatus.NewStateCheck("/fake/path", "seginstall", pb.UpgradeSteps_SEGINSTALL)
 		upgradeStepStatus := stateChecker.GetStatus()
 		Expect(upgradeStepStatus.Step).To(Equal(pb.UpgradeSteps_SEGINSTALL))
 		Expect(upgradeStepStatus.Status).To(Equal(pb.StepStatus_PENDING))
 	})
-	It("Reports RUNNING if statedir exists and contains inprogress file", func() {
-		fakePath := "/fake/path"
-		utils.System.Stat = func(name string) (os.FileInfo, error) {
-			if name == fakePath {
-				return nil, nil
-			}
-			return nil, errors.New("unexpected Stat call")
-		}
-		utils.System.FilePathGlob = func(glob string) ([]string, error) {
-			if glob == fakePath+"/*" {
-				return []string{filepath.Join(fakePath, "in.progress")}, nil
-			}
-			return nil, errors.New("didn't match expected glob pattern")
-		}
-		stateChecker := upgradestatus.NewStateCheck(fakePath, pb.UpgradeSteps_SEGINSTALL)
+	It("Reports PENDING if the step has not been recorded", func() {
+		store.Record("other-step", pb.StepStatus_COMPLETE, nil)
+
+		stateChecker := upgradestatus.NewStateCheck(dir, "seginstall", pb.UpgradeSteps_SEGINSTALL)
+		upgradeStepStatus := stateChecker.GetStatus()
+		Expect(upgradeStepStatus.Status).To(Equal(pb.StepStatus_PENDING))
+	})
+	It("Reports RUNNING if the step was last marked in progress", func() {
+		store.Record("seginstall", pb.StepStatus_RUNNING, nil)
+
+		stateChecker := upgradestatus.NewStateCheck(dir, "seginstall", pb.UpgradeSteps_SEGINSTALL)
 		upgradeStepStatus := stateChecker.GetStatus()
 		Expect(upgradeStepStatus.Step).To(Equal(pb.UpgradeSteps_SEGINSTALL))
 		Expect(upgradeStepStatus.Status).To(Equal(pb.StepStatus_RUNNING))
 	})
-	It("Reports FAILED if statedir exists and contains failed file", func() {
-		fakePath := "/fake/path"
-		utils.System.Stat = func(name string) (os.FileInfo, error) {
-			if name == fakePath {
-				return nil, nil
-			}
-			return nil, errors.New("unexpected Stat call")
-		}
-		utils.System.FilePathGlob = func(glob string) ([]string, error) {
-			if glob == fakePath+"/*" {
-				return []string{filepath.Join(fakePath, "failed")}, nil
-			}
-			return nil, errors.New("didn't match expected glob pattern")
-		}
-		stateChecker := upgradestatus.NewStateCheck(fakePath, pb.UpgradeSteps_SEGINSTALL)
+	It("Reports FAILED if the step was last marked failed", func() {
+		store.Record("seginstall", pb.StepStatus_RUNNING, nil)
+		store.Record("seginstall", pb.StepStatus_FAILED, errors.New("no agent"))
+
+		stateChecker := upgradestatus.NewStateCheck(dir, "seginstall", pb.UpgradeSteps_SEGINSTALL)
 		upgradeStepStatus := stateChecker.GetStatus()
 		Expect(upgradeStepStatus.Step).To(Equal(pb.UpgradeSteps_SEGINSTALL))
 		Expect(upgradeStepStatus.Status).To(Equal(pb.StepStatus_FAILED))
 	})
 
-	It("logs an error if there is more than one file at the specified path", func() {
-		overabundantDirectory := "/full/of/stuff"
-		utils.System.Stat = func(name string) (os.FileInfo, error) {
-			if name == overabundantDirectory {
-				return nil, nil
-			}
-			return nil, errors.New("unexpected Stat call")
-		}
+	It("logs an error and reports PENDING if the journal can't be read", func() {
+		err := ioutil.WriteFile(filepath.Join(dir, upgradestatus.StateFileName), []byte("{not json"), 0600)
+		Expect(err).ToNot(HaveOccurred())
 
-		utils.System.FilePathGlob = func(glob string) ([]string, error) {
-			if glob == overabundantDirectory+"/*" {
-				return []string{"first", "second"}, nil
-			}
-			return nil, errors.New("didn't match expected glob pattern")
-		}
-		stateChecker := upgradestatus.NewStateCheck(overabundantDirectory, pb.UpgradeSteps_SEGINSTALL)
+		stateChecker := upgradestatus.NewStateCheck(dir, "seginstall", pb.UpgradeSteps_SEGINSTALL)
 		upgradeStepStatus := stateChecker.GetStatus()
 
-		// This is a little brittle, sorry...
-		expectederr := fmt.Sprintf("%s has more than one file", overabundantDirectory)
-		Expect(testLog).To(gbytes.Say(expectederr))
-
-		// The installation should still be marked pending.
+		Expect(testLog).To(gbytes.Say("Couldn't read state of seginstall"))
 		Expect(upgradeStepStatus.Step).To(Equal(pb.UpgradeSteps_SEGINSTALL))
 		Expect(upgradeStepStatus.Status).To(Equal(pb.StepStatus_PENDING))
 	})
@@ -0,0 +1,300 @@
+package upgradestatus
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	pb "github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/utils"
+
+	"github.com/greenplum-db/gp-common-go-libs/gplog"
+)
+
+const (
+	// StateFileName is the name of the journal, kept in the hub's state
+	// directory, that records every step transition of an upgrade.
+	StateFileName = "upgrade_state.json"
+
+	// StateFileVersion is the current layout version of the journal. Bump it
+	// whenever the layout changes in a way older readers can't handle.
+	StateFileVersion = 1
+)
+
+// All hub goroutines share a single journal per state directory, so writers
+// are serialized across every StateStore in the process.
+var stateFileLock sync.Mutex
+
+// Transition is a single entry in the journal: a step moving to a new status.
+type Transition struct {
+	Step   string    `json:"step"`
+	Status string    `json:"status"`
+	Time   time.Time `json:"time"`
+	Host   string    `json:"host"`
+	Error  string    `json:"error,omitempty"`
+}
+
+// StepStatus converts the recorded status back into its gRPC representation.
+// Unknown statuses, such as those written by a newer hub, are reported as
+// PENDING.
+func (t Transition) StepStatus() pb.StepStatus {
+	status, ok := pb.StepStatus_value[t.Status]
+	if !ok {
+		return pb.StepStatus_PENDING
+	}
+	return pb.StepStatus(status)
+}
+
+type stateFile struct {
+	Version     int          `json:"version"`
+	Transitions []Transition `json:"transitions"`
+}
+
+// StateStore reads and appends to the upgrade journal. Every write replaces
+// the whole file with a rename, so readers only ever see a complete journal:
+// a step goes straight from RUNNING to COMPLETE or FAILED.
+type StateStore struct {
+	path string
+}
+
+func NewStateStore(stateDirPath string) *StateStore {
+	return &StateStore{
+		path: filepath.Join(stateDirPath, StateFileName),
+	}
+}
+
+func (s *StateStore) Path() string {
+	return s.path
+}
+
+// Record appends a transition of step to status. cause may be nil; if it
+// isn't, its message is saved alongside the transition.
+func (s *StateStore) Record(step string, status pb.StepStatus, cause error) error {
+	stateFileLock.Lock()
+	defer stateFileLock.Unlock()
+
+	state, err := s.read()
+	if err != nil {
+		return err
+	}
+
+	host, err := utils.GetHost()
+	if err != nil {
+		gplog.Error("could not determine hostname for state journal: %s", err)
+	}
+
+	transition := Transition{
+		Step:   step,
+		Status: status.String(),
+		Time:   utils.System.Now(),
+		Host:   host,
+	}
+	if cause != nil {
+		transition.Error = cause.Error()
+	}
+
+	state.Transitions = append(state.Transitions, transition)
+	return s.write(state)
+}
+
+// ErrInterrupted is recorded as the cause of failure for steps that were still
+// running when the hub went away.
+var ErrInterrupted = errors.New("interrupted: the hub stopped while this step was running")
+
+// MarkInterrupted records every step whose latest transition is RUNNING as
+// FAILED with ErrInterrupted, and returns their names. It is meant to be
+// called as the hub starts, when nothing can be running any more; otherwise
+// those steps would be reported as RUNNING forever.
+func (s *StateStore) MarkInterrupted() ([]string, error) {
+	stateFileLock.Lock()
+	defer stateFileLock.Unlock()
+
+	state, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+
+	latest := make(map[string]Transition)
+	var steps []string
+	for _, transition := range state.Transitions {
+		if _, ok := latest[transition.Step]; !ok {
+			steps = append(steps, transition.Step)
+		}
+		latest[transition.Step] = transition
+	}
+
+	host, err := utils.GetHost()
+	if err != nil {
+		gplog.Error("could not determine hostname for state journal: %s", err)
+	}
+
+	var interrupted []string
+	for _, step := range steps {
+		if latest[step].StepStatus() != pb.StepStatus_RUNNING {
+			continue
+		}
+
+		state.Transitions = append(state.Transitions, Transition{
+			Step:   step,
+			Status: pb.StepStatus_FAILED.String(),
+			Time:   utils.System.Now(),
+			Host:   host,
+			Error:  ErrInterrupted.Error(),
+		})
+		interrupted = append(interrupted, step)
+	}
+
+	if len(interrupted) == 0 {
+		return nil, nil
+	}
+	return interrupted, s.write(state)
+}
+
+// Status returns the most recently recorded status of step, or PENDING if the
+// step has never been recorded.
+func (s *StateStore) Status(step string) (pb.StepStatus, error) {
+	transition, err := s.Latest(step)
+	if err != nil || transition == nil {
+		return pb.StepStatus_PENDING, err
+	}
+
+	return transition.StepStatus(), nil
+}
+
+// Latest returns the most recent transition of step, or nil if the step has
+// never been recorded.
+func (s *StateStore) Latest(step string) (*Transition, error) {
+	history, err := s.History()
+	if err != nil {
+		return nil, err
+	}
+
+	for i := len(history) - 1; i >= 0; i-- {
+		if history[i].Step == step {
+			return &history[i], nil
+		}
+	}
+
+	return nil, nil
+}
+
+// StepTiming is when the latest run of a step started and finished, along
+// with how long the earlier runs of the step that completed took.
+type StepTiming struct {
+	Start             time.Time
+	End               time.Time // zero while the latest run hasn't finished
+	PreviousDurations []time.Duration
+}
+
+// Timing works out the StepTiming of step from its transitions. A run starts
+// when the step becomes RUNNING, and ends at the COMPLETE or FAILED that
+// follows. nil is returned if the step has never been run.
+func (s *StateStore) Timing(step string) (*StepTiming, error) {
+	history, err := s.History()
+	if err != nil {
+		return nil, err
+	}
+
+	var timing *StepTiming
+	var previous []time.Duration
+	completed := false
+	for _, transition := range history {
+		if transition.Step != step {
+			continue
+		}
+
+		switch transition.StepStatus() {
+		case pb.StepStatus_RUNNING:
+			if timing != nil && completed {
+				previous = append(previous, timing.End.Sub(timing.Start))
+			}
+			timing = &StepTiming{Start: transition.Time}
+			completed = false
+
+		case pb.StepStatus_COMPLETE, pb.StepStatus_FAILED:
+			if timing != nil && timing.End.IsZero() {
+				timing.End = transition.Time
+				completed = transition.StepStatus() == pb.StepStatus_COMPLETE
+			}
+		}
+	}
+
+	if timing != nil {
+		timing.PreviousDurations = previous
+	}
+	return timing, nil
+}
+
+// History returns every transition recorded so far, oldest first.
+func (s *StateStore) History() ([]Transition, error) {
+	state, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+
+	return state.Transitions, nil
+}
+
+func (s *StateStore) read() (*stateFile, error) {
+	contents, err := utils.System.ReadFile(s.path)
+	if err != nil {
+		if utils.System.IsNotExist(err) {
+			return &stateFile{Version: StateFileVersion}, nil
+		}
+		return nil, err
+	}
+
+	state := &stateFile{}
+	err = json.Unmarshal(contents, state)
+	if err != nil {
+		return nil, fmt.Errorf("state file %s is corrupt: %s", s.path, err)
+	}
+
+	if state.Version > StateFileVersion {
+		return nil, fmt.Errorf("state file %s has version %d, but only versions up to %d are supported",
+			s.path, state.Version, StateFileVersion)
+	}
+
+	return state, nil
+}
+
+// write lays the journal down next to its final location and renames it into
+// place, so that a crash mid-write leaves the previous journal intact.
+func (s *StateStore) write(state *stateFile) error {
+	state.Version = StateFileVersion
+
+	contents, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	err = utils.System.MkdirAll(filepath.Dir(s.path), 0700)
+	if err != nil {
+		return err
+	}
+
+	tmpPath := s.path + ".tmp"
+	f, err := utils.System.OpenFile(tmpPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+
+	_, err = f.Write(contents)
+	if err == nil {
+		err = f.Sync()
+	}
+	closeErr := f.Close()
+	if err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		utils.System.Remove(tmpPath)
+		return err
+	}
+
+	return utils.System.Rename(tmpPath, s.path)
+}
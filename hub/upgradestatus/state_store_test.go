@@ -0,0 +1,179 @@
+package upgradestatus_test
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/greenplum-db/gpupgrade/hub/upgradestatus"
+	pb "github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/utils"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("upgradestatus/StateStore", func() {
+	var (
+		dir   string
+		store *upgradestatus.StateStore
+	)
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "")
+		Expect(err).ToNot(HaveOccurred())
+
+		store = upgradestatus.NewStateStore(dir)
+	})
+
+	AfterEach(func() {
+		utils.System = utils.InitializeSystemFunctions()
+		os.RemoveAll(dir)
+	})
+
+	It("records the time, host and error of every transition", func() {
+		now := time.Date(2018, 6, 1, 12, 0, 0, 0, time.UTC)
+		utils.System.Now = func() time.Time { return now }
+		utils.System.Hostname = func() (string, error) { return "mdw", nil }
+
+		Expect(store.Record("step", pb.StepStatus_RUNNING, nil)).To(Succeed())
+		Expect(store.Record("step", pb.StepStatus_FAILED, errors.New("oops"))).To(Succeed())
+
+		history, err := store.History()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(history).To(Equal([]upgradestatus.Transition{
+			{Step: "step", Status: "RUNNING", Time: now, Host: "mdw"},
+			{Step: "step", Status: "FAILED", Time: now, Host: "mdw", Error: "oops"},
+		}))
+	})
+
+	It("reports the latest status of each step independently", func() {
+		store.Record("one", pb.StepStatus_RUNNING, nil)
+		store.Record("two", pb.StepStatus_RUNNING, nil)
+		store.Record("one", pb.StepStatus_COMPLETE, nil)
+
+		status, err := store.Status("one")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(status).To(Equal(pb.StepStatus_COMPLETE))
+
+		status, err = store.Status("two")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(status).To(Equal(pb.StepStatus_RUNNING))
+	})
+
+	It("survives being reopened", func() {
+		store.Record("step", pb.StepStatus_COMPLETE, nil)
+
+		status, err := upgradestatus.NewStateStore(dir).Status("step")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(status).To(Equal(pb.StepStatus_COMPLETE))
+	})
+
+	It("leaves the previous journal in place if the new one can't be written", func() {
+		store.Record("step", pb.StepStatus_RUNNING, nil)
+
+		utils.System.Rename = func(string, string) error {
+			return errors.New("rename failed")
+		}
+		err := store.Record("step", pb.StepStatus_COMPLETE, nil)
+		Expect(err).To(HaveOccurred())
+
+		status, err := store.Status("step")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(status).To(Equal(pb.StepStatus_RUNNING))
+	})
+
+	It("refuses to read a journal written by a newer version", func() {
+		contents := fmt.Sprintf(`{"version": %d, "transitions": []}`, upgradestatus.StateFileVersion+1)
+		err := ioutil.WriteFile(filepath.Join(dir, upgradestatus.StateFileName), []byte(contents), 0600)
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = store.History()
+		Expect(err).To(HaveOccurred())
+
+		err = store.Record("step", pb.StepStatus_RUNNING, nil)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("reports a step with an unrecognised status as pending", func() {
+		contents := `{"version": 1, "transitions": [{"step": "step", "status": "PAUSED"}]}`
+		err := ioutil.WriteFile(filepath.Join(dir, upgradestatus.StateFileName), []byte(contents), 0600)
+		Expect(err).ToNot(HaveOccurred())
+
+		status, err := store.Status("step")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(status).To(Equal(pb.StepStatus_PENDING))
+	})
+
+	It("marks steps left running as interrupted", func() {
+		store.Record("finished", pb.StepStatus_RUNNING, nil)
+		store.Record("finished", pb.StepStatus_COMPLETE, nil)
+		store.Record("abandoned", pb.StepStatus_RUNNING, nil)
+
+		interrupted, err := store.MarkInterrupted()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(interrupted).To(Equal([]string{"abandoned"}))
+
+		latest, err := store.Latest("abandoned")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(latest.StepStatus()).To(Equal(pb.StepStatus_FAILED))
+		Expect(latest.Error).To(Equal(upgradestatus.ErrInterrupted.Error()))
+
+		status, err := store.Status("finished")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(status).To(Equal(pb.StepStatus_COMPLETE))
+
+		By("leaving the journal alone when nothing was running")
+		interrupted, err = store.MarkInterrupted()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(interrupted).To(BeEmpty())
+	})
+
+	Describe("Timing", func() {
+		var now time.Time
+
+		BeforeEach(func() {
+			now = time.Date(2018, 6, 1, 12, 0, 0, 0, time.UTC)
+			utils.System.Now = func() time.Time { return now }
+		})
+
+		recordAt := func(offset time.Duration, step string, status pb.StepStatus) {
+			now = time.Date(2018, 6, 1, 12, 0, 0, 0, time.UTC).Add(offset)
+			Expect(store.Record(step, status, nil)).To(Succeed())
+		}
+
+		It("returns nil for a step that has never run", func() {
+			timing, err := store.Timing("step")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(timing).To(BeNil())
+		})
+
+		It("reports the start of a run that hasn't finished", func() {
+			recordAt(0, "step", pb.StepStatus_RUNNING)
+
+			timing, err := store.Timing("step")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(timing.Start).To(Equal(now))
+			Expect(timing.End.IsZero()).To(BeTrue())
+		})
+
+		It("reports the latest run, and the durations of earlier runs that completed", func() {
+			recordAt(0, "step", pb.StepStatus_RUNNING)
+			recordAt(1*time.Minute, "step", pb.StepStatus_COMPLETE)
+			recordAt(2*time.Minute, "step", pb.StepStatus_RUNNING)
+			recordAt(3*time.Minute, "other", pb.StepStatus_RUNNING)
+			recordAt(5*time.Minute, "step", pb.StepStatus_FAILED)
+			recordAt(10*time.Minute, "step", pb.StepStatus_RUNNING)
+			recordAt(13*time.Minute, "step", pb.StepStatus_COMPLETE)
+
+			timing, err := store.Timing("step")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(timing.End.Sub(timing.Start)).To(Equal(3 * time.Minute))
+			Expect(timing.PreviousDurations).To(Equal([]time.Duration{1 * time.Minute}))
+		})
+	})
+})
@@ -0,0 +1,336 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: agent_to_hub.proto
+
+package idl
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+
+import (
+	context "golang.org/x/net/context"
+	grpc "google.golang.org/grpc"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+type RegisterAgentRequest struct {
+	Hostname             string   `protobuf:"bytes,1,opt,name=Hostname" json:"Hostname,omitempty"`
+	Version              string   `protobuf:"bytes,2,opt,name=Version" json:"Version,omitempty"`
+	StateDir             string   `protobuf:"bytes,3,opt,name=StateDir" json:"StateDir,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RegisterAgentRequest) Reset()         { *m = RegisterAgentRequest{} }
+func (m *RegisterAgentRequest) String() string { return proto.CompactTextString(m) }
+func (*RegisterAgentRequest) ProtoMessage()    {}
+func (*RegisterAgentRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_agent_to_hub_da154aeaf73039d8, []int{0}
+}
+func (m *RegisterAgentRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RegisterAgentRequest.Unmarshal(m, b)
+}
+func (m *RegisterAgentRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RegisterAgentRequest.Marshal(b, m, deterministic)
+}
+func (dst *RegisterAgentRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RegisterAgentRequest.Merge(dst, src)
+}
+func (m *RegisterAgentRequest) XXX_Size() int {
+	return xxx_messageInfo_RegisterAgentRequest.Size(m)
+}
+func (m *RegisterAgentRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RegisterAgentRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RegisterAgentRequest proto.InternalMessageInfo
+
+func (m *RegisterAgentRequest) GetHostname() string {
+	if m != nil {
+		return m.Hostname
+	}
+	return ""
+}
+
+func (m *RegisterAgentRequest) GetVersion() string {
+	if m != nil {
+		return m.Version
+	}
+	return ""
+}
+
+func (m *RegisterAgentRequest) GetStateDir() string {
+	if m != nil {
+		return m.StateDir
+	}
+	return ""
+}
+
+type RegisterAgentReply struct {
+	HeartbeatIntervalSeconds int64    `protobuf:"varint,1,opt,name=HeartbeatIntervalSeconds" json:"HeartbeatIntervalSeconds,omitempty"`
+	XXX_NoUnkeyedLiteral     struct{} `json:"-"`
+	XXX_unrecognized         []byte   `json:"-"`
+	XXX_sizecache            int32    `json:"-"`
+}
+
+func (m *RegisterAgentReply) Reset()         { *m = RegisterAgentReply{} }
+func (m *RegisterAgentReply) String() string { return proto.CompactTextString(m) }
+func (*RegisterAgentReply) ProtoMessage()    {}
+func (*RegisterAgentReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_agent_to_hub_da154aeaf73039d8, []int{1}
+}
+func (m *RegisterAgentReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RegisterAgentReply.Unmarshal(m, b)
+}
+func (m *RegisterAgentReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RegisterAgentReply.Marshal(b, m, deterministic)
+}
+func (dst *RegisterAgentReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RegisterAgentReply.Merge(dst, src)
+}
+func (m *RegisterAgentReply) XXX_Size() int {
+	return xxx_messageInfo_RegisterAgentReply.Size(m)
+}
+func (m *RegisterAgentReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_RegisterAgentReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RegisterAgentReply proto.InternalMessageInfo
+
+func (m *RegisterAgentReply) GetHeartbeatIntervalSeconds() int64 {
+	if m != nil {
+		return m.HeartbeatIntervalSeconds
+	}
+	return 0
+}
+
+// HeartbeatRequest carries the same details as the registration, since they
+// may change if the agent is restarted without the hub noticing.
+type HeartbeatRequest struct {
+	Hostname             string   `protobuf:"bytes,1,opt,name=Hostname" json:"Hostname,omitempty"`
+	Version              string   `protobuf:"bytes,2,opt,name=Version" json:"Version,omitempty"`
+	StateDir             string   `protobuf:"bytes,3,opt,name=StateDir" json:"StateDir,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *HeartbeatRequest) Reset()         { *m = HeartbeatRequest{} }
+func (m *HeartbeatRequest) String() string { return proto.CompactTextString(m) }
+func (*HeartbeatRequest) ProtoMessage()    {}
+func (*HeartbeatRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_agent_to_hub_da154aeaf73039d8, []int{2}
+}
+func (m *HeartbeatRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_HeartbeatRequest.Unmarshal(m, b)
+}
+func (m *HeartbeatRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_HeartbeatRequest.Marshal(b, m, deterministic)
+}
+func (dst *HeartbeatRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_HeartbeatRequest.Merge(dst, src)
+}
+func (m *HeartbeatRequest) XXX_Size() int {
+	return xxx_messageInfo_HeartbeatRequest.Size(m)
+}
+func (m *HeartbeatRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_HeartbeatRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_HeartbeatRequest proto.InternalMessageInfo
+
+func (m *HeartbeatRequest) GetHostname() string {
+	if m != nil {
+		return m.Hostname
+	}
+	return ""
+}
+
+func (m *HeartbeatRequest) GetVersion() string {
+	if m != nil {
+		return m.Version
+	}
+	return ""
+}
+
+func (m *HeartbeatRequest) GetStateDir() string {
+	if m != nil {
+		return m.StateDir
+	}
+	return ""
+}
+
+type HeartbeatReply struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *HeartbeatReply) Reset()         { *m = HeartbeatReply{} }
+func (m *HeartbeatReply) String() string { return proto.CompactTextString(m) }
+func (*HeartbeatReply) ProtoMessage()    {}
+func (*HeartbeatReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_agent_to_hub_da154aeaf73039d8, []int{3}
+}
+func (m *HeartbeatReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_HeartbeatReply.Unmarshal(m, b)
+}
+func (m *HeartbeatReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_HeartbeatReply.Marshal(b, m, deterministic)
+}
+func (dst *HeartbeatReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_HeartbeatReply.Merge(dst, src)
+}
+func (m *HeartbeatReply) XXX_Size() int {
+	return xxx_messageInfo_HeartbeatReply.Size(m)
+}
+func (m *HeartbeatReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_HeartbeatReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_HeartbeatReply proto.InternalMessageInfo
+
+func init() {
+	proto.RegisterType((*RegisterAgentRequest)(nil), "idl.RegisterAgentRequest")
+	proto.RegisterType((*RegisterAgentReply)(nil), "idl.RegisterAgentReply")
+	proto.RegisterType((*HeartbeatRequest)(nil), "idl.HeartbeatRequest")
+	proto.RegisterType((*HeartbeatReply)(nil), "idl.HeartbeatReply")
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// AgentToHubClient is the client API for AgentToHub service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type AgentToHubClient interface {
+	RegisterAgent(ctx context.Context, in *RegisterAgentRequest, opts ...grpc.CallOption) (*RegisterAgentReply, error)
+	Heartbeat(ctx context.Context, in *HeartbeatRequest, opts ...grpc.CallOption) (*HeartbeatReply, error)
+}
+
+type agentToHubClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewAgentToHubClient(cc *grpc.ClientConn) AgentToHubClient {
+	return &agentToHubClient{cc}
+}
+
+func (c *agentToHubClient) RegisterAgent(ctx context.Context, in *RegisterAgentRequest, opts ...grpc.CallOption) (*RegisterAgentReply, error) {
+	out := new(RegisterAgentReply)
+	err := c.cc.Invoke(ctx, "/idl.AgentToHub/RegisterAgent", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *agentToHubClient) Heartbeat(ctx context.Context, in *HeartbeatRequest, opts ...grpc.CallOption) (*HeartbeatReply, error) {
+	out := new(HeartbeatReply)
+	err := c.cc.Invoke(ctx, "/idl.AgentToHub/Heartbeat", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Server API for AgentToHub service
+
+type AgentToHubServer interface {
+	RegisterAgent(context.Context, *RegisterAgentRequest) (*RegisterAgentReply, error)
+	Heartbeat(context.Context, *HeartbeatRequest) (*HeartbeatReply, error)
+}
+
+func RegisterAgentToHubServer(s *grpc.Server, srv AgentToHubServer) {
+	s.RegisterService(&_AgentToHub_serviceDesc, srv)
+}
+
+func _AgentToHub_RegisterAgent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RegisterAgentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentToHubServer).RegisterAgent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/idl.AgentToHub/RegisterAgent",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentToHubServer).RegisterAgent(ctx, req.(*RegisterAgentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AgentToHub_Heartbeat_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HeartbeatRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentToHubServer).Heartbeat(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/idl.AgentToHub/Heartbeat",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentToHubServer).Heartbeat(ctx, req.(*HeartbeatRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _AgentToHub_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "idl.AgentToHub",
+	HandlerType: (*AgentToHubServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "RegisterAgent",
+			Handler:    _AgentToHub_RegisterAgent_Handler,
+		},
+		{
+			MethodName: "Heartbeat",
+			Handler:    _AgentToHub_Heartbeat_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "agent_to_hub.proto",
+}
+
+func init() { proto.RegisterFile("agent_to_hub.proto", fileDescriptor_agent_to_hub_da154aeaf73039d8) }
+
+var fileDescriptor_agent_to_hub_da154aeaf73039d8 = []byte{
+	// 239 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x91, 0x41, 0x4b, 0xc4, 0x30,
+	0x14, 0x84, 0xad, 0x05, 0x75, 0x1f, 0x28, 0xcb, 0x53, 0x31, 0xf6, 0x24, 0x3d, 0x79, 0xea, 0x41,
+	0x4f, 0x7a, 0x13, 0x14, 0xea, 0x4d, 0xba, 0xe2, 0x75, 0x49, 0xcd, 0x63, 0x37, 0x10, 0x93, 0x98,
+	0xbc, 0x0a, 0xfd, 0x13, 0xfe, 0x66, 0xd9, 0xc8, 0x16, 0x2d, 0xf5, 0xe8, 0x71, 0x32, 0x33, 0x7c,
+	0x99, 0x04, 0x50, 0xae, 0xc8, 0xf2, 0x92, 0xdd, 0x72, 0xdd, 0xb5, 0x95, 0x0f, 0x8e, 0x1d, 0xe6,
+	0x5a, 0x99, 0x72, 0x0d, 0x27, 0x0d, 0xad, 0x74, 0x64, 0x0a, 0x77, 0x9b, 0x48, 0x43, 0xef, 0x1d,
+	0x45, 0xc6, 0x02, 0x0e, 0x6a, 0x17, 0xd9, 0xca, 0x37, 0x12, 0xd9, 0x45, 0x76, 0x39, 0x6b, 0x06,
+	0x8d, 0x02, 0xf6, 0x5f, 0x28, 0x44, 0xed, 0xac, 0xd8, 0x4d, 0xd6, 0x56, 0x6e, 0x5a, 0x0b, 0x96,
+	0x4c, 0xf7, 0x3a, 0x88, 0xfc, 0xbb, 0xb5, 0xd5, 0xe5, 0x13, 0xe0, 0x88, 0xe4, 0x4d, 0x8f, 0xb7,
+	0x20, 0x6a, 0x92, 0x81, 0x5b, 0x92, 0xfc, 0x68, 0x99, 0xc2, 0x87, 0x34, 0x0b, 0x7a, 0x75, 0x56,
+	0xc5, 0xc4, 0xcd, 0x9b, 0x3f, 0xfd, 0x52, 0xc1, 0x7c, 0xf0, 0xfe, 0xef, 0xde, 0x73, 0x38, 0xfa,
+	0x41, 0xf1, 0xa6, 0xbf, 0xfa, 0xcc, 0x00, 0xd2, 0x84, 0x67, 0x57, 0x77, 0x2d, 0x3e, 0xc0, 0xe1,
+	0xaf, 0x61, 0x78, 0x5e, 0x69, 0x65, 0xaa, 0xa9, 0x67, 0x2d, 0xce, 0xa6, 0x2c, 0x6f, 0xfa, 0x72,
+	0x07, 0x6f, 0x60, 0x36, 0x70, 0xf0, 0x34, 0xe5, 0xc6, 0xeb, 0x8a, 0xe3, 0xf1, 0x71, 0xaa, 0xb6,
+	0x7b, 0xe9, 0x43, 0xaf, 0xbf, 0x06, 0x00, 0xcd, 0x69, 0xd3, 0x00, 0xe6, 0x01, 0x00, 0x00,
+}
@@ -23,6 +23,32 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
 
+type AgentHealth int32
+
+const (
+	AgentHealth_MISSING AgentHealth = 0
+	AgentHealth_HEALTHY AgentHealth = 1
+	AgentHealth_STALE   AgentHealth = 2
+)
+
+var AgentHealth_name = map[int32]string{
+	0: "MISSING",
+	1: "HEALTHY",
+	2: "STALE",
+}
+var AgentHealth_value = map[string]int32{
+	"MISSING": 0,
+	"HEALTHY": 1,
+	"STALE":   2,
+}
+
+func (x AgentHealth) String() string {
+	return proto.EnumName(AgentHealth_name, int32(x))
+}
+func (AgentHealth) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_cli_to_hub_efce300fdd070e8b, []int{0}
+}
+
 type UpgradeSteps int32
 
 const (
@@ -40,73 +66,867 @@ const (
 	UpgradeSteps_RECONFIGURE_PORTS      UpgradeSteps = 11
 )
 
-var UpgradeSteps_name = map[int32]string{
-	0:  "UNKNOWN_STEP",
-	1:  "CHECK_CONFIG",
-	2:  "SEGINSTALL",
-	3:  "PREPARE_INIT_CLUSTER",
-	4:  "MASTERUPGRADE",
-	5:  "STOPPED_CLUSTER",
-	6:  "PREPARE_START_AGENTS",
-	7:  "CONVERSION",
-	8:  "SHARE_OIDS",
-	9:  "VALIDATE_START_CLUSTER",
-	10: "CONVERT_PRIMARIES",
-	11: "RECONFIGURE_PORTS",
+var UpgradeSteps_name = map[int32]string{
+	0:  "UNKNOWN_STEP",
+	1:  "CHECK_CONFIG",
+	2:  "SEGINSTALL",
+	3:  "PREPARE_INIT_CLUSTER",
+	4:  "MASTERUPGRADE",
+	5:  "STOPPED_CLUSTER",
+	6:  "PREPARE_START_AGENTS",
+	7:  "CONVERSION",
+	8:  "SHARE_OIDS",
+	9:  "VALIDATE_START_CLUSTER",
+	10: "CONVERT_PRIMARIES",
+	11: "RECONFIGURE_PORTS",
+}
+var UpgradeSteps_value = map[string]int32{
+	"UNKNOWN_STEP":           0,
+	"CHECK_CONFIG":           1,
+	"SEGINSTALL":             2,
+	"PREPARE_INIT_CLUSTER":   3,
+	"MASTERUPGRADE":          4,
+	"STOPPED_CLUSTER":        5,
+	"PREPARE_START_AGENTS":   6,
+	"CONVERSION":             7,
+	"SHARE_OIDS":             8,
+	"VALIDATE_START_CLUSTER": 9,
+	"CONVERT_PRIMARIES":      10,
+	"RECONFIGURE_PORTS":      11,
+}
+
+func (x UpgradeSteps) String() string {
+	return proto.EnumName(UpgradeSteps_name, int32(x))
+}
+func (UpgradeSteps) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_cli_to_hub_efce300fdd070e8b, []int{1}
+}
+
+// CheckSeverity is how much a check that doesn't pass matters: only ERROR
+// checks stop the upgrade.
+type CheckSeverity int32
+
+const (
+	CheckSeverity_UNKNOWN_SEVERITY CheckSeverity = 0
+	CheckSeverity_ERROR            CheckSeverity = 1
+	CheckSeverity_WARNING          CheckSeverity = 2
+	CheckSeverity_INFO             CheckSeverity = 3
+)
+
+var CheckSeverity_name = map[int32]string{
+	0: "UNKNOWN_SEVERITY",
+	1: "ERROR",
+	2: "WARNING",
+	3: "INFO",
+}
+var CheckSeverity_value = map[string]int32{
+	"UNKNOWN_SEVERITY": 0,
+	"ERROR":            1,
+	"WARNING":          2,
+	"INFO":             3,
+}
+
+func (x CheckSeverity) String() string {
+	return proto.EnumName(CheckSeverity_name, int32(x))
+}
+func (CheckSeverity) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_cli_to_hub_efce300fdd070e8b, []int{2}
+}
+
+type LogsRequest struct {
+	Source               LogSource `protobuf:"varint,1,opt,name=Source,enum=idl.LogSource" json:"Source,omitempty"`
+	Hostname             string    `protobuf:"bytes,2,opt,name=Hostname" json:"Hostname,omitempty"`
+	Content              int32     `protobuf:"varint,3,opt,name=Content" json:"Content,omitempty"`
+	Follow               bool      `protobuf:"varint,4,opt,name=Follow" json:"Follow,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *LogsRequest) Reset()         { *m = LogsRequest{} }
+func (m *LogsRequest) String() string { return proto.CompactTextString(m) }
+func (*LogsRequest) ProtoMessage()    {}
+func (*LogsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cli_to_hub_efce300fdd070e8b, []int{0}
+}
+func (m *LogsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LogsRequest.Unmarshal(m, b)
+}
+func (m *LogsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LogsRequest.Marshal(b, m, deterministic)
+}
+func (dst *LogsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LogsRequest.Merge(dst, src)
+}
+func (m *LogsRequest) XXX_Size() int {
+	return xxx_messageInfo_LogsRequest.Size(m)
+}
+func (m *LogsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_LogsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LogsRequest proto.InternalMessageInfo
+
+func (m *LogsRequest) GetSource() LogSource {
+	if m != nil {
+		return m.Source
+	}
+	return LogSource_HUB
+}
+
+func (m *LogsRequest) GetHostname() string {
+	if m != nil {
+		return m.Hostname
+	}
+	return ""
+}
+
+func (m *LogsRequest) GetContent() int32 {
+	if m != nil {
+		return m.Content
+	}
+	return 0
+}
+
+func (m *LogsRequest) GetFollow() bool {
+	if m != nil {
+		return m.Follow
+	}
+	return false
+}
+
+type CancelRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CancelRequest) Reset()         { *m = CancelRequest{} }
+func (m *CancelRequest) String() string { return proto.CompactTextString(m) }
+func (*CancelRequest) ProtoMessage()    {}
+func (*CancelRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cli_to_hub_efce300fdd070e8b, []int{1}
+}
+func (m *CancelRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CancelRequest.Unmarshal(m, b)
+}
+func (m *CancelRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CancelRequest.Marshal(b, m, deterministic)
+}
+func (dst *CancelRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CancelRequest.Merge(dst, src)
+}
+func (m *CancelRequest) XXX_Size() int {
+	return xxx_messageInfo_CancelRequest.Size(m)
+}
+func (m *CancelRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CancelRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CancelRequest proto.InternalMessageInfo
+
+type CancelReply struct {
+	// Cancelled names what was stopped, e.g. "convert-master" or
+	// "convert-primary (segment 0) on sdw1"; it is empty if nothing was running.
+	Cancelled            []string `protobuf:"bytes,1,rep,name=Cancelled" json:"Cancelled,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CancelReply) Reset()         { *m = CancelReply{} }
+func (m *CancelReply) String() string { return proto.CompactTextString(m) }
+func (*CancelReply) ProtoMessage()    {}
+func (*CancelReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cli_to_hub_efce300fdd070e8b, []int{2}
+}
+func (m *CancelReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CancelReply.Unmarshal(m, b)
+}
+func (m *CancelReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CancelReply.Marshal(b, m, deterministic)
+}
+func (dst *CancelReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CancelReply.Merge(dst, src)
+}
+func (m *CancelReply) XXX_Size() int {
+	return xxx_messageInfo_CancelReply.Size(m)
+}
+func (m *CancelReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_CancelReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CancelReply proto.InternalMessageInfo
+
+func (m *CancelReply) GetCancelled() []string {
+	if m != nil {
+		return m.Cancelled
+	}
+	return nil
+}
+
+type StatusAgentsRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StatusAgentsRequest) Reset()         { *m = StatusAgentsRequest{} }
+func (m *StatusAgentsRequest) String() string { return proto.CompactTextString(m) }
+func (*StatusAgentsRequest) ProtoMessage()    {}
+func (*StatusAgentsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cli_to_hub_efce300fdd070e8b, []int{3}
+}
+func (m *StatusAgentsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StatusAgentsRequest.Unmarshal(m, b)
+}
+func (m *StatusAgentsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StatusAgentsRequest.Marshal(b, m, deterministic)
+}
+func (dst *StatusAgentsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StatusAgentsRequest.Merge(dst, src)
+}
+func (m *StatusAgentsRequest) XXX_Size() int {
+	return xxx_messageInfo_StatusAgentsRequest.Size(m)
+}
+func (m *StatusAgentsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_StatusAgentsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StatusAgentsRequest proto.InternalMessageInfo
+
+type StatusAgentsReply struct {
+	Agents               []*AgentHealthStatus `protobuf:"bytes,1,rep,name=Agents" json:"Agents,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *StatusAgentsReply) Reset()         { *m = StatusAgentsReply{} }
+func (m *StatusAgentsReply) String() string { return proto.CompactTextString(m) }
+func (*StatusAgentsReply) ProtoMessage()    {}
+func (*StatusAgentsReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cli_to_hub_efce300fdd070e8b, []int{4}
+}
+func (m *StatusAgentsReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StatusAgentsReply.Unmarshal(m, b)
+}
+func (m *StatusAgentsReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StatusAgentsReply.Marshal(b, m, deterministic)
+}
+func (dst *StatusAgentsReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StatusAgentsReply.Merge(dst, src)
+}
+func (m *StatusAgentsReply) XXX_Size() int {
+	return xxx_messageInfo_StatusAgentsReply.Size(m)
+}
+func (m *StatusAgentsReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_StatusAgentsReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StatusAgentsReply proto.InternalMessageInfo
+
+func (m *StatusAgentsReply) GetAgents() []*AgentHealthStatus {
+	if m != nil {
+		return m.Agents
+	}
+	return nil
+}
+
+// AgentHealthStatus is what the hub knows about the agent on a segment host
+// from its registration and heartbeats.
+type AgentHealthStatus struct {
+	Hostname             string      `protobuf:"bytes,1,opt,name=Hostname" json:"Hostname,omitempty"`
+	Health               AgentHealth `protobuf:"varint,2,opt,name=Health,enum=idl.AgentHealth" json:"Health,omitempty"`
+	Version              string      `protobuf:"bytes,3,opt,name=Version" json:"Version,omitempty"`
+	StateDir             string      `protobuf:"bytes,4,opt,name=StateDir" json:"StateDir,omitempty"`
+	LastHeartbeat        int64       `protobuf:"varint,5,opt,name=LastHeartbeat" json:"LastHeartbeat,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *AgentHealthStatus) Reset()         { *m = AgentHealthStatus{} }
+func (m *AgentHealthStatus) String() string { return proto.CompactTextString(m) }
+func (*AgentHealthStatus) ProtoMessage()    {}
+func (*AgentHealthStatus) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cli_to_hub_efce300fdd070e8b, []int{5}
+}
+func (m *AgentHealthStatus) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AgentHealthStatus.Unmarshal(m, b)
+}
+func (m *AgentHealthStatus) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AgentHealthStatus.Marshal(b, m, deterministic)
+}
+func (dst *AgentHealthStatus) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AgentHealthStatus.Merge(dst, src)
+}
+func (m *AgentHealthStatus) XXX_Size() int {
+	return xxx_messageInfo_AgentHealthStatus.Size(m)
+}
+func (m *AgentHealthStatus) XXX_DiscardUnknown() {
+	xxx_messageInfo_AgentHealthStatus.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AgentHealthStatus proto.InternalMessageInfo
+
+func (m *AgentHealthStatus) GetHostname() string {
+	if m != nil {
+		return m.Hostname
+	}
+	return ""
+}
+
+func (m *AgentHealthStatus) GetHealth() AgentHealth {
+	if m != nil {
+		return m.Health
+	}
+	return AgentHealth_MISSING
+}
+
+func (m *AgentHealthStatus) GetVersion() string {
+	if m != nil {
+		return m.Version
+	}
+	return ""
+}
+
+func (m *AgentHealthStatus) GetStateDir() string {
+	if m != nil {
+		return m.StateDir
+	}
+	return ""
+}
+
+func (m *AgentHealthStatus) GetLastHeartbeat() int64 {
+	if m != nil {
+		return m.LastHeartbeat
+	}
+	return 0
+}
+
+type CheckAgentsRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CheckAgentsRequest) Reset()         { *m = CheckAgentsRequest{} }
+func (m *CheckAgentsRequest) String() string { return proto.CompactTextString(m) }
+func (*CheckAgentsRequest) ProtoMessage()    {}
+func (*CheckAgentsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cli_to_hub_efce300fdd070e8b, []int{6}
+}
+func (m *CheckAgentsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CheckAgentsRequest.Unmarshal(m, b)
+}
+func (m *CheckAgentsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CheckAgentsRequest.Marshal(b, m, deterministic)
+}
+func (dst *CheckAgentsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CheckAgentsRequest.Merge(dst, src)
+}
+func (m *CheckAgentsRequest) XXX_Size() int {
+	return xxx_messageInfo_CheckAgentsRequest.Size(m)
+}
+func (m *CheckAgentsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CheckAgentsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CheckAgentsRequest proto.InternalMessageInfo
+
+type CheckAgentsReply struct {
+	Agents               []*AgentStatus `protobuf:"bytes,1,rep,name=Agents" json:"Agents,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *CheckAgentsReply) Reset()         { *m = CheckAgentsReply{} }
+func (m *CheckAgentsReply) String() string { return proto.CompactTextString(m) }
+func (*CheckAgentsReply) ProtoMessage()    {}
+func (*CheckAgentsReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cli_to_hub_efce300fdd070e8b, []int{7}
+}
+func (m *CheckAgentsReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CheckAgentsReply.Unmarshal(m, b)
+}
+func (m *CheckAgentsReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CheckAgentsReply.Marshal(b, m, deterministic)
+}
+func (dst *CheckAgentsReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CheckAgentsReply.Merge(dst, src)
+}
+func (m *CheckAgentsReply) XXX_Size() int {
+	return xxx_messageInfo_CheckAgentsReply.Size(m)
+}
+func (m *CheckAgentsReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_CheckAgentsReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CheckAgentsReply proto.InternalMessageInfo
+
+func (m *CheckAgentsReply) GetAgents() []*AgentStatus {
+	if m != nil {
+		return m.Agents
+	}
+	return nil
+}
+
+// AgentStatus is the result of pinging the agent on a host.
+type AgentStatus struct {
+	Hostname             string   `protobuf:"bytes,1,opt,name=Hostname" json:"Hostname,omitempty"`
+	Reachable            bool     `protobuf:"varint,2,opt,name=Reachable" json:"Reachable,omitempty"`
+	LatencyMilliseconds  float64  `protobuf:"fixed64,3,opt,name=LatencyMilliseconds" json:"LatencyMilliseconds,omitempty"`
+	Version              string   `protobuf:"bytes,4,opt,name=Version" json:"Version,omitempty"`
+	Error                string   `protobuf:"bytes,5,opt,name=Error" json:"Error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AgentStatus) Reset()         { *m = AgentStatus{} }
+func (m *AgentStatus) String() string { return proto.CompactTextString(m) }
+func (*AgentStatus) ProtoMessage()    {}
+func (*AgentStatus) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cli_to_hub_efce300fdd070e8b, []int{8}
+}
+func (m *AgentStatus) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AgentStatus.Unmarshal(m, b)
+}
+func (m *AgentStatus) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AgentStatus.Marshal(b, m, deterministic)
+}
+func (dst *AgentStatus) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AgentStatus.Merge(dst, src)
+}
+func (m *AgentStatus) XXX_Size() int {
+	return xxx_messageInfo_AgentStatus.Size(m)
+}
+func (m *AgentStatus) XXX_DiscardUnknown() {
+	xxx_messageInfo_AgentStatus.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AgentStatus proto.InternalMessageInfo
+
+func (m *AgentStatus) GetHostname() string {
+	if m != nil {
+		return m.Hostname
+	}
+	return ""
+}
+
+func (m *AgentStatus) GetReachable() bool {
+	if m != nil {
+		return m.Reachable
+	}
+	return false
+}
+
+func (m *AgentStatus) GetLatencyMilliseconds() float64 {
+	if m != nil {
+		return m.LatencyMilliseconds
+	}
+	return 0
+}
+
+func (m *AgentStatus) GetVersion() string {
+	if m != nil {
+		return m.Version
+	}
+	return ""
+}
+
+func (m *AgentStatus) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+// VersionReply is what gpupgrade version reports. No RPC returns it; it gives
+// the version the same shape as the hub's replies under --format.
+type VersionReply struct {
+	Version              string   `protobuf:"bytes,1,opt,name=Version" json:"Version,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *VersionReply) Reset()         { *m = VersionReply{} }
+func (m *VersionReply) String() string { return proto.CompactTextString(m) }
+func (*VersionReply) ProtoMessage()    {}
+func (*VersionReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cli_to_hub_efce300fdd070e8b, []int{9}
+}
+func (m *VersionReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_VersionReply.Unmarshal(m, b)
+}
+func (m *VersionReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_VersionReply.Marshal(b, m, deterministic)
+}
+func (dst *VersionReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_VersionReply.Merge(dst, src)
+}
+func (m *VersionReply) XXX_Size() int {
+	return xxx_messageInfo_VersionReply.Size(m)
+}
+func (m *VersionReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_VersionReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_VersionReply proto.InternalMessageInfo
+
+func (m *VersionReply) GetVersion() string {
+	if m != nil {
+		return m.Version
+	}
+	return ""
+}
+
+type ShutdownRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ShutdownRequest) Reset()         { *m = ShutdownRequest{} }
+func (m *ShutdownRequest) String() string { return proto.CompactTextString(m) }
+func (*ShutdownRequest) ProtoMessage()    {}
+func (*ShutdownRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cli_to_hub_efce300fdd070e8b, []int{10}
+}
+func (m *ShutdownRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ShutdownRequest.Unmarshal(m, b)
+}
+func (m *ShutdownRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ShutdownRequest.Marshal(b, m, deterministic)
+}
+func (dst *ShutdownRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ShutdownRequest.Merge(dst, src)
+}
+func (m *ShutdownRequest) XXX_Size() int {
+	return xxx_messageInfo_ShutdownRequest.Size(m)
+}
+func (m *ShutdownRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ShutdownRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ShutdownRequest proto.InternalMessageInfo
+
+type ShutdownReply struct {
+	Hostname             string   `protobuf:"bytes,1,opt,name=Hostname" json:"Hostname,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ShutdownReply) Reset()         { *m = ShutdownReply{} }
+func (m *ShutdownReply) String() string { return proto.CompactTextString(m) }
+func (*ShutdownReply) ProtoMessage()    {}
+func (*ShutdownReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cli_to_hub_efce300fdd070e8b, []int{11}
+}
+func (m *ShutdownReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ShutdownReply.Unmarshal(m, b)
+}
+func (m *ShutdownReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ShutdownReply.Marshal(b, m, deterministic)
+}
+func (dst *ShutdownReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ShutdownReply.Merge(dst, src)
+}
+func (m *ShutdownReply) XXX_Size() int {
+	return xxx_messageInfo_ShutdownReply.Size(m)
+}
+func (m *ShutdownReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_ShutdownReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ShutdownReply proto.InternalMessageInfo
+
+func (m *ShutdownReply) GetHostname() string {
+	if m != nil {
+		return m.Hostname
+	}
+	return ""
+}
+
+type PrepareStopAgentsRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PrepareStopAgentsRequest) Reset()         { *m = PrepareStopAgentsRequest{} }
+func (m *PrepareStopAgentsRequest) String() string { return proto.CompactTextString(m) }
+func (*PrepareStopAgentsRequest) ProtoMessage()    {}
+func (*PrepareStopAgentsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cli_to_hub_efce300fdd070e8b, []int{12}
+}
+func (m *PrepareStopAgentsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PrepareStopAgentsRequest.Unmarshal(m, b)
+}
+func (m *PrepareStopAgentsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PrepareStopAgentsRequest.Marshal(b, m, deterministic)
+}
+func (dst *PrepareStopAgentsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PrepareStopAgentsRequest.Merge(dst, src)
+}
+func (m *PrepareStopAgentsRequest) XXX_Size() int {
+	return xxx_messageInfo_PrepareStopAgentsRequest.Size(m)
+}
+func (m *PrepareStopAgentsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_PrepareStopAgentsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PrepareStopAgentsRequest proto.InternalMessageInfo
+
+type PrepareStopAgentsReply struct {
+	Agents               []*AgentShutdownStatus `protobuf:"bytes,1,rep,name=Agents" json:"Agents,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}               `json:"-"`
+	XXX_unrecognized     []byte                 `json:"-"`
+	XXX_sizecache        int32                  `json:"-"`
+}
+
+func (m *PrepareStopAgentsReply) Reset()         { *m = PrepareStopAgentsReply{} }
+func (m *PrepareStopAgentsReply) String() string { return proto.CompactTextString(m) }
+func (*PrepareStopAgentsReply) ProtoMessage()    {}
+func (*PrepareStopAgentsReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cli_to_hub_efce300fdd070e8b, []int{13}
+}
+func (m *PrepareStopAgentsReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PrepareStopAgentsReply.Unmarshal(m, b)
+}
+func (m *PrepareStopAgentsReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PrepareStopAgentsReply.Marshal(b, m, deterministic)
+}
+func (dst *PrepareStopAgentsReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PrepareStopAgentsReply.Merge(dst, src)
+}
+func (m *PrepareStopAgentsReply) XXX_Size() int {
+	return xxx_messageInfo_PrepareStopAgentsReply.Size(m)
+}
+func (m *PrepareStopAgentsReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_PrepareStopAgentsReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PrepareStopAgentsReply proto.InternalMessageInfo
+
+func (m *PrepareStopAgentsReply) GetAgents() []*AgentShutdownStatus {
+	if m != nil {
+		return m.Agents
+	}
+	return nil
+}
+
+// AgentShutdownStatus is whether the agent on a host confirmed that it is
+// shutting down.
+type AgentShutdownStatus struct {
+	Hostname             string   `protobuf:"bytes,1,opt,name=Hostname" json:"Hostname,omitempty"`
+	Stopped              bool     `protobuf:"varint,2,opt,name=Stopped" json:"Stopped,omitempty"`
+	Error                string   `protobuf:"bytes,3,opt,name=Error" json:"Error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AgentShutdownStatus) Reset()         { *m = AgentShutdownStatus{} }
+func (m *AgentShutdownStatus) String() string { return proto.CompactTextString(m) }
+func (*AgentShutdownStatus) ProtoMessage()    {}
+func (*AgentShutdownStatus) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cli_to_hub_efce300fdd070e8b, []int{14}
+}
+func (m *AgentShutdownStatus) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AgentShutdownStatus.Unmarshal(m, b)
+}
+func (m *AgentShutdownStatus) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AgentShutdownStatus.Marshal(b, m, deterministic)
+}
+func (dst *AgentShutdownStatus) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AgentShutdownStatus.Merge(dst, src)
+}
+func (m *AgentShutdownStatus) XXX_Size() int {
+	return xxx_messageInfo_AgentShutdownStatus.Size(m)
+}
+func (m *AgentShutdownStatus) XXX_DiscardUnknown() {
+	xxx_messageInfo_AgentShutdownStatus.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AgentShutdownStatus proto.InternalMessageInfo
+
+func (m *AgentShutdownStatus) GetHostname() string {
+	if m != nil {
+		return m.Hostname
+	}
+	return ""
+}
+
+func (m *AgentShutdownStatus) GetStopped() bool {
+	if m != nil {
+		return m.Stopped
+	}
+	return false
+}
+
+func (m *AgentShutdownStatus) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+type RevertRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RevertRequest) Reset()         { *m = RevertRequest{} }
+func (m *RevertRequest) String() string { return proto.CompactTextString(m) }
+func (*RevertRequest) ProtoMessage()    {}
+func (*RevertRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cli_to_hub_efce300fdd070e8b, []int{15}
+}
+func (m *RevertRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RevertRequest.Unmarshal(m, b)
+}
+func (m *RevertRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RevertRequest.Marshal(b, m, deterministic)
+}
+func (dst *RevertRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RevertRequest.Merge(dst, src)
+}
+func (m *RevertRequest) XXX_Size() int {
+	return xxx_messageInfo_RevertRequest.Size(m)
+}
+func (m *RevertRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RevertRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RevertRequest proto.InternalMessageInfo
+
+type RevertReply struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RevertReply) Reset()         { *m = RevertReply{} }
+func (m *RevertReply) String() string { return proto.CompactTextString(m) }
+func (*RevertReply) ProtoMessage()    {}
+func (*RevertReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cli_to_hub_efce300fdd070e8b, []int{16}
 }
-var UpgradeSteps_value = map[string]int32{
-	"UNKNOWN_STEP":           0,
-	"CHECK_CONFIG":           1,
-	"SEGINSTALL":             2,
-	"PREPARE_INIT_CLUSTER":   3,
-	"MASTERUPGRADE":          4,
-	"STOPPED_CLUSTER":        5,
-	"PREPARE_START_AGENTS":   6,
-	"CONVERSION":             7,
-	"SHARE_OIDS":             8,
-	"VALIDATE_START_CLUSTER": 9,
-	"CONVERT_PRIMARIES":      10,
-	"RECONFIGURE_PORTS":      11,
+func (m *RevertReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RevertReply.Unmarshal(m, b)
+}
+func (m *RevertReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RevertReply.Marshal(b, m, deterministic)
+}
+func (dst *RevertReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RevertReply.Merge(dst, src)
+}
+func (m *RevertReply) XXX_Size() int {
+	return xxx_messageInfo_RevertReply.Size(m)
+}
+func (m *RevertReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_RevertReply.DiscardUnknown(m)
 }
 
-func (x UpgradeSteps) String() string {
-	return proto.EnumName(UpgradeSteps_name, int32(x))
+var xxx_messageInfo_RevertReply proto.InternalMessageInfo
+
+type UpgradeRunRequest struct {
+	OldDbPort            int32    `protobuf:"varint,1,opt,name=OldDbPort" json:"OldDbPort,omitempty"`
+	OldBinDir            string   `protobuf:"bytes,2,opt,name=OldBinDir" json:"OldBinDir,omitempty"`
+	NewDbPort            int32    `protobuf:"varint,3,opt,name=NewDbPort" json:"NewDbPort,omitempty"`
+	NewBinDir            string   `protobuf:"bytes,4,opt,name=NewBinDir" json:"NewBinDir,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
-func (UpgradeSteps) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_cli_to_hub_ed2de3a86ddbffdf, []int{0}
+
+func (m *UpgradeRunRequest) Reset()         { *m = UpgradeRunRequest{} }
+func (m *UpgradeRunRequest) String() string { return proto.CompactTextString(m) }
+func (*UpgradeRunRequest) ProtoMessage()    {}
+func (*UpgradeRunRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cli_to_hub_efce300fdd070e8b, []int{17}
+}
+func (m *UpgradeRunRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UpgradeRunRequest.Unmarshal(m, b)
+}
+func (m *UpgradeRunRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UpgradeRunRequest.Marshal(b, m, deterministic)
+}
+func (dst *UpgradeRunRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UpgradeRunRequest.Merge(dst, src)
+}
+func (m *UpgradeRunRequest) XXX_Size() int {
+	return xxx_messageInfo_UpgradeRunRequest.Size(m)
+}
+func (m *UpgradeRunRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_UpgradeRunRequest.DiscardUnknown(m)
 }
 
-type StepStatus int32
+var xxx_messageInfo_UpgradeRunRequest proto.InternalMessageInfo
 
-const (
-	StepStatus_UNKNOWN_STATUS StepStatus = 0
-	StepStatus_PENDING        StepStatus = 1
-	StepStatus_RUNNING        StepStatus = 2
-	StepStatus_COMPLETE       StepStatus = 3
-	StepStatus_FAILED         StepStatus = 4
-)
+func (m *UpgradeRunRequest) GetOldDbPort() int32 {
+	if m != nil {
+		return m.OldDbPort
+	}
+	return 0
+}
+
+func (m *UpgradeRunRequest) GetOldBinDir() string {
+	if m != nil {
+		return m.OldBinDir
+	}
+	return ""
+}
 
-var StepStatus_name = map[int32]string{
-	0: "UNKNOWN_STATUS",
-	1: "PENDING",
-	2: "RUNNING",
-	3: "COMPLETE",
-	4: "FAILED",
+func (m *UpgradeRunRequest) GetNewDbPort() int32 {
+	if m != nil {
+		return m.NewDbPort
+	}
+	return 0
+}
+
+func (m *UpgradeRunRequest) GetNewBinDir() string {
+	if m != nil {
+		return m.NewBinDir
+	}
+	return ""
 }
-var StepStatus_value = map[string]int32{
-	"UNKNOWN_STATUS": 0,
-	"PENDING":        1,
-	"RUNNING":        2,
-	"COMPLETE":       3,
-	"FAILED":         4,
+
+type UpgradeRunReply struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func (x StepStatus) String() string {
-	return proto.EnumName(StepStatus_name, int32(x))
+func (m *UpgradeRunReply) Reset()         { *m = UpgradeRunReply{} }
+func (m *UpgradeRunReply) String() string { return proto.CompactTextString(m) }
+func (*UpgradeRunReply) ProtoMessage()    {}
+func (*UpgradeRunReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cli_to_hub_efce300fdd070e8b, []int{18}
+}
+func (m *UpgradeRunReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UpgradeRunReply.Unmarshal(m, b)
+}
+func (m *UpgradeRunReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UpgradeRunReply.Marshal(b, m, deterministic)
+}
+func (dst *UpgradeRunReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UpgradeRunReply.Merge(dst, src)
 }
-func (StepStatus) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_cli_to_hub_ed2de3a86ddbffdf, []int{1}
+func (m *UpgradeRunReply) XXX_Size() int {
+	return xxx_messageInfo_UpgradeRunReply.Size(m)
 }
+func (m *UpgradeRunReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_UpgradeRunReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UpgradeRunReply proto.InternalMessageInfo
 
 type UpgradeReconfigurePortsRequest struct {
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -118,7 +938,7 @@ func (m *UpgradeReconfigurePortsRequest) Reset()         { *m = UpgradeReconfigu
 func (m *UpgradeReconfigurePortsRequest) String() string { return proto.CompactTextString(m) }
 func (*UpgradeReconfigurePortsRequest) ProtoMessage()    {}
 func (*UpgradeReconfigurePortsRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_cli_to_hub_ed2de3a86ddbffdf, []int{0}
+	return fileDescriptor_cli_to_hub_efce300fdd070e8b, []int{19}
 }
 func (m *UpgradeReconfigurePortsRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_UpgradeReconfigurePortsRequest.Unmarshal(m, b)
@@ -148,7 +968,7 @@ func (m *UpgradeReconfigurePortsReply) Reset()         { *m = UpgradeReconfigure
 func (m *UpgradeReconfigurePortsReply) String() string { return proto.CompactTextString(m) }
 func (*UpgradeReconfigurePortsReply) ProtoMessage()    {}
 func (*UpgradeReconfigurePortsReply) Descriptor() ([]byte, []int) {
-	return fileDescriptor_cli_to_hub_ed2de3a86ddbffdf, []int{1}
+	return fileDescriptor_cli_to_hub_efce300fdd070e8b, []int{20}
 }
 func (m *UpgradeReconfigurePortsReply) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_UpgradeReconfigurePortsReply.Unmarshal(m, b)
@@ -180,7 +1000,7 @@ func (m *UpgradeConvertPrimariesRequest) Reset()         { *m = UpgradeConvertPr
 func (m *UpgradeConvertPrimariesRequest) String() string { return proto.CompactTextString(m) }
 func (*UpgradeConvertPrimariesRequest) ProtoMessage()    {}
 func (*UpgradeConvertPrimariesRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_cli_to_hub_ed2de3a86ddbffdf, []int{2}
+	return fileDescriptor_cli_to_hub_efce300fdd070e8b, []int{21}
 }
 func (m *UpgradeConvertPrimariesRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_UpgradeConvertPrimariesRequest.Unmarshal(m, b)
@@ -224,7 +1044,7 @@ func (m *UpgradeConvertPrimariesReply) Reset()         { *m = UpgradeConvertPrim
 func (m *UpgradeConvertPrimariesReply) String() string { return proto.CompactTextString(m) }
 func (*UpgradeConvertPrimariesReply) ProtoMessage()    {}
 func (*UpgradeConvertPrimariesReply) Descriptor() ([]byte, []int) {
-	return fileDescriptor_cli_to_hub_ed2de3a86ddbffdf, []int{3}
+	return fileDescriptor_cli_to_hub_efce300fdd070e8b, []int{22}
 }
 func (m *UpgradeConvertPrimariesReply) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_UpgradeConvertPrimariesReply.Unmarshal(m, b)
@@ -254,7 +1074,7 @@ func (m *UpgradeShareOidsRequest) Reset()         { *m = UpgradeShareOidsRequest
 func (m *UpgradeShareOidsRequest) String() string { return proto.CompactTextString(m) }
 func (*UpgradeShareOidsRequest) ProtoMessage()    {}
 func (*UpgradeShareOidsRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_cli_to_hub_ed2de3a86ddbffdf, []int{4}
+	return fileDescriptor_cli_to_hub_efce300fdd070e8b, []int{23}
 }
 func (m *UpgradeShareOidsRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_UpgradeShareOidsRequest.Unmarshal(m, b)
@@ -284,7 +1104,7 @@ func (m *UpgradeShareOidsReply) Reset()         { *m = UpgradeShareOidsReply{} }
 func (m *UpgradeShareOidsReply) String() string { return proto.CompactTextString(m) }
 func (*UpgradeShareOidsReply) ProtoMessage()    {}
 func (*UpgradeShareOidsReply) Descriptor() ([]byte, []int) {
-	return fileDescriptor_cli_to_hub_ed2de3a86ddbffdf, []int{5}
+	return fileDescriptor_cli_to_hub_efce300fdd070e8b, []int{24}
 }
 func (m *UpgradeShareOidsReply) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_UpgradeShareOidsReply.Unmarshal(m, b)
@@ -305,8 +1125,6 @@ func (m *UpgradeShareOidsReply) XXX_DiscardUnknown() {
 var xxx_messageInfo_UpgradeShareOidsReply proto.InternalMessageInfo
 
 type UpgradeValidateStartClusterRequest struct {
-	NewBinDir            string   `protobuf:"bytes,1,opt,name=NewBinDir" json:"NewBinDir,omitempty"`
-	NewDataDir           string   `protobuf:"bytes,2,opt,name=NewDataDir" json:"NewDataDir,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -316,7 +1134,7 @@ func (m *UpgradeValidateStartClusterRequest) Reset()         { *m = UpgradeValid
 func (m *UpgradeValidateStartClusterRequest) String() string { return proto.CompactTextString(m) }
 func (*UpgradeValidateStartClusterRequest) ProtoMessage()    {}
 func (*UpgradeValidateStartClusterRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_cli_to_hub_ed2de3a86ddbffdf, []int{6}
+	return fileDescriptor_cli_to_hub_efce300fdd070e8b, []int{25}
 }
 func (m *UpgradeValidateStartClusterRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_UpgradeValidateStartClusterRequest.Unmarshal(m, b)
@@ -336,20 +1154,6 @@ func (m *UpgradeValidateStartClusterRequest) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_UpgradeValidateStartClusterRequest proto.InternalMessageInfo
 
-func (m *UpgradeValidateStartClusterRequest) GetNewBinDir() string {
-	if m != nil {
-		return m.NewBinDir
-	}
-	return ""
-}
-
-func (m *UpgradeValidateStartClusterRequest) GetNewDataDir() string {
-	if m != nil {
-		return m.NewDataDir
-	}
-	return ""
-}
-
 type UpgradeValidateStartClusterReply struct {
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
@@ -360,7 +1164,7 @@ func (m *UpgradeValidateStartClusterReply) Reset()         { *m = UpgradeValidat
 func (m *UpgradeValidateStartClusterReply) String() string { return proto.CompactTextString(m) }
 func (*UpgradeValidateStartClusterReply) ProtoMessage()    {}
 func (*UpgradeValidateStartClusterReply) Descriptor() ([]byte, []int) {
-	return fileDescriptor_cli_to_hub_ed2de3a86ddbffdf, []int{7}
+	return fileDescriptor_cli_to_hub_efce300fdd070e8b, []int{26}
 }
 func (m *UpgradeValidateStartClusterReply) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_UpgradeValidateStartClusterReply.Unmarshal(m, b)
@@ -390,7 +1194,7 @@ func (m *PingRequest) Reset()         { *m = PingRequest{} }
 func (m *PingRequest) String() string { return proto.CompactTextString(m) }
 func (*PingRequest) ProtoMessage()    {}
 func (*PingRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_cli_to_hub_ed2de3a86ddbffdf, []int{8}
+	return fileDescriptor_cli_to_hub_efce300fdd070e8b, []int{27}
 }
 func (m *PingRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_PingRequest.Unmarshal(m, b)
@@ -420,7 +1224,7 @@ func (m *PingReply) Reset()         { *m = PingReply{} }
 func (m *PingReply) String() string { return proto.CompactTextString(m) }
 func (*PingReply) ProtoMessage()    {}
 func (*PingReply) Descriptor() ([]byte, []int) {
-	return fileDescriptor_cli_to_hub_ed2de3a86ddbffdf, []int{9}
+	return fileDescriptor_cli_to_hub_efce300fdd070e8b, []int{28}
 }
 func (m *PingReply) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_PingReply.Unmarshal(m, b)
@@ -450,7 +1254,7 @@ func (m *StatusConversionRequest) Reset()         { *m = StatusConversionRequest
 func (m *StatusConversionRequest) String() string { return proto.CompactTextString(m) }
 func (*StatusConversionRequest) ProtoMessage()    {}
 func (*StatusConversionRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_cli_to_hub_ed2de3a86ddbffdf, []int{10}
+	return fileDescriptor_cli_to_hub_efce300fdd070e8b, []int{29}
 }
 func (m *StatusConversionRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_StatusConversionRequest.Unmarshal(m, b)
@@ -471,17 +1275,17 @@ func (m *StatusConversionRequest) XXX_DiscardUnknown() {
 var xxx_messageInfo_StatusConversionRequest proto.InternalMessageInfo
 
 type StatusConversionReply struct {
-	ConversionStatuses   []string `protobuf:"bytes,1,rep,name=conversionStatuses" json:"conversionStatuses,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	ConversionStatuses   []*SegmentConversionStatus `protobuf:"bytes,1,rep,name=conversionStatuses" json:"conversionStatuses,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                   `json:"-"`
+	XXX_unrecognized     []byte                     `json:"-"`
+	XXX_sizecache        int32                      `json:"-"`
 }
 
 func (m *StatusConversionReply) Reset()         { *m = StatusConversionReply{} }
 func (m *StatusConversionReply) String() string { return proto.CompactTextString(m) }
 func (*StatusConversionReply) ProtoMessage()    {}
 func (*StatusConversionReply) Descriptor() ([]byte, []int) {
-	return fileDescriptor_cli_to_hub_ed2de3a86ddbffdf, []int{11}
+	return fileDescriptor_cli_to_hub_efce300fdd070e8b, []int{30}
 }
 func (m *StatusConversionReply) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_StatusConversionReply.Unmarshal(m, b)
@@ -501,7 +1305,7 @@ func (m *StatusConversionReply) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_StatusConversionReply proto.InternalMessageInfo
 
-func (m *StatusConversionReply) GetConversionStatuses() []string {
+func (m *StatusConversionReply) GetConversionStatuses() []*SegmentConversionStatus {
 	if m != nil {
 		return m.ConversionStatuses
 	}
@@ -518,7 +1322,7 @@ func (m *StatusUpgradeRequest) Reset()         { *m = StatusUpgradeRequest{} }
 func (m *StatusUpgradeRequest) String() string { return proto.CompactTextString(m) }
 func (*StatusUpgradeRequest) ProtoMessage()    {}
 func (*StatusUpgradeRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_cli_to_hub_ed2de3a86ddbffdf, []int{12}
+	return fileDescriptor_cli_to_hub_efce300fdd070e8b, []int{31}
 }
 func (m *StatusUpgradeRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_StatusUpgradeRequest.Unmarshal(m, b)
@@ -549,7 +1353,7 @@ func (m *StatusUpgradeReply) Reset()         { *m = StatusUpgradeReply{} }
 func (m *StatusUpgradeReply) String() string { return proto.CompactTextString(m) }
 func (*StatusUpgradeReply) ProtoMessage()    {}
 func (*StatusUpgradeReply) Descriptor() ([]byte, []int) {
-	return fileDescriptor_cli_to_hub_ed2de3a86ddbffdf, []int{13}
+	return fileDescriptor_cli_to_hub_efce300fdd070e8b, []int{32}
 }
 func (m *StatusUpgradeReply) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_StatusUpgradeReply.Unmarshal(m, b)
@@ -576,19 +1380,55 @@ func (m *StatusUpgradeReply) GetListOfUpgradeStepStatuses() []*UpgradeStepStatus
 	return nil
 }
 
+type WatchUpgradeStatusRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *WatchUpgradeStatusRequest) Reset()         { *m = WatchUpgradeStatusRequest{} }
+func (m *WatchUpgradeStatusRequest) String() string { return proto.CompactTextString(m) }
+func (*WatchUpgradeStatusRequest) ProtoMessage()    {}
+func (*WatchUpgradeStatusRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cli_to_hub_efce300fdd070e8b, []int{33}
+}
+func (m *WatchUpgradeStatusRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WatchUpgradeStatusRequest.Unmarshal(m, b)
+}
+func (m *WatchUpgradeStatusRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WatchUpgradeStatusRequest.Marshal(b, m, deterministic)
+}
+func (dst *WatchUpgradeStatusRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WatchUpgradeStatusRequest.Merge(dst, src)
+}
+func (m *WatchUpgradeStatusRequest) XXX_Size() int {
+	return xxx_messageInfo_WatchUpgradeStatusRequest.Size(m)
+}
+func (m *WatchUpgradeStatusRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_WatchUpgradeStatusRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WatchUpgradeStatusRequest proto.InternalMessageInfo
+
 type UpgradeStepStatus struct {
-	Step                 UpgradeSteps `protobuf:"varint,1,opt,name=step,enum=idl.UpgradeSteps" json:"step,omitempty"`
-	Status               StepStatus   `protobuf:"varint,2,opt,name=status,enum=idl.StepStatus" json:"status,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
-	XXX_unrecognized     []byte       `json:"-"`
-	XXX_sizecache        int32        `json:"-"`
+	Step                      UpgradeSteps       `protobuf:"varint,1,opt,name=step,enum=idl.UpgradeSteps" json:"step,omitempty"`
+	Status                    StepStatus         `protobuf:"varint,2,opt,name=status,enum=idl.StepStatus" json:"status,omitempty"`
+	Progress                  *PgUpgradeProgress `protobuf:"bytes,3,opt,name=progress" json:"progress,omitempty"`
+	StartTime                 int64              `protobuf:"varint,4,opt,name=startTime" json:"startTime,omitempty"`
+	EndTime                   int64              `protobuf:"varint,5,opt,name=endTime" json:"endTime,omitempty"`
+	DurationSeconds           int64              `protobuf:"varint,6,opt,name=durationSeconds" json:"durationSeconds,omitempty"`
+	EstimatedSecondsRemaining int64              `protobuf:"varint,7,opt,name=estimatedSecondsRemaining" json:"estimatedSecondsRemaining,omitempty"`
+	PreviousDurationSeconds   []int64            `protobuf:"varint,8,rep,packed,name=previousDurationSeconds" json:"previousDurationSeconds,omitempty"`
+	XXX_NoUnkeyedLiteral      struct{}           `json:"-"`
+	XXX_unrecognized          []byte             `json:"-"`
+	XXX_sizecache             int32              `json:"-"`
 }
 
 func (m *UpgradeStepStatus) Reset()         { *m = UpgradeStepStatus{} }
 func (m *UpgradeStepStatus) String() string { return proto.CompactTextString(m) }
 func (*UpgradeStepStatus) ProtoMessage()    {}
 func (*UpgradeStepStatus) Descriptor() ([]byte, []int) {
-	return fileDescriptor_cli_to_hub_ed2de3a86ddbffdf, []int{14}
+	return fileDescriptor_cli_to_hub_efce300fdd070e8b, []int{34}
 }
 func (m *UpgradeStepStatus) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_UpgradeStepStatus.Unmarshal(m, b)
@@ -622,6 +1462,48 @@ func (m *UpgradeStepStatus) GetStatus() StepStatus {
 	return StepStatus_UNKNOWN_STATUS
 }
 
+func (m *UpgradeStepStatus) GetProgress() *PgUpgradeProgress {
+	if m != nil {
+		return m.Progress
+	}
+	return nil
+}
+
+func (m *UpgradeStepStatus) GetStartTime() int64 {
+	if m != nil {
+		return m.StartTime
+	}
+	return 0
+}
+
+func (m *UpgradeStepStatus) GetEndTime() int64 {
+	if m != nil {
+		return m.EndTime
+	}
+	return 0
+}
+
+func (m *UpgradeStepStatus) GetDurationSeconds() int64 {
+	if m != nil {
+		return m.DurationSeconds
+	}
+	return 0
+}
+
+func (m *UpgradeStepStatus) GetEstimatedSecondsRemaining() int64 {
+	if m != nil {
+		return m.EstimatedSecondsRemaining
+	}
+	return 0
+}
+
+func (m *UpgradeStepStatus) GetPreviousDurationSeconds() []int64 {
+	if m != nil {
+		return m.PreviousDurationSeconds
+	}
+	return nil
+}
+
 type CheckConfigRequest struct {
 	DbPort               int32    `protobuf:"varint,1,opt,name=dbPort" json:"dbPort,omitempty"`
 	OldBinDir            string   `protobuf:"bytes,2,opt,name=oldBinDir" json:"oldBinDir,omitempty"`
@@ -634,7 +1516,7 @@ func (m *CheckConfigRequest) Reset()         { *m = CheckConfigRequest{} }
 func (m *CheckConfigRequest) String() string { return proto.CompactTextString(m) }
 func (*CheckConfigRequest) ProtoMessage()    {}
 func (*CheckConfigRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_cli_to_hub_ed2de3a86ddbffdf, []int{15}
+	return fileDescriptor_cli_to_hub_efce300fdd070e8b, []int{35}
 }
 func (m *CheckConfigRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_CheckConfigRequest.Unmarshal(m, b)
@@ -680,7 +1562,7 @@ func (m *CheckConfigReply) Reset()         { *m = CheckConfigReply{} }
 func (m *CheckConfigReply) String() string { return proto.CompactTextString(m) }
 func (*CheckConfigReply) ProtoMessage()    {}
 func (*CheckConfigReply) Descriptor() ([]byte, []int) {
-	return fileDescriptor_cli_to_hub_ed2de3a86ddbffdf, []int{16}
+	return fileDescriptor_cli_to_hub_efce300fdd070e8b, []int{36}
 }
 func (m *CheckConfigReply) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_CheckConfigReply.Unmarshal(m, b)
@@ -717,7 +1599,7 @@ func (m *CheckSeginstallRequest) Reset()         { *m = CheckSeginstallRequest{}
 func (m *CheckSeginstallRequest) String() string { return proto.CompactTextString(m) }
 func (*CheckSeginstallRequest) ProtoMessage()    {}
 func (*CheckSeginstallRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_cli_to_hub_ed2de3a86ddbffdf, []int{17}
+	return fileDescriptor_cli_to_hub_efce300fdd070e8b, []int{37}
 }
 func (m *CheckSeginstallRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_CheckSeginstallRequest.Unmarshal(m, b)
@@ -747,7 +1629,7 @@ func (m *CheckSeginstallReply) Reset()         { *m = CheckSeginstallReply{} }
 func (m *CheckSeginstallReply) String() string { return proto.CompactTextString(m) }
 func (*CheckSeginstallReply) ProtoMessage()    {}
 func (*CheckSeginstallReply) Descriptor() ([]byte, []int) {
-	return fileDescriptor_cli_to_hub_ed2de3a86ddbffdf, []int{18}
+	return fileDescriptor_cli_to_hub_efce300fdd070e8b, []int{38}
 }
 func (m *CheckSeginstallReply) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_CheckSeginstallReply.Unmarshal(m, b)
@@ -777,7 +1659,7 @@ func (m *PrepareStartAgentsRequest) Reset()         { *m = PrepareStartAgentsReq
 func (m *PrepareStartAgentsRequest) String() string { return proto.CompactTextString(m) }
 func (*PrepareStartAgentsRequest) ProtoMessage()    {}
 func (*PrepareStartAgentsRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_cli_to_hub_ed2de3a86ddbffdf, []int{19}
+	return fileDescriptor_cli_to_hub_efce300fdd070e8b, []int{39}
 }
 func (m *PrepareStartAgentsRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_PrepareStartAgentsRequest.Unmarshal(m, b)
@@ -807,7 +1689,7 @@ func (m *PrepareStartAgentsReply) Reset()         { *m = PrepareStartAgentsReply
 func (m *PrepareStartAgentsReply) String() string { return proto.CompactTextString(m) }
 func (*PrepareStartAgentsReply) ProtoMessage()    {}
 func (*PrepareStartAgentsReply) Descriptor() ([]byte, []int) {
-	return fileDescriptor_cli_to_hub_ed2de3a86ddbffdf, []int{20}
+	return fileDescriptor_cli_to_hub_efce300fdd070e8b, []int{40}
 }
 func (m *PrepareStartAgentsReply) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_PrepareStartAgentsReply.Unmarshal(m, b)
@@ -840,7 +1722,7 @@ func (m *CountPerDb) Reset()         { *m = CountPerDb{} }
 func (m *CountPerDb) String() string { return proto.CompactTextString(m) }
 func (*CountPerDb) ProtoMessage()    {}
 func (*CountPerDb) Descriptor() ([]byte, []int) {
-	return fileDescriptor_cli_to_hub_ed2de3a86ddbffdf, []int{21}
+	return fileDescriptor_cli_to_hub_efce300fdd070e8b, []int{41}
 }
 func (m *CountPerDb) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_CountPerDb.Unmarshal(m, b)
@@ -892,7 +1774,7 @@ func (m *CheckObjectCountRequest) Reset()         { *m = CheckObjectCountRequest
 func (m *CheckObjectCountRequest) String() string { return proto.CompactTextString(m) }
 func (*CheckObjectCountRequest) ProtoMessage()    {}
 func (*CheckObjectCountRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_cli_to_hub_ed2de3a86ddbffdf, []int{22}
+	return fileDescriptor_cli_to_hub_efce300fdd070e8b, []int{42}
 }
 func (m *CheckObjectCountRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_CheckObjectCountRequest.Unmarshal(m, b)
@@ -930,7 +1812,7 @@ func (m *CheckObjectCountReply) Reset()         { *m = CheckObjectCountReply{} }
 func (m *CheckObjectCountReply) String() string { return proto.CompactTextString(m) }
 func (*CheckObjectCountReply) ProtoMessage()    {}
 func (*CheckObjectCountReply) Descriptor() ([]byte, []int) {
-	return fileDescriptor_cli_to_hub_ed2de3a86ddbffdf, []int{23}
+	return fileDescriptor_cli_to_hub_efce300fdd070e8b, []int{43}
 }
 func (m *CheckObjectCountReply) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_CheckObjectCountReply.Unmarshal(m, b)
@@ -969,7 +1851,7 @@ func (m *CheckVersionRequest) Reset()         { *m = CheckVersionRequest{} }
 func (m *CheckVersionRequest) String() string { return proto.CompactTextString(m) }
 func (*CheckVersionRequest) ProtoMessage()    {}
 func (*CheckVersionRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_cli_to_hub_ed2de3a86ddbffdf, []int{24}
+	return fileDescriptor_cli_to_hub_efce300fdd070e8b, []int{44}
 }
 func (m *CheckVersionRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_CheckVersionRequest.Unmarshal(m, b)
@@ -1005,6 +1887,7 @@ func (m *CheckVersionRequest) GetHost() string {
 
 type CheckVersionReply struct {
 	IsVersionCompatible  bool     `protobuf:"varint,1,opt,name=IsVersionCompatible" json:"IsVersionCompatible,omitempty"`
+	Reason               string   `protobuf:"bytes,2,opt,name=Reason" json:"Reason,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -1014,7 +1897,7 @@ func (m *CheckVersionReply) Reset()         { *m = CheckVersionReply{} }
 func (m *CheckVersionReply) String() string { return proto.CompactTextString(m) }
 func (*CheckVersionReply) ProtoMessage()    {}
 func (*CheckVersionReply) Descriptor() ([]byte, []int) {
-	return fileDescriptor_cli_to_hub_ed2de3a86ddbffdf, []int{25}
+	return fileDescriptor_cli_to_hub_efce300fdd070e8b, []int{45}
 }
 func (m *CheckVersionReply) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_CheckVersionReply.Unmarshal(m, b)
@@ -1041,6 +1924,13 @@ func (m *CheckVersionReply) GetIsVersionCompatible() bool {
 	return false
 }
 
+func (m *CheckVersionReply) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
 type CheckDiskSpaceRequest struct {
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
@@ -1051,7 +1941,7 @@ func (m *CheckDiskSpaceRequest) Reset()         { *m = CheckDiskSpaceRequest{} }
 func (m *CheckDiskSpaceRequest) String() string { return proto.CompactTextString(m) }
 func (*CheckDiskSpaceRequest) ProtoMessage()    {}
 func (*CheckDiskSpaceRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_cli_to_hub_ed2de3a86ddbffdf, []int{26}
+	return fileDescriptor_cli_to_hub_efce300fdd070e8b, []int{46}
 }
 func (m *CheckDiskSpaceRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_CheckDiskSpaceRequest.Unmarshal(m, b)
@@ -1069,44 +1959,276 @@ func (m *CheckDiskSpaceRequest) XXX_DiscardUnknown() {
 	xxx_messageInfo_CheckDiskSpaceRequest.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_CheckDiskSpaceRequest proto.InternalMessageInfo
+var xxx_messageInfo_CheckDiskSpaceRequest proto.InternalMessageInfo
+
+type CheckDiskSpaceReply struct {
+	Hosts                []*HostDiskSpace `protobuf:"bytes,2,rep,name=Hosts" json:"Hosts,omitempty"`
+	LinkMode             bool             `protobuf:"varint,3,opt,name=LinkMode" json:"LinkMode,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *CheckDiskSpaceReply) Reset()         { *m = CheckDiskSpaceReply{} }
+func (m *CheckDiskSpaceReply) String() string { return proto.CompactTextString(m) }
+func (*CheckDiskSpaceReply) ProtoMessage()    {}
+func (*CheckDiskSpaceReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cli_to_hub_efce300fdd070e8b, []int{47}
+}
+func (m *CheckDiskSpaceReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CheckDiskSpaceReply.Unmarshal(m, b)
+}
+func (m *CheckDiskSpaceReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CheckDiskSpaceReply.Marshal(b, m, deterministic)
+}
+func (dst *CheckDiskSpaceReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CheckDiskSpaceReply.Merge(dst, src)
+}
+func (m *CheckDiskSpaceReply) XXX_Size() int {
+	return xxx_messageInfo_CheckDiskSpaceReply.Size(m)
+}
+func (m *CheckDiskSpaceReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_CheckDiskSpaceReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CheckDiskSpaceReply proto.InternalMessageInfo
+
+func (m *CheckDiskSpaceReply) GetHosts() []*HostDiskSpace {
+	if m != nil {
+		return m.Hosts
+	}
+	return nil
+}
+
+func (m *CheckDiskSpaceReply) GetLinkMode() bool {
+	if m != nil {
+		return m.LinkMode
+	}
+	return false
+}
+
+type CheckAllRequest struct {
+	MasterHost           string   `protobuf:"bytes,1,opt,name=MasterHost" json:"MasterHost,omitempty"`
+	DbPort               int32    `protobuf:"varint,2,opt,name=DbPort" json:"DbPort,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CheckAllRequest) Reset()         { *m = CheckAllRequest{} }
+func (m *CheckAllRequest) String() string { return proto.CompactTextString(m) }
+func (*CheckAllRequest) ProtoMessage()    {}
+func (*CheckAllRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cli_to_hub_efce300fdd070e8b, []int{48}
+}
+func (m *CheckAllRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CheckAllRequest.Unmarshal(m, b)
+}
+func (m *CheckAllRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CheckAllRequest.Marshal(b, m, deterministic)
+}
+func (dst *CheckAllRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CheckAllRequest.Merge(dst, src)
+}
+func (m *CheckAllRequest) XXX_Size() int {
+	return xxx_messageInfo_CheckAllRequest.Size(m)
+}
+func (m *CheckAllRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CheckAllRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CheckAllRequest proto.InternalMessageInfo
+
+func (m *CheckAllRequest) GetMasterHost() string {
+	if m != nil {
+		return m.MasterHost
+	}
+	return ""
+}
+
+func (m *CheckAllRequest) GetDbPort() int32 {
+	if m != nil {
+		return m.DbPort
+	}
+	return 0
+}
+
+type CheckAllReply struct {
+	Results              []*CheckResult `protobuf:"bytes,1,rep,name=Results" json:"Results,omitempty"`
+	Passed               bool           `protobuf:"varint,2,opt,name=Passed" json:"Passed,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *CheckAllReply) Reset()         { *m = CheckAllReply{} }
+func (m *CheckAllReply) String() string { return proto.CompactTextString(m) }
+func (*CheckAllReply) ProtoMessage()    {}
+func (*CheckAllReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cli_to_hub_efce300fdd070e8b, []int{49}
+}
+func (m *CheckAllReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CheckAllReply.Unmarshal(m, b)
+}
+func (m *CheckAllReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CheckAllReply.Marshal(b, m, deterministic)
+}
+func (dst *CheckAllReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CheckAllReply.Merge(dst, src)
+}
+func (m *CheckAllReply) XXX_Size() int {
+	return xxx_messageInfo_CheckAllReply.Size(m)
+}
+func (m *CheckAllReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_CheckAllReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CheckAllReply proto.InternalMessageInfo
+
+func (m *CheckAllReply) GetResults() []*CheckResult {
+	if m != nil {
+		return m.Results
+	}
+	return nil
+}
+
+func (m *CheckAllReply) GetPassed() bool {
+	if m != nil {
+		return m.Passed
+	}
+	return false
+}
+
+type CheckResult struct {
+	Name                 string          `protobuf:"bytes,1,opt,name=Name" json:"Name,omitempty"`
+	Severity             CheckSeverity   `protobuf:"varint,2,opt,name=Severity,enum=idl.CheckSeverity" json:"Severity,omitempty"`
+	Hosts                []string        `protobuf:"bytes,3,rep,name=Hosts" json:"Hosts,omitempty"`
+	Passed               bool            `protobuf:"varint,4,opt,name=Passed" json:"Passed,omitempty"`
+	Findings             []*CheckFinding `protobuf:"bytes,5,rep,name=Findings" json:"Findings,omitempty"`
+	Error                string          `protobuf:"bytes,6,opt,name=Error" json:"Error,omitempty"`
+	Notes                []*CheckFinding `protobuf:"bytes,7,rep,name=Notes" json:"Notes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *CheckResult) Reset()         { *m = CheckResult{} }
+func (m *CheckResult) String() string { return proto.CompactTextString(m) }
+func (*CheckResult) ProtoMessage()    {}
+func (*CheckResult) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cli_to_hub_efce300fdd070e8b, []int{50}
+}
+func (m *CheckResult) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CheckResult.Unmarshal(m, b)
+}
+func (m *CheckResult) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CheckResult.Marshal(b, m, deterministic)
+}
+func (dst *CheckResult) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CheckResult.Merge(dst, src)
+}
+func (m *CheckResult) XXX_Size() int {
+	return xxx_messageInfo_CheckResult.Size(m)
+}
+func (m *CheckResult) XXX_DiscardUnknown() {
+	xxx_messageInfo_CheckResult.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CheckResult proto.InternalMessageInfo
+
+func (m *CheckResult) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *CheckResult) GetSeverity() CheckSeverity {
+	if m != nil {
+		return m.Severity
+	}
+	return CheckSeverity_UNKNOWN_SEVERITY
+}
+
+func (m *CheckResult) GetHosts() []string {
+	if m != nil {
+		return m.Hosts
+	}
+	return nil
+}
+
+func (m *CheckResult) GetPassed() bool {
+	if m != nil {
+		return m.Passed
+	}
+	return false
+}
+
+func (m *CheckResult) GetFindings() []*CheckFinding {
+	if m != nil {
+		return m.Findings
+	}
+	return nil
+}
+
+func (m *CheckResult) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+func (m *CheckResult) GetNotes() []*CheckFinding {
+	if m != nil {
+		return m.Notes
+	}
+	return nil
+}
 
-type CheckDiskSpaceReply struct {
-	SegmentFileSysUsage  []string `protobuf:"bytes,1,rep,name=SegmentFileSysUsage" json:"SegmentFileSysUsage,omitempty"`
+type CheckFinding struct {
+	Host                 string   `protobuf:"bytes,1,opt,name=Host" json:"Host,omitempty"`
+	Message              string   `protobuf:"bytes,2,opt,name=Message" json:"Message,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *CheckDiskSpaceReply) Reset()         { *m = CheckDiskSpaceReply{} }
-func (m *CheckDiskSpaceReply) String() string { return proto.CompactTextString(m) }
-func (*CheckDiskSpaceReply) ProtoMessage()    {}
-func (*CheckDiskSpaceReply) Descriptor() ([]byte, []int) {
-	return fileDescriptor_cli_to_hub_ed2de3a86ddbffdf, []int{27}
+func (m *CheckFinding) Reset()         { *m = CheckFinding{} }
+func (m *CheckFinding) String() string { return proto.CompactTextString(m) }
+func (*CheckFinding) ProtoMessage()    {}
+func (*CheckFinding) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cli_to_hub_efce300fdd070e8b, []int{51}
 }
-func (m *CheckDiskSpaceReply) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_CheckDiskSpaceReply.Unmarshal(m, b)
+func (m *CheckFinding) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CheckFinding.Unmarshal(m, b)
 }
-func (m *CheckDiskSpaceReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_CheckDiskSpaceReply.Marshal(b, m, deterministic)
+func (m *CheckFinding) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CheckFinding.Marshal(b, m, deterministic)
 }
-func (dst *CheckDiskSpaceReply) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_CheckDiskSpaceReply.Merge(dst, src)
+func (dst *CheckFinding) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CheckFinding.Merge(dst, src)
 }
-func (m *CheckDiskSpaceReply) XXX_Size() int {
-	return xxx_messageInfo_CheckDiskSpaceReply.Size(m)
+func (m *CheckFinding) XXX_Size() int {
+	return xxx_messageInfo_CheckFinding.Size(m)
 }
-func (m *CheckDiskSpaceReply) XXX_DiscardUnknown() {
-	xxx_messageInfo_CheckDiskSpaceReply.DiscardUnknown(m)
+func (m *CheckFinding) XXX_DiscardUnknown() {
+	xxx_messageInfo_CheckFinding.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_CheckDiskSpaceReply proto.InternalMessageInfo
+var xxx_messageInfo_CheckFinding proto.InternalMessageInfo
+
+func (m *CheckFinding) GetHost() string {
+	if m != nil {
+		return m.Host
+	}
+	return ""
+}
 
-func (m *CheckDiskSpaceReply) GetSegmentFileSysUsage() []string {
+func (m *CheckFinding) GetMessage() string {
 	if m != nil {
-		return m.SegmentFileSysUsage
+		return m.Message
 	}
-	return nil
+	return ""
 }
 
 type PrepareShutdownClustersRequest struct {
@@ -1121,7 +2243,7 @@ func (m *PrepareShutdownClustersRequest) Reset()         { *m = PrepareShutdownC
 func (m *PrepareShutdownClustersRequest) String() string { return proto.CompactTextString(m) }
 func (*PrepareShutdownClustersRequest) ProtoMessage()    {}
 func (*PrepareShutdownClustersRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_cli_to_hub_ed2de3a86ddbffdf, []int{28}
+	return fileDescriptor_cli_to_hub_efce300fdd070e8b, []int{52}
 }
 func (m *PrepareShutdownClustersRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_PrepareShutdownClustersRequest.Unmarshal(m, b)
@@ -1165,7 +2287,7 @@ func (m *PrepareShutdownClustersReply) Reset()         { *m = PrepareShutdownClu
 func (m *PrepareShutdownClustersReply) String() string { return proto.CompactTextString(m) }
 func (*PrepareShutdownClustersReply) ProtoMessage()    {}
 func (*PrepareShutdownClustersReply) Descriptor() ([]byte, []int) {
-	return fileDescriptor_cli_to_hub_ed2de3a86ddbffdf, []int{29}
+	return fileDescriptor_cli_to_hub_efce300fdd070e8b, []int{53}
 }
 func (m *PrepareShutdownClustersReply) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_PrepareShutdownClustersReply.Unmarshal(m, b)
@@ -1197,7 +2319,7 @@ func (m *PrepareInitClusterRequest) Reset()         { *m = PrepareInitClusterReq
 func (m *PrepareInitClusterRequest) String() string { return proto.CompactTextString(m) }
 func (*PrepareInitClusterRequest) ProtoMessage()    {}
 func (*PrepareInitClusterRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_cli_to_hub_ed2de3a86ddbffdf, []int{30}
+	return fileDescriptor_cli_to_hub_efce300fdd070e8b, []int{54}
 }
 func (m *PrepareInitClusterRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_PrepareInitClusterRequest.Unmarshal(m, b)
@@ -1241,7 +2363,7 @@ func (m *PrepareInitClusterReply) Reset()         { *m = PrepareInitClusterReply
 func (m *PrepareInitClusterReply) String() string { return proto.CompactTextString(m) }
 func (*PrepareInitClusterReply) ProtoMessage()    {}
 func (*PrepareInitClusterReply) Descriptor() ([]byte, []int) {
-	return fileDescriptor_cli_to_hub_ed2de3a86ddbffdf, []int{31}
+	return fileDescriptor_cli_to_hub_efce300fdd070e8b, []int{55}
 }
 func (m *PrepareInitClusterReply) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_PrepareInitClusterReply.Unmarshal(m, b)
@@ -1275,7 +2397,7 @@ func (m *UpgradeConvertMasterRequest) Reset()         { *m = UpgradeConvertMaste
 func (m *UpgradeConvertMasterRequest) String() string { return proto.CompactTextString(m) }
 func (*UpgradeConvertMasterRequest) ProtoMessage()    {}
 func (*UpgradeConvertMasterRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_cli_to_hub_ed2de3a86ddbffdf, []int{32}
+	return fileDescriptor_cli_to_hub_efce300fdd070e8b, []int{56}
 }
 func (m *UpgradeConvertMasterRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_UpgradeConvertMasterRequest.Unmarshal(m, b)
@@ -1333,7 +2455,7 @@ func (m *UpgradeConvertMasterReply) Reset()         { *m = UpgradeConvertMasterR
 func (m *UpgradeConvertMasterReply) String() string { return proto.CompactTextString(m) }
 func (*UpgradeConvertMasterReply) ProtoMessage()    {}
 func (*UpgradeConvertMasterReply) Descriptor() ([]byte, []int) {
-	return fileDescriptor_cli_to_hub_ed2de3a86ddbffdf, []int{33}
+	return fileDescriptor_cli_to_hub_efce300fdd070e8b, []int{57}
 }
 func (m *UpgradeConvertMasterReply) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_UpgradeConvertMasterReply.Unmarshal(m, b)
@@ -1354,6 +2476,25 @@ func (m *UpgradeConvertMasterReply) XXX_DiscardUnknown() {
 var xxx_messageInfo_UpgradeConvertMasterReply proto.InternalMessageInfo
 
 func init() {
+	proto.RegisterType((*LogsRequest)(nil), "idl.LogsRequest")
+	proto.RegisterType((*CancelRequest)(nil), "idl.CancelRequest")
+	proto.RegisterType((*CancelReply)(nil), "idl.CancelReply")
+	proto.RegisterType((*StatusAgentsRequest)(nil), "idl.StatusAgentsRequest")
+	proto.RegisterType((*StatusAgentsReply)(nil), "idl.StatusAgentsReply")
+	proto.RegisterType((*AgentHealthStatus)(nil), "idl.AgentHealthStatus")
+	proto.RegisterType((*CheckAgentsRequest)(nil), "idl.CheckAgentsRequest")
+	proto.RegisterType((*CheckAgentsReply)(nil), "idl.CheckAgentsReply")
+	proto.RegisterType((*AgentStatus)(nil), "idl.AgentStatus")
+	proto.RegisterType((*VersionReply)(nil), "idl.VersionReply")
+	proto.RegisterType((*ShutdownRequest)(nil), "idl.ShutdownRequest")
+	proto.RegisterType((*ShutdownReply)(nil), "idl.ShutdownReply")
+	proto.RegisterType((*PrepareStopAgentsRequest)(nil), "idl.PrepareStopAgentsRequest")
+	proto.RegisterType((*PrepareStopAgentsReply)(nil), "idl.PrepareStopAgentsReply")
+	proto.RegisterType((*AgentShutdownStatus)(nil), "idl.AgentShutdownStatus")
+	proto.RegisterType((*RevertRequest)(nil), "idl.RevertRequest")
+	proto.RegisterType((*RevertReply)(nil), "idl.RevertReply")
+	proto.RegisterType((*UpgradeRunRequest)(nil), "idl.UpgradeRunRequest")
+	proto.RegisterType((*UpgradeRunReply)(nil), "idl.UpgradeRunReply")
 	proto.RegisterType((*UpgradeReconfigurePortsRequest)(nil), "idl.UpgradeReconfigurePortsRequest")
 	proto.RegisterType((*UpgradeReconfigurePortsReply)(nil), "idl.UpgradeReconfigurePortsReply")
 	proto.RegisterType((*UpgradeConvertPrimariesRequest)(nil), "idl.UpgradeConvertPrimariesRequest")
@@ -1368,6 +2509,7 @@ func init() {
 	proto.RegisterType((*StatusConversionReply)(nil), "idl.StatusConversionReply")
 	proto.RegisterType((*StatusUpgradeRequest)(nil), "idl.StatusUpgradeRequest")
 	proto.RegisterType((*StatusUpgradeReply)(nil), "idl.StatusUpgradeReply")
+	proto.RegisterType((*WatchUpgradeStatusRequest)(nil), "idl.WatchUpgradeStatusRequest")
 	proto.RegisterType((*UpgradeStepStatus)(nil), "idl.UpgradeStepStatus")
 	proto.RegisterType((*CheckConfigRequest)(nil), "idl.CheckConfigRequest")
 	proto.RegisterType((*CheckConfigReply)(nil), "idl.CheckConfigReply")
@@ -1382,14 +2524,19 @@ func init() {
 	proto.RegisterType((*CheckVersionReply)(nil), "idl.CheckVersionReply")
 	proto.RegisterType((*CheckDiskSpaceRequest)(nil), "idl.CheckDiskSpaceRequest")
 	proto.RegisterType((*CheckDiskSpaceReply)(nil), "idl.CheckDiskSpaceReply")
+	proto.RegisterType((*CheckAllRequest)(nil), "idl.CheckAllRequest")
+	proto.RegisterType((*CheckAllReply)(nil), "idl.CheckAllReply")
+	proto.RegisterType((*CheckResult)(nil), "idl.CheckResult")
+	proto.RegisterType((*CheckFinding)(nil), "idl.CheckFinding")
 	proto.RegisterType((*PrepareShutdownClustersRequest)(nil), "idl.PrepareShutdownClustersRequest")
 	proto.RegisterType((*PrepareShutdownClustersReply)(nil), "idl.PrepareShutdownClustersReply")
 	proto.RegisterType((*PrepareInitClusterRequest)(nil), "idl.PrepareInitClusterRequest")
 	proto.RegisterType((*PrepareInitClusterReply)(nil), "idl.PrepareInitClusterReply")
 	proto.RegisterType((*UpgradeConvertMasterRequest)(nil), "idl.UpgradeConvertMasterRequest")
 	proto.RegisterType((*UpgradeConvertMasterReply)(nil), "idl.UpgradeConvertMasterReply")
+	proto.RegisterEnum("idl.AgentHealth", AgentHealth_name, AgentHealth_value)
 	proto.RegisterEnum("idl.UpgradeSteps", UpgradeSteps_name, UpgradeSteps_value)
-	proto.RegisterEnum("idl.StepStatus", StepStatus_name, StepStatus_value)
+	proto.RegisterEnum("idl.CheckSeverity", CheckSeverity_name, CheckSeverity_value)
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -1406,12 +2553,14 @@ const _ = grpc.SupportPackageIsVersion4
 type CliToHubClient interface {
 	Ping(ctx context.Context, in *PingRequest, opts ...grpc.CallOption) (*PingReply, error)
 	StatusUpgrade(ctx context.Context, in *StatusUpgradeRequest, opts ...grpc.CallOption) (*StatusUpgradeReply, error)
+	WatchUpgradeStatus(ctx context.Context, in *WatchUpgradeStatusRequest, opts ...grpc.CallOption) (CliToHub_WatchUpgradeStatusClient, error)
 	StatusConversion(ctx context.Context, in *StatusConversionRequest, opts ...grpc.CallOption) (*StatusConversionReply, error)
 	CheckConfig(ctx context.Context, in *CheckConfigRequest, opts ...grpc.CallOption) (*CheckConfigReply, error)
 	CheckSeginstall(ctx context.Context, in *CheckSeginstallRequest, opts ...grpc.CallOption) (*CheckSeginstallReply, error)
 	CheckObjectCount(ctx context.Context, in *CheckObjectCountRequest, opts ...grpc.CallOption) (*CheckObjectCountReply, error)
 	CheckVersion(ctx context.Context, in *CheckVersionRequest, opts ...grpc.CallOption) (*CheckVersionReply, error)
 	CheckDiskSpace(ctx context.Context, in *CheckDiskSpaceRequest, opts ...grpc.CallOption) (*CheckDiskSpaceReply, error)
+	CheckAll(ctx context.Context, in *CheckAllRequest, opts ...grpc.CallOption) (*CheckAllReply, error)
 	PrepareInitCluster(ctx context.Context, in *PrepareInitClusterRequest, opts ...grpc.CallOption) (*PrepareInitClusterReply, error)
 	PrepareShutdownClusters(ctx context.Context, in *PrepareShutdownClustersRequest, opts ...grpc.CallOption) (*PrepareShutdownClustersReply, error)
 	UpgradeConvertMaster(ctx context.Context, in *UpgradeConvertMasterRequest, opts ...grpc.CallOption) (*UpgradeConvertMasterReply, error)
@@ -1420,6 +2569,14 @@ type CliToHubClient interface {
 	UpgradeValidateStartCluster(ctx context.Context, in *UpgradeValidateStartClusterRequest, opts ...grpc.CallOption) (*UpgradeValidateStartClusterReply, error)
 	UpgradeConvertPrimaries(ctx context.Context, in *UpgradeConvertPrimariesRequest, opts ...grpc.CallOption) (*UpgradeConvertPrimariesReply, error)
 	UpgradeReconfigurePorts(ctx context.Context, in *UpgradeReconfigurePortsRequest, opts ...grpc.CallOption) (*UpgradeReconfigurePortsReply, error)
+	UpgradeRun(ctx context.Context, in *UpgradeRunRequest, opts ...grpc.CallOption) (*UpgradeRunReply, error)
+	Revert(ctx context.Context, in *RevertRequest, opts ...grpc.CallOption) (*RevertReply, error)
+	PrepareStopAgents(ctx context.Context, in *PrepareStopAgentsRequest, opts ...grpc.CallOption) (*PrepareStopAgentsReply, error)
+	Shutdown(ctx context.Context, in *ShutdownRequest, opts ...grpc.CallOption) (*ShutdownReply, error)
+	CheckAgents(ctx context.Context, in *CheckAgentsRequest, opts ...grpc.CallOption) (*CheckAgentsReply, error)
+	StatusAgents(ctx context.Context, in *StatusAgentsRequest, opts ...grpc.CallOption) (*StatusAgentsReply, error)
+	Logs(ctx context.Context, in *LogsRequest, opts ...grpc.CallOption) (CliToHub_LogsClient, error)
+	Cancel(ctx context.Context, in *CancelRequest, opts ...grpc.CallOption) (*CancelReply, error)
 }
 
 type cliToHubClient struct {
@@ -1448,6 +2605,38 @@ func (c *cliToHubClient) StatusUpgrade(ctx context.Context, in *StatusUpgradeReq
 	return out, nil
 }
 
+func (c *cliToHubClient) WatchUpgradeStatus(ctx context.Context, in *WatchUpgradeStatusRequest, opts ...grpc.CallOption) (CliToHub_WatchUpgradeStatusClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_CliToHub_serviceDesc.Streams[0], "/idl.CliToHub/WatchUpgradeStatus", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &cliToHubWatchUpgradeStatusClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type CliToHub_WatchUpgradeStatusClient interface {
+	Recv() (*UpgradeStepStatus, error)
+	grpc.ClientStream
+}
+
+type cliToHubWatchUpgradeStatusClient struct {
+	grpc.ClientStream
+}
+
+func (x *cliToHubWatchUpgradeStatusClient) Recv() (*UpgradeStepStatus, error) {
+	m := new(UpgradeStepStatus)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func (c *cliToHubClient) StatusConversion(ctx context.Context, in *StatusConversionRequest, opts ...grpc.CallOption) (*StatusConversionReply, error) {
 	out := new(StatusConversionReply)
 	err := c.cc.Invoke(ctx, "/idl.CliToHub/StatusConversion", in, out, opts...)
@@ -1502,6 +2691,15 @@ func (c *cliToHubClient) CheckDiskSpace(ctx context.Context, in *CheckDiskSpaceR
 	return out, nil
 }
 
+func (c *cliToHubClient) CheckAll(ctx context.Context, in *CheckAllRequest, opts ...grpc.CallOption) (*CheckAllReply, error) {
+	out := new(CheckAllReply)
+	err := c.cc.Invoke(ctx, "/idl.CliToHub/CheckAll", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *cliToHubClient) PrepareInitCluster(ctx context.Context, in *PrepareInitClusterRequest, opts ...grpc.CallOption) (*PrepareInitClusterReply, error) {
 	out := new(PrepareInitClusterReply)
 	err := c.cc.Invoke(ctx, "/idl.CliToHub/PrepareInitCluster", in, out, opts...)
@@ -1574,17 +2772,114 @@ func (c *cliToHubClient) UpgradeReconfigurePorts(ctx context.Context, in *Upgrad
 	return out, nil
 }
 
+func (c *cliToHubClient) UpgradeRun(ctx context.Context, in *UpgradeRunRequest, opts ...grpc.CallOption) (*UpgradeRunReply, error) {
+	out := new(UpgradeRunReply)
+	err := c.cc.Invoke(ctx, "/idl.CliToHub/UpgradeRun", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cliToHubClient) Revert(ctx context.Context, in *RevertRequest, opts ...grpc.CallOption) (*RevertReply, error) {
+	out := new(RevertReply)
+	err := c.cc.Invoke(ctx, "/idl.CliToHub/Revert", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cliToHubClient) PrepareStopAgents(ctx context.Context, in *PrepareStopAgentsRequest, opts ...grpc.CallOption) (*PrepareStopAgentsReply, error) {
+	out := new(PrepareStopAgentsReply)
+	err := c.cc.Invoke(ctx, "/idl.CliToHub/PrepareStopAgents", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cliToHubClient) Shutdown(ctx context.Context, in *ShutdownRequest, opts ...grpc.CallOption) (*ShutdownReply, error) {
+	out := new(ShutdownReply)
+	err := c.cc.Invoke(ctx, "/idl.CliToHub/Shutdown", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cliToHubClient) CheckAgents(ctx context.Context, in *CheckAgentsRequest, opts ...grpc.CallOption) (*CheckAgentsReply, error) {
+	out := new(CheckAgentsReply)
+	err := c.cc.Invoke(ctx, "/idl.CliToHub/CheckAgents", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cliToHubClient) StatusAgents(ctx context.Context, in *StatusAgentsRequest, opts ...grpc.CallOption) (*StatusAgentsReply, error) {
+	out := new(StatusAgentsReply)
+	err := c.cc.Invoke(ctx, "/idl.CliToHub/StatusAgents", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cliToHubClient) Logs(ctx context.Context, in *LogsRequest, opts ...grpc.CallOption) (CliToHub_LogsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_CliToHub_serviceDesc.Streams[1], "/idl.CliToHub/Logs", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &cliToHubLogsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type CliToHub_LogsClient interface {
+	Recv() (*LogChunk, error)
+	grpc.ClientStream
+}
+
+type cliToHubLogsClient struct {
+	grpc.ClientStream
+}
+
+func (x *cliToHubLogsClient) Recv() (*LogChunk, error) {
+	m := new(LogChunk)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *cliToHubClient) Cancel(ctx context.Context, in *CancelRequest, opts ...grpc.CallOption) (*CancelReply, error) {
+	out := new(CancelReply)
+	err := c.cc.Invoke(ctx, "/idl.CliToHub/Cancel", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // Server API for CliToHub service
 
 type CliToHubServer interface {
 	Ping(context.Context, *PingRequest) (*PingReply, error)
 	StatusUpgrade(context.Context, *StatusUpgradeRequest) (*StatusUpgradeReply, error)
+	WatchUpgradeStatus(*WatchUpgradeStatusRequest, CliToHub_WatchUpgradeStatusServer) error
 	StatusConversion(context.Context, *StatusConversionRequest) (*StatusConversionReply, error)
 	CheckConfig(context.Context, *CheckConfigRequest) (*CheckConfigReply, error)
 	CheckSeginstall(context.Context, *CheckSeginstallRequest) (*CheckSeginstallReply, error)
 	CheckObjectCount(context.Context, *CheckObjectCountRequest) (*CheckObjectCountReply, error)
 	CheckVersion(context.Context, *CheckVersionRequest) (*CheckVersionReply, error)
 	CheckDiskSpace(context.Context, *CheckDiskSpaceRequest) (*CheckDiskSpaceReply, error)
+	CheckAll(context.Context, *CheckAllRequest) (*CheckAllReply, error)
 	PrepareInitCluster(context.Context, *PrepareInitClusterRequest) (*PrepareInitClusterReply, error)
 	PrepareShutdownClusters(context.Context, *PrepareShutdownClustersRequest) (*PrepareShutdownClustersReply, error)
 	UpgradeConvertMaster(context.Context, *UpgradeConvertMasterRequest) (*UpgradeConvertMasterReply, error)
@@ -1593,6 +2888,14 @@ type CliToHubServer interface {
 	UpgradeValidateStartCluster(context.Context, *UpgradeValidateStartClusterRequest) (*UpgradeValidateStartClusterReply, error)
 	UpgradeConvertPrimaries(context.Context, *UpgradeConvertPrimariesRequest) (*UpgradeConvertPrimariesReply, error)
 	UpgradeReconfigurePorts(context.Context, *UpgradeReconfigurePortsRequest) (*UpgradeReconfigurePortsReply, error)
+	UpgradeRun(context.Context, *UpgradeRunRequest) (*UpgradeRunReply, error)
+	Revert(context.Context, *RevertRequest) (*RevertReply, error)
+	PrepareStopAgents(context.Context, *PrepareStopAgentsRequest) (*PrepareStopAgentsReply, error)
+	Shutdown(context.Context, *ShutdownRequest) (*ShutdownReply, error)
+	CheckAgents(context.Context, *CheckAgentsRequest) (*CheckAgentsReply, error)
+	StatusAgents(context.Context, *StatusAgentsRequest) (*StatusAgentsReply, error)
+	Logs(*LogsRequest, CliToHub_LogsServer) error
+	Cancel(context.Context, *CancelRequest) (*CancelReply, error)
 }
 
 func RegisterCliToHubServer(s *grpc.Server, srv CliToHubServer) {
@@ -1635,6 +2938,27 @@ func _CliToHub_StatusUpgrade_Handler(srv interface{}, ctx context.Context, dec f
 	return interceptor(ctx, in, info, handler)
 }
 
+func _CliToHub_WatchUpgradeStatus_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchUpgradeStatusRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(CliToHubServer).WatchUpgradeStatus(m, &cliToHubWatchUpgradeStatusServer{stream})
+}
+
+type CliToHub_WatchUpgradeStatusServer interface {
+	Send(*UpgradeStepStatus) error
+	grpc.ServerStream
+}
+
+type cliToHubWatchUpgradeStatusServer struct {
+	grpc.ServerStream
+}
+
+func (x *cliToHubWatchUpgradeStatusServer) Send(m *UpgradeStepStatus) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 func _CliToHub_StatusConversion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(StatusConversionRequest)
 	if err := dec(in); err != nil {
@@ -1743,6 +3067,24 @@ func _CliToHub_CheckDiskSpace_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _CliToHub_CheckAll_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CheckAllRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CliToHubServer).CheckAll(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/idl.CliToHub/CheckAll",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CliToHubServer).CheckAll(ctx, req.(*CheckAllRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _CliToHub_PrepareInitCluster_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(PrepareInitClusterRequest)
 	if err := dec(in); err != nil {
@@ -1887,6 +3229,153 @@ func _CliToHub_UpgradeReconfigurePorts_Handler(srv interface{}, ctx context.Cont
 	return interceptor(ctx, in, info, handler)
 }
 
+func _CliToHub_UpgradeRun_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpgradeRunRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CliToHubServer).UpgradeRun(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/idl.CliToHub/UpgradeRun",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CliToHubServer).UpgradeRun(ctx, req.(*UpgradeRunRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CliToHub_Revert_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevertRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CliToHubServer).Revert(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/idl.CliToHub/Revert",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CliToHubServer).Revert(ctx, req.(*RevertRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CliToHub_PrepareStopAgents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PrepareStopAgentsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CliToHubServer).PrepareStopAgents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/idl.CliToHub/PrepareStopAgents",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CliToHubServer).PrepareStopAgents(ctx, req.(*PrepareStopAgentsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CliToHub_Shutdown_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ShutdownRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CliToHubServer).Shutdown(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/idl.CliToHub/Shutdown",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CliToHubServer).Shutdown(ctx, req.(*ShutdownRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CliToHub_CheckAgents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CheckAgentsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CliToHubServer).CheckAgents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/idl.CliToHub/CheckAgents",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CliToHubServer).CheckAgents(ctx, req.(*CheckAgentsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CliToHub_StatusAgents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StatusAgentsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CliToHubServer).StatusAgents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/idl.CliToHub/StatusAgents",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CliToHubServer).StatusAgents(ctx, req.(*StatusAgentsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CliToHub_Logs_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(LogsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(CliToHubServer).Logs(m, &cliToHubLogsServer{stream})
+}
+
+type CliToHub_LogsServer interface {
+	Send(*LogChunk) error
+	grpc.ServerStream
+}
+
+type cliToHubLogsServer struct {
+	grpc.ServerStream
+}
+
+func (x *cliToHubLogsServer) Send(m *LogChunk) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _CliToHub_Cancel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CliToHubServer).Cancel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/idl.CliToHub/Cancel",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CliToHubServer).Cancel(ctx, req.(*CancelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _CliToHub_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "idl.CliToHub",
 	HandlerType: (*CliToHubServer)(nil),
@@ -1923,6 +3412,10 @@ var _CliToHub_serviceDesc = grpc.ServiceDesc{
 			MethodName: "CheckDiskSpace",
 			Handler:    _CliToHub_CheckDiskSpace_Handler,
 		},
+		{
+			MethodName: "CheckAll",
+			Handler:    _CliToHub_CheckAll_Handler,
+		},
 		{
 			MethodName: "PrepareInitCluster",
 			Handler:    _CliToHub_PrepareInitCluster_Handler,
@@ -1955,89 +3448,185 @@ var _CliToHub_serviceDesc = grpc.ServiceDesc{
 			MethodName: "UpgradeReconfigurePorts",
 			Handler:    _CliToHub_UpgradeReconfigurePorts_Handler,
 		},
+		{
+			MethodName: "UpgradeRun",
+			Handler:    _CliToHub_UpgradeRun_Handler,
+		},
+		{
+			MethodName: "Revert",
+			Handler:    _CliToHub_Revert_Handler,
+		},
+		{
+			MethodName: "PrepareStopAgents",
+			Handler:    _CliToHub_PrepareStopAgents_Handler,
+		},
+		{
+			MethodName: "Shutdown",
+			Handler:    _CliToHub_Shutdown_Handler,
+		},
+		{
+			MethodName: "CheckAgents",
+			Handler:    _CliToHub_CheckAgents_Handler,
+		},
+		{
+			MethodName: "StatusAgents",
+			Handler:    _CliToHub_StatusAgents_Handler,
+		},
+		{
+			MethodName: "Cancel",
+			Handler:    _CliToHub_Cancel_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchUpgradeStatus",
+			Handler:       _CliToHub_WatchUpgradeStatus_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Logs",
+			Handler:       _CliToHub_Logs_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "cli_to_hub.proto",
 }
 
-func init() { proto.RegisterFile("cli_to_hub.proto", fileDescriptor_cli_to_hub_ed2de3a86ddbffdf) }
-
-var fileDescriptor_cli_to_hub_ed2de3a86ddbffdf = []byte{
-	// 1215 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x57, 0xdd, 0x6e, 0xa3, 0xc6,
-	0x17, 0xb7, 0x63, 0x6f, 0x3e, 0x8e, 0x1d, 0x87, 0x4c, 0x12, 0xc7, 0x26, 0x91, 0x95, 0x3f, 0x7f,
-	0xad, 0x76, 0x95, 0x8b, 0xa8, 0xcd, 0x4a, 0xbd, 0xad, 0x58, 0x20, 0x31, 0x8d, 0x03, 0x14, 0x70,
-	0x2a, 0x55, 0x95, 0x2c, 0x6c, 0xcf, 0x3a, 0xec, 0x12, 0xe3, 0x02, 0x6e, 0x94, 0x37, 0xe8, 0x2b,
-	0xf4, 0x31, 0xfa, 0x86, 0xd5, 0x30, 0x98, 0x6f, 0xdc, 0x9b, 0xde, 0x31, 0xe7, 0x77, 0xe6, 0x7c,
-	0xcd, 0xe1, 0x77, 0x66, 0x80, 0x99, 0x39, 0xf6, 0x24, 0x70, 0x27, 0xcf, 0xeb, 0xe9, 0xcd, 0xca,
-	0x73, 0x03, 0x17, 0x35, 0xec, 0xb9, 0xc3, 0x5d, 0xc1, 0x60, 0xbc, 0x5a, 0x78, 0xd6, 0x1c, 0xeb,
-	0x78, 0xe6, 0x2e, 0xbf, 0xd8, 0x8b, 0xb5, 0x87, 0x35, 0xd7, 0x0b, 0x7c, 0x1d, 0xff, 0xbe, 0xc6,
-	0x7e, 0xc0, 0x0d, 0xe0, 0xb2, 0x52, 0x63, 0xe5, 0xbc, 0x71, 0xbf, 0xc5, 0x16, 0x04, 0x77, 0xf9,
-	0x07, 0xf6, 0x02, 0xcd, 0xb3, 0x5f, 0x2c, 0xcf, 0xc6, 0x1b, 0x0b, 0xe8, 0x12, 0x0e, 0x54, 0x67,
-	0xfe, 0xd9, 0x5e, 0x8a, 0xb6, 0xd7, 0xab, 0x5f, 0xd5, 0x3f, 0x1e, 0xe8, 0x89, 0x80, 0xa0, 0x0a,
-	0x7e, 0x8d, 0xd0, 0x1d, 0x8a, 0xc6, 0x82, 0x94, 0xf7, 0xa2, 0x75, 0xe2, 0xbd, 0x0f, 0xe7, 0x11,
-	0x6e, 0x3c, 0x5b, 0x1e, 0x56, 0xed, 0x79, 0x1c, 0xf8, 0x39, 0x9c, 0x15, 0x21, 0xb2, 0x67, 0x0a,
-	0x5c, 0x04, 0x3c, 0x59, 0x8e, 0x3d, 0xb7, 0x02, 0x6c, 0x04, 0x96, 0x17, 0x08, 0xce, 0xda, 0x0f,
-	0xb0, 0x97, 0x8a, 0x3a, 0x89, 0xab, 0x9e, 0x8b, 0x0b, 0x0d, 0x00, 0x14, 0xfc, 0x2a, 0x5a, 0x81,
-	0x95, 0x84, 0x9d, 0x92, 0x70, 0x1c, 0x5c, 0x6d, 0xf5, 0x41, 0xe2, 0x38, 0x84, 0x96, 0x66, 0x2f,
-	0x17, 0x9b, 0x78, 0x5b, 0x70, 0x40, 0x97, 0x51, 0x5e, 0x46, 0x60, 0x05, 0x6b, 0x9f, 0xa6, 0xed,
-	0xdb, 0xee, 0x72, 0xa3, 0x77, 0x0f, 0x67, 0x45, 0x68, 0xe5, 0xbc, 0xa1, 0x1b, 0x40, 0xb3, 0x58,
-	0x44, 0x55, 0xb0, 0xdf, 0xab, 0x5f, 0x35, 0x3e, 0x1e, 0xe8, 0x25, 0x08, 0xd7, 0x85, 0x53, 0xfa,
-	0x1d, 0x9f, 0x2f, 0x75, 0xf0, 0x15, 0x50, 0x4e, 0x4e, 0xac, 0x9b, 0xd0, 0x77, 0x6c, 0x3f, 0x50,
-	0xbf, 0x6c, 0x8a, 0x1a, 0xe0, 0x55, 0xc6, 0x49, 0xeb, 0xb6, 0x7b, 0x63, 0xcf, 0x9d, 0x9b, 0x02,
-	0xae, 0x57, 0x6f, 0xe4, 0x66, 0x70, 0x5c, 0x10, 0xa3, 0xf7, 0xd0, 0xf4, 0x03, 0xbc, 0x0a, 0xab,
-	0xde, 0xb9, 0x3d, 0xce, 0x5b, 0xf5, 0xf5, 0x10, 0x46, 0x1f, 0x60, 0xd7, 0x0f, 0x37, 0x84, 0xf5,
-	0xef, 0xdc, 0x1e, 0x85, 0x8a, 0x29, 0xbf, 0x11, 0xcc, 0xfd, 0x04, 0x48, 0x78, 0xc6, 0xb3, 0x6f,
-	0x42, 0xd8, 0xbe, 0x9b, 0x03, 0xee, 0xc2, 0xee, 0x7c, 0x4a, 0x1a, 0x39, 0xf4, 0xf3, 0x4e, 0x8f,
-	0x56, 0xe4, 0xe0, 0xdd, 0xb8, 0x5d, 0xa3, 0x86, 0x8c, 0x05, 0xdc, 0x0f, 0xc0, 0x64, 0x6c, 0x91,
-	0xd2, 0x70, 0xd0, 0xa6, 0x4b, 0xea, 0x37, 0xea, 0x96, 0x8c, 0x8c, 0xeb, 0x41, 0x37, 0xdc, 0x67,
-	0xe0, 0x85, 0xbd, 0xf4, 0x03, 0xcb, 0x71, 0x36, 0xe5, 0xee, 0xc2, 0x69, 0x01, 0x21, 0x2d, 0x70,
-	0x01, 0x7d, 0xcd, 0xc3, 0x2b, 0xcb, 0xa3, 0xad, 0xc3, 0x2f, 0xf0, 0x32, 0xf9, 0x2b, 0xfb, 0x70,
-	0x5e, 0x06, 0xd2, 0x1f, 0x12, 0x04, 0x77, 0xbd, 0x0c, 0x34, 0xec, 0x89, 0x53, 0x92, 0xa5, 0x38,
-	0x55, 0xac, 0x17, 0x1c, 0x45, 0x15, 0xad, 0x50, 0x0f, 0xf6, 0x78, 0x37, 0xd4, 0x0b, 0x73, 0x7c,
-	0xa7, 0x6f, 0x96, 0x24, 0xff, 0x21, 0xb6, 0x56, 0x14, 0x6b, 0x84, 0x58, 0x22, 0xe0, 0xbe, 0x87,
-	0xf3, 0x30, 0x5a, 0x75, 0xfa, 0x15, 0xcf, 0x82, 0x50, 0x96, 0x2a, 0xa8, 0x98, 0x29, 0x28, 0x5d,
-	0x71, 0x23, 0x38, 0x2b, 0x6e, 0x21, 0x75, 0xfb, 0x04, 0xed, 0x51, 0xd8, 0x19, 0xa1, 0x6c, 0xd3,
-	0x45, 0xf4, 0x18, 0x93, 0x14, 0xf4, 0x8c, 0x12, 0xc7, 0xc3, 0x49, 0x68, 0xed, 0x29, 0xf3, 0x57,
-	0x54, 0x39, 0x47, 0x08, 0x9a, 0x43, 0xd7, 0x0f, 0xa2, 0x83, 0x0c, 0xbf, 0x39, 0x09, 0x8e, 0xb3,
-	0x26, 0x48, 0x30, 0xdf, 0xc1, 0x89, 0xec, 0x47, 0x12, 0xc1, 0x7d, 0x59, 0x59, 0x81, 0x3d, 0x75,
-	0x68, 0xd5, 0xf6, 0xf5, 0x32, 0x88, 0x10, 0x4c, 0x68, 0x46, 0xb4, 0xfd, 0x6f, 0xc6, 0xca, 0x9a,
-	0xe1, 0xe4, 0x0f, 0x3d, 0xc9, 0x03, 0x91, 0x07, 0x03, 0x2f, 0x5e, 0xf0, 0x32, 0xb8, 0xb3, 0x1d,
-	0x6c, 0xbc, 0xf9, 0x63, 0xdf, 0x5a, 0xe0, 0xe8, 0x07, 0x2d, 0x83, 0x08, 0xb7, 0x6e, 0x4e, 0xf9,
-	0x79, 0x1d, 0xcc, 0xdd, 0xd7, 0x65, 0x44, 0x20, 0xff, 0x15, 0xb7, 0x56, 0x5a, 0x27, 0x8d, 0xf4,
-	0x73, 0xdc, 0x80, 0xf2, 0xd2, 0xce, 0xd3, 0x63, 0x55, 0xbd, 0xb7, 0xbb, 0x4c, 0xda, 0x36, 0x63,
-	0x92, 0x78, 0xfb, 0xab, 0x0e, 0x17, 0x59, 0xaa, 0x7f, 0xb4, 0x72, 0x7c, 0xbc, 0x25, 0xd3, 0x01,
-	0x80, 0xea, 0xcc, 0x73, 0x7c, 0x9c, 0x48, 0xb2, 0x61, 0x35, 0xb6, 0xb3, 0x79, 0xb3, 0xc0, 0xe6,
-	0x17, 0xd0, 0x2f, 0x0f, 0x6d, 0xe5, 0xbc, 0x5d, 0xff, 0xb9, 0x03, 0xed, 0x34, 0x3b, 0x21, 0x06,
-	0xda, 0x63, 0xe5, 0x41, 0x51, 0x7f, 0x51, 0x26, 0x86, 0x29, 0x69, 0x4c, 0x8d, 0x48, 0x84, 0xa1,
-	0x24, 0x3c, 0x4c, 0x04, 0x55, 0xb9, 0x93, 0xef, 0x99, 0x3a, 0xea, 0x00, 0x18, 0xd2, 0xbd, 0xac,
-	0x18, 0x26, 0x3f, 0x1a, 0x31, 0x3b, 0xa8, 0x07, 0xa7, 0x9a, 0x2e, 0x69, 0xbc, 0x2e, 0x4d, 0x64,
-	0x45, 0x36, 0x27, 0xc2, 0x68, 0x6c, 0x98, 0x92, 0xce, 0x34, 0xd0, 0x31, 0x1c, 0x3e, 0xf2, 0xe4,
-	0x7b, 0xac, 0xdd, 0xeb, 0xbc, 0x28, 0x31, 0x4d, 0x74, 0x02, 0x47, 0x86, 0xa9, 0x6a, 0x9a, 0x24,
-	0xc6, 0x7a, 0xef, 0xd2, 0x16, 0x0c, 0x93, 0xd7, 0xcd, 0x09, 0x7f, 0x2f, 0x29, 0xa6, 0xc1, 0xec,
-	0x12, 0x5f, 0x82, 0xaa, 0x3c, 0x49, 0xba, 0x21, 0xab, 0x0a, 0xb3, 0x17, 0xfa, 0x1e, 0x12, 0x3d,
-	0x55, 0x16, 0x0d, 0x66, 0x1f, 0xb1, 0xd0, 0x7d, 0xe2, 0x47, 0xb2, 0xc8, 0x9b, 0x9b, 0xad, 0x1b,
-	0xab, 0x07, 0xe8, 0x0c, 0x8e, 0xe9, 0x5e, 0x73, 0xa2, 0xe9, 0xf2, 0x23, 0xaf, 0xcb, 0x92, 0xc1,
-	0x00, 0x11, 0xeb, 0x12, 0x4d, 0x66, 0xac, 0x4b, 0x13, 0x4d, 0xd5, 0x4d, 0x83, 0x69, 0x5d, 0x9b,
-	0x00, 0x29, 0x1a, 0x47, 0xd0, 0x49, 0xea, 0xc0, 0x9b, 0x63, 0x83, 0xa9, 0xa1, 0x16, 0xec, 0x69,
-	0x92, 0x22, 0xca, 0x0a, 0x29, 0x42, 0x0b, 0xf6, 0xf4, 0xb1, 0xa2, 0x90, 0xc5, 0x0e, 0x6a, 0xc3,
-	0xbe, 0xa0, 0x3e, 0x6a, 0x23, 0xc9, 0x94, 0x98, 0x06, 0x02, 0xd8, 0xbd, 0xe3, 0xe5, 0x91, 0x24,
-	0x32, 0xcd, 0xdb, 0xbf, 0x01, 0xf6, 0x05, 0xc7, 0x36, 0xdd, 0xe1, 0x7a, 0x8a, 0xae, 0xa1, 0x49,
-	0xa6, 0x24, 0x62, 0x42, 0x96, 0x48, 0xcd, 0x4f, 0xb6, 0x93, 0x92, 0x90, 0x86, 0xaa, 0x21, 0x09,
-	0x0e, 0x33, 0x83, 0x0c, 0xf5, 0xa3, 0x09, 0x51, 0x1c, 0x7a, 0xec, 0x79, 0x19, 0x44, 0xcd, 0x28,
-	0xc0, 0xe4, 0x07, 0x2e, 0xba, 0x4c, 0xa9, 0x17, 0x46, 0x34, 0xcb, 0x56, 0xa0, 0xd4, 0xde, 0x8f,
-	0xd0, 0x4a, 0x8d, 0x10, 0x44, 0x3d, 0x17, 0x07, 0x14, 0x7b, 0x56, 0x04, 0xa8, 0x81, 0x07, 0x38,
-	0xca, 0x4d, 0x0c, 0x74, 0x91, 0xe8, 0x16, 0x26, 0x0c, 0xdb, 0x2f, 0x07, 0xe3, 0xec, 0xf2, 0xec,
-	0x1c, 0x65, 0x57, 0xc1, 0xf3, 0x51, 0x76, 0xa5, 0x94, 0xce, 0xd5, 0xd0, 0x67, 0x68, 0xa7, 0xc9,
-	0x15, 0xf5, 0x12, 0xed, 0x2c, 0x65, 0xb3, 0xdd, 0x12, 0x84, 0xda, 0x18, 0x42, 0x27, 0x4b, 0xa0,
-	0x28, 0xe5, 0x33, 0x4f, 0xb7, 0x6c, 0xaf, 0x14, 0xa3, 0x96, 0x4c, 0x40, 0x45, 0xc2, 0x41, 0x03,
-	0xda, 0x2a, 0x55, 0xe4, 0xc6, 0x5e, 0x56, 0xe2, 0xd4, 0xea, 0x2c, 0x99, 0xbe, 0x39, 0xe6, 0x44,
-	0xff, 0x4f, 0x6f, 0xad, 0x60, 0x6d, 0xf6, 0x7f, 0xdb, 0x95, 0xa8, 0x93, 0x5f, 0xe1, 0xb4, 0x8c,
-	0x74, 0xd0, 0x55, 0xfa, 0x3e, 0x54, 0x46, 0x95, 0xec, 0x60, 0x8b, 0x46, 0xbe, 0x2c, 0xa9, 0xeb,
-	0x43, 0xb6, 0x2c, 0xc5, 0x4b, 0x47, 0xb6, 0x2c, 0x85, 0x7b, 0x47, 0xd8, 0x4a, 0xf9, 0x1b, 0x77,
-	0xd4, 0x4a, 0x15, 0x77, 0xf4, 0xa8, 0x95, 0xca, 0xaf, 0xe9, 0x35, 0xe4, 0xc6, 0x13, 0xa1, 0xec,
-	0x12, 0x8d, 0x3e, 0xa4, 0x37, 0x6f, 0xb9, 0xca, 0xb3, 0xef, 0xff, 0x5d, 0x31, 0x3e, 0xd7, 0x8a,
-	0xd7, 0x46, 0x74, 0xae, 0xdb, 0x5f, 0x3a, 0xd1, 0xb9, 0x6e, 0x7d, 0xb0, 0xa4, 0x9d, 0xe4, 0x1f,
-	0x54, 0x59, 0x27, 0x15, 0x0f, 0xb2, 0xac, 0x93, 0xf2, 0x37, 0x59, 0x6d, 0xba, 0x1b, 0xbe, 0xf1,
-	0x3e, 0xfd, 0x13, 0x00, 0x00, 0xff, 0xff, 0x94, 0xff, 0x62, 0x71, 0xf7, 0x0d, 0x00, 0x00,
+func init() { proto.RegisterFile("cli_to_hub.proto", fileDescriptor_cli_to_hub_efce300fdd070e8b) }
+
+var fileDescriptor_cli_to_hub_efce300fdd070e8b = []byte{
+	// 2125 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x59, 0xdb, 0x6e, 0xe3, 0xc8,
+	0xd1, 0x36, 0xad, 0x83, 0xa5, 0x92, 0x65, 0x53, 0x6d, 0x5b, 0x96, 0x69, 0xff, 0x86, 0x86, 0xff,
+	0x6e, 0x46, 0xf0, 0x22, 0x86, 0xe3, 0x05, 0x16, 0xb9, 0x18, 0x20, 0xd0, 0x4a, 0x1c, 0x5b, 0x33,
+	0xb2, 0xa4, 0x6d, 0xca, 0x1e, 0x6c, 0x90, 0xc0, 0xa0, 0xa4, 0x5e, 0x99, 0x3b, 0x14, 0xa9, 0x90,
+	0xd4, 0x0e, 0x7c, 0x9d, 0x9b, 0x00, 0x79, 0x82, 0xe4, 0x0d, 0xf2, 0x00, 0x79, 0xac, 0x5c, 0xe4,
+	0x0d, 0x82, 0x3e, 0xf0, 0x28, 0x51, 0xb9, 0xc9, 0x9d, 0xaa, 0xbe, 0x3a, 0x75, 0x75, 0x75, 0x75,
+	0xb1, 0x05, 0xf2, 0xd4, 0x32, 0x9f, 0x7d, 0xe7, 0xf9, 0x65, 0x35, 0xb9, 0x5e, 0xba, 0x8e, 0xef,
+	0xa0, 0x9c, 0x39, 0xb3, 0x94, 0xfd, 0xa9, 0xb3, 0x58, 0x38, 0x36, 0x67, 0xa9, 0x7f, 0x96, 0xa0,
+	0xd2, 0x77, 0xe6, 0x1e, 0x26, 0x7f, 0x5a, 0x11, 0xcf, 0x47, 0xbf, 0x82, 0xa2, 0xee, 0xac, 0xdc,
+	0x29, 0x69, 0x48, 0x4d, 0xa9, 0x75, 0x70, 0x7b, 0x70, 0x6d, 0xce, 0xac, 0xeb, 0xbe, 0x33, 0xe7,
+	0x5c, 0x2c, 0x50, 0xa4, 0x40, 0xe9, 0xde, 0xf1, 0x7c, 0xdb, 0x58, 0x90, 0xc6, 0x6e, 0x53, 0x6a,
+	0x95, 0x71, 0x48, 0xa3, 0x06, 0xec, 0x75, 0x1c, 0xdb, 0x27, 0xb6, 0xdf, 0xc8, 0x35, 0xa5, 0x56,
+	0x01, 0x07, 0x24, 0xaa, 0x43, 0xf1, 0xbd, 0x63, 0x59, 0xce, 0x97, 0x46, 0xbe, 0x29, 0xb5, 0x4a,
+	0x58, 0x50, 0xea, 0x21, 0x54, 0x3b, 0x86, 0x3d, 0x25, 0x96, 0x08, 0x43, 0xfd, 0x06, 0x2a, 0x01,
+	0x63, 0x69, 0xbd, 0xa2, 0x0b, 0x28, 0x73, 0xd2, 0x22, 0xb3, 0x86, 0xd4, 0xcc, 0xb5, 0xca, 0x38,
+	0x62, 0xa8, 0x27, 0x70, 0xa4, 0xfb, 0x86, 0xbf, 0xf2, 0xda, 0x73, 0x62, 0xfb, 0xc1, 0x52, 0xd4,
+	0x0e, 0xd4, 0x92, 0x6c, 0x6a, 0xe9, 0x1a, 0x8a, 0x9c, 0x64, 0x66, 0x2a, 0xb7, 0x75, 0xb6, 0x3e,
+	0xc6, 0xba, 0x27, 0x86, 0xe5, 0xbf, 0x70, 0x15, 0x2c, 0xa4, 0xd4, 0x7f, 0x4a, 0x50, 0x5b, 0x43,
+	0x13, 0xab, 0x97, 0x52, 0xab, 0x6f, 0x41, 0x91, 0xcb, 0xb2, 0xbc, 0x1c, 0xdc, 0xca, 0x69, 0x0f,
+	0x58, 0xe0, 0x34, 0x4f, 0x4f, 0xc4, 0xf5, 0x4c, 0xc7, 0x66, 0x79, 0x2a, 0xe3, 0x80, 0xa4, 0xf6,
+	0xa9, 0x27, 0xd2, 0x35, 0x5d, 0x96, 0xa9, 0x32, 0x0e, 0x69, 0xf4, 0x15, 0x54, 0xfb, 0x86, 0x47,
+	0x6d, 0xb9, 0xfe, 0x84, 0x18, 0x7e, 0xa3, 0xd0, 0x94, 0x5a, 0x39, 0x9c, 0x64, 0xaa, 0xc7, 0x80,
+	0x3a, 0x2f, 0x64, 0xfa, 0x39, 0x99, 0x92, 0x77, 0x20, 0x27, 0xb8, 0x34, 0x23, 0xad, 0x54, 0x46,
+	0x62, 0xf1, 0xa6, 0x72, 0xf1, 0x0f, 0x09, 0x2a, 0x31, 0xfe, 0xd6, 0x2c, 0x5c, 0x40, 0x19, 0x13,
+	0x63, 0xfa, 0x62, 0x4c, 0x2c, 0x5e, 0x20, 0x25, 0x1c, 0x31, 0xd0, 0x0d, 0x1c, 0xf5, 0x0d, 0x9f,
+	0xd8, 0xd3, 0xd7, 0x07, 0xd3, 0xb2, 0x4c, 0x8f, 0x4c, 0x1d, 0x7b, 0xe6, 0xb1, 0x2c, 0x48, 0x78,
+	0x13, 0x14, 0xcf, 0x55, 0x3e, 0x99, 0xab, 0x63, 0x28, 0x68, 0xae, 0xeb, 0xb8, 0x2c, 0x0f, 0x65,
+	0xcc, 0x09, 0xb5, 0x05, 0xfb, 0x42, 0x80, 0xaf, 0x32, 0xa6, 0x2f, 0x25, 0xf4, 0xd5, 0x1a, 0x1c,
+	0xea, 0x2f, 0x2b, 0x7f, 0xe6, 0x7c, 0xb1, 0xa3, 0xea, 0xab, 0x46, 0x2c, 0xaa, 0xbd, 0x65, 0xa5,
+	0xaa, 0x02, 0x8d, 0x91, 0x4b, 0x96, 0x86, 0x4b, 0x74, 0xdf, 0x59, 0x26, 0xf3, 0xfd, 0x01, 0xea,
+	0x1b, 0x30, 0x6a, 0xf1, 0x26, 0x95, 0xf5, 0x46, 0x2c, 0xeb, 0xc2, 0x75, 0x2a, 0xfb, 0x06, 0x1c,
+	0x6d, 0x80, 0xb7, 0x6e, 0x42, 0x03, 0xf6, 0xa8, 0xdf, 0x25, 0x99, 0x89, 0x2d, 0x08, 0xc8, 0x28,
+	0x69, 0xb9, 0x78, 0xd2, 0x0e, 0xa1, 0x8a, 0xc9, 0x2f, 0xc4, 0xf5, 0x83, 0xf8, 0xab, 0x50, 0x09,
+	0x18, 0x4b, 0xeb, 0x55, 0xfd, 0xab, 0x04, 0xb5, 0xc7, 0xe5, 0xdc, 0x35, 0x66, 0x04, 0xaf, 0x82,
+	0x6c, 0xd1, 0xad, 0x1e, 0x5a, 0xb3, 0xee, 0x64, 0xe4, 0xb8, 0x3e, 0x0b, 0xa1, 0x80, 0x23, 0x86,
+	0x40, 0xbf, 0x37, 0x6d, 0x5a, 0xcb, 0xbc, 0x53, 0x44, 0x0c, 0x8a, 0x0e, 0xc8, 0x17, 0xa1, 0xcb,
+	0x9b, 0x45, 0xc4, 0x10, 0xa8, 0xd0, 0xe5, 0xdb, 0x1e, 0x31, 0xe8, 0xc6, 0xc5, 0x83, 0xa1, 0x01,
+	0x36, 0xe1, 0x32, 0x60, 0xd1, 0xba, 0xf9, 0xc9, 0x9c, 0xaf, 0x5c, 0x42, 0x4d, 0x85, 0x3b, 0x72,
+	0x09, 0x17, 0x99, 0x12, 0xd4, 0xc2, 0x1f, 0x42, 0x0b, 0x1d, 0xc7, 0xa6, 0x2b, 0x1f, 0xb9, 0xe6,
+	0xc2, 0x70, 0x4d, 0xe2, 0x25, 0x97, 0x2b, 0x82, 0x92, 0x36, 0x2f, 0x28, 0xb9, 0xdc, 0x28, 0xe4,
+	0xc8, 0xfb, 0xba, 0x75, 0xea, 0xfd, 0x0c, 0x4e, 0x05, 0xae, 0xbf, 0x18, 0x2e, 0x19, 0x9a, 0xb3,
+	0x30, 0xf0, 0x53, 0x38, 0x59, 0x87, 0xa8, 0xce, 0x57, 0xa0, 0x0a, 0xe0, 0xc9, 0xb0, 0xcc, 0x99,
+	0xe1, 0x13, 0xdd, 0x37, 0x5c, 0xbf, 0x63, 0xad, 0x3c, 0x9f, 0xb8, 0x81, 0xba, 0x0a, 0xcd, 0xad,
+	0x52, 0xd4, 0x52, 0x15, 0x2a, 0x23, 0xd3, 0x9e, 0x07, 0x2a, 0x15, 0x28, 0x73, 0x52, 0x44, 0xc6,
+	0x0b, 0x8e, 0x07, 0xce, 0x0f, 0x16, 0x97, 0x23, 0x70, 0xb2, 0x0e, 0xd1, 0x1a, 0xef, 0x03, 0x9a,
+	0x86, 0x2c, 0x2e, 0x42, 0x82, 0x7a, 0xbf, 0x60, 0xf5, 0xae, 0x93, 0xf9, 0x82, 0xd8, 0x7e, 0x27,
+	0x25, 0x85, 0x37, 0xe8, 0xa9, 0x75, 0x38, 0xe6, 0xbf, 0xc3, 0xfd, 0xe3, 0xee, 0x7f, 0x06, 0x94,
+	0xe2, 0x53, 0xdf, 0x63, 0x38, 0xb3, 0x4c, 0xcf, 0x1f, 0xfe, 0x14, 0x24, 0xcd, 0x27, 0xcb, 0x54,
+	0x08, 0xbc, 0xf5, 0xaf, 0xe1, 0x38, 0x5b, 0x51, 0x3d, 0x87, 0xb3, 0x4f, 0x86, 0x3f, 0x7d, 0x09,
+	0x31, 0xa6, 0x20, 0x02, 0xf9, 0xf7, 0x2e, 0xd4, 0xd6, 0x94, 0xd0, 0xd7, 0x90, 0xf7, 0x7c, 0xb2,
+	0x14, 0xd7, 0x69, 0x2d, 0xed, 0xd3, 0xc3, 0x0c, 0x46, 0x6f, 0xa1, 0xe8, 0x31, 0x05, 0x71, 0x6b,
+	0x1c, 0xf2, 0xfc, 0x44, 0x51, 0x09, 0x18, 0xdd, 0x42, 0x69, 0xe9, 0x3a, 0x73, 0x97, 0x78, 0xbc,
+	0x5f, 0x06, 0xeb, 0x18, 0xcd, 0x85, 0xd5, 0x91, 0x40, 0x71, 0x28, 0x47, 0x8b, 0xd2, 0xa3, 0xbb,
+	0x3d, 0x36, 0x17, 0x84, 0x9d, 0xa3, 0x1c, 0x8e, 0x18, 0xb4, 0x4b, 0x10, 0x7b, 0xc6, 0x30, 0x7e,
+	0x95, 0x04, 0x24, 0x6a, 0xc1, 0xe1, 0x6c, 0xe5, 0x1a, 0x3e, 0xdd, 0x06, 0xd1, 0xa2, 0x8b, 0x4c,
+	0x22, 0xcd, 0x46, 0xef, 0xe0, 0x8c, 0x78, 0xbe, 0xb9, 0x30, 0x7c, 0x32, 0x13, 0x3c, 0x4c, 0x16,
+	0x86, 0x69, 0x9b, 0xf6, 0xbc, 0xb1, 0xc7, 0x74, 0xb2, 0x05, 0xd0, 0x6f, 0xe1, 0x74, 0xe9, 0x92,
+	0x5f, 0x4c, 0x67, 0xe5, 0x75, 0x53, 0xfe, 0x4a, 0xcd, 0x5c, 0x2b, 0x87, 0xb3, 0x60, 0xf5, 0x83,
+	0xb8, 0xe6, 0x3a, 0xec, 0x28, 0x07, 0x47, 0xb4, 0x0e, 0xc5, 0x59, 0xbc, 0x1d, 0x09, 0x8a, 0xe6,
+	0xc1, 0x49, 0xf7, 0xa2, 0x90, 0xa1, 0x7e, 0x07, 0x72, 0xc2, 0x16, 0x2d, 0x23, 0x15, 0xf6, 0x39,
+	0xc9, 0x77, 0x41, 0x9c, 0xf7, 0x04, 0x4f, 0x6d, 0x40, 0x9d, 0xe9, 0xe9, 0x64, 0x6e, 0xda, 0x9e,
+	0x6f, 0x58, 0xe1, 0x14, 0x53, 0x87, 0xe3, 0x35, 0x84, 0x1e, 0xa6, 0x73, 0x38, 0x0b, 0xaf, 0x05,
+	0xc3, 0xf5, 0x93, 0x77, 0xc6, 0x19, 0x9c, 0x6e, 0x02, 0x79, 0x73, 0x82, 0x8e, 0xb3, 0xb2, 0xfd,
+	0x11, 0x71, 0xbb, 0x13, 0xba, 0xca, 0xee, 0x64, 0x10, 0xf5, 0x7d, 0x41, 0xd1, 0xfd, 0x6c, 0x3b,
+	0x4c, 0x8e, 0xad, 0xb1, 0x80, 0x03, 0x92, 0xae, 0xff, 0x9e, 0x18, 0x4b, 0x8e, 0x89, 0x6e, 0x1b,
+	0x32, 0xd4, 0xdf, 0xc0, 0x29, 0x8b, 0x76, 0x38, 0xf9, 0x99, 0x4c, 0x7d, 0xc6, 0x8b, 0x25, 0x34,
+	0xd1, 0xdf, 0x05, 0xa5, 0xf6, 0xe1, 0x64, 0x5d, 0x85, 0xe6, 0xed, 0x5b, 0xd8, 0xef, 0xb3, 0x53,
+	0xc4, 0x78, 0xc1, 0x89, 0xe3, 0x45, 0x1d, 0x2d, 0x01, 0x27, 0x84, 0xd4, 0x36, 0x1c, 0x31, 0x6b,
+	0x4f, 0x89, 0xfe, 0x92, 0xe5, 0x1c, 0x21, 0xc8, 0xd3, 0x9b, 0x4e, 0x6c, 0x24, 0xfb, 0xad, 0xfe,
+	0x11, 0x6a, 0x49, 0x13, 0xfc, 0xae, 0x3d, 0xea, 0x79, 0x82, 0xd3, 0x71, 0x16, 0x4b, 0xc3, 0x37,
+	0xe9, 0x54, 0x22, 0xb1, 0x2b, 0x71, 0x13, 0x44, 0x5d, 0x62, 0x62, 0x78, 0x8e, 0x2d, 0x8c, 0x0b,
+	0x8a, 0x36, 0x61, 0x66, 0xbe, 0x6b, 0x7a, 0x9f, 0xf5, 0xa5, 0x31, 0x0d, 0x9b, 0x90, 0x01, 0x47,
+	0x69, 0x80, 0xcf, 0x56, 0x05, 0x1a, 0x16, 0x3d, 0xd4, 0x74, 0xfd, 0x88, 0xad, 0x9f, 0x72, 0x22,
+	0x39, 0x2e, 0x40, 0xaf, 0xf1, 0xbe, 0x69, 0x7f, 0x7e, 0x70, 0x66, 0x84, 0xed, 0x4c, 0x09, 0x87,
+	0xf4, 0x87, 0x7c, 0x49, 0x92, 0x77, 0xd5, 0x1e, 0x1c, 0xf2, 0xd9, 0x2d, 0xac, 0x2f, 0x74, 0x09,
+	0xf0, 0x60, 0xd0, 0xfe, 0xcd, 0xf2, 0xc0, 0xab, 0x20, 0xc6, 0x89, 0x65, 0x6e, 0x37, 0xb1, 0x6d,
+	0x3a, 0x54, 0x23, 0x53, 0x34, 0xce, 0x2b, 0xd8, 0xc3, 0xc4, 0x5b, 0x59, 0xa9, 0x21, 0x90, 0x09,
+	0x71, 0x00, 0x07, 0x02, 0xd4, 0xe8, 0xc8, 0xf0, 0xbc, 0x70, 0xa6, 0x10, 0x94, 0xfa, 0x2f, 0x09,
+	0x2a, 0x31, 0x05, 0xba, 0x3d, 0xb1, 0xe2, 0x64, 0xbf, 0xd1, 0x35, 0x94, 0x74, 0x3a, 0x4f, 0x98,
+	0xfe, 0xab, 0xe8, 0x73, 0x28, 0x72, 0x14, 0x20, 0x38, 0x94, 0xa1, 0x63, 0x0a, 0xcf, 0x5f, 0x8e,
+	0xcd, 0xfc, 0x9c, 0x88, 0x45, 0x90, 0x8f, 0x47, 0x80, 0x7e, 0x0d, 0xa5, 0xf7, 0xa6, 0x3d, 0x33,
+	0xed, 0xb9, 0xd7, 0x28, 0xb0, 0x65, 0xd4, 0x22, 0xeb, 0x02, 0xc1, 0xa1, 0x48, 0x34, 0x03, 0x15,
+	0x63, 0x33, 0x10, 0x7a, 0x0b, 0x85, 0x81, 0xe3, 0x13, 0xaf, 0xb1, 0x97, 0x65, 0x81, 0xe3, 0xea,
+	0x3b, 0xd8, 0x8f, 0xb3, 0xc3, 0x72, 0x94, 0xa2, 0x72, 0xa4, 0x47, 0xf1, 0x81, 0x78, 0x9e, 0x31,
+	0x0f, 0x3e, 0x92, 0x02, 0x92, 0xce, 0x19, 0xc1, 0x29, 0x17, 0xf3, 0x9c, 0xb8, 0x8a, 0xff, 0x57,
+	0x73, 0x46, 0xa6, 0x75, 0xda, 0x48, 0x7e, 0x08, 0x1b, 0x50, 0xcf, 0x36, 0x53, 0xa3, 0x42, 0xe6,
+	0x79, 0xdb, 0xee, 0x32, 0x6a, 0x5b, 0x09, 0x93, 0xd4, 0xdb, 0xdf, 0x24, 0x38, 0x4f, 0x8e, 0x3d,
+	0x0f, 0x46, 0xdc, 0xe1, 0xf6, 0x95, 0x5e, 0x02, 0xd0, 0x69, 0xd2, 0xf0, 0x8d, 0xc8, 0x6f, 0x8c,
+	0x93, 0x0c, 0x2b, 0x97, 0x0a, 0x8b, 0x6a, 0xd3, 0x79, 0x52, 0x68, 0xf3, 0x19, 0x32, 0xc6, 0xa1,
+	0xad, 0x78, 0x73, 0x68, 0x4b, 0xeb, 0xf5, 0xea, 0x56, 0x7c, 0xef, 0x88, 0xef, 0xb5, 0x0a, 0xec,
+	0x3d, 0xf4, 0x74, 0xbd, 0x37, 0xb8, 0x93, 0x77, 0x28, 0x71, 0xaf, 0xb5, 0xfb, 0xe3, 0xfb, 0x1f,
+	0x65, 0x09, 0x95, 0xa1, 0xa0, 0x8f, 0xdb, 0x7d, 0x4d, 0xde, 0xbd, 0xfa, 0xcb, 0x2e, 0xec, 0xc7,
+	0xef, 0x77, 0x24, 0xc3, 0xfe, 0xe3, 0xe0, 0xe3, 0x60, 0xf8, 0x69, 0xf0, 0xac, 0x8f, 0xb5, 0x91,
+	0xbc, 0x43, 0x39, 0x9d, 0x7b, 0xad, 0xf3, 0xf1, 0xb9, 0x33, 0x1c, 0xbc, 0xef, 0xdd, 0xc9, 0x12,
+	0x3a, 0x00, 0xd0, 0xb5, 0xbb, 0xde, 0x80, 0x1a, 0xe9, 0xcb, 0xbb, 0xa8, 0x01, 0xc7, 0x23, 0xac,
+	0x8d, 0xda, 0x58, 0x7b, 0xee, 0x0d, 0x7a, 0xe3, 0xe7, 0x4e, 0xff, 0x51, 0x1f, 0x6b, 0x58, 0xce,
+	0xa1, 0x1a, 0x54, 0x1f, 0xda, 0xf4, 0xf7, 0xe3, 0xe8, 0x0e, 0xb7, 0xbb, 0x9a, 0x9c, 0x47, 0x47,
+	0x70, 0xa8, 0x8f, 0x87, 0xa3, 0x91, 0xd6, 0x0d, 0xe5, 0x0a, 0x71, 0x0b, 0xfa, 0xb8, 0x8d, 0xc7,
+	0xcf, 0xed, 0x3b, 0x6d, 0x30, 0xd6, 0xe5, 0x22, 0xf5, 0xd5, 0x19, 0x0e, 0x9e, 0x34, 0xac, 0xf7,
+	0x86, 0x03, 0x79, 0x8f, 0xf9, 0xbe, 0xa7, 0x72, 0xc3, 0x5e, 0x57, 0x97, 0x4b, 0x48, 0x81, 0xfa,
+	0x53, 0xbb, 0xdf, 0xeb, 0xb6, 0xc7, 0x81, 0x6a, 0x60, 0xb5, 0x8c, 0x4e, 0xa0, 0xc6, 0x75, 0xc7,
+	0xcf, 0x23, 0xdc, 0x7b, 0x68, 0xe3, 0x9e, 0xa6, 0xcb, 0x40, 0xd9, 0x58, 0xe3, 0x8b, 0x79, 0xc4,
+	0xda, 0xf3, 0x68, 0x88, 0xc7, 0xba, 0x5c, 0xb9, 0xba, 0x13, 0x6d, 0x26, 0x76, 0x9c, 0xe5, 0x30,
+	0x15, 0xda, 0x93, 0x86, 0x7b, 0xe3, 0x1f, 0xe5, 0x1d, 0x9a, 0x3c, 0x0d, 0xe3, 0x21, 0x96, 0x25,
+	0x9a, 0xd4, 0x4f, 0x6d, 0x3c, 0xa0, 0x19, 0xde, 0x45, 0x25, 0xc8, 0xf7, 0x06, 0xef, 0x87, 0x72,
+	0xee, 0xf6, 0xef, 0x87, 0x50, 0xea, 0x58, 0xe6, 0xd8, 0xb9, 0x5f, 0x4d, 0xd0, 0x15, 0xe4, 0xe9,
+	0x58, 0x8a, 0x78, 0x8b, 0x8a, 0x0d, 0xac, 0xca, 0x41, 0x8c, 0x43, 0xeb, 0x6e, 0x07, 0x69, 0x50,
+	0x4d, 0xcc, 0x86, 0xe8, 0x4c, 0x8c, 0x55, 0xeb, 0x73, 0xa4, 0x72, 0xba, 0x09, 0xe2, 0x66, 0x46,
+	0x80, 0xd6, 0xc7, 0x3e, 0x74, 0xc9, 0x14, 0x32, 0xe7, 0x41, 0x25, 0x63, 0xbe, 0x54, 0x77, 0x6e,
+	0x24, 0x34, 0x00, 0x39, 0x3d, 0x33, 0xa3, 0x8b, 0x58, 0x00, 0x6b, 0x53, 0xb6, 0xa2, 0x64, 0xa0,
+	0x3c, 0xc2, 0xdf, 0x89, 0xde, 0xcb, 0x07, 0x13, 0x74, 0x1a, 0x75, 0xad, 0xc4, 0x64, 0xa4, 0x9c,
+	0xac, 0x03, 0xdc, 0xc0, 0x47, 0x38, 0x4c, 0x8d, 0x2a, 0xe8, 0x3c, 0xde, 0x9a, 0x53, 0xa3, 0x8d,
+	0x72, 0xb6, 0x19, 0xe4, 0xc6, 0x06, 0x20, 0xa7, 0xc7, 0x02, 0xb1, 0xba, 0x8c, 0x01, 0x43, 0x51,
+	0x32, 0x50, 0x6e, 0xef, 0x7b, 0xd1, 0x6a, 0x83, 0x4f, 0xfe, 0x46, 0x24, 0x9d, 0x9c, 0x15, 0x94,
+	0xfa, 0x06, 0x84, 0xdb, 0xb8, 0x87, 0x83, 0xe4, 0x0d, 0x8d, 0x62, 0x3e, 0xd3, 0xf7, 0xb9, 0xd2,
+	0xd8, 0x88, 0x71, 0x4b, 0xdf, 0x41, 0x29, 0xb8, 0x3d, 0xd1, 0x71, 0x24, 0x17, 0xdd, 0xcb, 0x0a,
+	0x4a, 0x71, 0xb9, 0xde, 0x18, 0xd0, 0x7a, 0x87, 0x14, 0x55, 0x94, 0xd9, 0x8d, 0x95, 0x8b, 0x4c,
+	0x9c, 0x5b, 0x9d, 0xc2, 0x69, 0x46, 0xab, 0x47, 0xff, 0x1f, 0x57, 0xcd, 0xb8, 0x66, 0x94, 0x37,
+	0xdb, 0x85, 0xb8, 0x93, 0xdf, 0xc3, 0xf1, 0xa6, 0x2e, 0x89, 0x9a, 0xf1, 0x12, 0xdf, 0xd4, 0xdb,
+	0x95, 0xcb, 0x2d, 0x12, 0xe9, 0xb4, 0xc4, 0xe6, 0xdd, 0x64, 0x5a, 0xd6, 0xa7, 0x64, 0xe5, 0x22,
+	0x13, 0x0f, 0x4b, 0x30, 0xfd, 0xb9, 0x2c, 0x4a, 0x30, 0xe3, 0x03, 0x5b, 0x51, 0x32, 0x50, 0x6e,
+	0xcf, 0x81, 0xf3, 0x2d, 0xdf, 0xcf, 0xe8, 0x6d, 0x5c, 0x79, 0xcb, 0x77, 0xb8, 0xf2, 0xf5, 0x7f,
+	0x17, 0x0c, 0xf7, 0x35, 0xe3, 0xa9, 0x40, 0xec, 0xeb, 0xf6, 0x67, 0x0a, 0xe5, 0xcd, 0x76, 0xa1,
+	0xb4, 0x93, 0xf4, 0x6b, 0x48, 0xd2, 0x49, 0xc6, 0x6b, 0x8a, 0xf2, 0x66, 0xbb, 0x10, 0x77, 0xf2,
+	0x0e, 0x20, 0x7a, 0xa7, 0x41, 0x89, 0xae, 0x18, 0xbd, 0x22, 0x29, 0xc7, 0x6b, 0x7c, 0xae, 0x7d,
+	0x43, 0x47, 0x71, 0x1a, 0x3c, 0xe2, 0xa7, 0x2a, 0xf1, 0x40, 0xa5, 0xc8, 0x09, 0x1e, 0xd7, 0xf8,
+	0x01, 0x6a, 0x6b, 0x8f, 0x6e, 0xe8, 0xff, 0x92, 0xf5, 0x92, 0x7a, 0xa8, 0x53, 0xce, 0xb3, 0xe0,
+	0xf0, 0xc8, 0x07, 0x47, 0x43, 0x1c, 0xf9, 0xd4, 0x93, 0xa1, 0x82, 0x52, 0xdc, 0x64, 0x5b, 0x16,
+	0x41, 0xc4, 0xda, 0x72, 0xd2, 0xfd, 0xc9, 0x3a, 0x10, 0x76, 0xbe, 0xf8, 0x1b, 0xb6, 0xe8, 0x7c,
+	0x1b, 0x5e, 0xbb, 0x95, 0xfa, 0x06, 0x84, 0xdb, 0xf8, 0x06, 0xf2, 0xf4, 0x85, 0x5f, 0x5c, 0x98,
+	0xb1, 0xc7, 0x7e, 0xa5, 0x1a, 0x70, 0x3a, 0x2f, 0x2b, 0xfb, 0x33, 0xbb, 0x98, 0x6e, 0xa0, 0xc8,
+	0x1f, 0xd6, 0x45, 0xba, 0x13, 0xcf, 0xf2, 0x8a, 0x9c, 0xe0, 0x31, 0xf3, 0x93, 0x22, 0xfb, 0x23,
+	0xe1, 0xdb, 0xff, 0x0c, 0x00, 0xc7, 0x0b, 0xc0, 0x8a, 0x6f, 0x18, 0x00, 0x00,
 }
@@ -0,0 +1,545 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: common.proto
+
+package idl
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+type StepStatus int32
+
+const (
+	StepStatus_UNKNOWN_STATUS StepStatus = 0
+	StepStatus_PENDING        StepStatus = 1
+	StepStatus_RUNNING        StepStatus = 2
+	StepStatus_COMPLETE       StepStatus = 3
+	StepStatus_FAILED         StepStatus = 4
+)
+
+var StepStatus_name = map[int32]string{
+	0: "UNKNOWN_STATUS",
+	1: "PENDING",
+	2: "RUNNING",
+	3: "COMPLETE",
+	4: "FAILED",
+}
+var StepStatus_value = map[string]int32{
+	"UNKNOWN_STATUS": 0,
+	"PENDING":        1,
+	"RUNNING":        2,
+	"COMPLETE":       3,
+	"FAILED":         4,
+}
+
+func (x StepStatus) String() string {
+	return proto.EnumName(StepStatus_name, int32(x))
+}
+func (StepStatus) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_common_3660643b761fc98f, []int{0}
+}
+
+type SegmentRole int32
+
+const (
+	SegmentRole_UNKNOWN_ROLE SegmentRole = 0
+	SegmentRole_MASTER       SegmentRole = 1
+	SegmentRole_PRIMARY      SegmentRole = 2
+)
+
+var SegmentRole_name = map[int32]string{
+	0: "UNKNOWN_ROLE",
+	1: "MASTER",
+	2: "PRIMARY",
+}
+var SegmentRole_value = map[string]int32{
+	"UNKNOWN_ROLE": 0,
+	"MASTER":       1,
+	"PRIMARY":      2,
+}
+
+func (x SegmentRole) String() string {
+	return proto.EnumName(SegmentRole_name, int32(x))
+}
+func (SegmentRole) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_common_3660643b761fc98f, []int{1}
+}
+
+type LogSource int32
+
+const (
+	LogSource_HUB        LogSource = 0
+	LogSource_AGENT      LogSource = 1
+	LogSource_PG_UPGRADE LogSource = 2
+)
+
+var LogSource_name = map[int32]string{
+	0: "HUB",
+	1: "AGENT",
+	2: "PG_UPGRADE",
+}
+var LogSource_value = map[string]int32{
+	"HUB":        0,
+	"AGENT":      1,
+	"PG_UPGRADE": 2,
+}
+
+func (x LogSource) String() string {
+	return proto.EnumName(LogSource_name, int32(x))
+}
+func (LogSource) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_common_3660643b761fc98f, []int{2}
+}
+
+// SegmentConversionStatus is the state of pg_upgrade on a single segment, as
+// reported by the agent on that segment's host.
+type SegmentConversionStatus struct {
+	Dbid                 int32              `protobuf:"varint,1,opt,name=Dbid" json:"Dbid,omitempty"`
+	Content              int32              `protobuf:"varint,2,opt,name=Content" json:"Content,omitempty"`
+	Role                 SegmentRole        `protobuf:"varint,3,opt,name=Role,enum=idl.SegmentRole" json:"Role,omitempty"`
+	Hostname             string             `protobuf:"bytes,4,opt,name=Hostname" json:"Hostname,omitempty"`
+	Status               StepStatus         `protobuf:"varint,5,opt,name=Status,enum=idl.StepStatus" json:"Status,omitempty"`
+	StartTime            int64              `protobuf:"varint,6,opt,name=StartTime" json:"StartTime,omitempty"`
+	EndTime              int64              `protobuf:"varint,7,opt,name=EndTime" json:"EndTime,omitempty"`
+	Error                string             `protobuf:"bytes,8,opt,name=Error" json:"Error,omitempty"`
+	Progress             *PgUpgradeProgress `protobuf:"bytes,9,opt,name=Progress" json:"Progress,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
+}
+
+func (m *SegmentConversionStatus) Reset()         { *m = SegmentConversionStatus{} }
+func (m *SegmentConversionStatus) String() string { return proto.CompactTextString(m) }
+func (*SegmentConversionStatus) ProtoMessage()    {}
+func (*SegmentConversionStatus) Descriptor() ([]byte, []int) {
+	return fileDescriptor_common_3660643b761fc98f, []int{0}
+}
+func (m *SegmentConversionStatus) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SegmentConversionStatus.Unmarshal(m, b)
+}
+func (m *SegmentConversionStatus) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SegmentConversionStatus.Marshal(b, m, deterministic)
+}
+func (dst *SegmentConversionStatus) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SegmentConversionStatus.Merge(dst, src)
+}
+func (m *SegmentConversionStatus) XXX_Size() int {
+	return xxx_messageInfo_SegmentConversionStatus.Size(m)
+}
+func (m *SegmentConversionStatus) XXX_DiscardUnknown() {
+	xxx_messageInfo_SegmentConversionStatus.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SegmentConversionStatus proto.InternalMessageInfo
+
+func (m *SegmentConversionStatus) GetDbid() int32 {
+	if m != nil {
+		return m.Dbid
+	}
+	return 0
+}
+
+func (m *SegmentConversionStatus) GetContent() int32 {
+	if m != nil {
+		return m.Content
+	}
+	return 0
+}
+
+func (m *SegmentConversionStatus) GetRole() SegmentRole {
+	if m != nil {
+		return m.Role
+	}
+	return SegmentRole_UNKNOWN_ROLE
+}
+
+func (m *SegmentConversionStatus) GetHostname() string {
+	if m != nil {
+		return m.Hostname
+	}
+	return ""
+}
+
+func (m *SegmentConversionStatus) GetStatus() StepStatus {
+	if m != nil {
+		return m.Status
+	}
+	return StepStatus_UNKNOWN_STATUS
+}
+
+func (m *SegmentConversionStatus) GetStartTime() int64 {
+	if m != nil {
+		return m.StartTime
+	}
+	return 0
+}
+
+func (m *SegmentConversionStatus) GetEndTime() int64 {
+	if m != nil {
+		return m.EndTime
+	}
+	return 0
+}
+
+func (m *SegmentConversionStatus) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+func (m *SegmentConversionStatus) GetProgress() *PgUpgradeProgress {
+	if m != nil {
+		return m.Progress
+	}
+	return nil
+}
+
+// PgUpgradeProgress is how far along pg_upgrade is, as parsed from the
+// progress files that pg_upgrade --progress writes. It is only set once
+// pg_upgrade has reported progress.
+type PgUpgradeProgress struct {
+	Phase                string   `protobuf:"bytes,1,opt,name=Phase" json:"Phase,omitempty"`
+	PhasesDone           int32    `protobuf:"varint,2,opt,name=PhasesDone" json:"PhasesDone,omitempty"`
+	TotalPhases          int32    `protobuf:"varint,3,opt,name=TotalPhases" json:"TotalPhases,omitempty"`
+	PercentComplete      int32    `protobuf:"varint,4,opt,name=PercentComplete" json:"PercentComplete,omitempty"`
+	ElapsedSeconds       int64    `protobuf:"varint,5,opt,name=ElapsedSeconds" json:"ElapsedSeconds,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PgUpgradeProgress) Reset()         { *m = PgUpgradeProgress{} }
+func (m *PgUpgradeProgress) String() string { return proto.CompactTextString(m) }
+func (*PgUpgradeProgress) ProtoMessage()    {}
+func (*PgUpgradeProgress) Descriptor() ([]byte, []int) {
+	return fileDescriptor_common_3660643b761fc98f, []int{1}
+}
+func (m *PgUpgradeProgress) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PgUpgradeProgress.Unmarshal(m, b)
+}
+func (m *PgUpgradeProgress) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PgUpgradeProgress.Marshal(b, m, deterministic)
+}
+func (dst *PgUpgradeProgress) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PgUpgradeProgress.Merge(dst, src)
+}
+func (m *PgUpgradeProgress) XXX_Size() int {
+	return xxx_messageInfo_PgUpgradeProgress.Size(m)
+}
+func (m *PgUpgradeProgress) XXX_DiscardUnknown() {
+	xxx_messageInfo_PgUpgradeProgress.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PgUpgradeProgress proto.InternalMessageInfo
+
+func (m *PgUpgradeProgress) GetPhase() string {
+	if m != nil {
+		return m.Phase
+	}
+	return ""
+}
+
+func (m *PgUpgradeProgress) GetPhasesDone() int32 {
+	if m != nil {
+		return m.PhasesDone
+	}
+	return 0
+}
+
+func (m *PgUpgradeProgress) GetTotalPhases() int32 {
+	if m != nil {
+		return m.TotalPhases
+	}
+	return 0
+}
+
+func (m *PgUpgradeProgress) GetPercentComplete() int32 {
+	if m != nil {
+		return m.PercentComplete
+	}
+	return 0
+}
+
+func (m *PgUpgradeProgress) GetElapsedSeconds() int64 {
+	if m != nil {
+		return m.ElapsedSeconds
+	}
+	return 0
+}
+
+// LogChunk is the next piece of a log file.
+type LogChunk struct {
+	Hostname             string   `protobuf:"bytes,1,opt,name=Hostname" json:"Hostname,omitempty"`
+	Path                 string   `protobuf:"bytes,2,opt,name=Path" json:"Path,omitempty"`
+	Data                 []byte   `protobuf:"bytes,3,opt,name=Data" json:"Data,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *LogChunk) Reset()         { *m = LogChunk{} }
+func (m *LogChunk) String() string { return proto.CompactTextString(m) }
+func (*LogChunk) ProtoMessage()    {}
+func (*LogChunk) Descriptor() ([]byte, []int) {
+	return fileDescriptor_common_3660643b761fc98f, []int{2}
+}
+func (m *LogChunk) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LogChunk.Unmarshal(m, b)
+}
+func (m *LogChunk) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LogChunk.Marshal(b, m, deterministic)
+}
+func (dst *LogChunk) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LogChunk.Merge(dst, src)
+}
+func (m *LogChunk) XXX_Size() int {
+	return xxx_messageInfo_LogChunk.Size(m)
+}
+func (m *LogChunk) XXX_DiscardUnknown() {
+	xxx_messageInfo_LogChunk.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LogChunk proto.InternalMessageInfo
+
+func (m *LogChunk) GetHostname() string {
+	if m != nil {
+		return m.Hostname
+	}
+	return ""
+}
+
+func (m *LogChunk) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *LogChunk) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+// DataDirSpace is whether the filesystem that will hold a segment's new data
+// dir has room for what pg_upgrade writes there. Every byte count is in bytes.
+type DataDirSpace struct {
+	Content              int32    `protobuf:"varint,1,opt,name=Content" json:"Content,omitempty"`
+	NewDataDir           string   `protobuf:"bytes,2,opt,name=NewDataDir" json:"NewDataDir,omitempty"`
+	OldDataDirSize       uint64   `protobuf:"varint,3,opt,name=OldDataDirSize" json:"OldDataDirSize,omitempty"`
+	Required             uint64   `protobuf:"varint,4,opt,name=Required" json:"Required,omitempty"`
+	RequiredOnFilesystem uint64   `protobuf:"varint,5,opt,name=RequiredOnFilesystem" json:"RequiredOnFilesystem,omitempty"`
+	Available            uint64   `protobuf:"varint,6,opt,name=Available" json:"Available,omitempty"`
+	Sufficient           bool     `protobuf:"varint,7,opt,name=Sufficient" json:"Sufficient,omitempty"`
+	Error                string   `protobuf:"bytes,8,opt,name=Error" json:"Error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DataDirSpace) Reset()         { *m = DataDirSpace{} }
+func (m *DataDirSpace) String() string { return proto.CompactTextString(m) }
+func (*DataDirSpace) ProtoMessage()    {}
+func (*DataDirSpace) Descriptor() ([]byte, []int) {
+	return fileDescriptor_common_3660643b761fc98f, []int{3}
+}
+func (m *DataDirSpace) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DataDirSpace.Unmarshal(m, b)
+}
+func (m *DataDirSpace) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DataDirSpace.Marshal(b, m, deterministic)
+}
+func (dst *DataDirSpace) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DataDirSpace.Merge(dst, src)
+}
+func (m *DataDirSpace) XXX_Size() int {
+	return xxx_messageInfo_DataDirSpace.Size(m)
+}
+func (m *DataDirSpace) XXX_DiscardUnknown() {
+	xxx_messageInfo_DataDirSpace.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DataDirSpace proto.InternalMessageInfo
+
+func (m *DataDirSpace) GetContent() int32 {
+	if m != nil {
+		return m.Content
+	}
+	return 0
+}
+
+func (m *DataDirSpace) GetNewDataDir() string {
+	if m != nil {
+		return m.NewDataDir
+	}
+	return ""
+}
+
+func (m *DataDirSpace) GetOldDataDirSize() uint64 {
+	if m != nil {
+		return m.OldDataDirSize
+	}
+	return 0
+}
+
+func (m *DataDirSpace) GetRequired() uint64 {
+	if m != nil {
+		return m.Required
+	}
+	return 0
+}
+
+func (m *DataDirSpace) GetRequiredOnFilesystem() uint64 {
+	if m != nil {
+		return m.RequiredOnFilesystem
+	}
+	return 0
+}
+
+func (m *DataDirSpace) GetAvailable() uint64 {
+	if m != nil {
+		return m.Available
+	}
+	return 0
+}
+
+func (m *DataDirSpace) GetSufficient() bool {
+	if m != nil {
+		return m.Sufficient
+	}
+	return false
+}
+
+func (m *DataDirSpace) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+// HostDiskSpace is the room for the new data dirs of the segments on a host,
+// or why the agent there could not be asked.
+type HostDiskSpace struct {
+	Hostname             string          `protobuf:"bytes,1,opt,name=Hostname" json:"Hostname,omitempty"`
+	DataDirSpaces        []*DataDirSpace `protobuf:"bytes,2,rep,name=DataDirSpaces" json:"DataDirSpaces,omitempty"`
+	Error                string          `protobuf:"bytes,3,opt,name=Error" json:"Error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *HostDiskSpace) Reset()         { *m = HostDiskSpace{} }
+func (m *HostDiskSpace) String() string { return proto.CompactTextString(m) }
+func (*HostDiskSpace) ProtoMessage()    {}
+func (*HostDiskSpace) Descriptor() ([]byte, []int) {
+	return fileDescriptor_common_3660643b761fc98f, []int{4}
+}
+func (m *HostDiskSpace) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_HostDiskSpace.Unmarshal(m, b)
+}
+func (m *HostDiskSpace) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_HostDiskSpace.Marshal(b, m, deterministic)
+}
+func (dst *HostDiskSpace) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_HostDiskSpace.Merge(dst, src)
+}
+func (m *HostDiskSpace) XXX_Size() int {
+	return xxx_messageInfo_HostDiskSpace.Size(m)
+}
+func (m *HostDiskSpace) XXX_DiscardUnknown() {
+	xxx_messageInfo_HostDiskSpace.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_HostDiskSpace proto.InternalMessageInfo
+
+func (m *HostDiskSpace) GetHostname() string {
+	if m != nil {
+		return m.Hostname
+	}
+	return ""
+}
+
+func (m *HostDiskSpace) GetDataDirSpaces() []*DataDirSpace {
+	if m != nil {
+		return m.DataDirSpaces
+	}
+	return nil
+}
+
+func (m *HostDiskSpace) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+func init() {
+	proto.RegisterType((*SegmentConversionStatus)(nil), "idl.SegmentConversionStatus")
+	proto.RegisterType((*PgUpgradeProgress)(nil), "idl.PgUpgradeProgress")
+	proto.RegisterType((*LogChunk)(nil), "idl.LogChunk")
+	proto.RegisterType((*DataDirSpace)(nil), "idl.DataDirSpace")
+	proto.RegisterType((*HostDiskSpace)(nil), "idl.HostDiskSpace")
+	proto.RegisterEnum("idl.StepStatus", StepStatus_name, StepStatus_value)
+	proto.RegisterEnum("idl.SegmentRole", SegmentRole_name, SegmentRole_value)
+	proto.RegisterEnum("idl.LogSource", LogSource_name, LogSource_value)
+}
+
+func init() { proto.RegisterFile("common.proto", fileDescriptor_common_3660643b761fc98f) }
+
+var fileDescriptor_common_3660643b761fc98f = []byte{
+	// 651 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x54, 0x5d, 0x6f, 0x9b, 0x30,
+	0x14, 0x2d, 0x81, 0x7c, 0x70, 0x93, 0xa6, 0xd4, 0xaa, 0x36, 0x34, 0x4d, 0x15, 0x8a, 0xa6, 0x0d,
+	0xf5, 0xa1, 0x93, 0xb2, 0x87, 0xed, 0x95, 0x05, 0x9a, 0x56, 0x4b, 0x09, 0x32, 0x44, 0xd3, 0x9e,
+	0x2a, 0x1a, 0xdc, 0x14, 0x15, 0x70, 0x66, 0x9c, 0x4e, 0xeb, 0xef, 0xd8, 0x4f, 0xd9, 0xeb, 0xfe,
+	0xdb, 0x64, 0x93, 0x34, 0x34, 0xab, 0xf6, 0x76, 0xef, 0x39, 0xd6, 0xb9, 0x97, 0xe3, 0x63, 0xa0,
+	0x37, 0xa7, 0x79, 0x4e, 0x8b, 0xd3, 0x25, 0xa3, 0x9c, 0x22, 0x35, 0x4d, 0xb2, 0xc1, 0xef, 0x06,
+	0xbc, 0x0c, 0xc9, 0x22, 0x27, 0x05, 0x1f, 0xd1, 0xe2, 0x9e, 0xb0, 0x32, 0xa5, 0x45, 0xc8, 0x63,
+	0xbe, 0x2a, 0x11, 0x02, 0xcd, 0xbd, 0x4e, 0x13, 0x53, 0xb1, 0x14, 0xbb, 0x89, 0x65, 0x8d, 0x4c,
+	0x68, 0x8f, 0x68, 0xc1, 0x49, 0xc1, 0xcd, 0x86, 0x84, 0x37, 0x2d, 0x7a, 0x03, 0x1a, 0xa6, 0x19,
+	0x31, 0x55, 0x4b, 0xb1, 0xfb, 0x43, 0xe3, 0x34, 0x4d, 0xb2, 0xd3, 0xb5, 0xb2, 0xc0, 0xb1, 0x64,
+	0xd1, 0x2b, 0xe8, 0x9c, 0xd3, 0x92, 0x17, 0x71, 0x4e, 0x4c, 0xcd, 0x52, 0x6c, 0x1d, 0x3f, 0xf6,
+	0xe8, 0x1d, 0xb4, 0xaa, 0xc9, 0x66, 0x53, 0x6a, 0x1c, 0x54, 0x1a, 0x9c, 0x2c, 0x2b, 0x18, 0xaf,
+	0x69, 0xf4, 0x1a, 0xf4, 0x90, 0xc7, 0x8c, 0x47, 0x69, 0x4e, 0xcc, 0x96, 0xa5, 0xd8, 0x2a, 0xde,
+	0x02, 0x62, 0x45, 0xaf, 0x48, 0x24, 0xd7, 0x96, 0xdc, 0xa6, 0x45, 0x47, 0xd0, 0xf4, 0x18, 0xa3,
+	0xcc, 0xec, 0xc8, 0xc9, 0x55, 0x83, 0x86, 0xd0, 0x09, 0x18, 0x5d, 0x30, 0x52, 0x96, 0xa6, 0x6e,
+	0x29, 0x76, 0x77, 0xf8, 0x42, 0x0e, 0x0e, 0x16, 0xb3, 0xe5, 0x82, 0xc5, 0x09, 0xd9, 0xb0, 0xf8,
+	0xf1, 0xdc, 0xe0, 0x8f, 0x02, 0x87, 0xff, 0xf0, 0x42, 0x3f, 0xb8, 0x8d, 0x4b, 0x22, 0x1d, 0xd3,
+	0x71, 0xd5, 0xa0, 0x63, 0x00, 0x59, 0x94, 0x2e, 0x2d, 0xc8, 0xda, 0xb5, 0x1a, 0x82, 0x2c, 0xe8,
+	0x46, 0x94, 0xc7, 0x59, 0x05, 0x49, 0xff, 0x9a, 0xb8, 0x0e, 0x21, 0x1b, 0x0e, 0x02, 0xc2, 0xe6,
+	0xf2, 0x8e, 0xf2, 0x65, 0x46, 0x78, 0xe5, 0x5d, 0x13, 0xef, 0xc2, 0xe8, 0x2d, 0xf4, 0xbd, 0x2c,
+	0x5e, 0x96, 0x24, 0x09, 0xc9, 0x9c, 0x16, 0x49, 0x65, 0xa5, 0x8a, 0x77, 0xd0, 0x81, 0x0f, 0x9d,
+	0x09, 0x5d, 0x8c, 0x6e, 0x57, 0xc5, 0xdd, 0x93, 0x2b, 0x51, 0x76, 0xae, 0x04, 0x81, 0x16, 0xc4,
+	0xfc, 0x56, 0x6e, 0xad, 0x63, 0x59, 0xcb, 0x58, 0xc4, 0x3c, 0x96, 0x8b, 0xf6, 0xb0, 0xac, 0x07,
+	0xbf, 0x1a, 0xd0, 0x13, 0x85, 0x9b, 0xb2, 0x70, 0x19, 0xcf, 0x49, 0x3d, 0x27, 0xca, 0xd3, 0x9c,
+	0x1c, 0x03, 0xf8, 0xe4, 0xc7, 0xfa, 0xf0, 0x5a, 0xb8, 0x86, 0x88, 0x4f, 0x98, 0x66, 0xc9, 0x46,
+	0x2c, 0x7d, 0xa8, 0x12, 0xa5, 0xe1, 0x1d, 0x54, 0xac, 0x8d, 0xc9, 0xf7, 0x55, 0xca, 0x48, 0x22,
+	0xdd, 0xd0, 0xf0, 0x63, 0x8f, 0x86, 0x70, 0xb4, 0xa9, 0xa7, 0xc5, 0x59, 0x9a, 0x91, 0xf2, 0x67,
+	0xc9, 0x49, 0x2e, 0xcd, 0xd0, 0xf0, 0xb3, 0x9c, 0x08, 0x95, 0x73, 0x1f, 0xa7, 0x59, 0x7c, 0x9d,
+	0x55, 0xa1, 0xd2, 0xf0, 0x16, 0x10, 0x5b, 0x87, 0xab, 0x9b, 0x9b, 0x74, 0x9e, 0x8a, 0x4f, 0x12,
+	0xb9, 0xea, 0xe0, 0x1a, 0xf2, 0x7c, 0xb4, 0x06, 0x0f, 0xb0, 0x2f, 0xac, 0x74, 0xd3, 0xf2, 0xae,
+	0xb2, 0xe5, 0x7f, 0x5e, 0x7f, 0x84, 0xfd, 0xba, 0x85, 0xa5, 0xd9, 0xb0, 0x54, 0xbb, 0x3b, 0x3c,
+	0x94, 0x61, 0xac, 0x33, 0xf8, 0xe9, 0xb9, 0xed, 0x6c, 0xb5, 0x36, 0xfb, 0x24, 0x02, 0xd8, 0x3e,
+	0x1d, 0x84, 0xa0, 0x3f, 0xf3, 0xbf, 0xf8, 0xd3, 0xaf, 0xfe, 0x55, 0x18, 0x39, 0xd1, 0x2c, 0x34,
+	0xf6, 0x50, 0x17, 0xda, 0x81, 0xe7, 0xbb, 0x17, 0xfe, 0xd8, 0x50, 0x44, 0x83, 0x67, 0xbe, 0x2f,
+	0x9a, 0x06, 0xea, 0x41, 0x67, 0x34, 0xbd, 0x0c, 0x26, 0x5e, 0xe4, 0x19, 0x2a, 0x02, 0x68, 0x9d,
+	0x39, 0x17, 0x13, 0xcf, 0x35, 0xb4, 0x93, 0x4f, 0xd0, 0xad, 0x3d, 0x6a, 0x64, 0x40, 0x6f, 0x23,
+	0x8b, 0xa7, 0x13, 0xcf, 0xd8, 0x13, 0x87, 0x2f, 0x9d, 0x30, 0xf2, 0x70, 0xa5, 0x19, 0xe0, 0x8b,
+	0x4b, 0x07, 0x7f, 0x33, 0x1a, 0x27, 0xef, 0x41, 0x9f, 0xd0, 0x45, 0x48, 0x57, 0x6c, 0x4e, 0x50,
+	0x1b, 0xd4, 0xf3, 0xd9, 0x67, 0x63, 0x0f, 0xe9, 0xd0, 0x74, 0xc6, 0x9e, 0x1f, 0x19, 0x0a, 0xea,
+	0x03, 0x04, 0xe3, 0xab, 0x59, 0x30, 0xc6, 0x8e, 0xeb, 0x19, 0x8d, 0xeb, 0x96, 0xfc, 0x4d, 0x7d,
+	0xf8, 0x3b, 0x00, 0x4f, 0x5f, 0x5a, 0x87, 0xb6, 0x04, 0x00, 0x00,
+}
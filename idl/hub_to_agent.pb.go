@@ -23,10 +23,160 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
 
+// FileChunk is the next piece of a file pushed to an agent. A file is sent as
+// one or more chunks in a row with the same Path; the last of them carries
+// the checksum of the whole file.
+type FileChunk struct {
+	Path                 string   `protobuf:"bytes,1,opt,name=Path" json:"Path,omitempty"`
+	Data                 []byte   `protobuf:"bytes,2,opt,name=Data" json:"Data,omitempty"`
+	Checksum             string   `protobuf:"bytes,3,opt,name=Checksum" json:"Checksum,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *FileChunk) Reset()         { *m = FileChunk{} }
+func (m *FileChunk) String() string { return proto.CompactTextString(m) }
+func (*FileChunk) ProtoMessage()    {}
+func (*FileChunk) Descriptor() ([]byte, []int) {
+	return fileDescriptor_hub_to_agent_c4fd15e7e1a0c16c, []int{0}
+}
+func (m *FileChunk) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FileChunk.Unmarshal(m, b)
+}
+func (m *FileChunk) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FileChunk.Marshal(b, m, deterministic)
+}
+func (dst *FileChunk) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FileChunk.Merge(dst, src)
+}
+func (m *FileChunk) XXX_Size() int {
+	return xxx_messageInfo_FileChunk.Size(m)
+}
+func (m *FileChunk) XXX_DiscardUnknown() {
+	xxx_messageInfo_FileChunk.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FileChunk proto.InternalMessageInfo
+
+func (m *FileChunk) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *FileChunk) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+func (m *FileChunk) GetChecksum() string {
+	if m != nil {
+		return m.Checksum
+	}
+	return ""
+}
+
+type PushFilesReply struct {
+	Paths                []string `protobuf:"bytes,1,rep,name=Paths" json:"Paths,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PushFilesReply) Reset()         { *m = PushFilesReply{} }
+func (m *PushFilesReply) String() string { return proto.CompactTextString(m) }
+func (*PushFilesReply) ProtoMessage()    {}
+func (*PushFilesReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_hub_to_agent_c4fd15e7e1a0c16c, []int{1}
+}
+func (m *PushFilesReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PushFilesReply.Unmarshal(m, b)
+}
+func (m *PushFilesReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PushFilesReply.Marshal(b, m, deterministic)
+}
+func (dst *PushFilesReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PushFilesReply.Merge(dst, src)
+}
+func (m *PushFilesReply) XXX_Size() int {
+	return xxx_messageInfo_PushFilesReply.Size(m)
+}
+func (m *PushFilesReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_PushFilesReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PushFilesReply proto.InternalMessageInfo
+
+func (m *PushFilesReply) GetPaths() []string {
+	if m != nil {
+		return m.Paths
+	}
+	return nil
+}
+
+type TailLogsRequest struct {
+	Source               LogSource `protobuf:"varint,1,opt,name=Source,enum=idl.LogSource" json:"Source,omitempty"`
+	Content              int32     `protobuf:"varint,2,opt,name=Content" json:"Content,omitempty"`
+	Follow               bool      `protobuf:"varint,3,opt,name=Follow" json:"Follow,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *TailLogsRequest) Reset()         { *m = TailLogsRequest{} }
+func (m *TailLogsRequest) String() string { return proto.CompactTextString(m) }
+func (*TailLogsRequest) ProtoMessage()    {}
+func (*TailLogsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_hub_to_agent_c4fd15e7e1a0c16c, []int{2}
+}
+func (m *TailLogsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TailLogsRequest.Unmarshal(m, b)
+}
+func (m *TailLogsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TailLogsRequest.Marshal(b, m, deterministic)
+}
+func (dst *TailLogsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TailLogsRequest.Merge(dst, src)
+}
+func (m *TailLogsRequest) XXX_Size() int {
+	return xxx_messageInfo_TailLogsRequest.Size(m)
+}
+func (m *TailLogsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_TailLogsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TailLogsRequest proto.InternalMessageInfo
+
+func (m *TailLogsRequest) GetSource() LogSource {
+	if m != nil {
+		return m.Source
+	}
+	return LogSource_HUB
+}
+
+func (m *TailLogsRequest) GetContent() int32 {
+	if m != nil {
+		return m.Content
+	}
+	return 0
+}
+
+func (m *TailLogsRequest) GetFollow() bool {
+	if m != nil {
+		return m.Follow
+	}
+	return false
+}
+
 type UpgradeConvertPrimarySegmentsRequest struct {
 	OldBinDir            string         `protobuf:"bytes,1,opt,name=OldBinDir" json:"OldBinDir,omitempty"`
 	NewBinDir            string         `protobuf:"bytes,2,opt,name=NewBinDir" json:"NewBinDir,omitempty"`
 	DataDirPairs         []*DataDirPair `protobuf:"bytes,3,rep,name=DataDirPairs" json:"DataDirPairs,omitempty"`
+	LinkMode             bool           `protobuf:"varint,4,opt,name=LinkMode" json:"LinkMode,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
 	XXX_unrecognized     []byte         `json:"-"`
 	XXX_sizecache        int32          `json:"-"`
@@ -36,7 +186,7 @@ func (m *UpgradeConvertPrimarySegmentsRequest) Reset()         { *m = UpgradeCon
 func (m *UpgradeConvertPrimarySegmentsRequest) String() string { return proto.CompactTextString(m) }
 func (*UpgradeConvertPrimarySegmentsRequest) ProtoMessage()    {}
 func (*UpgradeConvertPrimarySegmentsRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_hub_to_agent_1ea90a78242ea64d, []int{0}
+	return fileDescriptor_hub_to_agent_c4fd15e7e1a0c16c, []int{3}
 }
 func (m *UpgradeConvertPrimarySegmentsRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_UpgradeConvertPrimarySegmentsRequest.Unmarshal(m, b)
@@ -77,6 +227,13 @@ func (m *UpgradeConvertPrimarySegmentsRequest) GetDataDirPairs() []*DataDirPair
 	return nil
 }
 
+func (m *UpgradeConvertPrimarySegmentsRequest) GetLinkMode() bool {
+	if m != nil {
+		return m.LinkMode
+	}
+	return false
+}
+
 type DataDirPair struct {
 	OldDataDir           string   `protobuf:"bytes,1,opt,name=OldDataDir" json:"OldDataDir,omitempty"`
 	NewDataDir           string   `protobuf:"bytes,2,opt,name=NewDataDir" json:"NewDataDir,omitempty"`
@@ -92,7 +249,7 @@ func (m *DataDirPair) Reset()         { *m = DataDirPair{} }
 func (m *DataDirPair) String() string { return proto.CompactTextString(m) }
 func (*DataDirPair) ProtoMessage()    {}
 func (*DataDirPair) Descriptor() ([]byte, []int) {
-	return fileDescriptor_hub_to_agent_1ea90a78242ea64d, []int{1}
+	return fileDescriptor_hub_to_agent_c4fd15e7e1a0c16c, []int{4}
 }
 func (m *DataDirPair) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_DataDirPair.Unmarshal(m, b)
@@ -157,7 +314,7 @@ func (m *UpgradeConvertPrimarySegmentsReply) Reset()         { *m = UpgradeConve
 func (m *UpgradeConvertPrimarySegmentsReply) String() string { return proto.CompactTextString(m) }
 func (*UpgradeConvertPrimarySegmentsReply) ProtoMessage()    {}
 func (*UpgradeConvertPrimarySegmentsReply) Descriptor() ([]byte, []int) {
-	return fileDescriptor_hub_to_agent_1ea90a78242ea64d, []int{2}
+	return fileDescriptor_hub_to_agent_c4fd15e7e1a0c16c, []int{5}
 }
 func (m *UpgradeConvertPrimarySegmentsReply) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_UpgradeConvertPrimarySegmentsReply.Unmarshal(m, b)
@@ -177,6 +334,194 @@ func (m *UpgradeConvertPrimarySegmentsReply) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_UpgradeConvertPrimarySegmentsReply proto.InternalMessageInfo
 
+type CancelAgentRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CancelAgentRequest) Reset()         { *m = CancelAgentRequest{} }
+func (m *CancelAgentRequest) String() string { return proto.CompactTextString(m) }
+func (*CancelAgentRequest) ProtoMessage()    {}
+func (*CancelAgentRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_hub_to_agent_c4fd15e7e1a0c16c, []int{6}
+}
+func (m *CancelAgentRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CancelAgentRequest.Unmarshal(m, b)
+}
+func (m *CancelAgentRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CancelAgentRequest.Marshal(b, m, deterministic)
+}
+func (dst *CancelAgentRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CancelAgentRequest.Merge(dst, src)
+}
+func (m *CancelAgentRequest) XXX_Size() int {
+	return xxx_messageInfo_CancelAgentRequest.Size(m)
+}
+func (m *CancelAgentRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CancelAgentRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CancelAgentRequest proto.InternalMessageInfo
+
+type CancelAgentReply struct {
+	Cancelled            []string `protobuf:"bytes,1,rep,name=Cancelled" json:"Cancelled,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CancelAgentReply) Reset()         { *m = CancelAgentReply{} }
+func (m *CancelAgentReply) String() string { return proto.CompactTextString(m) }
+func (*CancelAgentReply) ProtoMessage()    {}
+func (*CancelAgentReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_hub_to_agent_c4fd15e7e1a0c16c, []int{7}
+}
+func (m *CancelAgentReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CancelAgentReply.Unmarshal(m, b)
+}
+func (m *CancelAgentReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CancelAgentReply.Marshal(b, m, deterministic)
+}
+func (dst *CancelAgentReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CancelAgentReply.Merge(dst, src)
+}
+func (m *CancelAgentReply) XXX_Size() int {
+	return xxx_messageInfo_CancelAgentReply.Size(m)
+}
+func (m *CancelAgentReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_CancelAgentReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CancelAgentReply proto.InternalMessageInfo
+
+func (m *CancelAgentReply) GetCancelled() []string {
+	if m != nil {
+		return m.Cancelled
+	}
+	return nil
+}
+
+type RevertAgentRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RevertAgentRequest) Reset()         { *m = RevertAgentRequest{} }
+func (m *RevertAgentRequest) String() string { return proto.CompactTextString(m) }
+func (*RevertAgentRequest) ProtoMessage()    {}
+func (*RevertAgentRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_hub_to_agent_c4fd15e7e1a0c16c, []int{8}
+}
+func (m *RevertAgentRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RevertAgentRequest.Unmarshal(m, b)
+}
+func (m *RevertAgentRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RevertAgentRequest.Marshal(b, m, deterministic)
+}
+func (dst *RevertAgentRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RevertAgentRequest.Merge(dst, src)
+}
+func (m *RevertAgentRequest) XXX_Size() int {
+	return xxx_messageInfo_RevertAgentRequest.Size(m)
+}
+func (m *RevertAgentRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RevertAgentRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RevertAgentRequest proto.InternalMessageInfo
+
+type RevertAgentReply struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RevertAgentReply) Reset()         { *m = RevertAgentReply{} }
+func (m *RevertAgentReply) String() string { return proto.CompactTextString(m) }
+func (*RevertAgentReply) ProtoMessage()    {}
+func (*RevertAgentReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_hub_to_agent_c4fd15e7e1a0c16c, []int{9}
+}
+func (m *RevertAgentReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RevertAgentReply.Unmarshal(m, b)
+}
+func (m *RevertAgentReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RevertAgentReply.Marshal(b, m, deterministic)
+}
+func (dst *RevertAgentReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RevertAgentReply.Merge(dst, src)
+}
+func (m *RevertAgentReply) XXX_Size() int {
+	return xxx_messageInfo_RevertAgentReply.Size(m)
+}
+func (m *RevertAgentReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_RevertAgentReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RevertAgentReply proto.InternalMessageInfo
+
+type ShutdownAgentRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ShutdownAgentRequest) Reset()         { *m = ShutdownAgentRequest{} }
+func (m *ShutdownAgentRequest) String() string { return proto.CompactTextString(m) }
+func (*ShutdownAgentRequest) ProtoMessage()    {}
+func (*ShutdownAgentRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_hub_to_agent_c4fd15e7e1a0c16c, []int{10}
+}
+func (m *ShutdownAgentRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ShutdownAgentRequest.Unmarshal(m, b)
+}
+func (m *ShutdownAgentRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ShutdownAgentRequest.Marshal(b, m, deterministic)
+}
+func (dst *ShutdownAgentRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ShutdownAgentRequest.Merge(dst, src)
+}
+func (m *ShutdownAgentRequest) XXX_Size() int {
+	return xxx_messageInfo_ShutdownAgentRequest.Size(m)
+}
+func (m *ShutdownAgentRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ShutdownAgentRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ShutdownAgentRequest proto.InternalMessageInfo
+
+type ShutdownAgentReply struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ShutdownAgentReply) Reset()         { *m = ShutdownAgentReply{} }
+func (m *ShutdownAgentReply) String() string { return proto.CompactTextString(m) }
+func (*ShutdownAgentReply) ProtoMessage()    {}
+func (*ShutdownAgentReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_hub_to_agent_c4fd15e7e1a0c16c, []int{11}
+}
+func (m *ShutdownAgentReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ShutdownAgentReply.Unmarshal(m, b)
+}
+func (m *ShutdownAgentReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ShutdownAgentReply.Marshal(b, m, deterministic)
+}
+func (dst *ShutdownAgentReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ShutdownAgentReply.Merge(dst, src)
+}
+func (m *ShutdownAgentReply) XXX_Size() int {
+	return xxx_messageInfo_ShutdownAgentReply.Size(m)
+}
+func (m *ShutdownAgentReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_ShutdownAgentReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ShutdownAgentReply proto.InternalMessageInfo
+
 type PingAgentsRequest struct {
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
@@ -187,7 +532,7 @@ func (m *PingAgentsRequest) Reset()         { *m = PingAgentsRequest{} }
 func (m *PingAgentsRequest) String() string { return proto.CompactTextString(m) }
 func (*PingAgentsRequest) ProtoMessage()    {}
 func (*PingAgentsRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_hub_to_agent_1ea90a78242ea64d, []int{3}
+	return fileDescriptor_hub_to_agent_c4fd15e7e1a0c16c, []int{12}
 }
 func (m *PingAgentsRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_PingAgentsRequest.Unmarshal(m, b)
@@ -208,6 +553,7 @@ func (m *PingAgentsRequest) XXX_DiscardUnknown() {
 var xxx_messageInfo_PingAgentsRequest proto.InternalMessageInfo
 
 type PingAgentsReply struct {
+	Version              string   `protobuf:"bytes,1,opt,name=Version" json:"Version,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -217,7 +563,7 @@ func (m *PingAgentsReply) Reset()         { *m = PingAgentsReply{} }
 func (m *PingAgentsReply) String() string { return proto.CompactTextString(m) }
 func (*PingAgentsReply) ProtoMessage()    {}
 func (*PingAgentsReply) Descriptor() ([]byte, []int) {
-	return fileDescriptor_hub_to_agent_1ea90a78242ea64d, []int{4}
+	return fileDescriptor_hub_to_agent_c4fd15e7e1a0c16c, []int{13}
 }
 func (m *PingAgentsReply) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_PingAgentsReply.Unmarshal(m, b)
@@ -237,6 +583,13 @@ func (m *PingAgentsReply) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_PingAgentsReply proto.InternalMessageInfo
 
+func (m *PingAgentsReply) GetVersion() string {
+	if m != nil {
+		return m.Version
+	}
+	return ""
+}
+
 type CheckUpgradeStatusRequest struct {
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
@@ -247,7 +600,7 @@ func (m *CheckUpgradeStatusRequest) Reset()         { *m = CheckUpgradeStatusReq
 func (m *CheckUpgradeStatusRequest) String() string { return proto.CompactTextString(m) }
 func (*CheckUpgradeStatusRequest) ProtoMessage()    {}
 func (*CheckUpgradeStatusRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_hub_to_agent_1ea90a78242ea64d, []int{5}
+	return fileDescriptor_hub_to_agent_c4fd15e7e1a0c16c, []int{14}
 }
 func (m *CheckUpgradeStatusRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_CheckUpgradeStatusRequest.Unmarshal(m, b)
@@ -278,7 +631,7 @@ func (m *CheckUpgradeStatusReply) Reset()         { *m = CheckUpgradeStatusReply
 func (m *CheckUpgradeStatusReply) String() string { return proto.CompactTextString(m) }
 func (*CheckUpgradeStatusReply) ProtoMessage()    {}
 func (*CheckUpgradeStatusReply) Descriptor() ([]byte, []int) {
-	return fileDescriptor_hub_to_agent_1ea90a78242ea64d, []int{6}
+	return fileDescriptor_hub_to_agent_c4fd15e7e1a0c16c, []int{15}
 }
 func (m *CheckUpgradeStatusReply) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_CheckUpgradeStatusReply.Unmarshal(m, b)
@@ -317,7 +670,7 @@ func (m *CheckConversionStatusRequest) Reset()         { *m = CheckConversionSta
 func (m *CheckConversionStatusRequest) String() string { return proto.CompactTextString(m) }
 func (*CheckConversionStatusRequest) ProtoMessage()    {}
 func (*CheckConversionStatusRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_hub_to_agent_1ea90a78242ea64d, []int{7}
+	return fileDescriptor_hub_to_agent_c4fd15e7e1a0c16c, []int{16}
 }
 func (m *CheckConversionStatusRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_CheckConversionStatusRequest.Unmarshal(m, b)
@@ -364,7 +717,7 @@ func (m *SegmentInfo) Reset()         { *m = SegmentInfo{} }
 func (m *SegmentInfo) String() string { return proto.CompactTextString(m) }
 func (*SegmentInfo) ProtoMessage()    {}
 func (*SegmentInfo) Descriptor() ([]byte, []int) {
-	return fileDescriptor_hub_to_agent_1ea90a78242ea64d, []int{8}
+	return fileDescriptor_hub_to_agent_c4fd15e7e1a0c16c, []int{17}
 }
 func (m *SegmentInfo) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_SegmentInfo.Unmarshal(m, b)
@@ -406,17 +759,17 @@ func (m *SegmentInfo) GetDataDir() string {
 }
 
 type CheckConversionStatusReply struct {
-	Statuses             []string `protobuf:"bytes,1,rep,name=Statuses" json:"Statuses,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	Statuses             []*SegmentConversionStatus `protobuf:"bytes,1,rep,name=Statuses" json:"Statuses,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                   `json:"-"`
+	XXX_unrecognized     []byte                     `json:"-"`
+	XXX_sizecache        int32                      `json:"-"`
 }
 
 func (m *CheckConversionStatusReply) Reset()         { *m = CheckConversionStatusReply{} }
 func (m *CheckConversionStatusReply) String() string { return proto.CompactTextString(m) }
 func (*CheckConversionStatusReply) ProtoMessage()    {}
 func (*CheckConversionStatusReply) Descriptor() ([]byte, []int) {
-	return fileDescriptor_hub_to_agent_1ea90a78242ea64d, []int{9}
+	return fileDescriptor_hub_to_agent_c4fd15e7e1a0c16c, []int{18}
 }
 func (m *CheckConversionStatusReply) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_CheckConversionStatusReply.Unmarshal(m, b)
@@ -436,70 +789,27 @@ func (m *CheckConversionStatusReply) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_CheckConversionStatusReply proto.InternalMessageInfo
 
-func (m *CheckConversionStatusReply) GetStatuses() []string {
+func (m *CheckConversionStatusReply) GetStatuses() []*SegmentConversionStatus {
 	if m != nil {
 		return m.Statuses
 	}
 	return nil
 }
 
-type FileSysUsage struct {
-	Filesystem           string   `protobuf:"bytes,1,opt,name=Filesystem" json:"Filesystem,omitempty"`
-	Usage                float64  `protobuf:"fixed64,2,opt,name=Usage" json:"Usage,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
-}
-
-func (m *FileSysUsage) Reset()         { *m = FileSysUsage{} }
-func (m *FileSysUsage) String() string { return proto.CompactTextString(m) }
-func (*FileSysUsage) ProtoMessage()    {}
-func (*FileSysUsage) Descriptor() ([]byte, []int) {
-	return fileDescriptor_hub_to_agent_1ea90a78242ea64d, []int{10}
-}
-func (m *FileSysUsage) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_FileSysUsage.Unmarshal(m, b)
-}
-func (m *FileSysUsage) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_FileSysUsage.Marshal(b, m, deterministic)
-}
-func (dst *FileSysUsage) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_FileSysUsage.Merge(dst, src)
-}
-func (m *FileSysUsage) XXX_Size() int {
-	return xxx_messageInfo_FileSysUsage.Size(m)
-}
-func (m *FileSysUsage) XXX_DiscardUnknown() {
-	xxx_messageInfo_FileSysUsage.DiscardUnknown(m)
-}
-
-var xxx_messageInfo_FileSysUsage proto.InternalMessageInfo
-
-func (m *FileSysUsage) GetFilesystem() string {
-	if m != nil {
-		return m.Filesystem
-	}
-	return ""
-}
-
-func (m *FileSysUsage) GetUsage() float64 {
-	if m != nil {
-		return m.Usage
-	}
-	return 0
-}
-
 type CheckDiskSpaceRequestToAgent struct {
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	DataDirPairs         []*DataDirPair `protobuf:"bytes,1,rep,name=DataDirPairs" json:"DataDirPairs,omitempty"`
+	LinkMode             bool           `protobuf:"varint,2,opt,name=LinkMode" json:"LinkMode,omitempty"`
+	MarginPercent        int32          `protobuf:"varint,3,opt,name=MarginPercent" json:"MarginPercent,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
 }
 
 func (m *CheckDiskSpaceRequestToAgent) Reset()         { *m = CheckDiskSpaceRequestToAgent{} }
 func (m *CheckDiskSpaceRequestToAgent) String() string { return proto.CompactTextString(m) }
 func (*CheckDiskSpaceRequestToAgent) ProtoMessage()    {}
 func (*CheckDiskSpaceRequestToAgent) Descriptor() ([]byte, []int) {
-	return fileDescriptor_hub_to_agent_1ea90a78242ea64d, []int{11}
+	return fileDescriptor_hub_to_agent_c4fd15e7e1a0c16c, []int{19}
 }
 func (m *CheckDiskSpaceRequestToAgent) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_CheckDiskSpaceRequestToAgent.Unmarshal(m, b)
@@ -519,8 +829,29 @@ func (m *CheckDiskSpaceRequestToAgent) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_CheckDiskSpaceRequestToAgent proto.InternalMessageInfo
 
+func (m *CheckDiskSpaceRequestToAgent) GetDataDirPairs() []*DataDirPair {
+	if m != nil {
+		return m.DataDirPairs
+	}
+	return nil
+}
+
+func (m *CheckDiskSpaceRequestToAgent) GetLinkMode() bool {
+	if m != nil {
+		return m.LinkMode
+	}
+	return false
+}
+
+func (m *CheckDiskSpaceRequestToAgent) GetMarginPercent() int32 {
+	if m != nil {
+		return m.MarginPercent
+	}
+	return 0
+}
+
 type CheckDiskSpaceReplyFromAgent struct {
-	ListOfFileSysUsage   []*FileSysUsage `protobuf:"bytes,1,rep,name=ListOfFileSysUsage" json:"ListOfFileSysUsage,omitempty"`
+	DataDirSpaces        []*DataDirSpace `protobuf:"bytes,2,rep,name=DataDirSpaces" json:"DataDirSpaces,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
 	XXX_unrecognized     []byte          `json:"-"`
 	XXX_sizecache        int32           `json:"-"`
@@ -530,7 +861,7 @@ func (m *CheckDiskSpaceReplyFromAgent) Reset()         { *m = CheckDiskSpaceRepl
 func (m *CheckDiskSpaceReplyFromAgent) String() string { return proto.CompactTextString(m) }
 func (*CheckDiskSpaceReplyFromAgent) ProtoMessage()    {}
 func (*CheckDiskSpaceReplyFromAgent) Descriptor() ([]byte, []int) {
-	return fileDescriptor_hub_to_agent_1ea90a78242ea64d, []int{12}
+	return fileDescriptor_hub_to_agent_c4fd15e7e1a0c16c, []int{20}
 }
 func (m *CheckDiskSpaceReplyFromAgent) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_CheckDiskSpaceReplyFromAgent.Unmarshal(m, b)
@@ -550,17 +881,26 @@ func (m *CheckDiskSpaceReplyFromAgent) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_CheckDiskSpaceReplyFromAgent proto.InternalMessageInfo
 
-func (m *CheckDiskSpaceReplyFromAgent) GetListOfFileSysUsage() []*FileSysUsage {
+func (m *CheckDiskSpaceReplyFromAgent) GetDataDirSpaces() []*DataDirSpace {
 	if m != nil {
-		return m.ListOfFileSysUsage
+		return m.DataDirSpaces
 	}
 	return nil
 }
 
 func init() {
+	proto.RegisterType((*FileChunk)(nil), "idl.FileChunk")
+	proto.RegisterType((*PushFilesReply)(nil), "idl.PushFilesReply")
+	proto.RegisterType((*TailLogsRequest)(nil), "idl.TailLogsRequest")
 	proto.RegisterType((*UpgradeConvertPrimarySegmentsRequest)(nil), "idl.UpgradeConvertPrimarySegmentsRequest")
 	proto.RegisterType((*DataDirPair)(nil), "idl.DataDirPair")
 	proto.RegisterType((*UpgradeConvertPrimarySegmentsReply)(nil), "idl.UpgradeConvertPrimarySegmentsReply")
+	proto.RegisterType((*CancelAgentRequest)(nil), "idl.CancelAgentRequest")
+	proto.RegisterType((*CancelAgentReply)(nil), "idl.CancelAgentReply")
+	proto.RegisterType((*RevertAgentRequest)(nil), "idl.RevertAgentRequest")
+	proto.RegisterType((*RevertAgentReply)(nil), "idl.RevertAgentReply")
+	proto.RegisterType((*ShutdownAgentRequest)(nil), "idl.ShutdownAgentRequest")
+	proto.RegisterType((*ShutdownAgentReply)(nil), "idl.ShutdownAgentReply")
 	proto.RegisterType((*PingAgentsRequest)(nil), "idl.PingAgentsRequest")
 	proto.RegisterType((*PingAgentsReply)(nil), "idl.PingAgentsReply")
 	proto.RegisterType((*CheckUpgradeStatusRequest)(nil), "idl.CheckUpgradeStatusRequest")
@@ -568,7 +908,6 @@ func init() {
 	proto.RegisterType((*CheckConversionStatusRequest)(nil), "idl.CheckConversionStatusRequest")
 	proto.RegisterType((*SegmentInfo)(nil), "idl.SegmentInfo")
 	proto.RegisterType((*CheckConversionStatusReply)(nil), "idl.CheckConversionStatusReply")
-	proto.RegisterType((*FileSysUsage)(nil), "idl.FileSysUsage")
 	proto.RegisterType((*CheckDiskSpaceRequestToAgent)(nil), "idl.CheckDiskSpaceRequestToAgent")
 	proto.RegisterType((*CheckDiskSpaceReplyFromAgent)(nil), "idl.CheckDiskSpaceReplyFromAgent")
 }
@@ -590,6 +929,11 @@ type AgentClient interface {
 	CheckDiskSpaceOnAgents(ctx context.Context, in *CheckDiskSpaceRequestToAgent, opts ...grpc.CallOption) (*CheckDiskSpaceReplyFromAgent, error)
 	PingAgents(ctx context.Context, in *PingAgentsRequest, opts ...grpc.CallOption) (*PingAgentsReply, error)
 	UpgradeConvertPrimarySegments(ctx context.Context, in *UpgradeConvertPrimarySegmentsRequest, opts ...grpc.CallOption) (*UpgradeConvertPrimarySegmentsReply, error)
+	Shutdown(ctx context.Context, in *ShutdownAgentRequest, opts ...grpc.CallOption) (*ShutdownAgentReply, error)
+	TailLogs(ctx context.Context, in *TailLogsRequest, opts ...grpc.CallOption) (Agent_TailLogsClient, error)
+	PushFiles(ctx context.Context, opts ...grpc.CallOption) (Agent_PushFilesClient, error)
+	Cancel(ctx context.Context, in *CancelAgentRequest, opts ...grpc.CallOption) (*CancelAgentReply, error)
+	Revert(ctx context.Context, in *RevertAgentRequest, opts ...grpc.CallOption) (*RevertAgentReply, error)
 }
 
 type agentClient struct {
@@ -645,6 +989,99 @@ func (c *agentClient) UpgradeConvertPrimarySegments(ctx context.Context, in *Upg
 	return out, nil
 }
 
+func (c *agentClient) Shutdown(ctx context.Context, in *ShutdownAgentRequest, opts ...grpc.CallOption) (*ShutdownAgentReply, error) {
+	out := new(ShutdownAgentReply)
+	err := c.cc.Invoke(ctx, "/idl.Agent/Shutdown", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *agentClient) TailLogs(ctx context.Context, in *TailLogsRequest, opts ...grpc.CallOption) (Agent_TailLogsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Agent_serviceDesc.Streams[0], "/idl.Agent/TailLogs", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &agentTailLogsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Agent_TailLogsClient interface {
+	Recv() (*LogChunk, error)
+	grpc.ClientStream
+}
+
+type agentTailLogsClient struct {
+	grpc.ClientStream
+}
+
+func (x *agentTailLogsClient) Recv() (*LogChunk, error) {
+	m := new(LogChunk)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *agentClient) PushFiles(ctx context.Context, opts ...grpc.CallOption) (Agent_PushFilesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Agent_serviceDesc.Streams[1], "/idl.Agent/PushFiles", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &agentPushFilesClient{stream}
+	return x, nil
+}
+
+type Agent_PushFilesClient interface {
+	Send(*FileChunk) error
+	CloseAndRecv() (*PushFilesReply, error)
+	grpc.ClientStream
+}
+
+type agentPushFilesClient struct {
+	grpc.ClientStream
+}
+
+func (x *agentPushFilesClient) Send(m *FileChunk) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *agentPushFilesClient) CloseAndRecv() (*PushFilesReply, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(PushFilesReply)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *agentClient) Cancel(ctx context.Context, in *CancelAgentRequest, opts ...grpc.CallOption) (*CancelAgentReply, error) {
+	out := new(CancelAgentReply)
+	err := c.cc.Invoke(ctx, "/idl.Agent/Cancel", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *agentClient) Revert(ctx context.Context, in *RevertAgentRequest, opts ...grpc.CallOption) (*RevertAgentReply, error) {
+	out := new(RevertAgentReply)
+	err := c.cc.Invoke(ctx, "/idl.Agent/Revert", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // Server API for Agent service
 
 type AgentServer interface {
@@ -653,6 +1090,11 @@ type AgentServer interface {
 	CheckDiskSpaceOnAgents(context.Context, *CheckDiskSpaceRequestToAgent) (*CheckDiskSpaceReplyFromAgent, error)
 	PingAgents(context.Context, *PingAgentsRequest) (*PingAgentsReply, error)
 	UpgradeConvertPrimarySegments(context.Context, *UpgradeConvertPrimarySegmentsRequest) (*UpgradeConvertPrimarySegmentsReply, error)
+	Shutdown(context.Context, *ShutdownAgentRequest) (*ShutdownAgentReply, error)
+	TailLogs(*TailLogsRequest, Agent_TailLogsServer) error
+	PushFiles(Agent_PushFilesServer) error
+	Cancel(context.Context, *CancelAgentRequest) (*CancelAgentReply, error)
+	Revert(context.Context, *RevertAgentRequest) (*RevertAgentReply, error)
 }
 
 func RegisterAgentServer(s *grpc.Server, srv AgentServer) {
@@ -749,6 +1191,107 @@ func _Agent_UpgradeConvertPrimarySegments_Handler(srv interface{}, ctx context.C
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Agent_Shutdown_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ShutdownAgentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentServer).Shutdown(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/idl.Agent/Shutdown",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentServer).Shutdown(ctx, req.(*ShutdownAgentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Agent_TailLogs_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(TailLogsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(AgentServer).TailLogs(m, &agentTailLogsServer{stream})
+}
+
+type Agent_TailLogsServer interface {
+	Send(*LogChunk) error
+	grpc.ServerStream
+}
+
+type agentTailLogsServer struct {
+	grpc.ServerStream
+}
+
+func (x *agentTailLogsServer) Send(m *LogChunk) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Agent_PushFiles_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(AgentServer).PushFiles(&agentPushFilesServer{stream})
+}
+
+type Agent_PushFilesServer interface {
+	SendAndClose(*PushFilesReply) error
+	Recv() (*FileChunk, error)
+	grpc.ServerStream
+}
+
+type agentPushFilesServer struct {
+	grpc.ServerStream
+}
+
+func (x *agentPushFilesServer) SendAndClose(m *PushFilesReply) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *agentPushFilesServer) Recv() (*FileChunk, error) {
+	m := new(FileChunk)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _Agent_Cancel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelAgentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentServer).Cancel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/idl.Agent/Cancel",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentServer).Cancel(ctx, req.(*CancelAgentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Agent_Revert_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevertAgentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentServer).Revert(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/idl.Agent/Revert",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentServer).Revert(ctx, req.(*RevertAgentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _Agent_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "idl.Agent",
 	HandlerType: (*AgentServer)(nil),
@@ -773,49 +1316,91 @@ var _Agent_serviceDesc = grpc.ServiceDesc{
 			MethodName: "UpgradeConvertPrimarySegments",
 			Handler:    _Agent_UpgradeConvertPrimarySegments_Handler,
 		},
+		{
+			MethodName: "Shutdown",
+			Handler:    _Agent_Shutdown_Handler,
+		},
+		{
+			MethodName: "Cancel",
+			Handler:    _Agent_Cancel_Handler,
+		},
+		{
+			MethodName: "Revert",
+			Handler:    _Agent_Revert_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "TailLogs",
+			Handler:       _Agent_TailLogs_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "PushFiles",
+			Handler:       _Agent_PushFiles_Handler,
+			ClientStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "hub_to_agent.proto",
 }
 
-func init() { proto.RegisterFile("hub_to_agent.proto", fileDescriptor_hub_to_agent_1ea90a78242ea64d) }
-
-var fileDescriptor_hub_to_agent_1ea90a78242ea64d = []byte{
-	// 573 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x54, 0xdd, 0x8e, 0xd2, 0x40,
-	0x14, 0xde, 0xda, 0x45, 0xe1, 0xb0, 0x89, 0x32, 0xae, 0x6b, 0xad, 0x88, 0x38, 0xd9, 0x44, 0x4c,
-	0x0c, 0x17, 0xab, 0x17, 0x26, 0x7a, 0xb3, 0xd2, 0x6c, 0x34, 0x31, 0x40, 0xca, 0x72, 0x69, 0xd6,
-	0x42, 0x67, 0xcb, 0x64, 0x4b, 0xa7, 0x76, 0x06, 0x49, 0xdf, 0xc4, 0xc4, 0xc7, 0xf2, 0x85, 0x4c,
-	0x67, 0xa6, 0x30, 0x04, 0x58, 0xf7, 0x8e, 0xef, 0xe7, 0x1c, 0x4e, 0xbf, 0x39, 0x33, 0x80, 0x66,
-	0x8b, 0xc9, 0x95, 0x60, 0x57, 0x41, 0x44, 0x12, 0xd1, 0x4d, 0x33, 0x26, 0x18, 0xb2, 0x69, 0x18,
-	0xe3, 0xdf, 0x16, 0x9c, 0x8e, 0xd3, 0x28, 0x0b, 0x42, 0xd2, 0x63, 0xc9, 0x2f, 0x92, 0x89, 0x61,
-	0x46, 0xe7, 0x41, 0x96, 0x8f, 0x48, 0x34, 0x27, 0x89, 0xe0, 0x3e, 0xf9, 0xb9, 0x20, 0x5c, 0xa0,
-	0x26, 0xd4, 0x06, 0x71, 0xf8, 0x99, 0x26, 0x1e, 0xcd, 0x1c, 0xab, 0x6d, 0x75, 0x6a, 0xfe, 0x9a,
-	0x28, 0xd4, 0x3e, 0x59, 0x6a, 0xf5, 0x9e, 0x52, 0x57, 0x04, 0x7a, 0x0f, 0x47, 0x5e, 0x20, 0x02,
-	0x8f, 0x66, 0xc3, 0x80, 0x66, 0xdc, 0xb1, 0xdb, 0x76, 0xa7, 0x7e, 0xf6, 0xa8, 0x4b, 0xc3, 0xb8,
-	0x6b, 0x08, 0xfe, 0x86, 0x0b, 0xff, 0xb1, 0xa0, 0x6e, 0x10, 0xa8, 0x05, 0x30, 0x88, 0x43, 0xcd,
-	0xe8, 0x11, 0x0c, 0xa6, 0xd0, 0xfb, 0x64, 0x59, 0xea, 0x6a, 0x08, 0x83, 0x41, 0x0e, 0x3c, 0x18,
-	0xc4, 0xe1, 0x90, 0x65, 0xc2, 0xb1, 0xdb, 0x56, 0xa7, 0xe2, 0x97, 0xb0, 0x50, 0xfa, 0x64, 0x29,
-	0x95, 0x43, 0xa5, 0x68, 0x58, 0x28, 0x3d, 0x96, 0x08, 0x92, 0x08, 0xa7, 0xa2, 0x14, 0x0d, 0xf1,
-	0x29, 0xe0, 0xff, 0xe4, 0x96, 0xc6, 0x39, 0x7e, 0x0c, 0x8d, 0x21, 0x4d, 0xa2, 0xf3, 0xc8, 0x88,
-	0x12, 0x37, 0xe0, 0xa1, 0x49, 0x16, 0xbe, 0xe7, 0xf0, 0xac, 0x37, 0x23, 0xd3, 0x1b, 0xdd, 0x72,
-	0x24, 0x02, 0xb1, 0x58, 0xf9, 0x3f, 0xc2, 0xd3, 0x5d, 0x62, 0x1a, 0xe7, 0xa8, 0x0d, 0xf5, 0x61,
-	0xc6, 0xa6, 0x84, 0xf3, 0x6f, 0x94, 0x0b, 0x1d, 0x8a, 0x49, 0xe1, 0x19, 0x34, 0x65, 0xb1, 0x9a,
-	0x92, 0x53, 0x96, 0x6c, 0x34, 0x47, 0x6f, 0xa1, 0x5a, 0x8e, 0xec, 0x58, 0xc6, 0xb9, 0x68, 0xf2,
-	0x6b, 0x72, 0xcd, 0xfc, 0x95, 0x03, 0xb9, 0x50, 0xfd, 0xc2, 0xb8, 0x48, 0x82, 0x39, 0xd1, 0x09,
-	0xaf, 0x30, 0x1e, 0x43, 0xdd, 0x28, 0x32, 0xa3, 0xb3, 0x36, 0xa2, 0x43, 0x08, 0x0e, 0xbd, 0x09,
-	0x0d, 0x65, 0x83, 0x8a, 0x2f, 0x7f, 0x17, 0xee, 0xf2, 0xe4, 0x6c, 0xd9, 0xb7, 0x84, 0xf8, 0x03,
-	0xb8, 0x7b, 0x3e, 0xa0, 0x08, 0xc0, 0x85, 0xaa, 0x82, 0x44, 0x8d, 0x5f, 0xf3, 0x57, 0x18, 0x7b,
-	0x70, 0x74, 0x41, 0x63, 0x32, 0xca, 0xf9, 0x98, 0x07, 0x11, 0x29, 0x16, 0xa4, 0xc0, 0x3c, 0xe7,
-	0x82, 0xcc, 0xcb, 0x05, 0x5a, 0x33, 0xe8, 0x18, 0x2a, 0xd2, 0x28, 0x07, 0xb3, 0x7c, 0x05, 0x70,
-	0x4b, 0x07, 0xe8, 0x51, 0x7e, 0x33, 0x4a, 0x83, 0x29, 0xd1, 0xc9, 0x5d, 0x32, 0x79, 0x80, 0x38,
-	0xd8, 0xd6, 0xd3, 0x38, 0xbf, 0xc8, 0xd8, 0x5c, 0xea, 0xe8, 0x1c, 0x50, 0x71, 0x10, 0x83, 0x6b,
-	0x73, 0x16, 0x1d, 0x75, 0x43, 0x46, 0x6d, 0x0a, 0xfe, 0x0e, 0xf3, 0xd9, 0x5f, 0x1b, 0x2a, 0xaa,
-	0xd9, 0x25, 0xa0, 0xed, 0x55, 0x40, 0x2d, 0xd9, 0x66, 0xef, 0x02, 0xb9, 0xcd, 0xbd, 0x7a, 0xb1,
-	0x7b, 0x07, 0xe8, 0x3b, 0x3c, 0xd9, 0x19, 0x31, 0x7a, 0xb5, 0x2e, 0xdc, 0xb3, 0x3f, 0xee, 0xcb,
-	0xdb, 0x2c, 0xaa, 0xfd, 0x0f, 0x38, 0xd9, 0x4c, 0x68, 0x90, 0xa8, 0xdd, 0x37, 0xfb, 0xef, 0x89,
-	0xd7, 0xdd, 0x6d, 0x31, 0x13, 0xc6, 0x07, 0xe8, 0x13, 0xc0, 0xfa, 0x46, 0xa1, 0x13, 0x59, 0xb2,
-	0x75, 0xef, 0xdc, 0xe3, 0x2d, 0x5e, 0xcd, 0xb7, 0x80, 0x17, 0xb7, 0x5e, 0x65, 0xf4, 0x46, 0x16,
-	0xde, 0xe5, 0x99, 0x74, 0x5f, 0xdf, 0xc5, 0x2a, 0xff, 0x76, 0x72, 0x5f, 0x3e, 0xc3, 0xef, 0xfe,
-	0x05, 0x00, 0x00, 0xff, 0xff, 0x84, 0x4b, 0xef, 0x04, 0x9c, 0x05, 0x00, 0x00,
+func init() { proto.RegisterFile("hub_to_agent.proto", fileDescriptor_hub_to_agent_c4fd15e7e1a0c16c) }
+
+var fileDescriptor_hub_to_agent_c4fd15e7e1a0c16c = []byte{
+	// 877 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x56, 0xdd, 0x8e, 0xdb, 0x44,
+	0x14, 0x5e, 0x27, 0x9b, 0x34, 0x39, 0xfb, 0xd3, 0xed, 0x34, 0xbb, 0x75, 0x4d, 0x28, 0x61, 0xb4,
+	0x2a, 0x41, 0xa0, 0x55, 0xb5, 0xad, 0x04, 0x2a, 0x5c, 0x50, 0x12, 0xad, 0x00, 0xa5, 0x1b, 0xcb,
+	0xd9, 0xf6, 0xae, 0x2a, 0xde, 0x78, 0x70, 0x46, 0x71, 0x66, 0x82, 0x3d, 0x26, 0xca, 0x8b, 0x70,
+	0xc3, 0x6b, 0xf0, 0x70, 0x5c, 0xa2, 0xf9, 0xb1, 0x63, 0xd7, 0x49, 0x59, 0xee, 0x7c, 0xbe, 0xf3,
+	0x33, 0x9f, 0x3f, 0x9f, 0x73, 0xc6, 0x80, 0x66, 0xe9, 0xed, 0x7b, 0xc1, 0xdf, 0xfb, 0x21, 0x61,
+	0xe2, 0x62, 0x19, 0x73, 0xc1, 0x51, 0x9d, 0x06, 0x91, 0x73, 0x38, 0xe5, 0x8b, 0x05, 0x67, 0x1a,
+	0xc2, 0x63, 0x68, 0x5f, 0xd1, 0x88, 0x0c, 0x66, 0x29, 0x9b, 0x23, 0x04, 0xfb, 0xae, 0x2f, 0x66,
+	0xb6, 0xd5, 0xb3, 0xfa, 0x6d, 0x4f, 0x3d, 0x4b, 0x6c, 0xe8, 0x0b, 0xdf, 0xae, 0xf5, 0xac, 0xfe,
+	0xa1, 0xa7, 0x9e, 0x91, 0x03, 0xad, 0xc1, 0x8c, 0x4c, 0xe7, 0x49, 0xba, 0xb0, 0xeb, 0x2a, 0x36,
+	0xb7, 0xf1, 0x53, 0x38, 0x76, 0xd3, 0x64, 0x26, 0x8b, 0x26, 0x1e, 0x59, 0x46, 0x6b, 0xd4, 0x81,
+	0x86, 0xac, 0x94, 0xd8, 0x56, 0xaf, 0xde, 0x6f, 0x7b, 0xda, 0xc0, 0x73, 0xb8, 0x7f, 0xe3, 0xd3,
+	0x68, 0xc4, 0xc3, 0xc4, 0x23, 0xbf, 0xa7, 0x24, 0x11, 0xe8, 0x29, 0x34, 0x27, 0x3c, 0x8d, 0xa7,
+	0x44, 0x11, 0x38, 0xbe, 0x3c, 0xbe, 0xa0, 0x41, 0x74, 0x31, 0xe2, 0xa1, 0x46, 0x3d, 0xe3, 0x45,
+	0x36, 0xdc, 0x1b, 0x70, 0x26, 0x08, 0x13, 0x8a, 0x55, 0xc3, 0xcb, 0x4c, 0x74, 0x06, 0xcd, 0x2b,
+	0x1e, 0x45, 0x7c, 0xa5, 0x68, 0xb5, 0x3c, 0x63, 0xe1, 0xbf, 0x2d, 0x38, 0x7f, 0xb3, 0x0c, 0x63,
+	0x3f, 0x20, 0x03, 0xce, 0xfe, 0x20, 0xb1, 0x70, 0x63, 0xba, 0xf0, 0xe3, 0xf5, 0x84, 0x84, 0x0b,
+	0xc2, 0x44, 0x4e, 0xa1, 0x0b, 0xed, 0x71, 0x14, 0xfc, 0x48, 0xd9, 0x90, 0xc6, 0x46, 0x86, 0x0d,
+	0x20, 0xbd, 0xd7, 0x64, 0x65, 0xbc, 0x35, 0xed, 0xcd, 0x01, 0xf4, 0x02, 0x0e, 0xa5, 0x3a, 0x43,
+	0x1a, 0xbb, 0x3e, 0x8d, 0x13, 0xbb, 0xde, 0xab, 0xf7, 0x0f, 0x2e, 0x4f, 0xd4, 0x4b, 0x14, 0x1c,
+	0x5e, 0x29, 0x4a, 0x6a, 0x39, 0xa2, 0x6c, 0xfe, 0x9a, 0x07, 0xc4, 0xde, 0x57, 0xa4, 0x73, 0x1b,
+	0xff, 0x65, 0xc1, 0x41, 0x21, 0x18, 0x3d, 0x01, 0x18, 0x47, 0x81, 0x41, 0x0c, 0xbd, 0x02, 0x22,
+	0xfd, 0xd7, 0x64, 0x95, 0xf9, 0x35, 0xc1, 0x02, 0x22, 0x85, 0x1b, 0x47, 0x81, 0xcb, 0x63, 0xa1,
+	0xf4, 0x69, 0x78, 0x99, 0x29, 0x3d, 0xd7, 0x64, 0xa5, 0x3c, 0xfb, 0xda, 0x63, 0xcc, 0xa2, 0xd8,
+	0x8d, 0x92, 0xd8, 0xf8, 0x1c, 0xf0, 0x7f, 0x68, 0xba, 0x8c, 0xd6, 0xb8, 0x03, 0x68, 0xe0, 0xb3,
+	0x29, 0x89, 0x5e, 0xc9, 0x46, 0x34, 0x3a, 0xe3, 0x67, 0x70, 0x52, 0x42, 0x65, 0x9f, 0x74, 0xa1,
+	0xad, 0xb1, 0x88, 0x04, 0xa6, 0x57, 0x36, 0x80, 0xac, 0xe3, 0x11, 0x79, 0x4a, 0xa9, 0x0e, 0x82,
+	0x93, 0x12, 0x2a, 0x4f, 0x3c, 0x83, 0xce, 0x64, 0x96, 0x8a, 0x80, 0xaf, 0x58, 0x29, 0xb6, 0x03,
+	0xe8, 0x03, 0x5c, 0x46, 0x3f, 0x84, 0x07, 0x2e, 0x65, 0xe1, 0xab, 0xb0, 0xd0, 0x06, 0xf8, 0x2b,
+	0xb8, 0x5f, 0x04, 0x25, 0x3b, 0x1b, 0xee, 0xbd, 0x25, 0x71, 0x42, 0x39, 0x33, 0xc2, 0x67, 0x26,
+	0xfe, 0x04, 0x1e, 0xab, 0xee, 0x37, 0x62, 0x4c, 0x84, 0x2f, 0xd2, 0xbc, 0xd2, 0x77, 0xf0, 0x68,
+	0x9b, 0x53, 0x56, 0xec, 0xc1, 0x81, 0x1b, 0xf3, 0x29, 0x49, 0x92, 0x11, 0x4d, 0x84, 0xa9, 0x5a,
+	0x84, 0xf0, 0x0c, 0xba, 0x2a, 0x59, 0xeb, 0x2b, 0x0f, 0x2b, 0x15, 0x47, 0x5f, 0x43, 0x2b, 0x13,
+	0xdb, 0xb6, 0x0a, 0xdd, 0x66, 0xc0, 0x9f, 0xd9, 0x6f, 0xdc, 0xcb, 0x23, 0x64, 0xa7, 0xfd, 0xc4,
+	0x13, 0xc1, 0xfc, 0x05, 0x31, 0xbd, 0x91, 0xdb, 0xf8, 0x0d, 0x1c, 0x14, 0x92, 0x8a, 0x1f, 0xdd,
+	0x2a, 0x4f, 0x98, 0x5c, 0x07, 0xb7, 0x34, 0x30, 0x83, 0xa7, 0x9e, 0x65, 0x74, 0xd6, 0x73, 0x7a,
+	0x1b, 0x64, 0x26, 0x7e, 0x0b, 0xce, 0x8e, 0x17, 0x90, 0x02, 0x7c, 0x0b, 0x2d, 0x6d, 0x92, 0x8c,
+	0x7e, 0xb7, 0x48, 0xbf, 0x92, 0x94, 0x47, 0xe3, 0x3f, 0x2d, 0xa3, 0xcc, 0x90, 0x26, 0xf3, 0xc9,
+	0xd2, 0x9f, 0x12, 0x23, 0xc9, 0x0d, 0x57, 0xdf, 0xac, 0x32, 0x8b, 0xd6, 0xff, 0x9e, 0xc5, 0x5a,
+	0x79, 0x16, 0xd1, 0x39, 0x1c, 0xbd, 0xf6, 0xe3, 0x90, 0x32, 0x97, 0xc4, 0x53, 0x29, 0x8c, 0x9e,
+	0xa0, 0x32, 0x88, 0xdf, 0x55, 0x79, 0x2d, 0xa3, 0xf5, 0x55, 0xcc, 0x17, 0x9a, 0xd7, 0x37, 0x70,
+	0x64, 0x4e, 0x54, 0xde, 0xc4, 0xae, 0x29, 0x62, 0x0f, 0x8a, 0xc4, 0x74, 0x5e, 0x39, 0xee, 0x97,
+	0xfd, 0x96, 0x75, 0x52, 0xbb, 0xfc, 0xa7, 0x01, 0x0d, 0x5d, 0xe8, 0x06, 0x50, 0xb5, 0xaf, 0xd0,
+	0x13, 0x55, 0x67, 0x67, 0x37, 0x3a, 0xdd, 0x9d, 0x7e, 0x39, 0x0a, 0x7b, 0xe8, 0x1d, 0x9c, 0x6e,
+	0xfd, 0x5e, 0xe8, 0xf3, 0x4d, 0xe2, 0x8e, 0x66, 0x74, 0x3e, 0xfb, 0x58, 0x88, 0x2e, 0xff, 0x2b,
+	0x9c, 0x95, 0xd5, 0x19, 0xeb, 0x49, 0x2c, 0xd5, 0xdf, 0xf1, 0x49, 0x9d, 0xed, 0x21, 0x45, 0x75,
+	0xf1, 0x1e, 0xfa, 0x1e, 0x60, 0x33, 0xb8, 0xe8, 0x4c, 0xa5, 0x54, 0xc6, 0xdb, 0xe9, 0x54, 0x70,
+	0xcd, 0x2f, 0x85, 0x4f, 0x3f, 0xba, 0xd1, 0xd0, 0x97, 0x2a, 0xf1, 0x2e, 0x37, 0x89, 0xf3, 0xc5,
+	0x5d, 0x42, 0xf5, 0xb1, 0x3f, 0x40, 0x2b, 0x5b, 0x4c, 0xe8, 0xb1, 0x9e, 0x80, 0x2d, 0xfb, 0xcb,
+	0x79, 0xb4, 0xcd, 0xa5, 0x2b, 0x3c, 0x87, 0x56, 0x76, 0x99, 0x22, 0xfd, 0x72, 0x1f, 0xdc, 0xad,
+	0xce, 0x51, 0x76, 0x97, 0xaa, 0x9b, 0x1e, 0xef, 0x3d, 0xb3, 0xd0, 0x0b, 0x68, 0xe7, 0x37, 0x35,
+	0xd2, 0x77, 0x6d, 0xfe, 0x2b, 0xe0, 0x3c, 0xd4, 0x12, 0x95, 0x6e, 0x72, 0xbc, 0xd7, 0xb7, 0xd0,
+	0x4b, 0x68, 0xea, 0xa5, 0x8c, 0x34, 0x9f, 0xea, 0x72, 0x77, 0x4e, 0xab, 0x0e, 0x4d, 0xf3, 0x25,
+	0x34, 0xf5, 0xb6, 0x36, 0xb9, 0xd5, 0x85, 0xee, 0x9c, 0x56, 0x1d, 0x2a, 0xf7, 0xb6, 0xa9, 0xfe,
+	0x57, 0x9e, 0xff, 0x3b, 0x00, 0xa9, 0x32, 0xb5, 0x6e, 0xd8, 0x08, 0x00, 0x00,
 }
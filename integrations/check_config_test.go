@@ -29,9 +29,10 @@ var _ = Describe("check config", func() {
 		Expect(err).ToNot(HaveOccurred())
 
 		conf := &services.HubConfig{
-			CliToHubPort:   port,
-			HubToAgentPort: hubToAgentPort,
-			StateDir:       testStateDir,
+			CliToHubPort:      port,
+			HubToAgentPort:    hubToAgentPort,
+			StateDir:          testStateDir,
+			ServerCredentials: hubCredentials(),
 		}
 		commandExecer = &testutils.FakeCommandExecer{}
 		commandExecer.SetOutput(&testutils.FakeCommand{})
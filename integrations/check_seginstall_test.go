@@ -2,7 +2,6 @@ package integrations_test
 
 import (
 	"strings"
-	"time"
 
 	"github.com/greenplum-db/gpupgrade/hub/cluster_ssher"
 	"github.com/greenplum-db/gpupgrade/hub/services"
@@ -35,9 +34,10 @@ var _ = Describe("check", func() {
 		mockAgent, agentPort = testutils.NewMockAgentServer()
 
 		conf := &services.HubConfig{
-			CliToHubPort:   port,
-			HubToAgentPort: agentPort,
-			StateDir:       testStateDir,
+			CliToHubPort:      port,
+			HubToAgentPort:    agentPort,
+			StateDir:          testStateDir,
+			ServerCredentials: hubCredentials(),
 		}
 		outChan = make(chan []byte, 2)
 		errChan = make(chan error, 2)
@@ -51,11 +51,13 @@ var _ = Describe("check", func() {
 		cm = testutils.NewMockChecklistManager()
 		clusterSsher := cluster_ssher.NewClusterSsher(
 			cm,
-			services.NewPingerManager(conf.StateDir, 500*time.Millisecond),
-			commandExecer.Exec,
+			nil,
+			cluster_ssher.NewSSHBackend(commandExecer.Exec, cluster_ssher.SSHConfig{}),
 		)
 
 		hub = services.NewHub(testutils.InitClusterPairFromDB(), grpc.DialContext, commandExecer.Exec, conf, clusterSsher, cm)
+		clusterSsher.AgentPinger = hub
+		markStepsComplete(upgradestatus.CONFIG)
 		go hub.Start()
 	})
 
@@ -76,7 +78,7 @@ var _ = Describe("check", func() {
 	Describe("seginstall", func() {
 		It("updates status PENDING to RUNNING then to COMPLETE if successful", func() {
 			mockAgent.StatusConversionResponse = &pb.CheckConversionStatusReply{
-				Statuses: []string{},
+				Statuses: []*pb.SegmentConversionStatus{},
 			}
 
 			Expect(cm.IsPending(upgradestatus.SEGINSTALL)).To(BeTrue())
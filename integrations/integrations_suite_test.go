@@ -11,11 +11,16 @@ import (
 	"testing"
 	"time"
 
+	"github.com/greenplum-db/gpupgrade/certs"
+	"github.com/greenplum-db/gpupgrade/hub/upgradestatus"
+	pb "github.com/greenplum-db/gpupgrade/idl"
+
 	"github.com/greenplum-db/gp-common-go-libs/testhelper"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	"github.com/onsi/gomega/gbytes"
 	. "github.com/onsi/gomega/gexec"
+	"google.golang.org/grpc/credentials"
 )
 
 func TestCommands(t *testing.T) {
@@ -115,6 +120,34 @@ func runStatusUpgrade() string {
 	return string(runCommand("status", "upgrade").Out.Contents())
 }
 
+// hubCredentials loads the certificate that prepare init created for the hub,
+// so that the CLI can connect to the hubs started by the tests.
+func hubCredentials() credentials.TransportCredentials {
+	creds, err := certs.ServerCredentials(testStateDir, certs.Hub, certs.CLI)
+	Expect(err).ToNot(HaveOccurred())
+	return creds
+}
+
+// markStepsComplete records the given steps as COMPLETE in the upgrade journal,
+// so that the hub will allow the steps that depend on them to run.
+func markStepsComplete(steps ...string) {
+	store := upgradestatus.NewStateStore(testStateDir)
+	for _, step := range steps {
+		Expect(store.Record(step, pb.StepStatus_COMPLETE, nil)).To(Succeed())
+	}
+}
+
+// markMasterUpgradeComplete leaves the state dir looking as if pg_upgrade has
+// finished successfully on the master.
+func markMasterUpgradeComplete() {
+	markStepsComplete(upgradestatus.CONFIG)
+
+	pgUpgradeDir := filepath.Join(testStateDir, "pg_upgrade")
+	Expect(os.MkdirAll(pgUpgradeDir, 0700)).To(Succeed())
+	err := ioutil.WriteFile(filepath.Join(pgUpgradeDir, "1.done"), []byte("Upgrade complete\n"), 0600)
+	Expect(err).ToNot(HaveOccurred())
+}
+
 func checkPortIsAvailable(port int) bool {
 	t := time.After(2 * time.Second)
 	select {
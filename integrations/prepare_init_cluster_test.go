@@ -2,7 +2,6 @@ package integrations_test
 
 import (
 	"os"
-	"time"
 
 	"github.com/greenplum-db/gpupgrade/hub/cluster_ssher"
 	"github.com/greenplum-db/gpupgrade/hub/services"
@@ -28,20 +27,23 @@ var _ = Describe("prepare", func() {
 		Expect(err).ToNot(HaveOccurred())
 
 		conf := &services.HubConfig{
-			CliToHubPort:   port,
-			HubToAgentPort: 6416,
-			StateDir:       testStateDir,
+			CliToHubPort:      port,
+			HubToAgentPort:    6416,
+			StateDir:          testStateDir,
+			ServerCredentials: hubCredentials(),
 		}
 		commandExecer = &testutils.FakeCommandExecer{}
 		commandExecer.SetOutput(&testutils.FakeCommand{})
 		cm = testutils.NewMockChecklistManager()
 		clusterSsher := cluster_ssher.NewClusterSsher(
 			cm,
-			services.NewPingerManager(conf.StateDir, 500*time.Millisecond),
-			commandExecer.Exec,
+			nil,
+			cluster_ssher.NewSSHBackend(commandExecer.Exec, cluster_ssher.SSHConfig{}),
 		)
 
 		hub = services.NewHub(testutils.InitClusterPairFromDB(), grpc.DialContext, commandExecer.Exec, conf, clusterSsher, cm)
+		clusterSsher.AgentPinger = hub
+		markStepsComplete(upgradestatus.CONFIG)
 		go hub.Start()
 	})
 
@@ -2,7 +2,6 @@ package integrations_test
 
 import (
 	"errors"
-	"time"
 
 	"github.com/greenplum-db/gp-common-go-libs/testhelper"
 	"github.com/greenplum-db/gpupgrade/hub/cluster_ssher"
@@ -39,9 +38,10 @@ var _ = Describe("prepare shutdown-clusters", func() {
 		mockAgent, agentPort = testutils.NewMockAgentServer()
 
 		conf := &services.HubConfig{
-			CliToHubPort:   port,
-			HubToAgentPort: agentPort,
-			StateDir:       testStateDir,
+			CliToHubPort:      port,
+			HubToAgentPort:    agentPort,
+			StateDir:          testStateDir,
+			ServerCredentials: hubCredentials(),
 		}
 		outChan = make(chan []byte, 5)
 		errChan = make(chan error, 5)
@@ -61,10 +61,13 @@ var _ = Describe("prepare shutdown-clusters", func() {
 		cm = testutils.NewMockChecklistManager()
 		clusterSsher := cluster_ssher.NewClusterSsher(
 			cm,
-			services.NewPingerManager(conf.StateDir, 500*time.Millisecond),
-			commandExecer.Exec,
+			nil,
+			cluster_ssher.NewSSHBackend(commandExecer.Exec, cluster_ssher.SSHConfig{}),
 		)
 		hub = services.NewHub(clusterPair, grpc.DialContext, commandExecer.Exec, conf, clusterSsher, cm)
+		clusterSsher.AgentPinger = hub
+		Expect(clusterPair.WriteNewConfig(testStateDir)).To(Succeed())
+		markStepsComplete(upgradestatus.CHECK_ALL)
 		go hub.Start()
 	})
 
@@ -75,7 +78,7 @@ var _ = Describe("prepare shutdown-clusters", func() {
 
 	It("updates status PENDING, RUNNING then COMPLETE if successful", func() {
 		mockAgent.StatusConversionResponse = &pb.CheckConversionStatusReply{
-			Statuses: []string{},
+			Statuses: []*pb.SegmentConversionStatus{},
 		}
 
 		Expect(cm.IsPending(upgradestatus.SHUTDOWN_CLUSTERS)).To(BeTrue())
@@ -96,7 +99,7 @@ var _ = Describe("prepare shutdown-clusters", func() {
 
 	It("updates status to FAILED if it fails to run", func() {
 		mockAgent.StatusConversionResponse = &pb.CheckConversionStatusReply{
-			Statuses: []string{},
+			Statuses: []*pb.SegmentConversionStatus{},
 		}
 
 		Expect(cm.IsPending(upgradestatus.SHUTDOWN_CLUSTERS)).To(BeTrue())
@@ -3,7 +3,6 @@ package integrations_test
 import (
 	"os"
 	"strings"
-	"time"
 
 	"github.com/greenplum-db/gpupgrade/hub/cluster_ssher"
 	"github.com/greenplum-db/gpupgrade/hub/services"
@@ -33,9 +32,10 @@ var _ = Describe("prepare", func() {
 		Expect(err).ToNot(HaveOccurred())
 
 		conf := &services.HubConfig{
-			CliToHubPort:   port,
-			HubToAgentPort: agentPort,
-			StateDir:       testStateDir,
+			CliToHubPort:      port,
+			HubToAgentPort:    agentPort,
+			StateDir:          testStateDir,
+			ServerCredentials: hubCredentials(),
 		}
 		commandExecer = &testutils.FakeCommandExecer{}
 		commandExecer.SetOutput(&testutils.FakeCommand{})
@@ -43,14 +43,16 @@ var _ = Describe("prepare", func() {
 		cm = testutils.NewMockChecklistManager()
 		clusterSsher := cluster_ssher.NewClusterSsher(
 			cm,
-			services.NewPingerManager(conf.StateDir, 500*time.Millisecond),
-			commandExecer.Exec,
+			nil,
+			cluster_ssher.NewSSHBackend(commandExecer.Exec, cluster_ssher.SSHConfig{}),
 		)
 		hub = services.NewHub(testutils.InitClusterPairFromDB(), grpc.DialContext, commandExecer.Exec, conf, clusterSsher, cm)
+		clusterSsher.AgentPinger = hub
 
 		pgPort := os.Getenv("PGPORT")
 		Expect(pgPort).ToNot(Equal(""), "Please set PGPORT to a useful value and rerun the tests.")
 
+		markStepsComplete(upgradestatus.SEGINSTALL)
 		go hub.Start()
 	})
 
@@ -65,7 +67,7 @@ var _ = Describe("prepare", func() {
 			defer close(done)
 
 			mockAgent.StatusConversionResponse = &pb.CheckConversionStatusReply{
-				Statuses: []string{},
+				Statuses: []*pb.SegmentConversionStatus{},
 			}
 
 			Expect(cm.IsPending(upgradestatus.START_AGENTS)).To(BeTrue())
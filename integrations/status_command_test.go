@@ -2,7 +2,6 @@ package integrations_test
 
 import (
 	"os"
-	"time"
 
 	agentServices "github.com/greenplum-db/gpupgrade/agent/services"
 	"github.com/greenplum-db/gpupgrade/hub/cluster_ssher"
@@ -47,19 +46,21 @@ var _ = Describe("status", func() {
 		Expect(err).ToNot(HaveOccurred())
 
 		conf := &hubServices.HubConfig{
-			CliToHubPort:   port,
-			HubToAgentPort: agentPort,
-			StateDir:       testStateDir,
+			CliToHubPort:      port,
+			HubToAgentPort:    agentPort,
+			StateDir:          testStateDir,
+			ServerCredentials: hubCredentials(),
 		}
 		commandExecer = &testutils.FakeCommandExecer{}
 		commandExecer.SetOutput(&testutils.FakeCommand{})
 
 		clusterSsher := cluster_ssher.NewClusterSsher(
 			upgradestatus.NewChecklistManager(conf.StateDir),
-			hubServices.NewPingerManager(conf.StateDir, 500*time.Millisecond),
-			commandExecer.Exec,
+			nil,
+			cluster_ssher.NewSSHBackend(commandExecer.Exec, cluster_ssher.SSHConfig{}),
 		)
 		hub = hubServices.NewHub(testutils.InitClusterPairFromDB(), grpc.DialContext, commandExecer.Exec, conf, clusterSsher, cm)
+		clusterSsher.AgentPinger = hub
 		go hub.Start()
 	})
 
@@ -1,10 +1,9 @@
 package integrations_test
 
 import (
-	"time"
-
 	"github.com/greenplum-db/gpupgrade/hub/cluster_ssher"
 	"github.com/greenplum-db/gpupgrade/hub/services"
+	"github.com/greenplum-db/gpupgrade/hub/upgradestatus"
 	"github.com/greenplum-db/gpupgrade/testutils"
 
 	. "github.com/onsi/ginkgo"
@@ -25,19 +24,22 @@ var _ = Describe("upgrade convert master", func() {
 		Expect(err).ToNot(HaveOccurred())
 
 		conf := &services.HubConfig{
-			CliToHubPort:   port,
-			HubToAgentPort: 0,
-			StateDir:       testStateDir,
+			CliToHubPort:      port,
+			HubToAgentPort:    0,
+			StateDir:          testStateDir,
+			ServerCredentials: hubCredentials(),
 		}
 
 		cm = testutils.NewMockChecklistManager()
 
 		clusterSsher := cluster_ssher.NewClusterSsher(
 			cm,
-			services.NewPingerManager(conf.StateDir, 500*time.Millisecond),
-			commandExecer.Exec,
+			nil,
+			cluster_ssher.NewSSHBackend(commandExecer.Exec, cluster_ssher.SSHConfig{}),
 		)
 		hub = services.NewHub(testutils.InitClusterPairFromDB(), grpc.DialContext, commandExecer.Exec, conf, clusterSsher, cm)
+		clusterSsher.AgentPinger = hub
+		markStepsComplete(upgradestatus.SHUTDOWN_CLUSTERS)
 		go hub.Start()
 	})
 
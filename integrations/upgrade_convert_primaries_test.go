@@ -5,11 +5,11 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
-	"time"
 
 	agentServices "github.com/greenplum-db/gpupgrade/agent/services"
 	"github.com/greenplum-db/gpupgrade/hub/cluster_ssher"
 	"github.com/greenplum-db/gpupgrade/hub/services"
+	"github.com/greenplum-db/gpupgrade/hub/upgradestatus"
 	"github.com/greenplum-db/gpupgrade/testutils"
 
 	. "github.com/onsi/ginkgo"
@@ -48,9 +48,10 @@ var _ = Describe("upgrade convert primaries", func() {
 		Expect(err).ToNot(HaveOccurred())
 
 		conf := &services.HubConfig{
-			CliToHubPort:   port,
-			HubToAgentPort: 6416,
-			StateDir:       testStateDir,
+			CliToHubPort:      port,
+			HubToAgentPort:    6416,
+			StateDir:          testStateDir,
+			ServerCredentials: hubCredentials(),
 		}
 		hubOutChan = make(chan []byte, 10)
 
@@ -61,11 +62,12 @@ var _ = Describe("upgrade convert primaries", func() {
 
 		clusterSsher := cluster_ssher.NewClusterSsher(
 			cm,
-			services.NewPingerManager(conf.StateDir, 500*time.Millisecond),
-			hubCommandExecer.Exec,
+			nil,
+			cluster_ssher.NewSSHBackend(hubCommandExecer.Exec, cluster_ssher.SSHConfig{}),
 		)
 		clusterPair = testutils.InitClusterPairFromDB()
 		hub = services.NewHub(clusterPair, grpc.DialContext, hubCommandExecer.Exec, conf, clusterSsher, cm)
+		clusterSsher.AgentPinger = hub
 		go hub.Start()
 
 		agentCommandOutput = make(chan []byte, 12)
@@ -78,7 +80,7 @@ var _ = Describe("upgrade convert primaries", func() {
 			Port:     6416,
 			StateDir: testStateDir,
 		})
-		setStateFile(testStateDir, "start-agents", "completed")
+		markStepsComplete(upgradestatus.SHARE_OIDS, upgradestatus.START_AGENTS)
 		go agent.Start()
 	})
 
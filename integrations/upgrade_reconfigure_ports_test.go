@@ -2,7 +2,6 @@ package integrations_test
 
 import (
 	"errors"
-	"time"
 
 	"github.com/greenplum-db/gpupgrade/hub/cluster_ssher"
 	"github.com/greenplum-db/gpupgrade/hub/services"
@@ -38,9 +37,10 @@ var _ = Describe("upgrade reconfigure ports", func() {
 		Expect(err).ToNot(HaveOccurred())
 
 		conf := &services.HubConfig{
-			CliToHubPort:   port,
-			HubToAgentPort: agentPort,
-			StateDir:       testStateDir,
+			CliToHubPort:      port,
+			HubToAgentPort:    agentPort,
+			StateDir:          testStateDir,
+			ServerCredentials: hubCredentials(),
 		}
 
 		outChan = make(chan []byte, 10)
@@ -54,10 +54,12 @@ var _ = Describe("upgrade reconfigure ports", func() {
 		cm = testutils.NewMockChecklistManager()
 		clusterSsher := cluster_ssher.NewClusterSsher(
 			cm,
-			services.NewPingerManager(conf.StateDir, 500*time.Millisecond),
-			hubExecer.Exec,
+			nil,
+			cluster_ssher.NewSSHBackend(hubExecer.Exec, cluster_ssher.SSHConfig{}),
 		)
 		hub = services.NewHub(testutils.InitClusterPairFromDB(), grpc.DialContext, hubExecer.Exec, conf, clusterSsher, cm)
+		clusterSsher.AgentPinger = hub
+		markStepsComplete(upgradestatus.VALIDATE_START_CLUSTER)
 		go hub.Start()
 	})
 
@@ -2,7 +2,9 @@ package integrations_test
 
 import (
 	"errors"
-	"time"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 
 	agentServices "github.com/greenplum-db/gpupgrade/agent/services"
 	"github.com/greenplum-db/gpupgrade/hub/cluster_ssher"
@@ -50,9 +52,10 @@ var _ = Describe("upgrade share oids", func() {
 		Expect(err).ToNot(HaveOccurred())
 
 		conf := &hubServices.HubConfig{
-			CliToHubPort:   port,
-			HubToAgentPort: agentPort,
-			StateDir:       testStateDir,
+			CliToHubPort:      port,
+			HubToAgentPort:    agentPort,
+			StateDir:          testStateDir,
+			ServerCredentials: hubCredentials(),
 		}
 
 		outChan = make(chan []byte, 10)
@@ -66,10 +69,12 @@ var _ = Describe("upgrade share oids", func() {
 		cm = testutils.NewMockChecklistManager()
 		clusterSsher := cluster_ssher.NewClusterSsher(
 			cm,
-			hubServices.NewPingerManager(conf.StateDir, 500*time.Millisecond),
-			hubExecer.Exec,
+			nil,
+			cluster_ssher.NewSSHBackend(hubExecer.Exec, cluster_ssher.SSHConfig{}),
 		)
 		hub = hubServices.NewHub(testutils.InitClusterPairFromDB(), grpc.DialContext, hubExecer.Exec, conf, clusterSsher, cm)
+		clusterSsher.AgentPinger = hub
+		markMasterUpgradeComplete()
 		go hub.Start()
 	})
 
@@ -83,23 +88,28 @@ var _ = Describe("upgrade share oids", func() {
 
 	It("updates status PENDING to RUNNING then to COMPLETE if successful", func() {
 
+		oidFile := filepath.Join(testStateDir, "pg_upgrade", "pg_upgrade_dump_1_oids.sql")
+		Expect(ioutil.WriteFile(oidFile, []byte("oids"), 0600)).To(Succeed())
+		defer os.Remove(oidFile)
+
 		Expect(cm.IsPending(upgradestatus.SHARE_OIDS)).To(BeTrue())
 
 		upgradeShareOidsSession := runCommand("upgrade", "share-oids")
 		Eventually(upgradeShareOidsSession).Should(Exit(0))
 
-		Expect(hubExecer.Calls()[0]).To(ContainSubstring("rsync"))
-		Expect(cm.IsComplete(upgradestatus.SHARE_OIDS)).To(BeTrue())
+		Eventually(func() bool { return cm.IsComplete(upgradestatus.SHARE_OIDS) }).Should(BeTrue())
+		Expect(hubExecer.Calls()).ToNot(ContainElement(ContainSubstring("rsync")))
 
 	})
 
 	It("updates status to FAILED if it fails to run", func() {
 
 		Expect(cm.IsPending(upgradestatus.SHARE_OIDS)).To(BeTrue())
-		errChan <- errors.New("fake test error, share oid failed to send files")
+		errChan <- errors.New("exit status 1") // pgrep finds no pg_upgrade
 
+		// pg_upgrade left no OID files to share.
 		upgradeShareOidsSession := runCommand("upgrade", "share-oids")
 		Eventually(upgradeShareOidsSession).Should(Exit(0))
-		Expect(cm.IsFailed(upgradestatus.SHARE_OIDS)).To(BeTrue())
+		Eventually(func() bool { return cm.IsFailed(upgradestatus.SHARE_OIDS) }).Should(BeTrue())
 	})
 })
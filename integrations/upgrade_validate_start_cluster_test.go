@@ -1,12 +1,11 @@
 package integrations_test
 
 import (
-	"time"
-
 	"github.com/greenplum-db/gp-common-go-libs/testhelper"
 	"github.com/greenplum-db/gpupgrade/hub/cluster_ssher"
 	"github.com/greenplum-db/gpupgrade/hub/services"
 	"github.com/greenplum-db/gpupgrade/hub/upgradestatus"
+	pb "github.com/greenplum-db/gpupgrade/idl"
 	"github.com/greenplum-db/gpupgrade/testutils"
 
 	. "github.com/onsi/ginkgo"
@@ -25,6 +24,7 @@ var _ = Describe("upgrade validate-start-cluster", func() {
 		clusterPair   *services.ClusterPair
 		testExecutor  *testhelper.TestExecutor
 		cm            *testutils.MockChecklistManager
+		mockAgent     *testutils.MockAgentServer
 	)
 
 	BeforeEach(func() {
@@ -33,10 +33,18 @@ var _ = Describe("upgrade validate-start-cluster", func() {
 		port, err = testutils.GetOpenPort()
 		Expect(err).ToNot(HaveOccurred())
 
+		// The agent reports that the primaries have been converted.
+		var agentPort int
+		mockAgent, agentPort = testutils.NewMockAgentServer()
+		mockAgent.StatusConversionResponse = &pb.CheckConversionStatusReply{
+			Statuses: []*pb.SegmentConversionStatus{{Role: pb.SegmentRole_PRIMARY, Status: pb.StepStatus_COMPLETE}},
+		}
+
 		conf := &services.HubConfig{
-			CliToHubPort:   port,
-			HubToAgentPort: 6416,
-			StateDir:       testStateDir,
+			CliToHubPort:      port,
+			HubToAgentPort:    agentPort,
+			StateDir:          testStateDir,
+			ServerCredentials: hubCredentials(),
 		}
 		outChan = make(chan []byte, 2)
 		errChan = make(chan error, 2)
@@ -50,18 +58,21 @@ var _ = Describe("upgrade validate-start-cluster", func() {
 		cm = testutils.NewMockChecklistManager()
 		clusterSsher := cluster_ssher.NewClusterSsher(
 			cm,
-			services.NewPingerManager(conf.StateDir, 500*time.Millisecond),
-			commandExecer.Exec,
+			nil,
+			cluster_ssher.NewSSHBackend(commandExecer.Exec, cluster_ssher.SSHConfig{}),
 		)
 		clusterPair = testutils.InitClusterPairFromDB()
 		testExecutor = &testhelper.TestExecutor{}
 		clusterPair.NewCluster.Executor = testExecutor
 		hub = services.NewHub(clusterPair, grpc.DialContext, commandExecer.Exec, conf, clusterSsher, cm)
+		clusterSsher.AgentPinger = hub
+		markStepsComplete(upgradestatus.START_AGENTS)
 		go hub.Start()
 	})
 
 	AfterEach(func() {
 		hub.Stop()
+		mockAgent.Stop()
 		Expect(checkPortIsAvailable(port)).To(BeTrue())
 	})
 
@@ -31,19 +31,21 @@ var _ = Describe("version command", func() {
 		Expect(err).ToNot(HaveOccurred())
 
 		conf := &services.HubConfig{
-			CliToHubPort:   port,
-			HubToAgentPort: 6416,
-			StateDir:       testStateDir,
+			CliToHubPort:      port,
+			HubToAgentPort:    6416,
+			StateDir:          testStateDir,
+			ServerCredentials: hubCredentials(),
 		}
 		commandExecer = &testutils.FakeCommandExecer{}
 		commandExecer.SetOutput(&testutils.FakeCommand{})
 
 		clusterSsher := cluster_ssher.NewClusterSsher(
 			upgradestatus.NewChecklistManager(conf.StateDir),
-			services.NewPingerManager(conf.StateDir, 500*time.Millisecond),
-			commandExecer.Exec,
+			nil,
+			cluster_ssher.NewSSHBackend(commandExecer.Exec, cluster_ssher.SSHConfig{}),
 		)
 		hub = services.NewHub(testutils.InitClusterPairFromDB(), grpc.DialContext, commandExecer.Exec, conf, clusterSsher, cm)
+		clusterSsher.AgentPinger = hub
 		go hub.Start()
 	})
 
@@ -9,6 +9,7 @@ import (
 	idl "github.com/greenplum-db/gpupgrade/idl"
 	context "golang.org/x/net/context"
 	grpc "google.golang.org/grpc"
+	metadata "google.golang.org/grpc/metadata"
 	reflect "reflect"
 )
 
@@ -71,6 +72,24 @@ func (mr *MockCliToHubClientMockRecorder) StatusUpgrade(ctx, in interface{}, opt
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StatusUpgrade", reflect.TypeOf((*MockCliToHubClient)(nil).StatusUpgrade), varargs...)
 }
 
+// WatchUpgradeStatus mocks base method
+func (m *MockCliToHubClient) WatchUpgradeStatus(ctx context.Context, in *idl.WatchUpgradeStatusRequest, opts ...grpc.CallOption) (idl.CliToHub_WatchUpgradeStatusClient, error) {
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "WatchUpgradeStatus", varargs...)
+	ret0, _ := ret[0].(idl.CliToHub_WatchUpgradeStatusClient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WatchUpgradeStatus indicates an expected call of WatchUpgradeStatus
+func (mr *MockCliToHubClientMockRecorder) WatchUpgradeStatus(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WatchUpgradeStatus", reflect.TypeOf((*MockCliToHubClient)(nil).WatchUpgradeStatus), varargs...)
+}
+
 // StatusConversion mocks base method
 func (m *MockCliToHubClient) StatusConversion(ctx context.Context, in *idl.StatusConversionRequest, opts ...grpc.CallOption) (*idl.StatusConversionReply, error) {
 	varargs := []interface{}{ctx, in}
@@ -179,6 +198,24 @@ func (mr *MockCliToHubClientMockRecorder) CheckDiskSpace(ctx, in interface{}, op
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckDiskSpace", reflect.TypeOf((*MockCliToHubClient)(nil).CheckDiskSpace), varargs...)
 }
 
+// CheckAll mocks base method
+func (m *MockCliToHubClient) CheckAll(ctx context.Context, in *idl.CheckAllRequest, opts ...grpc.CallOption) (*idl.CheckAllReply, error) {
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "CheckAll", varargs...)
+	ret0, _ := ret[0].(*idl.CheckAllReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CheckAll indicates an expected call of CheckAll
+func (mr *MockCliToHubClientMockRecorder) CheckAll(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckAll", reflect.TypeOf((*MockCliToHubClient)(nil).CheckAll), varargs...)
+}
+
 // PrepareInitCluster mocks base method
 func (m *MockCliToHubClient) PrepareInitCluster(ctx context.Context, in *idl.PrepareInitClusterRequest, opts ...grpc.CallOption) (*idl.PrepareInitClusterReply, error) {
 	varargs := []interface{}{ctx, in}
@@ -323,6 +360,368 @@ func (mr *MockCliToHubClientMockRecorder) UpgradeReconfigurePorts(ctx, in interf
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpgradeReconfigurePorts", reflect.TypeOf((*MockCliToHubClient)(nil).UpgradeReconfigurePorts), varargs...)
 }
 
+// UpgradeRun mocks base method
+func (m *MockCliToHubClient) UpgradeRun(ctx context.Context, in *idl.UpgradeRunRequest, opts ...grpc.CallOption) (*idl.UpgradeRunReply, error) {
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "UpgradeRun", varargs...)
+	ret0, _ := ret[0].(*idl.UpgradeRunReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpgradeRun indicates an expected call of UpgradeRun
+func (mr *MockCliToHubClientMockRecorder) UpgradeRun(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpgradeRun", reflect.TypeOf((*MockCliToHubClient)(nil).UpgradeRun), varargs...)
+}
+
+// Revert mocks base method
+func (m *MockCliToHubClient) Revert(ctx context.Context, in *idl.RevertRequest, opts ...grpc.CallOption) (*idl.RevertReply, error) {
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Revert", varargs...)
+	ret0, _ := ret[0].(*idl.RevertReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Revert indicates an expected call of Revert
+func (mr *MockCliToHubClientMockRecorder) Revert(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revert", reflect.TypeOf((*MockCliToHubClient)(nil).Revert), varargs...)
+}
+
+// PrepareStopAgents mocks base method
+func (m *MockCliToHubClient) PrepareStopAgents(ctx context.Context, in *idl.PrepareStopAgentsRequest, opts ...grpc.CallOption) (*idl.PrepareStopAgentsReply, error) {
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "PrepareStopAgents", varargs...)
+	ret0, _ := ret[0].(*idl.PrepareStopAgentsReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PrepareStopAgents indicates an expected call of PrepareStopAgents
+func (mr *MockCliToHubClientMockRecorder) PrepareStopAgents(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PrepareStopAgents", reflect.TypeOf((*MockCliToHubClient)(nil).PrepareStopAgents), varargs...)
+}
+
+// Shutdown mocks base method
+func (m *MockCliToHubClient) Shutdown(ctx context.Context, in *idl.ShutdownRequest, opts ...grpc.CallOption) (*idl.ShutdownReply, error) {
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Shutdown", varargs...)
+	ret0, _ := ret[0].(*idl.ShutdownReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Shutdown indicates an expected call of Shutdown
+func (mr *MockCliToHubClientMockRecorder) Shutdown(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Shutdown", reflect.TypeOf((*MockCliToHubClient)(nil).Shutdown), varargs...)
+}
+
+// CheckAgents mocks base method
+func (m *MockCliToHubClient) CheckAgents(ctx context.Context, in *idl.CheckAgentsRequest, opts ...grpc.CallOption) (*idl.CheckAgentsReply, error) {
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "CheckAgents", varargs...)
+	ret0, _ := ret[0].(*idl.CheckAgentsReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CheckAgents indicates an expected call of CheckAgents
+func (mr *MockCliToHubClientMockRecorder) CheckAgents(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckAgents", reflect.TypeOf((*MockCliToHubClient)(nil).CheckAgents), varargs...)
+}
+
+// StatusAgents mocks base method
+func (m *MockCliToHubClient) StatusAgents(ctx context.Context, in *idl.StatusAgentsRequest, opts ...grpc.CallOption) (*idl.StatusAgentsReply, error) {
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "StatusAgents", varargs...)
+	ret0, _ := ret[0].(*idl.StatusAgentsReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StatusAgents indicates an expected call of StatusAgents
+func (mr *MockCliToHubClientMockRecorder) StatusAgents(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StatusAgents", reflect.TypeOf((*MockCliToHubClient)(nil).StatusAgents), varargs...)
+}
+
+// Logs mocks base method
+func (m *MockCliToHubClient) Logs(ctx context.Context, in *idl.LogsRequest, opts ...grpc.CallOption) (idl.CliToHub_LogsClient, error) {
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Logs", varargs...)
+	ret0, _ := ret[0].(idl.CliToHub_LogsClient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Logs indicates an expected call of Logs
+func (mr *MockCliToHubClientMockRecorder) Logs(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Logs", reflect.TypeOf((*MockCliToHubClient)(nil).Logs), varargs...)
+}
+
+// Cancel mocks base method
+func (m *MockCliToHubClient) Cancel(ctx context.Context, in *idl.CancelRequest, opts ...grpc.CallOption) (*idl.CancelReply, error) {
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Cancel", varargs...)
+	ret0, _ := ret[0].(*idl.CancelReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Cancel indicates an expected call of Cancel
+func (mr *MockCliToHubClientMockRecorder) Cancel(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Cancel", reflect.TypeOf((*MockCliToHubClient)(nil).Cancel), varargs...)
+}
+
+// MockCliToHub_WatchUpgradeStatusClient is a mock of CliToHub_WatchUpgradeStatusClient interface
+type MockCliToHub_WatchUpgradeStatusClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockCliToHub_WatchUpgradeStatusClientMockRecorder
+}
+
+// MockCliToHub_WatchUpgradeStatusClientMockRecorder is the mock recorder for MockCliToHub_WatchUpgradeStatusClient
+type MockCliToHub_WatchUpgradeStatusClientMockRecorder struct {
+	mock *MockCliToHub_WatchUpgradeStatusClient
+}
+
+// NewMockCliToHub_WatchUpgradeStatusClient creates a new mock instance
+func NewMockCliToHub_WatchUpgradeStatusClient(ctrl *gomock.Controller) *MockCliToHub_WatchUpgradeStatusClient {
+	mock := &MockCliToHub_WatchUpgradeStatusClient{ctrl: ctrl}
+	mock.recorder = &MockCliToHub_WatchUpgradeStatusClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockCliToHub_WatchUpgradeStatusClient) EXPECT() *MockCliToHub_WatchUpgradeStatusClientMockRecorder {
+	return m.recorder
+}
+
+// Recv mocks base method
+func (m *MockCliToHub_WatchUpgradeStatusClient) Recv() (*idl.UpgradeStepStatus, error) {
+	ret := m.ctrl.Call(m, "Recv")
+	ret0, _ := ret[0].(*idl.UpgradeStepStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Recv indicates an expected call of Recv
+func (mr *MockCliToHub_WatchUpgradeStatusClientMockRecorder) Recv() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Recv", reflect.TypeOf((*MockCliToHub_WatchUpgradeStatusClient)(nil).Recv))
+}
+
+// Header mocks base method
+func (m *MockCliToHub_WatchUpgradeStatusClient) Header() (metadata.MD, error) {
+	ret := m.ctrl.Call(m, "Header")
+	ret0, _ := ret[0].(metadata.MD)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Header indicates an expected call of Header
+func (mr *MockCliToHub_WatchUpgradeStatusClientMockRecorder) Header() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Header", reflect.TypeOf((*MockCliToHub_WatchUpgradeStatusClient)(nil).Header))
+}
+
+// Trailer mocks base method
+func (m *MockCliToHub_WatchUpgradeStatusClient) Trailer() metadata.MD {
+	ret := m.ctrl.Call(m, "Trailer")
+	ret0, _ := ret[0].(metadata.MD)
+	return ret0
+}
+
+// Trailer indicates an expected call of Trailer
+func (mr *MockCliToHub_WatchUpgradeStatusClientMockRecorder) Trailer() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Trailer", reflect.TypeOf((*MockCliToHub_WatchUpgradeStatusClient)(nil).Trailer))
+}
+
+// CloseSend mocks base method
+func (m *MockCliToHub_WatchUpgradeStatusClient) CloseSend() error {
+	ret := m.ctrl.Call(m, "CloseSend")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CloseSend indicates an expected call of CloseSend
+func (mr *MockCliToHub_WatchUpgradeStatusClientMockRecorder) CloseSend() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseSend", reflect.TypeOf((*MockCliToHub_WatchUpgradeStatusClient)(nil).CloseSend))
+}
+
+// Context mocks base method
+func (m *MockCliToHub_WatchUpgradeStatusClient) Context() context.Context {
+	ret := m.ctrl.Call(m, "Context")
+	ret0, _ := ret[0].(context.Context)
+	return ret0
+}
+
+// Context indicates an expected call of Context
+func (mr *MockCliToHub_WatchUpgradeStatusClientMockRecorder) Context() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Context", reflect.TypeOf((*MockCliToHub_WatchUpgradeStatusClient)(nil).Context))
+}
+
+// SendMsg mocks base method
+func (m_2 *MockCliToHub_WatchUpgradeStatusClient) SendMsg(m interface{}) error {
+	ret := m_2.ctrl.Call(m_2, "SendMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendMsg indicates an expected call of SendMsg
+func (mr *MockCliToHub_WatchUpgradeStatusClientMockRecorder) SendMsg(m interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMsg", reflect.TypeOf((*MockCliToHub_WatchUpgradeStatusClient)(nil).SendMsg), m)
+}
+
+// RecvMsg mocks base method
+func (m_2 *MockCliToHub_WatchUpgradeStatusClient) RecvMsg(m interface{}) error {
+	ret := m_2.ctrl.Call(m_2, "RecvMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecvMsg indicates an expected call of RecvMsg
+func (mr *MockCliToHub_WatchUpgradeStatusClientMockRecorder) RecvMsg(m interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecvMsg", reflect.TypeOf((*MockCliToHub_WatchUpgradeStatusClient)(nil).RecvMsg), m)
+}
+
+// MockCliToHub_LogsClient is a mock of CliToHub_LogsClient interface
+type MockCliToHub_LogsClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockCliToHub_LogsClientMockRecorder
+}
+
+// MockCliToHub_LogsClientMockRecorder is the mock recorder for MockCliToHub_LogsClient
+type MockCliToHub_LogsClientMockRecorder struct {
+	mock *MockCliToHub_LogsClient
+}
+
+// NewMockCliToHub_LogsClient creates a new mock instance
+func NewMockCliToHub_LogsClient(ctrl *gomock.Controller) *MockCliToHub_LogsClient {
+	mock := &MockCliToHub_LogsClient{ctrl: ctrl}
+	mock.recorder = &MockCliToHub_LogsClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockCliToHub_LogsClient) EXPECT() *MockCliToHub_LogsClientMockRecorder {
+	return m.recorder
+}
+
+// Recv mocks base method
+func (m *MockCliToHub_LogsClient) Recv() (*idl.LogChunk, error) {
+	ret := m.ctrl.Call(m, "Recv")
+	ret0, _ := ret[0].(*idl.LogChunk)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Recv indicates an expected call of Recv
+func (mr *MockCliToHub_LogsClientMockRecorder) Recv() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Recv", reflect.TypeOf((*MockCliToHub_LogsClient)(nil).Recv))
+}
+
+// Header mocks base method
+func (m *MockCliToHub_LogsClient) Header() (metadata.MD, error) {
+	ret := m.ctrl.Call(m, "Header")
+	ret0, _ := ret[0].(metadata.MD)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Header indicates an expected call of Header
+func (mr *MockCliToHub_LogsClientMockRecorder) Header() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Header", reflect.TypeOf((*MockCliToHub_LogsClient)(nil).Header))
+}
+
+// Trailer mocks base method
+func (m *MockCliToHub_LogsClient) Trailer() metadata.MD {
+	ret := m.ctrl.Call(m, "Trailer")
+	ret0, _ := ret[0].(metadata.MD)
+	return ret0
+}
+
+// Trailer indicates an expected call of Trailer
+func (mr *MockCliToHub_LogsClientMockRecorder) Trailer() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Trailer", reflect.TypeOf((*MockCliToHub_LogsClient)(nil).Trailer))
+}
+
+// CloseSend mocks base method
+func (m *MockCliToHub_LogsClient) CloseSend() error {
+	ret := m.ctrl.Call(m, "CloseSend")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CloseSend indicates an expected call of CloseSend
+func (mr *MockCliToHub_LogsClientMockRecorder) CloseSend() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseSend", reflect.TypeOf((*MockCliToHub_LogsClient)(nil).CloseSend))
+}
+
+// Context mocks base method
+func (m *MockCliToHub_LogsClient) Context() context.Context {
+	ret := m.ctrl.Call(m, "Context")
+	ret0, _ := ret[0].(context.Context)
+	return ret0
+}
+
+// Context indicates an expected call of Context
+func (mr *MockCliToHub_LogsClientMockRecorder) Context() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Context", reflect.TypeOf((*MockCliToHub_LogsClient)(nil).Context))
+}
+
+// SendMsg mocks base method
+func (m_2 *MockCliToHub_LogsClient) SendMsg(m interface{}) error {
+	ret := m_2.ctrl.Call(m_2, "SendMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendMsg indicates an expected call of SendMsg
+func (mr *MockCliToHub_LogsClientMockRecorder) SendMsg(m interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMsg", reflect.TypeOf((*MockCliToHub_LogsClient)(nil).SendMsg), m)
+}
+
+// RecvMsg mocks base method
+func (m_2 *MockCliToHub_LogsClient) RecvMsg(m interface{}) error {
+	ret := m_2.ctrl.Call(m_2, "RecvMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecvMsg indicates an expected call of RecvMsg
+func (mr *MockCliToHub_LogsClientMockRecorder) RecvMsg(m interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecvMsg", reflect.TypeOf((*MockCliToHub_LogsClient)(nil).RecvMsg), m)
+}
+
 // MockCliToHubServer is a mock of CliToHubServer interface
 type MockCliToHubServer struct {
 	ctrl     *gomock.Controller
@@ -372,6 +771,18 @@ func (mr *MockCliToHubServerMockRecorder) StatusUpgrade(arg0, arg1 interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StatusUpgrade", reflect.TypeOf((*MockCliToHubServer)(nil).StatusUpgrade), arg0, arg1)
 }
 
+// WatchUpgradeStatus mocks base method
+func (m *MockCliToHubServer) WatchUpgradeStatus(arg0 *idl.WatchUpgradeStatusRequest, arg1 idl.CliToHub_WatchUpgradeStatusServer) error {
+	ret := m.ctrl.Call(m, "WatchUpgradeStatus", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WatchUpgradeStatus indicates an expected call of WatchUpgradeStatus
+func (mr *MockCliToHubServerMockRecorder) WatchUpgradeStatus(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WatchUpgradeStatus", reflect.TypeOf((*MockCliToHubServer)(nil).WatchUpgradeStatus), arg0, arg1)
+}
+
 // StatusConversion mocks base method
 func (m *MockCliToHubServer) StatusConversion(arg0 context.Context, arg1 *idl.StatusConversionRequest) (*idl.StatusConversionReply, error) {
 	ret := m.ctrl.Call(m, "StatusConversion", arg0, arg1)
@@ -450,6 +861,19 @@ func (mr *MockCliToHubServerMockRecorder) CheckDiskSpace(arg0, arg1 interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckDiskSpace", reflect.TypeOf((*MockCliToHubServer)(nil).CheckDiskSpace), arg0, arg1)
 }
 
+// CheckAll mocks base method
+func (m *MockCliToHubServer) CheckAll(arg0 context.Context, arg1 *idl.CheckAllRequest) (*idl.CheckAllReply, error) {
+	ret := m.ctrl.Call(m, "CheckAll", arg0, arg1)
+	ret0, _ := ret[0].(*idl.CheckAllReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CheckAll indicates an expected call of CheckAll
+func (mr *MockCliToHubServerMockRecorder) CheckAll(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckAll", reflect.TypeOf((*MockCliToHubServer)(nil).CheckAll), arg0, arg1)
+}
+
 // PrepareInitCluster mocks base method
 func (m *MockCliToHubServer) PrepareInitCluster(arg0 context.Context, arg1 *idl.PrepareInitClusterRequest) (*idl.PrepareInitClusterReply, error) {
 	ret := m.ctrl.Call(m, "PrepareInitCluster", arg0, arg1)
@@ -553,3 +977,316 @@ func (m *MockCliToHubServer) UpgradeReconfigurePorts(arg0 context.Context, arg1
 func (mr *MockCliToHubServerMockRecorder) UpgradeReconfigurePorts(arg0, arg1 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpgradeReconfigurePorts", reflect.TypeOf((*MockCliToHubServer)(nil).UpgradeReconfigurePorts), arg0, arg1)
 }
+
+// UpgradeRun mocks base method
+func (m *MockCliToHubServer) UpgradeRun(arg0 context.Context, arg1 *idl.UpgradeRunRequest) (*idl.UpgradeRunReply, error) {
+	ret := m.ctrl.Call(m, "UpgradeRun", arg0, arg1)
+	ret0, _ := ret[0].(*idl.UpgradeRunReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpgradeRun indicates an expected call of UpgradeRun
+func (mr *MockCliToHubServerMockRecorder) UpgradeRun(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpgradeRun", reflect.TypeOf((*MockCliToHubServer)(nil).UpgradeRun), arg0, arg1)
+}
+
+// Revert mocks base method
+func (m *MockCliToHubServer) Revert(arg0 context.Context, arg1 *idl.RevertRequest) (*idl.RevertReply, error) {
+	ret := m.ctrl.Call(m, "Revert", arg0, arg1)
+	ret0, _ := ret[0].(*idl.RevertReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Revert indicates an expected call of Revert
+func (mr *MockCliToHubServerMockRecorder) Revert(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revert", reflect.TypeOf((*MockCliToHubServer)(nil).Revert), arg0, arg1)
+}
+
+// PrepareStopAgents mocks base method
+func (m *MockCliToHubServer) PrepareStopAgents(arg0 context.Context, arg1 *idl.PrepareStopAgentsRequest) (*idl.PrepareStopAgentsReply, error) {
+	ret := m.ctrl.Call(m, "PrepareStopAgents", arg0, arg1)
+	ret0, _ := ret[0].(*idl.PrepareStopAgentsReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PrepareStopAgents indicates an expected call of PrepareStopAgents
+func (mr *MockCliToHubServerMockRecorder) PrepareStopAgents(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PrepareStopAgents", reflect.TypeOf((*MockCliToHubServer)(nil).PrepareStopAgents), arg0, arg1)
+}
+
+// Shutdown mocks base method
+func (m *MockCliToHubServer) Shutdown(arg0 context.Context, arg1 *idl.ShutdownRequest) (*idl.ShutdownReply, error) {
+	ret := m.ctrl.Call(m, "Shutdown", arg0, arg1)
+	ret0, _ := ret[0].(*idl.ShutdownReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Shutdown indicates an expected call of Shutdown
+func (mr *MockCliToHubServerMockRecorder) Shutdown(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Shutdown", reflect.TypeOf((*MockCliToHubServer)(nil).Shutdown), arg0, arg1)
+}
+
+// CheckAgents mocks base method
+func (m *MockCliToHubServer) CheckAgents(arg0 context.Context, arg1 *idl.CheckAgentsRequest) (*idl.CheckAgentsReply, error) {
+	ret := m.ctrl.Call(m, "CheckAgents", arg0, arg1)
+	ret0, _ := ret[0].(*idl.CheckAgentsReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CheckAgents indicates an expected call of CheckAgents
+func (mr *MockCliToHubServerMockRecorder) CheckAgents(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckAgents", reflect.TypeOf((*MockCliToHubServer)(nil).CheckAgents), arg0, arg1)
+}
+
+// StatusAgents mocks base method
+func (m *MockCliToHubServer) StatusAgents(arg0 context.Context, arg1 *idl.StatusAgentsRequest) (*idl.StatusAgentsReply, error) {
+	ret := m.ctrl.Call(m, "StatusAgents", arg0, arg1)
+	ret0, _ := ret[0].(*idl.StatusAgentsReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StatusAgents indicates an expected call of StatusAgents
+func (mr *MockCliToHubServerMockRecorder) StatusAgents(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StatusAgents", reflect.TypeOf((*MockCliToHubServer)(nil).StatusAgents), arg0, arg1)
+}
+
+// Logs mocks base method
+func (m *MockCliToHubServer) Logs(arg0 *idl.LogsRequest, arg1 idl.CliToHub_LogsServer) error {
+	ret := m.ctrl.Call(m, "Logs", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Logs indicates an expected call of Logs
+func (mr *MockCliToHubServerMockRecorder) Logs(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Logs", reflect.TypeOf((*MockCliToHubServer)(nil).Logs), arg0, arg1)
+}
+
+// Cancel mocks base method
+func (m *MockCliToHubServer) Cancel(arg0 context.Context, arg1 *idl.CancelRequest) (*idl.CancelReply, error) {
+	ret := m.ctrl.Call(m, "Cancel", arg0, arg1)
+	ret0, _ := ret[0].(*idl.CancelReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Cancel indicates an expected call of Cancel
+func (mr *MockCliToHubServerMockRecorder) Cancel(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Cancel", reflect.TypeOf((*MockCliToHubServer)(nil).Cancel), arg0, arg1)
+}
+
+// MockCliToHub_WatchUpgradeStatusServer is a mock of CliToHub_WatchUpgradeStatusServer interface
+type MockCliToHub_WatchUpgradeStatusServer struct {
+	ctrl     *gomock.Controller
+	recorder *MockCliToHub_WatchUpgradeStatusServerMockRecorder
+}
+
+// MockCliToHub_WatchUpgradeStatusServerMockRecorder is the mock recorder for MockCliToHub_WatchUpgradeStatusServer
+type MockCliToHub_WatchUpgradeStatusServerMockRecorder struct {
+	mock *MockCliToHub_WatchUpgradeStatusServer
+}
+
+// NewMockCliToHub_WatchUpgradeStatusServer creates a new mock instance
+func NewMockCliToHub_WatchUpgradeStatusServer(ctrl *gomock.Controller) *MockCliToHub_WatchUpgradeStatusServer {
+	mock := &MockCliToHub_WatchUpgradeStatusServer{ctrl: ctrl}
+	mock.recorder = &MockCliToHub_WatchUpgradeStatusServerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockCliToHub_WatchUpgradeStatusServer) EXPECT() *MockCliToHub_WatchUpgradeStatusServerMockRecorder {
+	return m.recorder
+}
+
+// Send mocks base method
+func (m *MockCliToHub_WatchUpgradeStatusServer) Send(arg0 *idl.UpgradeStepStatus) error {
+	ret := m.ctrl.Call(m, "Send", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Send indicates an expected call of Send
+func (mr *MockCliToHub_WatchUpgradeStatusServerMockRecorder) Send(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Send", reflect.TypeOf((*MockCliToHub_WatchUpgradeStatusServer)(nil).Send), arg0)
+}
+
+// SetHeader mocks base method
+func (m *MockCliToHub_WatchUpgradeStatusServer) SetHeader(arg0 metadata.MD) error {
+	ret := m.ctrl.Call(m, "SetHeader", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetHeader indicates an expected call of SetHeader
+func (mr *MockCliToHub_WatchUpgradeStatusServerMockRecorder) SetHeader(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetHeader", reflect.TypeOf((*MockCliToHub_WatchUpgradeStatusServer)(nil).SetHeader), arg0)
+}
+
+// SendHeader mocks base method
+func (m *MockCliToHub_WatchUpgradeStatusServer) SendHeader(arg0 metadata.MD) error {
+	ret := m.ctrl.Call(m, "SendHeader", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendHeader indicates an expected call of SendHeader
+func (mr *MockCliToHub_WatchUpgradeStatusServerMockRecorder) SendHeader(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendHeader", reflect.TypeOf((*MockCliToHub_WatchUpgradeStatusServer)(nil).SendHeader), arg0)
+}
+
+// SetTrailer mocks base method
+func (m *MockCliToHub_WatchUpgradeStatusServer) SetTrailer(arg0 metadata.MD) {
+	m.ctrl.Call(m, "SetTrailer", arg0)
+}
+
+// SetTrailer indicates an expected call of SetTrailer
+func (mr *MockCliToHub_WatchUpgradeStatusServerMockRecorder) SetTrailer(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTrailer", reflect.TypeOf((*MockCliToHub_WatchUpgradeStatusServer)(nil).SetTrailer), arg0)
+}
+
+// Context mocks base method
+func (m *MockCliToHub_WatchUpgradeStatusServer) Context() context.Context {
+	ret := m.ctrl.Call(m, "Context")
+	ret0, _ := ret[0].(context.Context)
+	return ret0
+}
+
+// Context indicates an expected call of Context
+func (mr *MockCliToHub_WatchUpgradeStatusServerMockRecorder) Context() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Context", reflect.TypeOf((*MockCliToHub_WatchUpgradeStatusServer)(nil).Context))
+}
+
+// SendMsg mocks base method
+func (m_2 *MockCliToHub_WatchUpgradeStatusServer) SendMsg(m interface{}) error {
+	ret := m_2.ctrl.Call(m_2, "SendMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendMsg indicates an expected call of SendMsg
+func (mr *MockCliToHub_WatchUpgradeStatusServerMockRecorder) SendMsg(m interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMsg", reflect.TypeOf((*MockCliToHub_WatchUpgradeStatusServer)(nil).SendMsg), m)
+}
+
+// RecvMsg mocks base method
+func (m_2 *MockCliToHub_WatchUpgradeStatusServer) RecvMsg(m interface{}) error {
+	ret := m_2.ctrl.Call(m_2, "RecvMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecvMsg indicates an expected call of RecvMsg
+func (mr *MockCliToHub_WatchUpgradeStatusServerMockRecorder) RecvMsg(m interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecvMsg", reflect.TypeOf((*MockCliToHub_WatchUpgradeStatusServer)(nil).RecvMsg), m)
+}
+
+// MockCliToHub_LogsServer is a mock of CliToHub_LogsServer interface
+type MockCliToHub_LogsServer struct {
+	ctrl     *gomock.Controller
+	recorder *MockCliToHub_LogsServerMockRecorder
+}
+
+// MockCliToHub_LogsServerMockRecorder is the mock recorder for MockCliToHub_LogsServer
+type MockCliToHub_LogsServerMockRecorder struct {
+	mock *MockCliToHub_LogsServer
+}
+
+// NewMockCliToHub_LogsServer creates a new mock instance
+func NewMockCliToHub_LogsServer(ctrl *gomock.Controller) *MockCliToHub_LogsServer {
+	mock := &MockCliToHub_LogsServer{ctrl: ctrl}
+	mock.recorder = &MockCliToHub_LogsServerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockCliToHub_LogsServer) EXPECT() *MockCliToHub_LogsServerMockRecorder {
+	return m.recorder
+}
+
+// Send mocks base method
+func (m *MockCliToHub_LogsServer) Send(arg0 *idl.LogChunk) error {
+	ret := m.ctrl.Call(m, "Send", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Send indicates an expected call of Send
+func (mr *MockCliToHub_LogsServerMockRecorder) Send(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Send", reflect.TypeOf((*MockCliToHub_LogsServer)(nil).Send), arg0)
+}
+
+// SetHeader mocks base method
+func (m *MockCliToHub_LogsServer) SetHeader(arg0 metadata.MD) error {
+	ret := m.ctrl.Call(m, "SetHeader", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetHeader indicates an expected call of SetHeader
+func (mr *MockCliToHub_LogsServerMockRecorder) SetHeader(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetHeader", reflect.TypeOf((*MockCliToHub_LogsServer)(nil).SetHeader), arg0)
+}
+
+// SendHeader mocks base method
+func (m *MockCliToHub_LogsServer) SendHeader(arg0 metadata.MD) error {
+	ret := m.ctrl.Call(m, "SendHeader", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendHeader indicates an expected call of SendHeader
+func (mr *MockCliToHub_LogsServerMockRecorder) SendHeader(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendHeader", reflect.TypeOf((*MockCliToHub_LogsServer)(nil).SendHeader), arg0)
+}
+
+// SetTrailer mocks base method
+func (m *MockCliToHub_LogsServer) SetTrailer(arg0 metadata.MD) {
+	m.ctrl.Call(m, "SetTrailer", arg0)
+}
+
+// SetTrailer indicates an expected call of SetTrailer
+func (mr *MockCliToHub_LogsServerMockRecorder) SetTrailer(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTrailer", reflect.TypeOf((*MockCliToHub_LogsServer)(nil).SetTrailer), arg0)
+}
+
+// Context mocks base method
+func (m *MockCliToHub_LogsServer) Context() context.Context {
+	ret := m.ctrl.Call(m, "Context")
+	ret0, _ := ret[0].(context.Context)
+	return ret0
+}
+
+// Context indicates an expected call of Context
+func (mr *MockCliToHub_LogsServerMockRecorder) Context() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Context", reflect.TypeOf((*MockCliToHub_LogsServer)(nil).Context))
+}
+
+// SendMsg mocks base method
+func (m_2 *MockCliToHub_LogsServer) SendMsg(m interface{}) error {
+	ret := m_2.ctrl.Call(m_2, "SendMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendMsg indicates an expected call of SendMsg
+func (mr *MockCliToHub_LogsServerMockRecorder) SendMsg(m interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMsg", reflect.TypeOf((*MockCliToHub_LogsServer)(nil).SendMsg), m)
+}
+
+// RecvMsg mocks base method
+func (m_2 *MockCliToHub_LogsServer) RecvMsg(m interface{}) error {
+	ret := m_2.ctrl.Call(m_2, "RecvMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecvMsg indicates an expected call of RecvMsg
+func (mr *MockCliToHub_LogsServerMockRecorder) RecvMsg(m interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecvMsg", reflect.TypeOf((*MockCliToHub_LogsServer)(nil).RecvMsg), m)
+}
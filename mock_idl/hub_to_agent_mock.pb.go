@@ -9,6 +9,7 @@ import (
 	idl "github.com/greenplum-db/gpupgrade/idl"
 	context "golang.org/x/net/context"
 	grpc "google.golang.org/grpc"
+	metadata "google.golang.org/grpc/metadata"
 	reflect "reflect"
 )
 
@@ -125,6 +126,326 @@ func (mr *MockAgentClientMockRecorder) UpgradeConvertPrimarySegments(ctx, in int
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpgradeConvertPrimarySegments", reflect.TypeOf((*MockAgentClient)(nil).UpgradeConvertPrimarySegments), varargs...)
 }
 
+// Shutdown mocks base method
+func (m *MockAgentClient) Shutdown(ctx context.Context, in *idl.ShutdownAgentRequest, opts ...grpc.CallOption) (*idl.ShutdownAgentReply, error) {
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Shutdown", varargs...)
+	ret0, _ := ret[0].(*idl.ShutdownAgentReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Shutdown indicates an expected call of Shutdown
+func (mr *MockAgentClientMockRecorder) Shutdown(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Shutdown", reflect.TypeOf((*MockAgentClient)(nil).Shutdown), varargs...)
+}
+
+// TailLogs mocks base method
+func (m *MockAgentClient) TailLogs(ctx context.Context, in *idl.TailLogsRequest, opts ...grpc.CallOption) (idl.Agent_TailLogsClient, error) {
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "TailLogs", varargs...)
+	ret0, _ := ret[0].(idl.Agent_TailLogsClient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TailLogs indicates an expected call of TailLogs
+func (mr *MockAgentClientMockRecorder) TailLogs(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TailLogs", reflect.TypeOf((*MockAgentClient)(nil).TailLogs), varargs...)
+}
+
+// PushFiles mocks base method
+func (m *MockAgentClient) PushFiles(ctx context.Context, opts ...grpc.CallOption) (idl.Agent_PushFilesClient, error) {
+	varargs := []interface{}{ctx}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "PushFiles", varargs...)
+	ret0, _ := ret[0].(idl.Agent_PushFilesClient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PushFiles indicates an expected call of PushFiles
+func (mr *MockAgentClientMockRecorder) PushFiles(ctx interface{}, opts ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{ctx}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PushFiles", reflect.TypeOf((*MockAgentClient)(nil).PushFiles), varargs...)
+}
+
+// Cancel mocks base method
+func (m *MockAgentClient) Cancel(ctx context.Context, in *idl.CancelAgentRequest, opts ...grpc.CallOption) (*idl.CancelAgentReply, error) {
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Cancel", varargs...)
+	ret0, _ := ret[0].(*idl.CancelAgentReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Cancel indicates an expected call of Cancel
+func (mr *MockAgentClientMockRecorder) Cancel(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Cancel", reflect.TypeOf((*MockAgentClient)(nil).Cancel), varargs...)
+}
+
+// Revert mocks base method
+func (m *MockAgentClient) Revert(ctx context.Context, in *idl.RevertAgentRequest, opts ...grpc.CallOption) (*idl.RevertAgentReply, error) {
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Revert", varargs...)
+	ret0, _ := ret[0].(*idl.RevertAgentReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Revert indicates an expected call of Revert
+func (mr *MockAgentClientMockRecorder) Revert(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revert", reflect.TypeOf((*MockAgentClient)(nil).Revert), varargs...)
+}
+
+// MockAgent_TailLogsClient is a mock of Agent_TailLogsClient interface
+type MockAgent_TailLogsClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockAgent_TailLogsClientMockRecorder
+}
+
+// MockAgent_TailLogsClientMockRecorder is the mock recorder for MockAgent_TailLogsClient
+type MockAgent_TailLogsClientMockRecorder struct {
+	mock *MockAgent_TailLogsClient
+}
+
+// NewMockAgent_TailLogsClient creates a new mock instance
+func NewMockAgent_TailLogsClient(ctrl *gomock.Controller) *MockAgent_TailLogsClient {
+	mock := &MockAgent_TailLogsClient{ctrl: ctrl}
+	mock.recorder = &MockAgent_TailLogsClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockAgent_TailLogsClient) EXPECT() *MockAgent_TailLogsClientMockRecorder {
+	return m.recorder
+}
+
+// Recv mocks base method
+func (m *MockAgent_TailLogsClient) Recv() (*idl.LogChunk, error) {
+	ret := m.ctrl.Call(m, "Recv")
+	ret0, _ := ret[0].(*idl.LogChunk)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Recv indicates an expected call of Recv
+func (mr *MockAgent_TailLogsClientMockRecorder) Recv() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Recv", reflect.TypeOf((*MockAgent_TailLogsClient)(nil).Recv))
+}
+
+// Header mocks base method
+func (m *MockAgent_TailLogsClient) Header() (metadata.MD, error) {
+	ret := m.ctrl.Call(m, "Header")
+	ret0, _ := ret[0].(metadata.MD)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Header indicates an expected call of Header
+func (mr *MockAgent_TailLogsClientMockRecorder) Header() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Header", reflect.TypeOf((*MockAgent_TailLogsClient)(nil).Header))
+}
+
+// Trailer mocks base method
+func (m *MockAgent_TailLogsClient) Trailer() metadata.MD {
+	ret := m.ctrl.Call(m, "Trailer")
+	ret0, _ := ret[0].(metadata.MD)
+	return ret0
+}
+
+// Trailer indicates an expected call of Trailer
+func (mr *MockAgent_TailLogsClientMockRecorder) Trailer() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Trailer", reflect.TypeOf((*MockAgent_TailLogsClient)(nil).Trailer))
+}
+
+// CloseSend mocks base method
+func (m *MockAgent_TailLogsClient) CloseSend() error {
+	ret := m.ctrl.Call(m, "CloseSend")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CloseSend indicates an expected call of CloseSend
+func (mr *MockAgent_TailLogsClientMockRecorder) CloseSend() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseSend", reflect.TypeOf((*MockAgent_TailLogsClient)(nil).CloseSend))
+}
+
+// Context mocks base method
+func (m *MockAgent_TailLogsClient) Context() context.Context {
+	ret := m.ctrl.Call(m, "Context")
+	ret0, _ := ret[0].(context.Context)
+	return ret0
+}
+
+// Context indicates an expected call of Context
+func (mr *MockAgent_TailLogsClientMockRecorder) Context() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Context", reflect.TypeOf((*MockAgent_TailLogsClient)(nil).Context))
+}
+
+// SendMsg mocks base method
+func (m_2 *MockAgent_TailLogsClient) SendMsg(m interface{}) error {
+	ret := m_2.ctrl.Call(m_2, "SendMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendMsg indicates an expected call of SendMsg
+func (mr *MockAgent_TailLogsClientMockRecorder) SendMsg(m interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMsg", reflect.TypeOf((*MockAgent_TailLogsClient)(nil).SendMsg), m)
+}
+
+// RecvMsg mocks base method
+func (m_2 *MockAgent_TailLogsClient) RecvMsg(m interface{}) error {
+	ret := m_2.ctrl.Call(m_2, "RecvMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecvMsg indicates an expected call of RecvMsg
+func (mr *MockAgent_TailLogsClientMockRecorder) RecvMsg(m interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecvMsg", reflect.TypeOf((*MockAgent_TailLogsClient)(nil).RecvMsg), m)
+}
+
+// MockAgent_PushFilesClient is a mock of Agent_PushFilesClient interface
+type MockAgent_PushFilesClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockAgent_PushFilesClientMockRecorder
+}
+
+// MockAgent_PushFilesClientMockRecorder is the mock recorder for MockAgent_PushFilesClient
+type MockAgent_PushFilesClientMockRecorder struct {
+	mock *MockAgent_PushFilesClient
+}
+
+// NewMockAgent_PushFilesClient creates a new mock instance
+func NewMockAgent_PushFilesClient(ctrl *gomock.Controller) *MockAgent_PushFilesClient {
+	mock := &MockAgent_PushFilesClient{ctrl: ctrl}
+	mock.recorder = &MockAgent_PushFilesClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockAgent_PushFilesClient) EXPECT() *MockAgent_PushFilesClientMockRecorder {
+	return m.recorder
+}
+
+// Send mocks base method
+func (m *MockAgent_PushFilesClient) Send(arg0 *idl.FileChunk) error {
+	ret := m.ctrl.Call(m, "Send", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Send indicates an expected call of Send
+func (mr *MockAgent_PushFilesClientMockRecorder) Send(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Send", reflect.TypeOf((*MockAgent_PushFilesClient)(nil).Send), arg0)
+}
+
+// CloseAndRecv mocks base method
+func (m *MockAgent_PushFilesClient) CloseAndRecv() (*idl.PushFilesReply, error) {
+	ret := m.ctrl.Call(m, "CloseAndRecv")
+	ret0, _ := ret[0].(*idl.PushFilesReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CloseAndRecv indicates an expected call of CloseAndRecv
+func (mr *MockAgent_PushFilesClientMockRecorder) CloseAndRecv() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseAndRecv", reflect.TypeOf((*MockAgent_PushFilesClient)(nil).CloseAndRecv))
+}
+
+// Header mocks base method
+func (m *MockAgent_PushFilesClient) Header() (metadata.MD, error) {
+	ret := m.ctrl.Call(m, "Header")
+	ret0, _ := ret[0].(metadata.MD)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Header indicates an expected call of Header
+func (mr *MockAgent_PushFilesClientMockRecorder) Header() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Header", reflect.TypeOf((*MockAgent_PushFilesClient)(nil).Header))
+}
+
+// Trailer mocks base method
+func (m *MockAgent_PushFilesClient) Trailer() metadata.MD {
+	ret := m.ctrl.Call(m, "Trailer")
+	ret0, _ := ret[0].(metadata.MD)
+	return ret0
+}
+
+// Trailer indicates an expected call of Trailer
+func (mr *MockAgent_PushFilesClientMockRecorder) Trailer() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Trailer", reflect.TypeOf((*MockAgent_PushFilesClient)(nil).Trailer))
+}
+
+// CloseSend mocks base method
+func (m *MockAgent_PushFilesClient) CloseSend() error {
+	ret := m.ctrl.Call(m, "CloseSend")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CloseSend indicates an expected call of CloseSend
+func (mr *MockAgent_PushFilesClientMockRecorder) CloseSend() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseSend", reflect.TypeOf((*MockAgent_PushFilesClient)(nil).CloseSend))
+}
+
+// Context mocks base method
+func (m *MockAgent_PushFilesClient) Context() context.Context {
+	ret := m.ctrl.Call(m, "Context")
+	ret0, _ := ret[0].(context.Context)
+	return ret0
+}
+
+// Context indicates an expected call of Context
+func (mr *MockAgent_PushFilesClientMockRecorder) Context() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Context", reflect.TypeOf((*MockAgent_PushFilesClient)(nil).Context))
+}
+
+// SendMsg mocks base method
+func (m_2 *MockAgent_PushFilesClient) SendMsg(m interface{}) error {
+	ret := m_2.ctrl.Call(m_2, "SendMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendMsg indicates an expected call of SendMsg
+func (mr *MockAgent_PushFilesClientMockRecorder) SendMsg(m interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMsg", reflect.TypeOf((*MockAgent_PushFilesClient)(nil).SendMsg), m)
+}
+
+// RecvMsg mocks base method
+func (m_2 *MockAgent_PushFilesClient) RecvMsg(m interface{}) error {
+	ret := m_2.ctrl.Call(m_2, "RecvMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecvMsg indicates an expected call of RecvMsg
+func (mr *MockAgent_PushFilesClientMockRecorder) RecvMsg(m interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecvMsg", reflect.TypeOf((*MockAgent_PushFilesClient)(nil).RecvMsg), m)
+}
+
 // MockAgentServer is a mock of AgentServer interface
 type MockAgentServer struct {
 	ctrl     *gomock.Controller
@@ -212,3 +533,289 @@ func (m *MockAgentServer) UpgradeConvertPrimarySegments(arg0 context.Context, ar
 func (mr *MockAgentServerMockRecorder) UpgradeConvertPrimarySegments(arg0, arg1 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpgradeConvertPrimarySegments", reflect.TypeOf((*MockAgentServer)(nil).UpgradeConvertPrimarySegments), arg0, arg1)
 }
+
+// Shutdown mocks base method
+func (m *MockAgentServer) Shutdown(arg0 context.Context, arg1 *idl.ShutdownAgentRequest) (*idl.ShutdownAgentReply, error) {
+	ret := m.ctrl.Call(m, "Shutdown", arg0, arg1)
+	ret0, _ := ret[0].(*idl.ShutdownAgentReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Shutdown indicates an expected call of Shutdown
+func (mr *MockAgentServerMockRecorder) Shutdown(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Shutdown", reflect.TypeOf((*MockAgentServer)(nil).Shutdown), arg0, arg1)
+}
+
+// TailLogs mocks base method
+func (m *MockAgentServer) TailLogs(arg0 *idl.TailLogsRequest, arg1 idl.Agent_TailLogsServer) error {
+	ret := m.ctrl.Call(m, "TailLogs", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// TailLogs indicates an expected call of TailLogs
+func (mr *MockAgentServerMockRecorder) TailLogs(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TailLogs", reflect.TypeOf((*MockAgentServer)(nil).TailLogs), arg0, arg1)
+}
+
+// PushFiles mocks base method
+func (m *MockAgentServer) PushFiles(arg0 idl.Agent_PushFilesServer) error {
+	ret := m.ctrl.Call(m, "PushFiles", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PushFiles indicates an expected call of PushFiles
+func (mr *MockAgentServerMockRecorder) PushFiles(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PushFiles", reflect.TypeOf((*MockAgentServer)(nil).PushFiles), arg0)
+}
+
+// Cancel mocks base method
+func (m *MockAgentServer) Cancel(arg0 context.Context, arg1 *idl.CancelAgentRequest) (*idl.CancelAgentReply, error) {
+	ret := m.ctrl.Call(m, "Cancel", arg0, arg1)
+	ret0, _ := ret[0].(*idl.CancelAgentReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Cancel indicates an expected call of Cancel
+func (mr *MockAgentServerMockRecorder) Cancel(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Cancel", reflect.TypeOf((*MockAgentServer)(nil).Cancel), arg0, arg1)
+}
+
+// Revert mocks base method
+func (m *MockAgentServer) Revert(arg0 context.Context, arg1 *idl.RevertAgentRequest) (*idl.RevertAgentReply, error) {
+	ret := m.ctrl.Call(m, "Revert", arg0, arg1)
+	ret0, _ := ret[0].(*idl.RevertAgentReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Revert indicates an expected call of Revert
+func (mr *MockAgentServerMockRecorder) Revert(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revert", reflect.TypeOf((*MockAgentServer)(nil).Revert), arg0, arg1)
+}
+
+// MockAgent_TailLogsServer is a mock of Agent_TailLogsServer interface
+type MockAgent_TailLogsServer struct {
+	ctrl     *gomock.Controller
+	recorder *MockAgent_TailLogsServerMockRecorder
+}
+
+// MockAgent_TailLogsServerMockRecorder is the mock recorder for MockAgent_TailLogsServer
+type MockAgent_TailLogsServerMockRecorder struct {
+	mock *MockAgent_TailLogsServer
+}
+
+// NewMockAgent_TailLogsServer creates a new mock instance
+func NewMockAgent_TailLogsServer(ctrl *gomock.Controller) *MockAgent_TailLogsServer {
+	mock := &MockAgent_TailLogsServer{ctrl: ctrl}
+	mock.recorder = &MockAgent_TailLogsServerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockAgent_TailLogsServer) EXPECT() *MockAgent_TailLogsServerMockRecorder {
+	return m.recorder
+}
+
+// Send mocks base method
+func (m *MockAgent_TailLogsServer) Send(arg0 *idl.LogChunk) error {
+	ret := m.ctrl.Call(m, "Send", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Send indicates an expected call of Send
+func (mr *MockAgent_TailLogsServerMockRecorder) Send(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Send", reflect.TypeOf((*MockAgent_TailLogsServer)(nil).Send), arg0)
+}
+
+// SetHeader mocks base method
+func (m *MockAgent_TailLogsServer) SetHeader(arg0 metadata.MD) error {
+	ret := m.ctrl.Call(m, "SetHeader", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetHeader indicates an expected call of SetHeader
+func (mr *MockAgent_TailLogsServerMockRecorder) SetHeader(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetHeader", reflect.TypeOf((*MockAgent_TailLogsServer)(nil).SetHeader), arg0)
+}
+
+// SendHeader mocks base method
+func (m *MockAgent_TailLogsServer) SendHeader(arg0 metadata.MD) error {
+	ret := m.ctrl.Call(m, "SendHeader", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendHeader indicates an expected call of SendHeader
+func (mr *MockAgent_TailLogsServerMockRecorder) SendHeader(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendHeader", reflect.TypeOf((*MockAgent_TailLogsServer)(nil).SendHeader), arg0)
+}
+
+// SetTrailer mocks base method
+func (m *MockAgent_TailLogsServer) SetTrailer(arg0 metadata.MD) {
+	m.ctrl.Call(m, "SetTrailer", arg0)
+}
+
+// SetTrailer indicates an expected call of SetTrailer
+func (mr *MockAgent_TailLogsServerMockRecorder) SetTrailer(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTrailer", reflect.TypeOf((*MockAgent_TailLogsServer)(nil).SetTrailer), arg0)
+}
+
+// Context mocks base method
+func (m *MockAgent_TailLogsServer) Context() context.Context {
+	ret := m.ctrl.Call(m, "Context")
+	ret0, _ := ret[0].(context.Context)
+	return ret0
+}
+
+// Context indicates an expected call of Context
+func (mr *MockAgent_TailLogsServerMockRecorder) Context() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Context", reflect.TypeOf((*MockAgent_TailLogsServer)(nil).Context))
+}
+
+// SendMsg mocks base method
+func (m_2 *MockAgent_TailLogsServer) SendMsg(m interface{}) error {
+	ret := m_2.ctrl.Call(m_2, "SendMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendMsg indicates an expected call of SendMsg
+func (mr *MockAgent_TailLogsServerMockRecorder) SendMsg(m interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMsg", reflect.TypeOf((*MockAgent_TailLogsServer)(nil).SendMsg), m)
+}
+
+// RecvMsg mocks base method
+func (m_2 *MockAgent_TailLogsServer) RecvMsg(m interface{}) error {
+	ret := m_2.ctrl.Call(m_2, "RecvMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecvMsg indicates an expected call of RecvMsg
+func (mr *MockAgent_TailLogsServerMockRecorder) RecvMsg(m interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecvMsg", reflect.TypeOf((*MockAgent_TailLogsServer)(nil).RecvMsg), m)
+}
+
+// MockAgent_PushFilesServer is a mock of Agent_PushFilesServer interface
+type MockAgent_PushFilesServer struct {
+	ctrl     *gomock.Controller
+	recorder *MockAgent_PushFilesServerMockRecorder
+}
+
+// MockAgent_PushFilesServerMockRecorder is the mock recorder for MockAgent_PushFilesServer
+type MockAgent_PushFilesServerMockRecorder struct {
+	mock *MockAgent_PushFilesServer
+}
+
+// NewMockAgent_PushFilesServer creates a new mock instance
+func NewMockAgent_PushFilesServer(ctrl *gomock.Controller) *MockAgent_PushFilesServer {
+	mock := &MockAgent_PushFilesServer{ctrl: ctrl}
+	mock.recorder = &MockAgent_PushFilesServerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockAgent_PushFilesServer) EXPECT() *MockAgent_PushFilesServerMockRecorder {
+	return m.recorder
+}
+
+// SendAndClose mocks base method
+func (m *MockAgent_PushFilesServer) SendAndClose(arg0 *idl.PushFilesReply) error {
+	ret := m.ctrl.Call(m, "SendAndClose", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendAndClose indicates an expected call of SendAndClose
+func (mr *MockAgent_PushFilesServerMockRecorder) SendAndClose(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendAndClose", reflect.TypeOf((*MockAgent_PushFilesServer)(nil).SendAndClose), arg0)
+}
+
+// Recv mocks base method
+func (m *MockAgent_PushFilesServer) Recv() (*idl.FileChunk, error) {
+	ret := m.ctrl.Call(m, "Recv")
+	ret0, _ := ret[0].(*idl.FileChunk)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Recv indicates an expected call of Recv
+func (mr *MockAgent_PushFilesServerMockRecorder) Recv() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Recv", reflect.TypeOf((*MockAgent_PushFilesServer)(nil).Recv))
+}
+
+// SetHeader mocks base method
+func (m *MockAgent_PushFilesServer) SetHeader(arg0 metadata.MD) error {
+	ret := m.ctrl.Call(m, "SetHeader", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetHeader indicates an expected call of SetHeader
+func (mr *MockAgent_PushFilesServerMockRecorder) SetHeader(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetHeader", reflect.TypeOf((*MockAgent_PushFilesServer)(nil).SetHeader), arg0)
+}
+
+// SendHeader mocks base method
+func (m *MockAgent_PushFilesServer) SendHeader(arg0 metadata.MD) error {
+	ret := m.ctrl.Call(m, "SendHeader", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendHeader indicates an expected call of SendHeader
+func (mr *MockAgent_PushFilesServerMockRecorder) SendHeader(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendHeader", reflect.TypeOf((*MockAgent_PushFilesServer)(nil).SendHeader), arg0)
+}
+
+// SetTrailer mocks base method
+func (m *MockAgent_PushFilesServer) SetTrailer(arg0 metadata.MD) {
+	m.ctrl.Call(m, "SetTrailer", arg0)
+}
+
+// SetTrailer indicates an expected call of SetTrailer
+func (mr *MockAgent_PushFilesServerMockRecorder) SetTrailer(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTrailer", reflect.TypeOf((*MockAgent_PushFilesServer)(nil).SetTrailer), arg0)
+}
+
+// Context mocks base method
+func (m *MockAgent_PushFilesServer) Context() context.Context {
+	ret := m.ctrl.Call(m, "Context")
+	ret0, _ := ret[0].(context.Context)
+	return ret0
+}
+
+// Context indicates an expected call of Context
+func (mr *MockAgent_PushFilesServerMockRecorder) Context() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Context", reflect.TypeOf((*MockAgent_PushFilesServer)(nil).Context))
+}
+
+// SendMsg mocks base method
+func (m_2 *MockAgent_PushFilesServer) SendMsg(m interface{}) error {
+	ret := m_2.ctrl.Call(m_2, "SendMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendMsg indicates an expected call of SendMsg
+func (mr *MockAgent_PushFilesServerMockRecorder) SendMsg(m interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMsg", reflect.TypeOf((*MockAgent_PushFilesServer)(nil).SendMsg), m)
+}
+
+// RecvMsg mocks base method
+func (m_2 *MockAgent_PushFilesServer) RecvMsg(m interface{}) error {
+	ret := m_2.ctrl.Call(m_2, "RecvMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecvMsg indicates an expected call of RecvMsg
+func (mr *MockAgent_PushFilesServerMockRecorder) RecvMsg(m interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecvMsg", reflect.TypeOf((*MockAgent_PushFilesServer)(nil).RecvMsg), m)
+}
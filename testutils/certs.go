@@ -0,0 +1,60 @@
+package testutils
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/greenplum-db/gpupgrade/certs"
+
+	"google.golang.org/grpc/credentials"
+)
+
+// AgentCredentials issues a certificate for the agent on host from the CA in
+// hubStateDir, and loads it the way the agent does to serve the hub, once the
+// hub has copied it over.
+func AgentCredentials(hubStateDir string, host string) (credentials.TransportCredentials, error) {
+	return loadAgentCertificate(hubStateDir, host, func(stateDir string, name string) (credentials.TransportCredentials, error) {
+		return certs.ServerCredentials(stateDir, name, certs.Hub)
+	})
+}
+
+// AgentClientCredentials is AgentCredentials for an agent registering with
+// the hub.
+func AgentClientCredentials(hubStateDir string, host string) (credentials.TransportCredentials, error) {
+	return loadAgentCertificate(hubStateDir, host, certs.ClientCredentials)
+}
+
+func loadAgentCertificate(hubStateDir string, host string,
+	load func(string, string) (credentials.TransportCredentials, error)) (credentials.TransportCredentials, error) {
+
+	source, err := certs.IssueAgentCertificate(hubStateDir, host)
+	if err != nil {
+		return nil, err
+	}
+
+	agentStateDir, err := ioutil.TempDir("", "")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(agentStateDir)
+
+	destination := certs.Dir(agentStateDir)
+	err = os.MkdirAll(destination, 0700)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, name := range []string{certs.CA + ".crt", certs.Agent + ".crt", certs.Agent + ".key"} {
+		contents, err := ioutil.ReadFile(filepath.Join(source, name))
+		if err != nil {
+			return nil, err
+		}
+		err = ioutil.WriteFile(filepath.Join(destination, name), contents, 0600)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return load(agentStateDir, certs.Agent)
+}
@@ -0,0 +1,36 @@
+package testutils
+
+import (
+	"sync"
+
+	"github.com/greenplum-db/gpupgrade/hub/cluster_ssher"
+)
+
+// FakeBackend is a cluster_ssher.Backend that answers the commands run on
+// each host with the Result set for that host, and records them.
+type FakeBackend struct {
+	mu       sync.Mutex
+	Results  map[string]cluster_ssher.Result
+	Commands map[string][]string
+}
+
+func NewFakeBackend() *FakeBackend {
+	return &FakeBackend{
+		Results:  make(map[string]cluster_ssher.Result),
+		Commands: make(map[string][]string),
+	}
+}
+
+func (b *FakeBackend) Run(host string, command string) cluster_ssher.Result {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.Commands[host] = append(b.Commands[host], command)
+	result := b.Results[host]
+	result.Host = host
+	return result
+}
+
+func (b *FakeBackend) Copy(host string, sources []string, destination string) cluster_ssher.Result {
+	return cluster_ssher.Result{Host: host}
+}
@@ -2,6 +2,7 @@ package testutils
 
 import (
 	"context"
+	"io"
 	"net"
 	"sync"
 
@@ -19,11 +20,23 @@ type MockAgentServer struct {
 	StatusConversionRequest              *pb.CheckConversionStatusRequest
 	StatusConversionResponse             *pb.CheckConversionStatusReply
 	UpgradeConvertPrimarySegmentsRequest *pb.UpgradeConvertPrimarySegmentsRequest
+	CheckDiskSpaceRequest                *pb.CheckDiskSpaceRequestToAgent
+	CheckDiskSpaceResponse               *pb.CheckDiskSpaceReplyFromAgent
+	TailLogsRequest                      *pb.TailLogsRequest
+	LogChunks                            []*pb.LogChunk
+	FileChunks                           []*pb.FileChunk
+	Cancelled                            []string
+	Reverted                             bool
+	RevertGate                           chan struct{} // if set, Revert waits for it to be closed
+	PushFilesGate                        chan struct{} // if set, PushFiles waits for it to be closed before reading
+	Version                              string
 
 	Err chan error
 }
 
-func NewMockAgentServer() (*MockAgentServer, int) {
+// NewMockAgentServer starts a MockAgentServer on a free port, which it returns.
+// opts are passed on to the gRPC server.
+func NewMockAgentServer(opts ...grpc.ServerOption) (*MockAgentServer, int) {
 	lis, err := net.Listen("tcp", "localhost:0")
 	if err != nil {
 		panic(err)
@@ -31,7 +44,7 @@ func NewMockAgentServer() (*MockAgentServer, int) {
 
 	mockServer := &MockAgentServer{
 		addr:       lis.Addr(),
-		grpcServer: grpc.NewServer(),
+		grpcServer: grpc.NewServer(opts...),
 		Err:        make(chan error, 10000),
 	}
 
@@ -63,16 +76,25 @@ func (m *MockAgentServer) CheckConversionStatus(ctx context.Context, in *pb.Chec
 	return m.StatusConversionResponse, err
 }
 
-func (m *MockAgentServer) CheckDiskSpaceOnAgents(context.Context, *pb.CheckDiskSpaceRequestToAgent) (*pb.CheckDiskSpaceReplyFromAgent, error) {
+func (m *MockAgentServer) CheckDiskSpaceOnAgents(ctx context.Context, in *pb.CheckDiskSpaceRequestToAgent) (*pb.CheckDiskSpaceReplyFromAgent, error) {
 	m.increaseCalls()
 
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.CheckDiskSpaceRequest = in
+	if len(m.Err) != 0 {
+		return nil, <-m.Err
+	}
+	if m.CheckDiskSpaceResponse != nil {
+		return m.CheckDiskSpaceResponse, nil
+	}
 	return &pb.CheckDiskSpaceReplyFromAgent{}, nil
 }
 
 func (m *MockAgentServer) PingAgents(context.Context, *pb.PingAgentsRequest) (*pb.PingAgentsReply, error) {
 	m.increaseCalls()
 
-	return &pb.PingAgentsReply{}, nil
+	return &pb.PingAgentsReply{Version: m.Version}, nil
 }
 
 func (m *MockAgentServer) UpgradeConvertPrimarySegments(ctx context.Context, in *pb.UpgradeConvertPrimarySegmentsRequest) (*pb.UpgradeConvertPrimarySegmentsReply, error) {
@@ -90,6 +112,47 @@ func (m *MockAgentServer) UpgradeConvertPrimarySegments(ctx context.Context, in
 	return &pb.UpgradeConvertPrimarySegmentsReply{}, err
 }
 
+func (m *MockAgentServer) Shutdown(context.Context, *pb.ShutdownAgentRequest) (*pb.ShutdownAgentReply, error) {
+	m.increaseCalls()
+
+	var err error
+	if len(m.Err) != 0 {
+		err = <-m.Err
+	}
+
+	return &pb.ShutdownAgentReply{}, err
+}
+
+func (m *MockAgentServer) Cancel(context.Context, *pb.CancelAgentRequest) (*pb.CancelAgentReply, error) {
+	m.increaseCalls()
+
+	var err error
+	if len(m.Err) != 0 {
+		err = <-m.Err
+	}
+
+	return &pb.CancelAgentReply{Cancelled: m.Cancelled}, err
+}
+
+func (m *MockAgentServer) Revert(context.Context, *pb.RevertAgentRequest) (*pb.RevertAgentReply, error) {
+	m.increaseCalls()
+
+	if m.RevertGate != nil {
+		<-m.RevertGate
+	}
+
+	var err error
+	if len(m.Err) != 0 {
+		err = <-m.Err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.Reverted = err == nil
+
+	return &pb.RevertAgentReply{}, err
+}
+
 func (m *MockAgentServer) Stop() {
 	m.grpcServer.Stop()
 }
@@ -107,3 +170,68 @@ func (m *MockAgentServer) NumberOfCalls() int {
 
 	return m.numCalls
 }
+
+func (m *MockAgentServer) TailLogs(in *pb.TailLogsRequest, stream pb.Agent_TailLogsServer) error {
+	m.increaseCalls()
+
+	m.TailLogsRequest = in
+
+	for _, chunk := range m.LogChunks {
+		err := stream.Send(chunk)
+		if err != nil {
+			return err
+		}
+	}
+
+	var err error
+	if len(m.Err) != 0 {
+		err = <-m.Err
+	}
+
+	return err
+}
+
+func (m *MockAgentServer) PushFiles(stream pb.Agent_PushFilesServer) error {
+	m.increaseCalls()
+
+	if m.PushFilesGate != nil {
+		select {
+		case <-m.PushFilesGate:
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		}
+	}
+
+	var paths []string
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		m.mu.Lock()
+		m.FileChunks = append(m.FileChunks, chunk)
+		m.mu.Unlock()
+
+		if chunk.Checksum != "" {
+			paths = append(paths, chunk.Path)
+		}
+	}
+
+	if len(m.Err) != 0 {
+		return <-m.Err
+	}
+
+	return stream.SendAndClose(&pb.PushFilesReply{Paths: paths})
+}
+
+// ReceivedFileChunks returns the chunks of the files pushed to the agent.
+func (m *MockAgentServer) ReceivedFileChunks() []*pb.FileChunk {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.FileChunks
+}
@@ -5,6 +5,7 @@ type MockChecklistManager struct {
 	mapFailed     map[string]bool
 	mapInProgress map[string]bool
 	mapReset      map[string]bool
+	mapCause      map[string]error
 }
 
 func NewMockChecklistManager() *MockChecklistManager {
@@ -13,6 +14,7 @@ func NewMockChecklistManager() *MockChecklistManager {
 		mapFailed:     make(map[string]bool, 0),
 		mapInProgress: make(map[string]bool, 0),
 		mapReset:      make(map[string]bool, 0),
+		mapCause:      make(map[string]error, 0),
 	}
 }
 
@@ -26,8 +28,9 @@ func (cm *MockChecklistManager) MarkInProgress(step string) error {
 	return nil
 }
 
-func (cm *MockChecklistManager) MarkFailed(step string) error {
+func (cm *MockChecklistManager) MarkFailed(step string, cause error) error {
 	cm.mapFailed[step] = true
+	cm.mapCause[step] = cause
 	return nil
 }
 
@@ -63,3 +66,8 @@ func (cm *MockChecklistManager) IsInProgress(step string) bool {
 func (cm *MockChecklistManager) WasReset(step string) bool {
 	return cm.mapReset[step]
 }
+
+// FailureCause returns the cause that step was last marked as failed with.
+func (cm *MockChecklistManager) FailureCause(step string) error {
+	return cm.mapCause[step]
+}
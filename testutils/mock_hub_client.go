@@ -11,6 +11,12 @@ import (
 type MockHubClient struct {
 	UpgradeShareOidsRequest        *pb.UpgradeShareOidsRequest
 	UpgradeReconfigurePortsRequest *pb.UpgradeReconfigurePortsRequest
+	UpgradeRunRequest              *pb.UpgradeRunRequest
+	RevertRequest                  *pb.RevertRequest
+	CancelRequest                  *pb.CancelRequest
+	CancelReply                    *pb.CancelReply
+	CheckAllRequest                *pb.CheckAllRequest
+	CheckAllReply                  *pb.CheckAllReply
 
 	UpgradeConvertPrimariesRequest  *pb.UpgradeConvertPrimariesRequest
 	UpgradeConvertPrimariesResponse *pb.UpgradeConvertPrimariesReply
@@ -29,6 +35,10 @@ func (m *MockHubClient) StatusUpgrade(ctx context.Context, in *pb.StatusUpgradeR
 	return nil, nil
 }
 
+func (m *MockHubClient) WatchUpgradeStatus(ctx context.Context, in *pb.WatchUpgradeStatusRequest, opts ...grpc.CallOption) (pb.CliToHub_WatchUpgradeStatusClient, error) {
+	return nil, nil
+}
+
 func (m *MockHubClient) StatusConversion(ctx context.Context, in *pb.StatusConversionRequest, opts ...grpc.CallOption) (*pb.StatusConversionReply, error) {
 	return nil, nil
 }
@@ -90,3 +100,47 @@ func (m *MockHubClient) UpgradeReconfigurePorts(ctx context.Context, in *pb.Upgr
 
 	return nil, m.Err
 }
+
+func (m *MockHubClient) UpgradeRun(ctx context.Context, in *pb.UpgradeRunRequest, opts ...grpc.CallOption) (*pb.UpgradeRunReply, error) {
+	m.UpgradeRunRequest = in
+
+	return &pb.UpgradeRunReply{}, m.Err
+}
+
+func (m *MockHubClient) Revert(ctx context.Context, in *pb.RevertRequest, opts ...grpc.CallOption) (*pb.RevertReply, error) {
+	m.RevertRequest = in
+
+	return &pb.RevertReply{}, m.Err
+}
+
+func (m *MockHubClient) PrepareStopAgents(ctx context.Context, in *pb.PrepareStopAgentsRequest, opts ...grpc.CallOption) (*pb.PrepareStopAgentsReply, error) {
+	return nil, nil
+}
+
+func (m *MockHubClient) Shutdown(ctx context.Context, in *pb.ShutdownRequest, opts ...grpc.CallOption) (*pb.ShutdownReply, error) {
+	return nil, nil
+}
+
+func (m *MockHubClient) CheckAgents(ctx context.Context, in *pb.CheckAgentsRequest, opts ...grpc.CallOption) (*pb.CheckAgentsReply, error) {
+	return nil, nil
+}
+
+func (m *MockHubClient) StatusAgents(ctx context.Context, in *pb.StatusAgentsRequest, opts ...grpc.CallOption) (*pb.StatusAgentsReply, error) {
+	return nil, nil
+}
+
+func (m *MockHubClient) Logs(ctx context.Context, in *pb.LogsRequest, opts ...grpc.CallOption) (pb.CliToHub_LogsClient, error) {
+	return nil, nil
+}
+
+func (m *MockHubClient) Cancel(ctx context.Context, in *pb.CancelRequest, opts ...grpc.CallOption) (*pb.CancelReply, error) {
+	m.CancelRequest = in
+
+	return m.CancelReply, m.Err
+}
+
+func (m *MockHubClient) CheckAll(ctx context.Context, in *pb.CheckAllRequest, opts ...grpc.CallOption) (*pb.CheckAllReply, error) {
+	m.CheckAllRequest = in
+
+	return m.CheckAllReply, m.Err
+}
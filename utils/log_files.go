@@ -0,0 +1,111 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"io"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// LogFollowInterval is how often StreamLogs checks the files it follows for
+// new output.
+var LogFollowInterval = 500 * time.Millisecond
+
+// logChunkSize is the most that StreamLogs sends at once.
+const logChunkSize = 32 * 1024
+
+// ErrNoLogs is returned by StreamLogs when none of the files exist.
+var ErrNoLogs = errors.New("no log files found")
+
+// PGUpgradeLogPatterns matches the logs that pg_upgrade writes to its working
+// directory dir, along with the output it was started with.
+func PGUpgradeLogPatterns(dir string) []string {
+	return []string{filepath.Join(dir, "*.log"), filepath.Join(dir, "nohup.out")}
+}
+
+// StreamLogs hands the contents of the files matching patterns to send, a
+// chunk at a time and a file at a time. If follow is set it keeps going, and
+// sends whatever is added to the files, or new files that match, until ctx is
+// done. A file that is truncated is sent again from the start.
+func StreamLogs(ctx context.Context, patterns []string, follow bool, send func(path string, data []byte) error) error {
+	offsets := make(map[string]int64)
+	for {
+		var paths []string
+		for _, pattern := range patterns {
+			matches, err := System.FilePathGlob(pattern)
+			if err != nil {
+				return err
+			}
+			paths = append(paths, matches...)
+		}
+		sort.Strings(paths)
+
+		if len(paths) == 0 && !follow {
+			return ErrNoLogs
+		}
+
+		for _, path := range paths {
+			offset, err := sendLogFrom(path, offsets[path], send)
+			if err != nil {
+				return err
+			}
+			offsets[path] = offset
+		}
+
+		if !follow {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(LogFollowInterval):
+		}
+	}
+}
+
+// sendLogFrom sends everything in the file at path after offset, and returns
+// the offset that it got to. Files that have gone away are skipped.
+func sendLogFrom(path string, offset int64, send func(path string, data []byte) error) (int64, error) {
+	f, err := System.Open(path)
+	if err != nil {
+		if System.IsNotExist(err) {
+			return 0, nil
+		}
+		return offset, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return offset, err
+	}
+	if info.Size() < offset {
+		offset = 0
+	}
+
+	_, err = f.Seek(offset, io.SeekStart)
+	if err != nil {
+		return offset, err
+	}
+
+	buf := make([]byte, logChunkSize)
+	for {
+		n, err := f.Read(buf)
+		if n > 0 {
+			sendErr := send(path, buf[:n])
+			if sendErr != nil {
+				return offset, sendErr
+			}
+			offset += int64(n)
+		}
+		if err == io.EOF {
+			return offset, nil
+		}
+		if err != nil {
+			return offset, err
+		}
+	}
+}
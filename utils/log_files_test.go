@@ -0,0 +1,123 @@
+package utils
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("StreamLogs", func() {
+	var (
+		dir            string
+		followInterval time.Duration
+
+		mu   sync.Mutex
+		sent map[string]string
+	)
+
+	send := func(path string, data []byte) error {
+		mu.Lock()
+		defer mu.Unlock()
+
+		sent[filepath.Base(path)] += string(data)
+		return nil
+	}
+
+	sentTo := func(name string) func() string {
+		return func() string {
+			mu.Lock()
+			defer mu.Unlock()
+
+			return sent[name]
+		}
+	}
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "")
+		Expect(err).ToNot(HaveOccurred())
+
+		sent = make(map[string]string)
+
+		followInterval = LogFollowInterval
+		LogFollowInterval = 10 * time.Millisecond
+	})
+
+	AfterEach(func() {
+		LogFollowInterval = followInterval
+		os.RemoveAll(dir)
+	})
+
+	It("sends every file that matches, in order", func() {
+		Expect(ioutil.WriteFile(filepath.Join(dir, "b.log"), []byte("second\n"), 0600)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(dir, "a.log"), []byte("first\n"), 0600)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(dir, "nohup.out"), []byte("output\n"), 0600)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(dir, "other.txt"), []byte("ignored\n"), 0600)).To(Succeed())
+
+		var order []string
+		err := StreamLogs(context.Background(), PGUpgradeLogPatterns(dir), false, func(path string, data []byte) error {
+			order = append(order, filepath.Base(path))
+			return send(path, data)
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(order).To(Equal([]string{"a.log", "b.log", "nohup.out"}))
+		Expect(sent).To(Equal(map[string]string{
+			"a.log":     "first\n",
+			"b.log":     "second\n",
+			"nohup.out": "output\n",
+		}))
+	})
+
+	It("fails when there are no files", func() {
+		err := StreamLogs(context.Background(), PGUpgradeLogPatterns(dir), false, send)
+		Expect(err).To(Equal(ErrNoLogs))
+	})
+
+	It("sends what is added to the files it follows until it is cancelled", func() {
+		path := filepath.Join(dir, "a.log")
+		Expect(ioutil.WriteFile(path, []byte("first\n"), 0600)).To(Succeed())
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error)
+		go func() {
+			done <- StreamLogs(ctx, PGUpgradeLogPatterns(dir), true, send)
+		}()
+
+		Eventually(sentTo("a.log")).Should(Equal("first\n"))
+
+		f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0600)
+		Expect(err).ToNot(HaveOccurred())
+		_, err = f.WriteString("second\n")
+		Expect(err).ToNot(HaveOccurred())
+		f.Close()
+
+		Eventually(sentTo("a.log")).Should(Equal("first\nsecond\n"))
+
+		Expect(ioutil.WriteFile(filepath.Join(dir, "b.log"), []byte("new file\n"), 0600)).To(Succeed())
+		Eventually(sentTo("b.log")).Should(Equal("new file\n"))
+
+		cancel()
+		Eventually(done).Should(Receive(BeNil()))
+	})
+
+	It("starts over when a file it follows is truncated", func() {
+		path := filepath.Join(dir, "a.log")
+		Expect(ioutil.WriteFile(path, []byte("a long first run\n"), 0600)).To(Succeed())
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go StreamLogs(ctx, PGUpgradeLogPatterns(dir), true, send)
+
+		Eventually(sentTo("a.log")).Should(Equal("a long first run\n"))
+
+		Expect(ioutil.WriteFile(path, []byte("rerun\n"), 0600)).To(Succeed())
+		Eventually(sentTo("a.log")).Should(Equal("a long first run\nrerun\n"))
+	})
+})
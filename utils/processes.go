@@ -0,0 +1,536 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/greenplum-db/gpupgrade/helpers"
+
+	"github.com/greenplum-db/gp-common-go-libs/gplog"
+)
+
+// TerminateGracePeriod is how long Processes.Terminate waits for a process to
+// exit after asking it to before killing it.
+var TerminateGracePeriod = 10 * time.Second
+
+// processPollInterval is how often Processes.Terminate checks whether a
+// process started before a restart, which it can't wait for, has exited.
+var processPollInterval = 100 * time.Millisecond
+
+// ErrCancelled is returned for a process that was terminated by
+// Processes.Terminate, and is recorded as the reason a cancelled step failed.
+var ErrCancelled = errors.New("cancelled")
+
+// ProcessRecord is what Processes knows about a child process it started.
+// The records are kept in the state directory, so that they outlive the hub
+// or agent that started the processes.
+type ProcessRecord struct {
+	Name      string    `json:"name"`
+	PID       int       `json:"pid"`
+	LogPath   string    `json:"logPath,omitempty"`
+	StartTime time.Time `json:"startTime"`
+	EndTime   time.Time `json:"endTime"` // zero while the process runs
+
+	// ProcessStart is when the process started, in clock ticks after boot,
+	// as field 22 of /proc/<pid>/stat has it. It tells the process apart
+	// from a later one that has been given the same PID; it is zero where
+	// there is no /proc.
+	ProcessStart uint64 `json:"processStart,omitempty"`
+
+	// ExitCode is -1 if the process was killed by a signal, or exited while
+	// nobody was waiting for it.
+	ExitCode  int  `json:"exitCode"`
+	Cancelled bool `json:"cancelled,omitempty"`
+}
+
+// Running returns whether the process is still running. Processes starts
+// every process as the leader of its own process group, so a process that
+// doesn't lead one, or that started at another time than the one recorded, is
+// an unrelated process that has been given the same PID.
+func (r *ProcessRecord) Running() bool {
+	if !r.EndTime.IsZero() {
+		return false
+	}
+
+	pgid, err := syscall.Getpgid(r.PID)
+	if err != nil || pgid != r.PID {
+		return false
+	}
+
+	if r.ProcessStart == 0 {
+		return true
+	}
+	start, err := processStartTime(r.PID)
+	return err == nil && start == r.ProcessStart
+}
+
+// processStartTime returns when the process pid started, in clock ticks
+// after boot, from field 22 of /proc/<pid>/stat.
+func processStartTime(pid int) (uint64, error) {
+	stat, err := ioutil.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+	if err != nil {
+		return 0, err
+	}
+
+	// The command name, field 2, is in parentheses and may itself contain
+	// spaces and parentheses, so the fields are counted from the last ")".
+	end := bytes.LastIndexByte(stat, ')')
+	if end < 0 {
+		return 0, fmt.Errorf("malformed /proc/%d/stat", pid)
+	}
+	fields := strings.Fields(string(stat[end+1:]))
+	const startTimeField = 22 - 3 // fields[0] is field 3
+	if len(fields) <= startTimeField {
+		return 0, fmt.Errorf("malformed /proc/%d/stat", pid)
+	}
+
+	return strconv.ParseUint(fields[startTimeField], 10, 64)
+}
+
+// Processes supervises the child processes started for the upgrade steps. It
+// records when each one started and how it exited, so that their status can
+// be answered from the records, and terminates them when the user cancels the
+// upgrade.
+type Processes struct {
+	recordPath string
+
+	mu      sync.Mutex
+	running map[*process]bool
+	records map[string]*ProcessRecord
+}
+
+type process struct {
+	name    string
+	cmd     helpers.Command
+	cleanup func() error
+	done    chan struct{}
+
+	terminated bool
+}
+
+// NewProcesses returns a Processes that keeps its records in recordPath,
+// starting from the ones already there. With an empty recordPath they are
+// only kept in memory.
+func NewProcesses(recordPath string) *Processes {
+	p := &Processes{
+		recordPath: recordPath,
+		running:    make(map[*process]bool),
+		records:    make(map[string]*ProcessRecord),
+	}
+
+	err := p.load()
+	if err != nil {
+		gplog.Error("could not read the process records in %s: %s", recordPath, err)
+	}
+	return p
+}
+
+// Start starts cmd, in a process group of its own so that the processes it
+// starts in turn can be terminated along with it, and supervises it under
+// name until it exits. logPath is where cmd writes its output. cleanup, if it
+// isn't nil, is called after the process has been terminated, to undo what it
+// left half done.
+func (p *Processes) Start(name string, logPath string, cmd helpers.Command, cleanup func() error) error {
+	proc, err := p.start(name, logPath, cmd, cleanup)
+	if err != nil {
+		return err
+	}
+
+	go p.wait(proc)
+	return nil
+}
+
+// CombinedOutput runs cmd like Start does and returns its combined output
+// once it exits, or ErrCancelled if it was terminated.
+func (p *Processes) CombinedOutput(name string, cmd helpers.Command) ([]byte, error) {
+	execCmd, ok := cmd.(*exec.Cmd)
+	if !ok {
+		return p.combinedOutput(name, cmd)
+	}
+
+	var output bytes.Buffer
+	execCmd.Stdout = &output
+	execCmd.Stderr = &output
+
+	proc, err := p.start(name, "", cmd, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	err = p.wait(proc)
+	if p.wasTerminated(proc) {
+		return output.Bytes(), ErrCancelled
+	}
+	return output.Bytes(), err
+}
+
+// Record returns a copy of the record of the process last started under
+// name, or nil if there is none.
+func (p *Processes) Record(name string) *ProcessRecord {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	record, ok := p.records[name]
+	if !ok {
+		return nil
+	}
+
+	copied := *record
+	return &copied
+}
+
+// Records returns copies of all the records, ordered by name.
+func (p *Processes) Records() []ProcessRecord {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.sortedRecords()
+}
+
+// Terminate asks every process that is running to exit, kills the ones that
+// are still running after TerminateGracePeriod, and then cleans up after
+// them. Processes that were started before a restart are terminated as far
+// as their records allow, but there is nothing to clean up after them with.
+// It returns the names of the processes it terminated.
+func (p *Processes) Terminate() []string {
+	p.mu.Lock()
+	var procs []*process
+	supervised := make(map[string]bool)
+	for proc := range p.running {
+		proc.terminated = true
+		procs = append(procs, proc)
+		supervised[proc.name] = true
+	}
+
+	var orphans []*ProcessRecord
+	for name, record := range p.records {
+		if !supervised[name] && record.Running() {
+			orphans = append(orphans, record)
+		}
+	}
+	p.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for _, proc := range procs {
+		wg.Add(1)
+		go func(proc *process) {
+			defer wg.Done()
+			terminate(proc)
+		}(proc)
+	}
+	for _, record := range orphans {
+		wg.Add(1)
+		go func(record *ProcessRecord) {
+			defer wg.Done()
+			p.terminateOrphan(record)
+		}(record)
+	}
+	wg.Wait()
+
+	names := make(map[string]bool)
+	for _, proc := range procs {
+		// Commands that can't be waited for are forgotten once terminated.
+		p.mu.Lock()
+		delete(p.running, proc)
+		p.mu.Unlock()
+
+		if proc.cleanup != nil {
+			err := proc.cleanup()
+			if err != nil {
+				gplog.Error("could not clean up after %s: %s", proc.name, err)
+			}
+		}
+		names[proc.name] = true
+	}
+	for _, record := range orphans {
+		names[record.Name] = true
+	}
+
+	var terminated []string
+	for name := range names {
+		terminated = append(terminated, name)
+	}
+	sort.Strings(terminated)
+	return terminated
+}
+
+// Forget drops the records of every process, so that the steps they were
+// started for read as never having been run. It refuses while any of them is
+// still running.
+func (p *Processes) Forget() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for proc := range p.running {
+		return fmt.Errorf("%s is still running", proc.name)
+	}
+	for name, record := range p.records {
+		if record.Running() {
+			return fmt.Errorf("%s is still running", name)
+		}
+	}
+
+	p.records = make(map[string]*ProcessRecord)
+	p.save()
+	return nil
+}
+
+// Running returns whether a process started under name is running.
+func (p *Processes) Running(name string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for proc := range p.running {
+		if proc.name == name {
+			return true
+		}
+	}
+
+	record, ok := p.records[name]
+	return ok && record.Running()
+}
+
+func (p *Processes) start(name string, logPath string, cmd helpers.Command, cleanup func() error) (*process, error) {
+	execCmd, isExecCmd := cmd.(*exec.Cmd)
+	if isExecCmd {
+		execCmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	}
+
+	err := cmd.Start()
+	if err != nil {
+		return nil, err
+	}
+
+	proc := &process{name: name, cmd: cmd, cleanup: cleanup, done: make(chan struct{})}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.running[proc] = true
+
+	// Only real processes have a PID worth recording.
+	if isExecCmd {
+		pid := execCmd.Process.Pid
+		processStart, err := processStartTime(pid)
+		if err != nil {
+			gplog.Debug("could not read when %s (process %d) started: %s", name, pid, err)
+		}
+
+		p.records[name] = &ProcessRecord{
+			Name:         name,
+			PID:          pid,
+			LogPath:      logPath,
+			StartTime:    System.Now(),
+			ProcessStart: processStart,
+		}
+		p.save()
+	}
+
+	gplog.Debug("started %s", name)
+	return proc, nil
+}
+
+// combinedOutput tracks commands that aren't *exec.Cmd, which can't be
+// signalled, for as long as they run.
+func (p *Processes) combinedOutput(name string, cmd helpers.Command) ([]byte, error) {
+	proc := &process{name: name, cmd: cmd, done: make(chan struct{})}
+	p.mu.Lock()
+	p.running[proc] = true
+	p.mu.Unlock()
+
+	output, err := cmd.CombinedOutput()
+	p.finish(proc, ExitCode(err))
+
+	if p.wasTerminated(proc) {
+		return output, ErrCancelled
+	}
+	return output, err
+}
+
+// wait waits for proc to exit, if it can, and records how it exited.
+func (p *Processes) wait(proc *process) error {
+	var err error
+	if execCmd, ok := proc.cmd.(*exec.Cmd); ok {
+		err = execCmd.Wait()
+		p.finish(proc, ExitCode(err))
+	}
+	return err
+}
+
+func (p *Processes) finish(proc *process, exitCode int) {
+	p.mu.Lock()
+	delete(p.running, proc)
+	if record, ok := p.records[proc.name]; ok && record.EndTime.IsZero() {
+		record.EndTime = System.Now()
+		record.ExitCode = exitCode
+		record.Cancelled = proc.terminated
+		p.save()
+	}
+	p.mu.Unlock()
+
+	close(proc.done)
+}
+
+func (p *Processes) wasTerminated(proc *process) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return proc.terminated
+}
+
+// terminateOrphan terminates a process that was started before a restart.
+// It isn't a child any more, so rather than being waited for, it is polled
+// until it has exited, and its exit code is lost. Since its PID may have been
+// given to another process once it exited, the record is checked before each
+// signal.
+func (p *Processes) terminateOrphan(record *ProcessRecord) {
+	gplog.Info("terminating %s (process %d), started before a restart", record.Name, record.PID)
+	if record.Running() {
+		syscall.Kill(-record.PID, syscall.SIGTERM)
+	}
+
+	killAt := time.Now().Add(TerminateGracePeriod)
+	for record.Running() {
+		if !killAt.IsZero() && time.Now().After(killAt) {
+			gplog.Warn("%s did not exit after %s; killing it", record.Name, TerminateGracePeriod)
+			syscall.Kill(-record.PID, syscall.SIGKILL)
+			killAt = time.Time{}
+		}
+		time.Sleep(processPollInterval)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	record.EndTime = System.Now()
+	record.ExitCode = -1
+	record.Cancelled = true
+	p.save()
+}
+
+// terminate sends SIGTERM to the process group of proc, and SIGKILL if it
+// hasn't exited after TerminateGracePeriod. Commands that aren't *exec.Cmd are
+// killed if they can be.
+func terminate(proc *process) {
+	execCmd, ok := proc.cmd.(*exec.Cmd)
+	if !ok {
+		if killer, ok := proc.cmd.(interface{ Kill() error }); ok {
+			err := killer.Kill()
+			if err != nil {
+				gplog.Error("could not kill %s: %s", proc.name, err)
+			}
+		}
+		return
+	}
+
+	pgid := -execCmd.Process.Pid
+	gplog.Info("terminating %s (process %d)", proc.name, execCmd.Process.Pid)
+	syscall.Kill(pgid, syscall.SIGTERM)
+
+	select {
+	case <-proc.done:
+	case <-time.After(TerminateGracePeriod):
+		gplog.Warn("%s did not exit after %s; killing it", proc.name, TerminateGracePeriod)
+		syscall.Kill(pgid, syscall.SIGKILL)
+		<-proc.done
+	}
+}
+
+// sortedRecords must be called with mu held.
+func (p *Processes) sortedRecords() []ProcessRecord {
+	var records []ProcessRecord
+	for _, record := range p.records {
+		records = append(records, *record)
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].Name < records[j].Name })
+	return records
+}
+
+func (p *Processes) load() error {
+	if p.recordPath == "" {
+		return nil
+	}
+
+	contents, err := System.ReadFile(p.recordPath)
+	if System.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var records []ProcessRecord
+	err = json.Unmarshal(contents, &records)
+	if err != nil {
+		return err
+	}
+
+	for i := range records {
+		p.records[records[i].Name] = &records[i]
+	}
+	return nil
+}
+
+// save writes the records to a temporary file and renames it over
+// recordPath, so that a crash can't leave them half written. It must be
+// called with mu held.
+func (p *Processes) save() {
+	if p.recordPath == "" {
+		return
+	}
+
+	contents, err := json.MarshalIndent(p.sortedRecords(), "", "  ")
+	if err == nil {
+		tmpPath := p.recordPath + ".tmp"
+		err = System.WriteFile(tmpPath, contents, 0600)
+		if err == nil {
+			err = System.Rename(tmpPath, p.recordPath)
+		}
+	}
+	if err != nil {
+		gplog.Error("could not save the process records to %s: %s", p.recordPath, err)
+	}
+}
+
+// ExitCode returns the exit code of the process that err was returned for by
+// Run, Wait and the like: 0 for no error, or -1 if the process was killed by
+// a signal or couldn't be run at all.
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		if status, ok := exitErr.Sys().(syscall.WaitStatus); ok {
+			return status.ExitStatus()
+		}
+	}
+	return -1
+}
+
+// StopPostgres stops the server running in dataDir, if there is one, with the
+// pg_ctl in binDir. It is for cleaning up after a pg_upgrade that was
+// terminated, which leaves the servers it started running.
+func StopPostgres(commandExecer helpers.CommandExecer, binDir string, dataDir string) error {
+	_, err := System.Stat(filepath.Join(dataDir, "postmaster.pid"))
+	if System.IsNotExist(err) {
+		return nil
+	}
+
+	command := fmt.Sprintf("source %[1]s/../greenplum_path.sh; %[1]s/pg_ctl stop -D %[2]s -m fast -w", binDir, dataDir)
+	output, err := commandExecer("bash", "-c", command).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("could not stop the server in %s: %s: %s", dataDir, err, output)
+	}
+	return nil
+}
@@ -0,0 +1,235 @@
+package utils
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/greenplum-db/gpupgrade/helpers"
+
+	"github.com/greenplum-db/gp-common-go-libs/testhelper"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Processes", func() {
+	var (
+		processes   *Processes
+		gracePeriod time.Duration
+	)
+
+	BeforeEach(func() {
+		testhelper.SetupTestLogger()
+
+		processes = NewProcesses("")
+		gracePeriod = TerminateGracePeriod
+	})
+
+	AfterEach(func() {
+		TerminateGracePeriod = gracePeriod
+		processes.Terminate()
+	})
+
+	It("terminates the processes it started, along with their children, and cleans up after them", func() {
+		cleanedUp := false
+		err := processes.Start("convert-master", "", exec.Command("bash", "-c", "sleep 60; true"), func() error {
+			cleanedUp = true
+			return nil
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(processes.Running("convert-master")).To(BeTrue())
+
+		Expect(processes.Terminate()).To(Equal([]string{"convert-master"}))
+		Expect(cleanedUp).To(BeTrue())
+		Expect(processes.Running("convert-master")).To(BeFalse())
+	})
+
+	It("kills processes that don't exit when asked", func() {
+		TerminateGracePeriod = 100 * time.Millisecond
+
+		err := processes.Start("stubborn", "", exec.Command("bash", "-c", `trap "" TERM; sleep 60; true`), nil)
+		Expect(err).ToNot(HaveOccurred())
+		// Give bash the time to ignore SIGTERM.
+		time.Sleep(100 * time.Millisecond)
+
+		Expect(processes.Terminate()).To(Equal([]string{"stubborn"}))
+	})
+
+	It("forgets processes once they exit", func() {
+		err := processes.Start("quick", "", exec.Command("true"), nil)
+		Expect(err).ToNot(HaveOccurred())
+
+		Eventually(func() bool { return processes.Running("quick") }).Should(BeFalse())
+		Expect(processes.Terminate()).To(BeEmpty())
+	})
+
+	It("returns the output of commands that run to completion", func() {
+		output, err := processes.CombinedOutput("gpstart", exec.Command("bash", "-c", "echo started; echo warning >&2"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(output)).To(Equal("started\nwarning\n"))
+	})
+
+	It("returns ErrCancelled for commands that are terminated", func() {
+		errs := make(chan error)
+		go func() {
+			_, err := processes.CombinedOutput("gpstop", exec.Command("sleep", "60"))
+			errs <- err
+		}()
+
+		Eventually(func() bool { return processes.Running("gpstop") }).Should(BeTrue())
+		Expect(processes.Terminate()).To(Equal([]string{"gpstop"}))
+		Eventually(errs).Should(Receive(Equal(ErrCancelled)))
+	})
+})
+
+var _ = Describe("Processes records", func() {
+	var (
+		dir        string
+		recordPath string
+		processes  *Processes
+	)
+
+	BeforeEach(func() {
+		testhelper.SetupTestLogger()
+
+		var err error
+		dir, err = ioutil.TempDir("", "")
+		Expect(err).ToNot(HaveOccurred())
+
+		recordPath = filepath.Join(dir, "processes.json")
+		processes = NewProcesses(recordPath)
+	})
+
+	AfterEach(func() {
+		processes.Terminate()
+		os.RemoveAll(dir)
+	})
+
+	It("records how a process exited, and keeps the record across restarts", func() {
+		err := processes.Start("convert-master", "/state/pg_upgrade.log", exec.Command("bash", "-c", "exit 3"), nil)
+		Expect(err).ToNot(HaveOccurred())
+
+		Eventually(func() bool { return processes.Running("convert-master") }).Should(BeFalse())
+
+		for _, p := range []*Processes{processes, NewProcesses(recordPath)} {
+			record := p.Record("convert-master")
+			Expect(record).ToNot(BeNil())
+			Expect(record.PID).ToNot(BeZero())
+			Expect(record.LogPath).To(Equal("/state/pg_upgrade.log"))
+			Expect(record.ExitCode).To(Equal(3))
+			Expect(record.Cancelled).To(BeFalse())
+			Expect(record.EndTime).ToNot(BeTemporally("<", record.StartTime))
+		}
+	})
+
+	It("records that a process was cancelled", func() {
+		err := processes.Start("convert-master", "", exec.Command("sleep", "60"), nil)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(processes.Terminate()).To(Equal([]string{"convert-master"}))
+
+		record := processes.Record("convert-master")
+		Expect(record.Cancelled).To(BeTrue())
+		Expect(record.ExitCode).To(Equal(-1))
+	})
+
+	It("terminates processes that were started before a restart", func() {
+		err := processes.Start("convert-master", "", exec.Command("sleep", "60"), nil)
+		Expect(err).ToNot(HaveOccurred())
+		pid := processes.Record("convert-master").PID
+
+		restarted := NewProcesses(recordPath)
+		Expect(restarted.Running("convert-master")).To(BeTrue())
+
+		Expect(restarted.Terminate()).To(Equal([]string{"convert-master"}))
+		Expect(restarted.Running("convert-master")).To(BeFalse())
+		Expect(restarted.Record("convert-master").Cancelled).To(BeTrue())
+		Expect(syscall.Kill(pid, 0)).To(HaveOccurred())
+	})
+
+	It("forgets the processes once none is running", func() {
+		err := processes.Start("convert-master", "", exec.Command("sleep", "60"), nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(processes.Forget()).To(MatchError("convert-master is still running"))
+
+		processes.Terminate()
+		Expect(processes.Forget()).To(Succeed())
+		Expect(processes.Records()).To(BeEmpty())
+		Expect(NewProcesses(recordPath).Records()).To(BeEmpty())
+	})
+
+	It("doesn't take an unrelated process with the same PID for one it started", func() {
+		// Started without a process group of its own.
+		cmd := exec.Command("sleep", "60")
+		Expect(cmd.Start()).To(Succeed())
+		defer func() {
+			cmd.Process.Kill()
+			cmd.Wait()
+		}()
+
+		record := ProcessRecord{Name: "convert-master", PID: cmd.Process.Pid}
+		Expect(record.Running()).To(BeFalse())
+	})
+
+	It("doesn't take a process that started at another time for one it started", func() {
+		err := processes.Start("convert-master", "", exec.Command("sleep", "60"), nil)
+		Expect(err).ToNot(HaveOccurred())
+
+		record := processes.Record("convert-master")
+		Expect(record.ProcessStart).ToNot(BeZero())
+		Expect(record.Running()).To(BeTrue())
+
+		// As if the process had exited and its PID been given to another.
+		record.ProcessStart++
+		Expect(record.Running()).To(BeFalse())
+	})
+})
+
+var _ = Describe("ExitCode", func() {
+	It("returns the exit code of a process", func() {
+		Expect(ExitCode(nil)).To(Equal(0))
+		Expect(ExitCode(exec.Command("bash", "-c", "exit 7").Run())).To(Equal(7))
+		Expect(ExitCode(exec.Command("/does/not/exist").Run())).To(Equal(-1))
+	})
+})
+
+var _ = Describe("StopPostgres", func() {
+	var (
+		dataDir string
+		calls   []string
+		execer  helpers.CommandExecer
+	)
+
+	BeforeEach(func() {
+		var err error
+		dataDir, err = ioutil.TempDir("", "")
+		Expect(err).ToNot(HaveOccurred())
+
+		calls = nil
+		execer = func(name string, args ...string) helpers.Command {
+			calls = append(calls, args[len(args)-1])
+			return exec.Command("true")
+		}
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dataDir)
+	})
+
+	It("stops the server running in the data directory", func() {
+		Expect(ioutil.WriteFile(filepath.Join(dataDir, "postmaster.pid"), []byte("1234\n"), 0600)).To(Succeed())
+
+		Expect(StopPostgres(execer, "/new/bin", dataDir)).To(Succeed())
+		Expect(calls).To(Equal([]string{
+			"source /new/bin/../greenplum_path.sh; /new/bin/pg_ctl stop -D " + dataDir + " -m fast -w",
+		}))
+	})
+
+	It("does nothing if no server is running there", func() {
+		Expect(StopPostgres(execer, "/new/bin", dataDir)).To(Succeed())
+		Expect(calls).To(BeEmpty())
+	})
+})
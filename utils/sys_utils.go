@@ -6,6 +6,7 @@ import (
 	"os"
 	"os/user"
 	"path/filepath"
+	"strings"
 	"time"
 )
 
@@ -33,6 +34,7 @@ type SystemFunctions struct {
 	OpenFile     func(name string, flag int, perm os.FileMode) (*os.File, error)
 	Remove       func(name string) error
 	RemoveAll    func(name string) error
+	Rename       func(oldpath, newpath string) error
 	ReadFile     func(filename string) ([]byte, error)
 	WriteFile    func(filename string, data []byte, perm os.FileMode) error
 	Stat         func(name string) (os.FileInfo, error)
@@ -53,6 +55,7 @@ func InitializeSystemFunctions() *SystemFunctions {
 		OpenFile:     os.OpenFile,
 		Remove:       os.Remove,
 		RemoveAll:    os.RemoveAll,
+		Rename:       os.Rename,
 		Stat:         os.Stat,
 		FilePathGlob: filepath.Glob,
 		ReadFile:     ioutil.ReadFile,
@@ -82,6 +85,11 @@ func GetHost() (string, error) {
 	return hostname, err
 }
 
+// ShellQuote quotes s as a single word for a POSIX shell.
+func ShellQuote(s string) string {
+	return "'" + strings.Replace(s, "'", `'\''`, -1) + "'"
+}
+
 func GetStateDir() string {
 	stateDir := os.Getenv("GPUPGRADE_HOME")
 	if stateDir == "" {
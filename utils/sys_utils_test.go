@@ -102,4 +102,10 @@ var _ = Describe("user utils", func() {
 
 	})
 
+	Describe("#ShellQuote", func() {
+		It("quotes a word so that the shell takes it as is", func() {
+			Expect(ShellQuote("/usr/local/greenplum db/bin")).To(Equal(`'/usr/local/greenplum db/bin'`))
+			Expect(ShellQuote("it's $HOME")).To(Equal(`'it'\''s $HOME'`))
+		})
+	})
 })